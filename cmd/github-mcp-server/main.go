@@ -7,7 +7,9 @@ import (
 	"strings"
 
 	"github.com/github/github-mcp-server/internal/ghmcp"
+	"github.com/github/github-mcp-server/pkg/archive"
 	"github.com/github/github-mcp-server/pkg/github"
+	"github.com/github/github-mcp-server/pkg/raw"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
@@ -50,18 +52,41 @@ var (
 				enabledToolsets = []string{github.ToolsetMetadataDefault.ID}
 			}
 
+			var sanitizeExemptToolsets []string
+			if err := viper.UnmarshalKey("sanitize-exempt-toolsets", &sanitizeExemptToolsets); err != nil {
+				return fmt.Errorf("failed to unmarshal sanitize-exempt-toolsets: %w", err)
+			}
+
+			var allowedLinkHosts []string
+			if err := viper.UnmarshalKey("allowed-link-hosts", &allowedLinkHosts); err != nil {
+				return fmt.Errorf("failed to unmarshal allowed-link-hosts: %w", err)
+			}
+
+			var repositoryDeletionAllowlist []string
+			if err := viper.UnmarshalKey("repository-deletion-allowlist", &repositoryDeletionAllowlist); err != nil {
+				return fmt.Errorf("failed to unmarshal repository-deletion-allowlist: %w", err)
+			}
+
 			stdioServerConfig := ghmcp.StdioServerConfig{
-				Version:              version,
-				Host:                 viper.GetString("host"),
-				Token:                token,
-				EnabledToolsets:      enabledToolsets,
-				DynamicToolsets:      viper.GetBool("dynamic_toolsets"),
-				ReadOnly:             viper.GetBool("read-only"),
-				ExportTranslations:   viper.GetBool("export-translations"),
-				EnableCommandLogging: viper.GetBool("enable-command-logging"),
-				LogFilePath:          viper.GetString("log-file"),
-				ContentWindowSize:    viper.GetInt("content-window-size"),
-				LockdownMode:         viper.GetBool("lockdown-mode"),
+				Version:                     version,
+				Host:                        viper.GetString("host"),
+				Token:                       token,
+				EnabledToolsets:             enabledToolsets,
+				DynamicToolsets:             viper.GetBool("dynamic_toolsets"),
+				ReadOnly:                    viper.GetBool("read-only"),
+				ExportTranslations:          viper.GetBool("export-translations"),
+				EnableCommandLogging:        viper.GetBool("enable-command-logging"),
+				LogFilePath:                 viper.GetString("log-file"),
+				ContentWindowSize:           viper.GetInt("content-window-size"),
+				LockdownMode:                viper.GetBool("lockdown-mode"),
+				SanitizeExemptToolsets:      sanitizeExemptToolsets,
+				AllowedLinkHosts:            allowedLinkHosts,
+				RepositoryDeletionAllowlist: repositoryDeletionAllowlist,
+				AppendErrorDiagnostics:      viper.GetBool("append-error-diagnostics"),
+				LFSMaxSize:                  viper.GetInt64("lfs-max-size"),
+				MaxInlineSize:               viper.GetInt("max-inline-size"),
+				ArchiveCacheDir:             viper.GetString("archive-cache-dir"),
+				ArchiveCacheSize:            viper.GetInt64("archive-cache-size"),
 			}
 			return ghmcp.RunStdioServer(stdioServerConfig)
 		},
@@ -84,6 +109,14 @@ func init() {
 	rootCmd.PersistentFlags().String("gh-host", "", "Specify the GitHub hostname (for GitHub Enterprise etc.)")
 	rootCmd.PersistentFlags().Int("content-window-size", 5000, "Specify the content window size")
 	rootCmd.PersistentFlags().Bool("lockdown-mode", false, "Enable lockdown mode")
+	rootCmd.PersistentFlags().StringSlice("sanitize-exempt-toolsets", nil, "Comma-separated list of toolsets whose tool results are not sanitized")
+	rootCmd.PersistentFlags().StringSlice("allowed-link-hosts", nil, "Comma-separated list of additional hosts that markdown links and images in tool results may point to")
+	rootCmd.PersistentFlags().Int64("lfs-max-size", raw.DefaultLFSMaxSize, "Largest Git LFS object, in bytes, that is downloaded in place of its pointer file. Set to 0 to return LFS pointers as is")
+	rootCmd.PersistentFlags().Int("max-inline-size", github.DefaultMaxInlineSize, "Largest binary file, in bytes, whose content get_file_contents returns. Larger files are described by a summary instead")
+	rootCmd.PersistentFlags().String("archive-cache-dir", "", "Directory to cache repository archives in (default: a directory in the system temp dir)")
+	rootCmd.PersistentFlags().Int64("archive-cache-size", archive.DefaultCacheSize, "Size limit of the repository archive cache, in bytes. The least recently used archives are removed to stay under it")
+	rootCmd.PersistentFlags().StringSlice("repository-deletion-allowlist", nil, "Comma-separated list of repositories, as owner/repo or patterns such as owner/*, that delete_repository may delete. delete_repository is disabled when empty")
+	rootCmd.PersistentFlags().Bool("append-error-diagnostics", false, "Append a summary of the GitHub API errors hit during a tool call, with request IDs, to its result")
 
 	// Bind flag to viper
 	_ = viper.BindPFlag("toolsets", rootCmd.PersistentFlags().Lookup("toolsets"))
@@ -95,6 +128,14 @@ func init() {
 	_ = viper.BindPFlag("host", rootCmd.PersistentFlags().Lookup("gh-host"))
 	_ = viper.BindPFlag("content-window-size", rootCmd.PersistentFlags().Lookup("content-window-size"))
 	_ = viper.BindPFlag("lockdown-mode", rootCmd.PersistentFlags().Lookup("lockdown-mode"))
+	_ = viper.BindPFlag("sanitize-exempt-toolsets", rootCmd.PersistentFlags().Lookup("sanitize-exempt-toolsets"))
+	_ = viper.BindPFlag("allowed-link-hosts", rootCmd.PersistentFlags().Lookup("allowed-link-hosts"))
+	_ = viper.BindPFlag("lfs-max-size", rootCmd.PersistentFlags().Lookup("lfs-max-size"))
+	_ = viper.BindPFlag("max-inline-size", rootCmd.PersistentFlags().Lookup("max-inline-size"))
+	_ = viper.BindPFlag("archive-cache-dir", rootCmd.PersistentFlags().Lookup("archive-cache-dir"))
+	_ = viper.BindPFlag("archive-cache-size", rootCmd.PersistentFlags().Lookup("archive-cache-size"))
+	_ = viper.BindPFlag("repository-deletion-allowlist", rootCmd.PersistentFlags().Lookup("repository-deletion-allowlist"))
+	_ = viper.BindPFlag("append-error-diagnostics", rootCmd.PersistentFlags().Lookup("append-error-diagnostics"))
 
 	// Add subcommands
 	rootCmd.AddCommand(stdioCmd)
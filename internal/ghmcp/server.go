@@ -14,6 +14,7 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/github/github-mcp-server/pkg/archive"
 	"github.com/github/github-mcp-server/pkg/errors"
 	"github.com/github/github-mcp-server/pkg/github"
 	mcplog "github.com/github/github-mcp-server/pkg/log"
@@ -54,6 +55,38 @@ type MCPServerConfig struct {
 
 	// LockdownMode indicates if we should enable lockdown mode
 	LockdownMode bool
+
+	// RepositoryDeletionAllowlist is a list of owner/repo patterns of the repositories delete_repository may delete
+	RepositoryDeletionAllowlist []string
+
+	// SanitizeExemptToolsets is a list of toolsets whose tool results are not sanitized
+	SanitizeExemptToolsets []string
+
+	// AllowedLinkHosts is a list of additional hosts that markdown links and images in tool results may point to
+	AllowedLinkHosts []string
+
+	// AppendErrorDiagnostics indicates if a summary of the GitHub errors hit during a tool call
+	// should be appended to its result
+	AppendErrorDiagnostics bool
+
+	// LFSMaxSize is the size of the largest Git LFS object that is downloaded in place of its pointer, in bytes.
+	// 0 disables resolving LFS pointers.
+	LFSMaxSize int64
+
+	// MaxInlineSize is the size of the largest binary file whose content get_file_contents returns, in bytes
+	MaxInlineSize int
+
+	// ArchiveCacheDir is the directory repository archives are cached in. Defaults to a directory in the system temp dir.
+	ArchiveCacheDir string
+
+	// ArchiveCacheSize is the size limit of the repository archive cache, in bytes
+	ArchiveCacheSize int64
+
+	// Logger receives the GitHub errors hit during tool calls, if set
+	Logger *slog.Logger
+
+	// ErrorMetrics is fed with the GitHub errors hit during tool calls, if set
+	ErrorMetrics errors.Metrics
 }
 
 const stdioServerLogPrefix = "stdioserver"
@@ -64,6 +97,12 @@ func NewMCPServer(cfg MCPServerConfig) (*server.MCPServer, error) {
 		return nil, fmt.Errorf("failed to parse API host: %w", err)
 	}
 
+	// Links back to the GitHub instance we're targeting are always safe to render
+	allowedLinkHosts := append([]string{apiHost.rawURL.Hostname()}, cfg.AllowedLinkHosts...)
+	if u, err := url.Parse(cfg.Host); err == nil && u.Hostname() != "" {
+		allowedLinkHosts = append(allowedLinkHosts, u.Hostname())
+	}
+
 	// Construct our REST client
 	restClient := gogithub.NewClient(nil).WithAuthToken(cfg.Token)
 	restClient.UserAgent = fmt.Sprintf("github-mcp-server/%s", cfg.Version)
@@ -100,13 +139,6 @@ func NewMCPServer(cfg MCPServerConfig) (*server.MCPServer, error) {
 
 	hooks := &server.Hooks{
 		OnBeforeInitialize: []server.OnBeforeInitializeFunc{beforeInit},
-		OnBeforeAny: []server.BeforeAnyHookFunc{
-			func(ctx context.Context, _ any, _ mcp.MCPMethod, _ any) {
-				// Ensure the context is cleared of any previous errors
-				// as context isn't propagated through middleware
-				errors.ContextWithGitHubErrors(ctx)
-			},
-		},
 	}
 
 	enabledToolsets := cfg.EnabledToolsets
@@ -135,11 +167,6 @@ func NewMCPServer(cfg MCPServerConfig) (*server.MCPServer, error) {
 	// Generate instructions based on enabled toolsets
 	instructions := github.GenerateInstructions(enabledToolsets)
 
-	ghServer := github.NewServer(cfg.Version,
-		server.WithInstructions(instructions),
-		server.WithHooks(hooks),
-	)
-
 	getClient := func(_ context.Context) (*gogithub.Client, error) {
 		return restClient, nil // closing over client
 	}
@@ -153,7 +180,11 @@ func NewMCPServer(cfg MCPServerConfig) (*server.MCPServer, error) {
 		if err != nil {
 			return nil, fmt.Errorf("failed to get GitHub client: %w", err)
 		}
-		return raw.NewClient(client, apiHost.rawURL), nil // closing over client
+		rawClient := raw.NewClient(client, apiHost.rawURL).WithLFS(raw.LFSOptions{
+			ServerURL: apiHost.webURL,
+			MaxSize:   cfg.LFSMaxSize,
+		})
+		return rawClient, nil // closing over client
 	}
 
 	// Create default toolsets
@@ -164,7 +195,14 @@ func NewMCPServer(cfg MCPServerConfig) (*server.MCPServer, error) {
 		getRawClient,
 		cfg.Translator,
 		cfg.ContentWindowSize,
-		github.FeatureFlags{LockdownMode: cfg.LockdownMode},
+		github.ToolsetConfig{
+			MaxInlineSize:               cfg.MaxInlineSize,
+			ArchiveCache:                archive.NewCache(cfg.ArchiveCacheDir, cfg.ArchiveCacheSize),
+			RepositoryDeletionAllowlist: cfg.RepositoryDeletionAllowlist,
+		},
+		github.FeatureFlags{
+			LockdownMode: cfg.LockdownMode,
+		},
 	)
 	err = tsg.EnableToolsets(enabledToolsets, nil)
 
@@ -172,6 +210,22 @@ func NewMCPServer(cfg MCPServerConfig) (*server.MCPServer, error) {
 		return nil, fmt.Errorf("failed to enable toolsets: %w", err)
 	}
 
+	ghServer := github.NewServer(cfg.Version,
+		github.SanitizeConfig{
+			Toolsets:         tsg,
+			ExemptToolsets:   cfg.SanitizeExemptToolsets,
+			AllowedLinkHosts: allowedLinkHosts,
+		},
+		server.WithInstructions(instructions),
+		server.WithHooks(hooks),
+		// Each tool call collects the GitHub errors it runs into on its own context, then reports them
+		server.WithToolHandlerMiddleware(errors.NewErrorReportingMiddleware(errors.ReportOptions{
+			Logger:            cfg.Logger,
+			Metrics:           cfg.ErrorMetrics,
+			AppendDiagnostics: cfg.AppendErrorDiagnostics,
+		})),
+	)
+
 	// Register all mcp functionality with the server
 	tsg.RegisterAll(ghServer)
 
@@ -219,6 +273,32 @@ type StdioServerConfig struct {
 
 	// LockdownMode indicates if we should enable lockdown mode
 	LockdownMode bool
+
+	// RepositoryDeletionAllowlist is a list of owner/repo patterns of the repositories delete_repository may delete
+	RepositoryDeletionAllowlist []string
+
+	// SanitizeExemptToolsets is a list of toolsets whose tool results are not sanitized
+	SanitizeExemptToolsets []string
+
+	// AllowedLinkHosts is a list of additional hosts that markdown links and images in tool results may point to
+	AllowedLinkHosts []string
+
+	// AppendErrorDiagnostics indicates if a summary of the GitHub errors hit during a tool call
+	// should be appended to its result
+	AppendErrorDiagnostics bool
+
+	// LFSMaxSize is the size of the largest Git LFS object that is downloaded in place of its pointer, in bytes.
+	// 0 disables resolving LFS pointers.
+	LFSMaxSize int64
+
+	// MaxInlineSize is the size of the largest binary file whose content get_file_contents returns, in bytes
+	MaxInlineSize int
+
+	// ArchiveCacheDir is the directory repository archives are cached in. Defaults to a directory in the system temp dir.
+	ArchiveCacheDir string
+
+	// ArchiveCacheSize is the size limit of the repository archive cache, in bytes
+	ArchiveCacheSize int64
 }
 
 // RunStdioServer is not concurrent safe.
@@ -229,23 +309,6 @@ func RunStdioServer(cfg StdioServerConfig) error {
 
 	t, dumpTranslations := translations.TranslationHelper()
 
-	ghServer, err := NewMCPServer(MCPServerConfig{
-		Version:           cfg.Version,
-		Host:              cfg.Host,
-		Token:             cfg.Token,
-		EnabledToolsets:   cfg.EnabledToolsets,
-		DynamicToolsets:   cfg.DynamicToolsets,
-		ReadOnly:          cfg.ReadOnly,
-		Translator:        t,
-		ContentWindowSize: cfg.ContentWindowSize,
-		LockdownMode:      cfg.LockdownMode,
-	})
-	if err != nil {
-		return fmt.Errorf("failed to create MCP server: %w", err)
-	}
-
-	stdioServer := server.NewStdioServer(ghServer)
-
 	var slogHandler slog.Handler
 	var logOutput io.Writer
 	if cfg.LogFilePath != "" {
@@ -261,6 +324,34 @@ func RunStdioServer(cfg StdioServerConfig) error {
 	}
 	logger := slog.New(slogHandler)
 	logger.Info("starting server", "version", cfg.Version, "host", cfg.Host, "dynamicToolsets", cfg.DynamicToolsets, "readOnly", cfg.ReadOnly, "lockdownEnabled", cfg.LockdownMode)
+
+	errorCounter := errors.NewErrorCounter()
+	ghServer, err := NewMCPServer(MCPServerConfig{
+		Version:                     cfg.Version,
+		Host:                        cfg.Host,
+		Token:                       cfg.Token,
+		EnabledToolsets:             cfg.EnabledToolsets,
+		DynamicToolsets:             cfg.DynamicToolsets,
+		ReadOnly:                    cfg.ReadOnly,
+		Translator:                  t,
+		ContentWindowSize:           cfg.ContentWindowSize,
+		LockdownMode:                cfg.LockdownMode,
+		RepositoryDeletionAllowlist: cfg.RepositoryDeletionAllowlist,
+		SanitizeExemptToolsets:      cfg.SanitizeExemptToolsets,
+		AllowedLinkHosts:            cfg.AllowedLinkHosts,
+		AppendErrorDiagnostics:      cfg.AppendErrorDiagnostics,
+		LFSMaxSize:                  cfg.LFSMaxSize,
+		MaxInlineSize:               cfg.MaxInlineSize,
+		ArchiveCacheDir:             cfg.ArchiveCacheDir,
+		ArchiveCacheSize:            cfg.ArchiveCacheSize,
+		Logger:                      logger,
+		ErrorMetrics:                errorCounter,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create MCP server: %w", err)
+	}
+
+	stdioServer := server.NewStdioServer(ghServer)
 	stdLogger := log.New(logOutput, stdioServerLogPrefix, 0)
 	stdioServer.SetErrorLogger(stdLogger)
 
@@ -278,8 +369,6 @@ func RunStdioServer(cfg StdioServerConfig) error {
 			loggedIO := mcplog.NewIOLogger(in, out, logger)
 			in, out = loggedIO, loggedIO
 		}
-		// enable GitHub errors in the context
-		ctx := errors.ContextWithGitHubErrors(ctx)
 		errC <- stdioServer.Listen(ctx, in, out)
 	}()
 
@@ -289,7 +378,7 @@ func RunStdioServer(cfg StdioServerConfig) error {
 	// Wait for shutdown signal
 	select {
 	case <-ctx.Done():
-		logger.Info("shutting down server", "signal", "context done")
+		logger.Info("shutting down server", "signal", "context done", "githubErrors", errorCounter)
 	case err := <-errC:
 		if err != nil {
 			logger.Error("error running server", "error", err)
@@ -305,6 +394,7 @@ type apiHost struct {
 	graphqlURL  *url.URL
 	uploadURL   *url.URL
 	rawURL      *url.URL
+	webURL      *url.URL
 }
 
 func newDotcomHost() (apiHost, error) {
@@ -318,7 +408,7 @@ func newDotcomHost() (apiHost, error) {
 		return apiHost{}, fmt.Errorf("failed to parse dotcom GraphQL URL: %w", err)
 	}
 
-	uploadURL, err := url.Parse("https://uploads.github.com")
+	uploadURL, err := url.Parse("https://uploads.github.com/")
 	if err != nil {
 		return apiHost{}, fmt.Errorf("failed to parse dotcom Upload URL: %w", err)
 	}
@@ -328,11 +418,17 @@ func newDotcomHost() (apiHost, error) {
 		return apiHost{}, fmt.Errorf("failed to parse dotcom Raw URL: %w", err)
 	}
 
+	webURL, err := url.Parse("https://github.com/")
+	if err != nil {
+		return apiHost{}, fmt.Errorf("failed to parse dotcom Web URL: %w", err)
+	}
+
 	return apiHost{
 		baseRESTURL: baseRestURL,
 		graphqlURL:  gqlURL,
 		uploadURL:   uploadURL,
 		rawURL:      rawURL,
+		webURL:      webURL,
 	}, nil
 }
 
@@ -357,7 +453,7 @@ func newGHECHost(hostname string) (apiHost, error) {
 		return apiHost{}, fmt.Errorf("failed to parse GHEC GraphQL URL: %w", err)
 	}
 
-	uploadURL, err := url.Parse(fmt.Sprintf("https://uploads.%s", u.Hostname()))
+	uploadURL, err := url.Parse(fmt.Sprintf("https://uploads.%s/", u.Hostname()))
 	if err != nil {
 		return apiHost{}, fmt.Errorf("failed to parse GHEC Upload URL: %w", err)
 	}
@@ -367,11 +463,17 @@ func newGHECHost(hostname string) (apiHost, error) {
 		return apiHost{}, fmt.Errorf("failed to parse GHEC Raw URL: %w", err)
 	}
 
+	webURL, err := url.Parse(fmt.Sprintf("https://%s/", u.Hostname()))
+	if err != nil {
+		return apiHost{}, fmt.Errorf("failed to parse GHEC Web URL: %w", err)
+	}
+
 	return apiHost{
 		baseRESTURL: restURL,
 		graphqlURL:  gqlURL,
 		uploadURL:   uploadURL,
 		rawURL:      rawURL,
+		webURL:      webURL,
 	}, nil
 }
 
@@ -419,11 +521,17 @@ func newGHESHost(hostname string) (apiHost, error) {
 		return apiHost{}, fmt.Errorf("failed to parse GHES Raw URL: %w", err)
 	}
 
+	webURL, err := url.Parse(fmt.Sprintf("%s://%s/", u.Scheme, u.Hostname()))
+	if err != nil {
+		return apiHost{}, fmt.Errorf("failed to parse GHES Web URL: %w", err)
+	}
+
 	return apiHost{
 		baseRESTURL: restURL,
 		graphqlURL:  gqlURL,
 		uploadURL:   uploadURL,
 		rawURL:      rawURL,
+		webURL:      webURL,
 	}, nil
 }
 
@@ -2,6 +2,8 @@
 // because I do not want to take a dependency on the entire testify module just to use this equality check.
 //
 // There is a modification in objectsAreEqual to check that typed nils are equal, even if their types are different.
+// There is another modification in objectsAreEqualValues to compare slices element by element, as a list variable
+// such as []githubv4.ID is decoded from the request body as []any.
 //
 // The original license, copied from https://github.com/stretchr/testify/blob/016e2e9c269209287f33ec203f340a9a723fe22c/LICENSE
 //
@@ -44,6 +46,18 @@ func objectsAreEqualValues(expected, actual any) bool {
 		return false
 	}
 
+	if expectedValue.Kind() == reflect.Slice && actualValue.Kind() == reflect.Slice {
+		if expectedValue.Len() != actualValue.Len() {
+			return false
+		}
+		for i := 0; i < expectedValue.Len(); i++ {
+			if !objectsAreEqualValues(expectedValue.Index(i).Interface(), actualValue.Index(i).Interface()) {
+				return false
+			}
+		}
+		return true
+	}
+
 	expectedType := expectedValue.Type()
 	actualType := actualValue.Type()
 	if !expectedType.ConvertibleTo(actualType) {
@@ -1,6 +1,7 @@
 // The contents of this file are taken from https://github.com/stretchr/testify/blob/016e2e9c269209287f33ec203f340a9a723fe22c/assert/assertions_test.go#L140-L174
 //
-// There is a modification to test objectsAreEqualValues to check that typed nils are equal, even if their types are different.
+// There is a modification to test objectsAreEqualValues to check that typed nils are equal, even if their types are different,
+// and that slices are compared element by element.
 
 // The original license, copied from https://github.com/stretchr/testify/blob/016e2e9c269209287f33ec203f340a9a723fe22c/LICENSE
 //
@@ -57,8 +58,11 @@ func TestObjectsAreEqualValues(t *testing.T) {
 		{3.14, complex128(1e+100 + 1e+100i), false},
 		{complex128(1e+10 + 1e+10i), complex64(1e+10 + 1e+10i), true},
 		{complex64(1e+10 + 1e+10i), complex128(1e+10 + 1e+10i), true},
-		{(*string)(nil), nil, true},         // typed nil vs untyped nil
-		{(*string)(nil), (*int)(nil), true}, // different typed nils
+		{(*string)(nil), nil, true},                           // typed nil vs untyped nil
+		{(*string)(nil), (*int)(nil), true},                   // different typed nils
+		{[]uint32{10, 20}, []any{int32(10), int32(20)}, true}, // slices element by element
+		{[]uint32{10, 20}, []any{int32(10)}, false},
+		{[]string{"a"}, []any{"b"}, false},
 	}
 
 	for _, c := range cases {
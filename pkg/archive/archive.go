@@ -0,0 +1,213 @@
+// Package archive reads repository archives, the tarballs and zipballs GitHub serves for a commit,
+// and caches them on disk so that their files can be listed, read and searched without
+// downloading them again.
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"strings"
+)
+
+// Format is the format of a repository archive.
+type Format string
+
+const (
+	Tarball Format = "tarball"
+	Zipball Format = "zipball"
+)
+
+// ext returns the file extension of archives in the format.
+func (f Format) ext() string {
+	if f == Zipball {
+		return ".zip"
+	}
+	return ".tar.gz"
+}
+
+// ParseFormat parses the name of an archive format, defaulting to Tarball.
+func ParseFormat(s string) (Format, error) {
+	switch Format(s) {
+	case "", Tarball:
+		return Tarball, nil
+	case Zipball:
+		return Zipball, nil
+	default:
+		return "", fmt.Errorf("unknown archive format %q, expected %q or %q", s, Tarball, Zipball)
+	}
+}
+
+// Entry is a file, directory or symbolic link in an archive.
+type Entry struct {
+	// Path is relative to the root of the repository
+	Path string `json:"path"`
+	// Type is "file", "dir" or "symlink"
+	Type string `json:"type"`
+	Size int64  `json:"size"`
+	// Target is the target of a symbolic link
+	Target string `json:"target,omitempty"`
+}
+
+// Archive is a repository archive stored on disk.
+type Archive struct {
+	// Path is the location of the archive on disk
+	Path   string
+	Format Format
+	// Size is the size of the archive on disk, in bytes
+	Size int64
+
+	// cache is the cache the archive was returned by, which keeps it until it is closed
+	cache  *Cache
+	closed bool
+}
+
+// Close lets the cache evict the archive again. The archive must not be read once it is closed.
+func (a *Archive) Close() error {
+	if a.cache != nil && !a.closed {
+		a.closed = true
+		a.cache.release(a.Path)
+	}
+	return nil
+}
+
+// Walk calls fn for every entry of the archive, in archive order. r reads the content of files.
+// Walk stops at the first error returned by fn, and returns it unless it is fs.SkipAll.
+func (a *Archive) Walk(fn func(e Entry, r io.Reader) error) error {
+	var err error
+	if a.Format == Zipball {
+		err = a.walkZip(fn)
+	} else {
+		err = a.walkTar(fn)
+	}
+	if errors.Is(err, fs.SkipAll) {
+		return nil
+	}
+	return err
+}
+
+// ReadEntry calls fn with the entry at path and a reader for its content.
+// It returns an error wrapping fs.ErrNotExist if the archive has no such entry.
+func (a *Archive) ReadEntry(path string, fn func(e Entry, r io.Reader) error) error {
+	path = strings.Trim(path, "/")
+	found := false
+	err := a.Walk(func(e Entry, r io.Reader) error {
+		if e.Path != path {
+			return nil
+		}
+		found = true
+		if err := fn(e, r); err != nil {
+			return err
+		}
+		return fs.SkipAll
+	})
+	if err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("%s: %w", path, fs.ErrNotExist)
+	}
+	return nil
+}
+
+func (a *Archive) walkTar(fn func(e Entry, r io.Reader) error) error {
+	f, err := os.Open(a.Path)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		return fmt.Errorf("failed to read archive: %w", err)
+	}
+	defer func() { _ = zr.Close() }()
+
+	tr := tar.NewReader(zr)
+	for {
+		h, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read archive: %w", err)
+		}
+
+		e := Entry{Path: stripRoot(h.Name), Size: h.Size}
+		switch h.Typeflag {
+		case tar.TypeReg:
+			e.Type = "file"
+		case tar.TypeDir:
+			e.Type, e.Size = "dir", 0
+		case tar.TypeSymlink:
+			e.Type, e.Target = "symlink", h.Linkname
+		default:
+			// GitHub archives also hold a pax global header with the commit SHA
+			continue
+		}
+		if e.Path == "" {
+			continue
+		}
+		if err := fn(e, tr); err != nil {
+			return err
+		}
+	}
+}
+
+func (a *Archive) walkZip(fn func(e Entry, r io.Reader) error) error {
+	zr, err := zip.OpenReader(a.Path)
+	if err != nil {
+		return fmt.Errorf("failed to read archive: %w", err)
+	}
+	defer func() { _ = zr.Close() }()
+
+	for _, f := range zr.File {
+		e := Entry{Path: stripRoot(f.Name), Size: int64(f.UncompressedSize64)}
+		if e.Path == "" {
+			continue
+		}
+		mode := f.Mode()
+		switch {
+		case mode.IsDir():
+			e.Type, e.Size = "dir", 0
+		case mode&fs.ModeSymlink != 0:
+			e.Type, e.Size = "symlink", 0
+		default:
+			e.Type = "file"
+		}
+
+		rc, err := f.Open()
+		if err != nil {
+			return fmt.Errorf("failed to read archive: %w", err)
+		}
+		if e.Type == "symlink" {
+			// Zip archives store the target of a symbolic link as its content
+			target, err := io.ReadAll(io.LimitReader(rc, 4096))
+			if err != nil {
+				_ = rc.Close()
+				return fmt.Errorf("failed to read archive: %w", err)
+			}
+			e.Target = string(target)
+		}
+		err = fn(e, rc)
+		_ = rc.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// stripRoot removes the directory named after the repository and commit that GitHub
+// puts all files of an archive in, and the trailing slash of directories.
+func stripRoot(name string) string {
+	name = strings.TrimSuffix(name, "/")
+	if i := strings.IndexByte(name, '/'); i >= 0 {
+		return name[i+1:]
+	}
+	return ""
+}
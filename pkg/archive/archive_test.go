@@ -0,0 +1,159 @@
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testFile is a file in a test archive. Files with a target are symbolic links.
+type testFile struct {
+	name, content, target string
+}
+
+var testFiles = []testFile{
+	{name: "README.md", content: "# Hello\n"},
+	{name: "src/"},
+	{name: "src/main.go", content: "package main\n"},
+	{name: "link", target: "README.md"},
+}
+
+// testTarball builds a tarball laid out like the ones GitHub serves.
+func testTarball(t *testing.T, files []testFile) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gw)
+	require.NoError(t, tw.WriteHeader(&tar.Header{Typeflag: tar.TypeXGlobalHeader, Name: "pax_global_header", PAXRecords: map[string]string{"comment": "abc123"}}))
+	require.NoError(t, tw.WriteHeader(&tar.Header{Typeflag: tar.TypeDir, Name: "octocat-hello-abc123/", Mode: 0o755}))
+	for _, f := range files {
+		h := &tar.Header{Name: "octocat-hello-abc123/" + f.name, Mode: 0o644}
+		switch {
+		case f.target != "":
+			h.Typeflag, h.Linkname = tar.TypeSymlink, f.target
+		case f.name[len(f.name)-1] == '/':
+			h.Typeflag, h.Mode = tar.TypeDir, 0o755
+		default:
+			h.Typeflag, h.Size = tar.TypeReg, int64(len(f.content))
+		}
+		require.NoError(t, tw.WriteHeader(h))
+		_, err := tw.Write([]byte(f.content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gw.Close())
+	return buf.Bytes()
+}
+
+// testZipball builds a zipball laid out like the ones GitHub serves.
+func testZipball(t *testing.T, files []testFile) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	_, err := zw.Create("octocat-hello-abc123/")
+	require.NoError(t, err)
+	for _, f := range files {
+		h := &zip.FileHeader{Name: "octocat-hello-abc123/" + f.name}
+		content := f.content
+		switch {
+		case f.target != "":
+			h.SetMode(fs.ModeSymlink | 0o777)
+			content = f.target
+		case f.name[len(f.name)-1] == '/':
+			h.SetMode(fs.ModeDir | 0o755)
+		default:
+			h.SetMode(0o644)
+		}
+		w, err := zw.CreateHeader(h)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+func writeArchive(t *testing.T, format Format, data []byte) *Archive {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "archive"+format.ext())
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+	return &Archive{Path: path, Format: format, Size: int64(len(data))}
+}
+
+func TestArchiveWalk(t *testing.T) {
+	expected := []Entry{
+		{Path: "README.md", Type: "file", Size: 8},
+		{Path: "src", Type: "dir"},
+		{Path: "src/main.go", Type: "file", Size: 13},
+		{Path: "link", Type: "symlink", Target: "README.md"},
+	}
+
+	for _, format := range []Format{Tarball, Zipball} {
+		t.Run(string(format), func(t *testing.T) {
+			var data []byte
+			if format == Zipball {
+				data = testZipball(t, testFiles)
+			} else {
+				data = testTarball(t, testFiles)
+			}
+			a := writeArchive(t, format, data)
+
+			var entries []Entry
+			contents := map[string]string{}
+			require.NoError(t, a.Walk(func(e Entry, r io.Reader) error {
+				entries = append(entries, e)
+				if e.Type == "file" {
+					content, err := io.ReadAll(r)
+					require.NoError(t, err)
+					contents[e.Path] = string(content)
+				}
+				return nil
+			}))
+			assert.Equal(t, expected, entries)
+			assert.Equal(t, map[string]string{"README.md": "# Hello\n", "src/main.go": "package main\n"}, contents)
+
+			t.Run("read entry", func(t *testing.T) {
+				var content []byte
+				require.NoError(t, a.ReadEntry("/src/main.go", func(_ Entry, r io.Reader) error {
+					var err error
+					content, err = io.ReadAll(r)
+					return err
+				}))
+				assert.Equal(t, "package main\n", string(content))
+			})
+
+			t.Run("missing entry", func(t *testing.T) {
+				err := a.ReadEntry("nope.txt", func(Entry, io.Reader) error { return nil })
+				assert.ErrorIs(t, err, fs.ErrNotExist)
+			})
+
+			t.Run("stop walking", func(t *testing.T) {
+				n := 0
+				require.NoError(t, a.Walk(func(Entry, io.Reader) error {
+					n++
+					return fs.SkipAll
+				}))
+				assert.Equal(t, 1, n)
+			})
+		})
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	for input, expected := range map[string]Format{"": Tarball, "tarball": Tarball, "zipball": Zipball} {
+		format, err := ParseFormat(input)
+		require.NoError(t, err)
+		assert.Equal(t, expected, format)
+	}
+	_, err := ParseFormat("rar")
+	assert.Error(t, err)
+}
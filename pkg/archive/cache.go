@@ -0,0 +1,225 @@
+package archive
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultCacheSize is the default size limit of the archive cache, in bytes.
+const DefaultCacheSize = 1024 * 1024 * 1024
+
+// ErrTooLarge is returned when an archive doesn't fit in the cache.
+var ErrTooLarge = errors.New("repository archive is larger than the cache size limit")
+
+// validName matches the owner and repository names that are safe to use as path components.
+var validName = regexp.MustCompile(`^[A-Za-z0-9._-]+$`)
+
+// fullSHA matches complete SHA-1 and SHA-256 commit IDs.
+var fullSHA = regexp.MustCompile(`^([0-9a-f]{40}|[0-9a-f]{64})$`)
+
+// DownloadFunc writes the archive of a commit in the given format to w.
+type DownloadFunc func(ctx context.Context, format Format, w io.Writer) error
+
+// Cache stores repository archives on disk, keyed by repository and commit. Since the
+// content of a commit never changes, cached archives are only evicted to stay under the size limit,
+// least recently used first. Archives returned by Get are not evicted until they are closed.
+type Cache struct {
+	dir     string
+	maxSize int64
+
+	mu    sync.Mutex
+	locks map[string]*sync.Mutex
+	// inUse counts the archives returned by Get that were not closed yet, by path
+	inUse map[string]int
+}
+
+// DefaultCacheDir returns the directory the archive cache uses by default.
+func DefaultCacheDir() string {
+	return filepath.Join(os.TempDir(), "github-mcp-server", "archives")
+}
+
+// NewCache returns a cache that stores up to maxSize bytes of archives in dir.
+// The directory is created when the first archive is stored.
+func NewCache(dir string, maxSize int64) *Cache {
+	if dir == "" {
+		dir = DefaultCacheDir()
+	}
+	if maxSize <= 0 {
+		maxSize = DefaultCacheSize
+	}
+	return &Cache{dir: dir, maxSize: maxSize, locks: make(map[string]*sync.Mutex), inUse: make(map[string]int)}
+}
+
+// Get returns the archive of a commit, calling download to store it if it isn't cached yet.
+// An archive cached in another format is returned rather than downloading the commit again.
+// cached reports whether the archive was already in the cache. The caller must close the archive
+// once it is done reading it.
+//
+// The cache doesn't check whether the caller can read the repository: sha must be the full commit
+// SHA, as resolved through the API with the caller's credentials before every call.
+func (c *Cache) Get(ctx context.Context, owner, repo, sha string, format Format, download DownloadFunc) (a *Archive, cached bool, err error) {
+	for _, name := range []string{owner, repo} {
+		if !validName.MatchString(name) || name == "." || name == ".." {
+			return nil, false, fmt.Errorf("invalid repository name %q", name)
+		}
+	}
+	if !fullSHA.MatchString(sha) {
+		return nil, false, fmt.Errorf("invalid commit SHA %q, archives are cached by full commit SHA", sha)
+	}
+	base := filepath.Join(c.dir, owner, repo, sha)
+
+	lock := c.lock(base)
+	lock.Lock()
+	defer lock.Unlock()
+
+	for _, f := range []Format{format, otherFormat(format)} {
+		// The archive is marked in use before it is looked up, so that it can't be evicted in between
+		path := base + f.ext()
+		c.acquire(path)
+		info, err := os.Stat(path)
+		if err != nil {
+			c.release(path)
+			continue
+		}
+		// Bump the modification time, which eviction uses to find the least recently used archives
+		now := time.Now()
+		_ = os.Chtimes(path, now, now)
+		return &Archive{Path: path, Format: f, Size: info.Size(), cache: c}, true, nil
+	}
+
+	a, err = c.store(ctx, base, format, download)
+	if err != nil {
+		return nil, false, err
+	}
+	c.acquire(a.Path)
+	a.cache = c
+	c.evict()
+	return a, false, nil
+}
+
+// acquire marks the archive at path as in use.
+func (c *Cache) acquire(path string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.inUse[path]++
+}
+
+// release undoes acquire.
+func (c *Cache) release(path string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.inUse[path]--; c.inUse[path] <= 0 {
+		delete(c.inUse, path)
+	}
+}
+
+// lock returns the lock that serializes downloads of the archive at base.
+func (c *Cache) lock(base string) *sync.Mutex {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	lock, ok := c.locks[base]
+	if !ok {
+		lock = &sync.Mutex{}
+		c.locks[base] = lock
+	}
+	return lock
+}
+
+// store downloads an archive to a temporary file and moves it into place once it is complete.
+func (c *Cache) store(ctx context.Context, base string, format Format, download DownloadFunc) (*Archive, error) {
+	if err := os.MkdirAll(filepath.Dir(base), 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create archive cache directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(base), filepath.Base(base)+".*.tmp")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create archive file: %w", err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	w := &limitedWriter{w: tmp, remaining: c.maxSize}
+	err = download(ctx, format, w)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	path := base + format.ext()
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return nil, fmt.Errorf("failed to store archive: %w", err)
+	}
+	return &Archive{Path: path, Format: format, Size: c.maxSize - w.remaining}, nil
+}
+
+type cachedFile struct {
+	path    string
+	size    int64
+	modTime time.Time
+}
+
+// evict removes the least recently used archives until the cache fits its size limit.
+// Archives that are in use are never removed.
+func (c *Cache) evict() {
+	var files []cachedFile
+	var total int64
+	_ = filepath.WalkDir(c.dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !(strings.HasSuffix(path, Tarball.ext()) || strings.HasSuffix(path, Zipball.ext())) {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		files = append(files, cachedFile{path: path, size: info.Size(), modTime: info.ModTime()})
+		total += info.Size()
+		return nil
+	})
+
+	sort.Slice(files, func(i, j int) bool { return files[i].modTime.Before(files[j].modTime) })
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, f := range files {
+		if total <= c.maxSize {
+			return
+		}
+		if c.inUse[f.path] > 0 {
+			continue
+		}
+		if err := os.Remove(f.path); err == nil {
+			total -= f.size
+		}
+	}
+}
+
+func otherFormat(f Format) Format {
+	if f == Zipball {
+		return Tarball
+	}
+	return Zipball
+}
+
+// limitedWriter fails writes once more than remaining bytes have been written.
+type limitedWriter struct {
+	w         io.Writer
+	remaining int64
+}
+
+func (l *limitedWriter) Write(p []byte) (int, error) {
+	if int64(len(p)) > l.remaining {
+		return 0, ErrTooLarge
+	}
+	n, err := l.w.Write(p)
+	l.remaining -= int64(n)
+	return n, err
+}
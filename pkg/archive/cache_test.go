@@ -0,0 +1,126 @@
+package archive
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testSHA1 = "0123456789abcdef0123456789abcdef01234567"
+	testSHA2 = "89abcdef0123456789abcdef0123456789abcdef"
+	testSHA3 = "fedcba9876543210fedcba9876543210fedcba98"
+)
+
+func TestCacheGet(t *testing.T) {
+	ctx := context.Background()
+	data := testTarball(t, testFiles)
+	downloads := 0
+	download := func(_ context.Context, format Format, w io.Writer) error {
+		downloads++
+		assert.Equal(t, Tarball, format)
+		_, err := io.Copy(w, bytes.NewReader(data))
+		return err
+	}
+
+	cache := NewCache(t.TempDir(), 0)
+	a, cached, err := cache.Get(ctx, "octocat", "hello", testSHA1, Tarball, download)
+	require.NoError(t, err)
+	assert.False(t, cached)
+	assert.Equal(t, int64(len(data)), a.Size)
+	assert.Equal(t, Tarball, a.Format)
+
+	// The archive is served from the cache, even when asked for in another format
+	a2, cached, err := cache.Get(ctx, "octocat", "hello", testSHA1, Zipball, download)
+	require.NoError(t, err)
+	assert.True(t, cached)
+	assert.Equal(t, a, a2)
+	assert.Equal(t, 1, downloads)
+
+	t.Run("invalid names", func(t *testing.T) {
+		for _, name := range []string{"..", "a/b", ""} {
+			_, _, err := cache.Get(ctx, name, "hello", testSHA1, Tarball, download)
+			assert.Error(t, err)
+		}
+		assert.Equal(t, 1, downloads)
+	})
+
+	t.Run("abbreviated or invalid SHAs", func(t *testing.T) {
+		for _, sha := range []string{"abc123", "HEAD", "../" + testSHA1[3:], strings.ToUpper(testSHA1)} {
+			_, _, err := cache.Get(ctx, "octocat", "hello", sha, Tarball, download)
+			assert.ErrorContains(t, err, "invalid commit SHA")
+		}
+		assert.Equal(t, 1, downloads)
+	})
+
+	t.Run("failed download is not cached", func(t *testing.T) {
+		_, _, err := cache.Get(ctx, "octocat", "hello", testSHA2, Tarball, func(context.Context, Format, io.Writer) error {
+			return errors.New("boom")
+		})
+		require.ErrorContains(t, err, "boom")
+		_, cached, err := cache.Get(ctx, "octocat", "hello", testSHA2, Tarball, download)
+		require.NoError(t, err)
+		assert.False(t, cached)
+	})
+}
+
+func TestCacheSizeLimit(t *testing.T) {
+	ctx := context.Background()
+	data := bytes.Repeat([]byte("x"), 100)
+	download := func(_ context.Context, _ Format, w io.Writer) error {
+		_, err := w.Write(data)
+		return err
+	}
+
+	cache := NewCache(t.TempDir(), 250)
+	first, _, err := cache.Get(ctx, "octocat", "hello", testSHA1, Tarball, download)
+	require.NoError(t, err)
+	require.NoError(t, first.Close())
+	second, _, err := cache.Get(ctx, "octocat", "hello", testSHA2, Tarball, download)
+	require.NoError(t, err)
+	require.NoError(t, second.Close())
+
+	// Use the first archive again, so the second is the least recently used
+	past := time.Now().Add(-time.Hour)
+	require.NoError(t, os.Chtimes(second.Path, past, past))
+	again, cached, err := cache.Get(ctx, "octocat", "hello", testSHA1, Tarball, download)
+	require.NoError(t, err)
+	assert.True(t, cached)
+	require.NoError(t, again.Close())
+
+	third, _, err := cache.Get(ctx, "octocat", "hello", testSHA3, Tarball, download)
+	require.NoError(t, err)
+	assert.FileExists(t, first.Path)
+	assert.NoFileExists(t, second.Path)
+	assert.FileExists(t, third.Path)
+
+	t.Run("archives in use are not evicted", func(t *testing.T) {
+		cache := NewCache(t.TempDir(), 150)
+		first, _, err := cache.Get(ctx, "octocat", "hello", testSHA1, Tarball, download)
+		require.NoError(t, err)
+		second, _, err := cache.Get(ctx, "octocat", "hello", testSHA2, Tarball, download)
+		require.NoError(t, err)
+		assert.FileExists(t, first.Path)
+
+		// Once closed, the first archive is evicted by the next download
+		require.NoError(t, first.Close())
+		require.NoError(t, second.Close())
+		_, _, err = cache.Get(ctx, "octocat", "hello", testSHA3, Tarball, download)
+		require.NoError(t, err)
+		assert.NoFileExists(t, first.Path)
+	})
+
+	t.Run("archive larger than the cache", func(t *testing.T) {
+		small := NewCache(t.TempDir(), 50)
+		_, _, err := small.Get(ctx, "octocat", "hello", testSHA1, Tarball, download)
+		assert.ErrorIs(t, err, ErrTooLarge)
+	})
+}
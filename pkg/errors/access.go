@@ -0,0 +1,143 @@
+package errors
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// AccessDiagnostics explains why a token could not access a resource, based on the headers
+// GitHub returns with every REST API response.
+type AccessDiagnostics struct {
+	// SSORequired is set when the organization enforces SAML SSO and the token is not authorized for it
+	SSORequired bool `json:"sso_required,omitempty"`
+	// SSOAuthorizationURL is the URL where the token can be authorized for the organization
+	SSOAuthorizationURL string `json:"sso_authorization_url,omitempty"`
+	// AcceptedPermissions are the alternative sets of fine-grained permissions the endpoint accepts.
+	// A token needs all the permissions of one of the sets, e.g. [["issues=write"], ["pull_requests=write", "contents=read"]]
+	AcceptedPermissions [][]string `json:"accepted_permissions,omitempty"`
+	// TokenScopes are the OAuth scopes granted to a classic token
+	TokenScopes []string `json:"token_scopes,omitempty"`
+	// AcceptedScopes are the OAuth scopes the endpoint accepts
+	AcceptedScopes []string `json:"accepted_scopes,omitempty"`
+	// MissingScopes is set when the token holds none of the accepted scopes
+	MissingScopes []string `json:"missing_scopes,omitempty"`
+}
+
+var ssoURLPattern = regexp.MustCompile(`url=(\S+)`)
+
+// DiagnoseAccess inspects the SSO, permission and scope headers of a response.
+// It returns nil when the response carries nothing worth reporting.
+func DiagnoseAccess(resp *http.Response) *AccessDiagnostics {
+	if resp == nil {
+		return nil
+	}
+
+	d := &AccessDiagnostics{}
+
+	// e.g. "required; url=https://github.com/orgs/octo-org/sso?authorization_request=..."
+	if sso := resp.Header.Get("X-GitHub-SSO"); strings.HasPrefix(sso, "required") {
+		d.SSORequired = true
+		if m := ssoURLPattern.FindStringSubmatch(sso); m != nil {
+			d.SSOAuthorizationURL = strings.TrimSuffix(m[1], ";")
+		}
+	}
+
+	d.AcceptedPermissions = parsePermissionSets(resp.Header.Get("X-Accepted-GitHub-Permissions"))
+
+	if _, ok := resp.Header["X-Oauth-Scopes"]; ok {
+		d.TokenScopes = splitHeaderList(resp.Header.Get("X-OAuth-Scopes"))
+		d.AcceptedScopes = splitHeaderList(resp.Header.Get("X-Accepted-OAuth-Scopes"))
+		if len(d.AcceptedScopes) > 0 && !hasAnyScope(d.TokenScopes, d.AcceptedScopes) {
+			d.MissingScopes = d.AcceptedScopes
+		}
+	}
+
+	if !d.SSORequired && len(d.AcceptedPermissions) == 0 && len(d.TokenScopes) == 0 && len(d.AcceptedScopes) == 0 {
+		return nil
+	}
+	return d
+}
+
+// Remediation describes what needs to change for the token to get access, or returns an
+// empty string if the diagnostics don't point to a specific fix.
+func (d *AccessDiagnostics) Remediation() string {
+	if d == nil {
+		return ""
+	}
+	switch {
+	case d.SSORequired && d.SSOAuthorizationURL != "":
+		return "The organization enforces SAML single sign-on. Authorize the token at " + d.SSOAuthorizationURL + " and retry."
+	case d.SSORequired:
+		return "The organization enforces SAML single sign-on. Authorize the token for the organization and retry."
+	case len(d.MissingScopes) > 0:
+		return "The token is missing a required OAuth scope. Grant one of: " + strings.Join(d.MissingScopes, ", ") + "."
+	case len(d.AcceptedPermissions) == 1:
+		return "If this is a fine-grained token, it needs these permissions on the resource: " + strings.Join(d.AcceptedPermissions[0], ", ") + "."
+	case len(d.AcceptedPermissions) > 1:
+		sets := make([]string, len(d.AcceptedPermissions))
+		for i, set := range d.AcceptedPermissions {
+			sets[i] = "(" + strings.Join(set, ", ") + ")"
+		}
+		return "If this is a fine-grained token, it needs all the permissions of one of these sets on the resource: " + strings.Join(sets, " or ") + "."
+	default:
+		return ""
+	}
+}
+
+// parsePermissionSets parses the X-Accepted-GitHub-Permissions header, a ";"-separated list of
+// alternatives, each a ","-separated set of permissions that are all required,
+// e.g. "issues=write; pull_requests=write, contents=read".
+func parsePermissionSets(v string) [][]string {
+	var sets [][]string
+	for _, alternative := range strings.Split(v, ";") {
+		if set := splitHeaderList(alternative); len(set) > 0 {
+			sets = append(sets, set)
+		}
+	}
+	return sets
+}
+
+func splitHeaderList(v string) []string {
+	var out []string
+	for _, part := range strings.Split(v, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
+
+// impliedScopes lists the classic token scopes that are granted by a broader scope.
+var impliedScopes = map[string][]string{
+	"repo":             {"repo:status", "repo_deployment", "public_repo", "repo:invite", "security_events"},
+	"admin:org":        {"write:org", "read:org", "manage_runners:org"},
+	"write:org":        {"read:org"},
+	"admin:public_key": {"write:public_key", "read:public_key"},
+	"write:public_key": {"read:public_key"},
+	"admin:repo_hook":  {"write:repo_hook", "read:repo_hook"},
+	"write:repo_hook":  {"read:repo_hook"},
+	"admin:gpg_key":    {"write:gpg_key", "read:gpg_key"},
+	"write:gpg_key":    {"read:gpg_key"},
+	"user":             {"read:user", "user:email", "user:follow"},
+	"project":          {"read:project"},
+	"write:packages":   {"read:packages"},
+	"write:discussion": {"read:discussion"},
+	"admin:enterprise": {"manage_runners:enterprise", "manage_billing:enterprise", "read:enterprise"},
+}
+
+func hasAnyScope(granted, accepted []string) bool {
+	have := make(map[string]bool)
+	for _, s := range granted {
+		have[s] = true
+		for _, implied := range impliedScopes[s] {
+			have[implied] = true
+		}
+	}
+	for _, s := range accepted {
+		if have[s] {
+			return true
+		}
+	}
+	return false
+}
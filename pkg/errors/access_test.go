@@ -0,0 +1,112 @@
+package errors
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/google/go-github/v77/github"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiagnoseAccess(t *testing.T) {
+	tests := []struct {
+		name                string
+		headers             map[string]string
+		expected            *AccessDiagnostics
+		expectedRemediation string
+	}{
+		{
+			name:     "no access headers",
+			headers:  map[string]string{"X-GitHub-Request-Id": "ABCD"},
+			expected: nil,
+		},
+		{
+			name:    "sso required with authorization url",
+			headers: map[string]string{"X-GitHub-SSO": "required; url=https://github.com/orgs/octo-org/sso?authorization_request=abc"},
+			expected: &AccessDiagnostics{
+				SSORequired:         true,
+				SSOAuthorizationURL: "https://github.com/orgs/octo-org/sso?authorization_request=abc",
+			},
+			expectedRemediation: "Authorize the token at https://github.com/orgs/octo-org/sso?authorization_request=abc",
+		},
+		{
+			name:     "partial sso results are not a failure",
+			headers:  map[string]string{"X-GitHub-SSO": "partial-results; organizations=21955855"},
+			expected: nil,
+		},
+		{
+			name: "missing oauth scope",
+			headers: map[string]string{
+				"X-OAuth-Scopes":          "read:org, gist",
+				"X-Accepted-OAuth-Scopes": "repo",
+			},
+			expected: &AccessDiagnostics{
+				TokenScopes:    []string{"read:org", "gist"},
+				AcceptedScopes: []string{"repo"},
+				MissingScopes:  []string{"repo"},
+			},
+			expectedRemediation: "Grant one of: repo.",
+		},
+		{
+			name: "scope implied by broader scope",
+			headers: map[string]string{
+				"X-OAuth-Scopes":          "repo",
+				"X-Accepted-OAuth-Scopes": "public_repo",
+			},
+			expected: &AccessDiagnostics{
+				TokenScopes:    []string{"repo"},
+				AcceptedScopes: []string{"public_repo"},
+			},
+		},
+		{
+			name:    "fine-grained permissions",
+			headers: map[string]string{"X-Accepted-GitHub-Permissions": "issues=write; pull_requests=write"},
+			expected: &AccessDiagnostics{
+				AcceptedPermissions: [][]string{{"issues=write"}, {"pull_requests=write"}},
+			},
+			expectedRemediation: "one of these sets on the resource: (issues=write) or (pull_requests=write)",
+		},
+		{
+			name:    "fine-grained permissions required together",
+			headers: map[string]string{"X-Accepted-GitHub-Permissions": "contents=read,pull_requests=write"},
+			expected: &AccessDiagnostics{
+				AcceptedPermissions: [][]string{{"contents=read", "pull_requests=write"}},
+			},
+			expectedRemediation: "it needs these permissions on the resource: contents=read, pull_requests=write.",
+		},
+		{
+			name:    "alternative sets of fine-grained permissions",
+			headers: map[string]string{"X-Accepted-GitHub-Permissions": "issues=write; pull_requests=write, contents=read"},
+			expected: &AccessDiagnostics{
+				AcceptedPermissions: [][]string{{"issues=write"}, {"pull_requests=write", "contents=read"}},
+			},
+			expectedRemediation: "(issues=write) or (pull_requests=write, contents=read)",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			d := DiagnoseAccess(newResponse(http.StatusForbidden, tc.headers))
+			assert.Equal(t, tc.expected, d)
+			if tc.expectedRemediation == "" {
+				assert.Empty(t, d.Remediation())
+			} else {
+				assert.Contains(t, d.Remediation(), tc.expectedRemediation)
+			}
+		})
+	}
+}
+
+func TestGitHubAPIErrorDetailsIncludeAccess(t *testing.T) {
+	resp := newResponse(http.StatusNotFound, map[string]string{"X-Accepted-GitHub-Permissions": "contents=read"})
+	details := newGitHubAPIError("failed to get file", &github.Response{Response: resp}, &github.ErrorResponse{Response: resp, Message: "Not Found"}).Details()
+
+	assert.Equal(t, CategoryNotFound, details.Category)
+	assert.Equal(t, &AccessDiagnostics{AcceptedPermissions: [][]string{{"contents=read"}}}, details.Access)
+	assert.Contains(t, details.Remediation, "contents=read")
+
+	// Validation errors aren't caused by missing access, so no diagnostics are attached
+	resp = newResponse(http.StatusUnprocessableEntity, map[string]string{"X-Accepted-GitHub-Permissions": "contents=read"})
+	details = newGitHubAPIError("failed to create file", &github.Response{Response: resp}, &github.ErrorResponse{Response: resp, Message: "Validation Failed"}).Details()
+	assert.Nil(t, details.Access)
+}
@@ -0,0 +1,231 @@
+package errors
+
+import (
+	"errors"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v77/github"
+)
+
+// ErrorCategory classifies a failed GitHub API call so that agents can decide how to react to it.
+type ErrorCategory string
+
+const (
+	CategoryNotFound             ErrorCategory = "not_found"
+	CategoryPermissionDenied     ErrorCategory = "permission_denied"
+	CategoryAuthenticationFailed ErrorCategory = "authentication_failed"
+	CategorySSORequired          ErrorCategory = "sso_required"
+	CategoryRateLimited          ErrorCategory = "rate_limited"
+	CategoryValidationFailed     ErrorCategory = "validation_failed"
+	CategoryConflict             ErrorCategory = "conflict"
+	CategoryServerError          ErrorCategory = "server_error"
+	CategoryUnknown              ErrorCategory = "unknown"
+)
+
+// FieldError describes a single validation failure reported by the GitHub API.
+type FieldError struct {
+	Resource string `json:"resource,omitempty"`
+	Field    string `json:"field,omitempty"`
+	Code     string `json:"code,omitempty"`
+	Message  string `json:"message,omitempty"`
+}
+
+// ErrorDetails is the structured payload returned alongside the text of a failed tool call.
+type ErrorDetails struct {
+	Category          ErrorCategory `json:"category"`
+	Message           string        `json:"message"`
+	Status            int           `json:"status,omitempty"`
+	DocumentationURL  string        `json:"documentation_url,omitempty"`
+	Errors            []FieldError  `json:"errors,omitempty"`
+	RetryAfterSeconds int           `json:"retry_after_seconds,omitempty"`
+	Remediation       string        `json:"remediation,omitempty"`
+	// Access explains which permission, scope or SSO authorization is missing, when GitHub reports it
+	Access *AccessDiagnostics `json:"access,omitempty"`
+}
+
+var remediations = map[ErrorCategory]string{
+	CategoryNotFound:             "Check that the owner, repository and resource identifiers are correct. Private resources also return not found when the token cannot access them.",
+	CategoryPermissionDenied:     "The token does not have permission for this operation. Grant the required scope or fine-grained permission, or use a token with access to the resource.",
+	CategoryAuthenticationFailed: "The token is missing, invalid or expired. Provide a valid GitHub token.",
+	CategorySSORequired:          "The organization enforces SAML single sign-on. Authorize the token for the organization and retry.",
+	CategoryRateLimited:          "The rate limit was exceeded. Wait until the limit resets before retrying, and reduce the number of requests.",
+	CategoryValidationFailed:     "The request was rejected as invalid. Fix the parameters listed in errors and retry.",
+	CategoryConflict:             "The resource was modified concurrently or is in a conflicting state. Refresh it and retry.",
+	CategoryServerError:          "GitHub failed to process the request. Retry later.",
+}
+
+// Details returns the structured description of the error.
+func (e *GitHubAPIError) Details() *ErrorDetails {
+	details := &ErrorDetails{
+		Category: CategoryUnknown,
+		Message:  e.Error(),
+	}
+
+	var httpResp *http.Response
+	if e.Response != nil {
+		httpResp = e.Response.Response
+	}
+
+	var rateLimitErr *github.RateLimitError
+	var abuseErr *github.AbuseRateLimitError
+	var errResp *github.ErrorResponse
+	switch {
+	case errors.As(e.Err, &rateLimitErr):
+		details.Category = CategoryRateLimited
+		if seconds := int(time.Until(rateLimitErr.Rate.Reset.Time).Seconds()); seconds > 0 {
+			details.RetryAfterSeconds = seconds
+		}
+		if rateLimitErr.Response != nil {
+			httpResp = rateLimitErr.Response
+		}
+	case errors.As(e.Err, &abuseErr):
+		details.Category = CategoryRateLimited
+		if abuseErr.RetryAfter != nil {
+			details.RetryAfterSeconds = int(abuseErr.RetryAfter.Seconds())
+		}
+		if abuseErr.Response != nil {
+			httpResp = abuseErr.Response
+		}
+	case errors.As(e.Err, &errResp):
+		details.DocumentationURL = errResp.DocumentationURL
+		for _, fe := range errResp.Errors {
+			details.Errors = append(details.Errors, FieldError{
+				Resource: fe.Resource,
+				Field:    fe.Field,
+				Code:     fe.Code,
+				Message:  fe.Message,
+			})
+		}
+		if errResp.Response != nil {
+			httpResp = errResp.Response
+		}
+	}
+
+	if httpResp != nil {
+		details.Status = httpResp.StatusCode
+		if details.Category == CategoryUnknown {
+			details.Category = categoryFromResponse(httpResp)
+		}
+		if details.RetryAfterSeconds == 0 && details.Category == CategoryRateLimited {
+			details.RetryAfterSeconds = retryAfterFromHeaders(httpResp.Header)
+		}
+		if isAccessCategory(details.Category) {
+			details.Access = DiagnoseAccess(httpResp)
+		}
+	}
+
+	details.Remediation = remediations[details.Category]
+	if remediation := details.Access.Remediation(); remediation != "" {
+		details.Remediation = remediation
+	}
+	return details
+}
+
+// isAccessCategory reports whether errors in the category can be caused by missing token access.
+// GitHub answers 404 rather than 403 for private resources the token can't see.
+func isAccessCategory(c ErrorCategory) bool {
+	switch c {
+	case CategoryNotFound, CategoryPermissionDenied, CategorySSORequired, CategoryAuthenticationFailed:
+		return true
+	default:
+		return false
+	}
+}
+
+// graphQLStatusPattern matches the error returned by the GraphQL client for non-200 responses.
+var graphQLStatusPattern = regexp.MustCompile(`non-200 OK status code: (\d{3})`)
+
+// Details returns the structured description of the error. GraphQL errors don't expose
+// an HTTP response, so the category is inferred from the error message.
+func (e *GitHubGraphQLError) Details() *ErrorDetails {
+	details := &ErrorDetails{
+		Category: CategoryUnknown,
+		Message:  e.Error(),
+	}
+
+	if e.Err != nil {
+		msg := e.Err.Error()
+		if m := graphQLStatusPattern.FindStringSubmatch(msg); m != nil {
+			details.Status, _ = strconv.Atoi(m[1])
+			details.Category = categoryFromStatus(details.Status)
+		}
+		if details.Category == CategoryUnknown {
+			details.Category = categoryFromGraphQLMessage(msg)
+		}
+	}
+
+	details.Remediation = remediations[details.Category]
+	return details
+}
+
+func categoryFromResponse(resp *http.Response) ErrorCategory {
+	if strings.HasPrefix(resp.Header.Get("X-GitHub-SSO"), "required") {
+		return CategorySSORequired
+	}
+	if resp.StatusCode == http.StatusForbidden && resp.Header.Get("X-RateLimit-Remaining") == "0" {
+		return CategoryRateLimited
+	}
+	return categoryFromStatus(resp.StatusCode)
+}
+
+func categoryFromStatus(status int) ErrorCategory {
+	switch {
+	case status == http.StatusNotFound || status == http.StatusGone:
+		return CategoryNotFound
+	case status == http.StatusUnauthorized:
+		return CategoryAuthenticationFailed
+	case status == http.StatusForbidden:
+		return CategoryPermissionDenied
+	case status == http.StatusTooManyRequests:
+		return CategoryRateLimited
+	case status == http.StatusUnprocessableEntity || status == http.StatusBadRequest:
+		return CategoryValidationFailed
+	case status == http.StatusConflict:
+		return CategoryConflict
+	case status >= 500:
+		return CategoryServerError
+	default:
+		return CategoryUnknown
+	}
+}
+
+func categoryFromGraphQLMessage(msg string) ErrorCategory {
+	lower := strings.ToLower(msg)
+	switch {
+	case strings.Contains(lower, "saml"):
+		return CategorySSORequired
+	case strings.Contains(lower, "rate limit"):
+		return CategoryRateLimited
+	case strings.Contains(lower, "could not resolve to"), strings.Contains(lower, "not found"):
+		return CategoryNotFound
+	case strings.Contains(lower, "not accessible"), strings.Contains(lower, "does not have permission"),
+		strings.Contains(lower, "must have"), strings.Contains(lower, "forbidden"):
+		return CategoryPermissionDenied
+	case strings.Contains(lower, "bad credentials"):
+		return CategoryAuthenticationFailed
+	case strings.Contains(lower, "invalid"), strings.Contains(lower, "argument"):
+		return CategoryValidationFailed
+	default:
+		return CategoryUnknown
+	}
+}
+
+func retryAfterFromHeaders(h http.Header) int {
+	if v := h.Get("Retry-After"); v != "" {
+		if seconds, err := strconv.Atoi(v); err == nil {
+			return seconds
+		}
+	}
+	if v := h.Get("X-RateLimit-Reset"); v != "" {
+		if reset, err := strconv.ParseInt(v, 10, 64); err == nil {
+			if seconds := int(time.Until(time.Unix(reset, 0)).Seconds()); seconds > 0 {
+				return seconds
+			}
+		}
+	}
+	return 0
+}
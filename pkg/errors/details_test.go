@@ -0,0 +1,205 @@
+package errors
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v77/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newResponse(status int, headers map[string]string) *http.Response {
+	resp := &http.Response{
+		StatusCode: status,
+		Header:     http.Header{},
+		Request:    &http.Request{Method: http.MethodGet},
+	}
+	for k, v := range headers {
+		resp.Header.Set(k, v)
+	}
+	return resp
+}
+
+func TestGitHubAPIErrorDetails(t *testing.T) {
+	tests := []struct {
+		name               string
+		resp               *http.Response
+		err                func(resp *http.Response) error
+		expectedCategory   ErrorCategory
+		expectedStatus     int
+		expectedDocURL     string
+		expectedErrors     []FieldError
+		expectedRetryAfter int
+	}{
+		{
+			name: "not found",
+			resp: newResponse(http.StatusNotFound, nil),
+			err: func(resp *http.Response) error {
+				return &github.ErrorResponse{Response: resp, Message: "Not Found", DocumentationURL: "https://docs.github.com/rest"}
+			},
+			expectedCategory: CategoryNotFound,
+			expectedStatus:   http.StatusNotFound,
+			expectedDocURL:   "https://docs.github.com/rest",
+		},
+		{
+			name: "permission denied",
+			resp: newResponse(http.StatusForbidden, nil),
+			err: func(resp *http.Response) error {
+				return &github.ErrorResponse{Response: resp, Message: "Resource not accessible by personal access token"}
+			},
+			expectedCategory: CategoryPermissionDenied,
+			expectedStatus:   http.StatusForbidden,
+		},
+		{
+			name: "sso required",
+			resp: newResponse(http.StatusForbidden, map[string]string{"X-GitHub-SSO": "required; url=https://github.com/orgs/o/sso?authorization_request=abc"}),
+			err: func(resp *http.Response) error {
+				return &github.ErrorResponse{Response: resp, Message: "Resource protected by organization SAML enforcement."}
+			},
+			expectedCategory: CategorySSORequired,
+			expectedStatus:   http.StatusForbidden,
+		},
+		{
+			name: "validation failed",
+			resp: newResponse(http.StatusUnprocessableEntity, nil),
+			err: func(resp *http.Response) error {
+				return &github.ErrorResponse{
+					Response: resp,
+					Message:  "Validation Failed",
+					Errors:   []github.Error{{Resource: "Issue", Field: "title", Code: "missing_field"}},
+				}
+			},
+			expectedCategory: CategoryValidationFailed,
+			expectedStatus:   http.StatusUnprocessableEntity,
+			expectedErrors:   []FieldError{{Resource: "Issue", Field: "title", Code: "missing_field"}},
+		},
+		{
+			name: "secondary rate limit",
+			resp: newResponse(http.StatusForbidden, nil),
+			err: func(resp *http.Response) error {
+				return &github.AbuseRateLimitError{Response: resp, Message: "secondary rate limit", RetryAfter: github.Ptr(90 * time.Second)}
+			},
+			expectedCategory:   CategoryRateLimited,
+			expectedStatus:     http.StatusForbidden,
+			expectedRetryAfter: 90,
+		},
+		{
+			name: "too many requests with retry-after header",
+			resp: newResponse(http.StatusTooManyRequests, map[string]string{"Retry-After": "30"}),
+			err: func(resp *http.Response) error {
+				return &github.ErrorResponse{Response: resp, Message: "Too Many Requests"}
+			},
+			expectedCategory:   CategoryRateLimited,
+			expectedStatus:     http.StatusTooManyRequests,
+			expectedRetryAfter: 30,
+		},
+		{
+			name: "server error",
+			resp: newResponse(http.StatusBadGateway, nil),
+			err: func(resp *http.Response) error {
+				return &github.ErrorResponse{Response: resp, Message: "Bad Gateway"}
+			},
+			expectedCategory: CategoryServerError,
+			expectedStatus:   http.StatusBadGateway,
+		},
+		{
+			name: "no response",
+			err: func(_ *http.Response) error {
+				return fmt.Errorf("connection refused")
+			},
+			expectedCategory: CategoryUnknown,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var resp *github.Response
+			if tc.resp != nil {
+				resp = &github.Response{Response: tc.resp}
+			}
+			details := newGitHubAPIError("failed to do thing", resp, tc.err(tc.resp)).Details()
+
+			assert.Equal(t, tc.expectedCategory, details.Category)
+			assert.Equal(t, tc.expectedStatus, details.Status)
+			assert.Equal(t, tc.expectedDocURL, details.DocumentationURL)
+			assert.Equal(t, tc.expectedErrors, details.Errors)
+			assert.Equal(t, tc.expectedRetryAfter, details.RetryAfterSeconds)
+			assert.Contains(t, details.Message, "failed to do thing")
+			if tc.expectedCategory != CategoryUnknown {
+				assert.NotEmpty(t, details.Remediation)
+			}
+		})
+	}
+}
+
+func TestGitHubGraphQLErrorDetails(t *testing.T) {
+	tests := []struct {
+		name             string
+		err              error
+		expectedCategory ErrorCategory
+		expectedStatus   int
+	}{
+		{
+			name:             "not found",
+			err:              fmt.Errorf("Could not resolve to a Repository with the name 'owner/missing'."),
+			expectedCategory: CategoryNotFound,
+		},
+		{
+			name:             "saml",
+			err:              fmt.Errorf("Resource protected by organization SAML enforcement. You must grant your Personal Access token access to this organization."),
+			expectedCategory: CategorySSORequired,
+		},
+		{
+			name:             "rate limited",
+			err:              fmt.Errorf("API rate limit exceeded for user ID 1."),
+			expectedCategory: CategoryRateLimited,
+		},
+		{
+			name:             "permission",
+			err:              fmt.Errorf("Resource not accessible by integration"),
+			expectedCategory: CategoryPermissionDenied,
+		},
+		{
+			name:             "non-200 status",
+			err:              fmt.Errorf("non-200 OK status code: 401 Unauthorized body: \"{\\\"message\\\":\\\"Bad credentials\\\"}\""),
+			expectedCategory: CategoryAuthenticationFailed,
+			expectedStatus:   http.StatusUnauthorized,
+		},
+		{
+			name:             "unknown",
+			err:              fmt.Errorf("something odd happened"),
+			expectedCategory: CategoryUnknown,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			details := newGitHubGraphQLError("failed to query", tc.err).Details()
+			assert.Equal(t, tc.expectedCategory, details.Category)
+			assert.Equal(t, tc.expectedStatus, details.Status)
+		})
+	}
+}
+
+func TestErrorResponsesIncludeStructuredContent(t *testing.T) {
+	ctx := ContextWithGitHubErrors(context.Background())
+
+	resp := &github.Response{Response: newResponse(http.StatusNotFound, nil)}
+	result := NewGitHubAPIErrorResponse(ctx, "failed to get issue", resp, &github.ErrorResponse{Response: resp.Response, Message: "Not Found"})
+	require.True(t, result.IsError)
+	require.Len(t, result.Content, 1)
+	details, ok := result.StructuredContent.(*ErrorDetails)
+	require.True(t, ok)
+	assert.Equal(t, CategoryNotFound, details.Category)
+	assert.Equal(t, http.StatusNotFound, details.Status)
+
+	result = NewGitHubGraphQLErrorResponse(ctx, "failed to get discussion", fmt.Errorf("Could not resolve to a Discussion with the number of 1."))
+	require.True(t, result.IsError)
+	details, ok = result.StructuredContent.(*ErrorDetails)
+	require.True(t, ok)
+	assert.Equal(t, CategoryNotFound, details.Category)
+}
@@ -3,6 +3,7 @@ package errors
 import (
 	"context"
 	"fmt"
+	"sync"
 
 	"github.com/google/go-github/v77/github"
 	"github.com/mark3labs/mcp-go/mcp"
@@ -27,6 +28,15 @@ func (e *GitHubAPIError) Error() string {
 	return fmt.Errorf("%s: %w", e.Message, e.Err).Error()
 }
 
+// RequestID returns the X-GitHub-Request-Id of the failed request, which GitHub support
+// can use to look the request up, or an empty string if there was no response.
+func (e *GitHubAPIError) RequestID() string {
+	if e.Response == nil || e.Response.Response == nil {
+		return ""
+	}
+	return e.Response.Header.Get("X-GitHub-Request-Id")
+}
+
 type GitHubGraphQLError struct {
 	Message string `json:"message"`
 	Err     error  `json:"-"`
@@ -45,8 +55,27 @@ func (e *GitHubGraphQLError) Error() string {
 
 type GitHubErrorKey struct{}
 type GitHubCtxErrors struct {
+	mu      sync.Mutex
 	api     []*GitHubAPIError
 	graphQL []*GitHubGraphQLError
+	// parent is the collector of an outer context, which also receives every error added to this one
+	parent *GitHubCtxErrors
+}
+
+func (e *GitHubCtxErrors) addAPIError(err *GitHubAPIError) {
+	for ; e != nil; e = e.parent {
+		e.mu.Lock()
+		e.api = append(e.api, err)
+		e.mu.Unlock()
+	}
+}
+
+func (e *GitHubCtxErrors) addGraphQLError(err *GitHubGraphQLError) {
+	for ; e != nil; e = e.parent {
+		e.mu.Lock()
+		e.graphQL = append(e.graphQL, err)
+		e.mu.Unlock()
+	}
 }
 
 // ContextWithGitHubErrors updates or creates a context with a pointer to GitHub error information (to be used by middleware).
@@ -56,6 +85,8 @@ func ContextWithGitHubErrors(ctx context.Context) context.Context {
 	}
 	if val, ok := ctx.Value(GitHubErrorKey{}).(*GitHubCtxErrors); ok {
 		// If the context already has GitHubCtxErrors, we just empty the slices to start fresh
+		val.mu.Lock()
+		defer val.mu.Unlock()
 		val.api = []*GitHubAPIError{}
 		val.graphQL = []*GitHubGraphQLError{}
 	} else {
@@ -69,6 +100,8 @@ func ContextWithGitHubErrors(ctx context.Context) context.Context {
 // GetGitHubAPIErrors retrieves the slice of GitHubAPIErrors from the context.
 func GetGitHubAPIErrors(ctx context.Context) ([]*GitHubAPIError, error) {
 	if val, ok := ctx.Value(GitHubErrorKey{}).(*GitHubCtxErrors); ok {
+		val.mu.Lock()
+		defer val.mu.Unlock()
 		return val.api, nil // return the slice of API errors from the context
 	}
 	return nil, fmt.Errorf("context does not contain GitHubCtxErrors")
@@ -77,11 +110,27 @@ func GetGitHubAPIErrors(ctx context.Context) ([]*GitHubAPIError, error) {
 // GetGitHubGraphQLErrors retrieves the slice of GitHubGraphQLErrors from the context.
 func GetGitHubGraphQLErrors(ctx context.Context) ([]*GitHubGraphQLError, error) {
 	if val, ok := ctx.Value(GitHubErrorKey{}).(*GitHubCtxErrors); ok {
+		val.mu.Lock()
+		defer val.mu.Unlock()
 		return val.graphQL, nil // return the slice of GraphQL errors from the context
 	}
 	return nil, fmt.Errorf("context does not contain GitHubCtxErrors")
 }
 
+// DrainGitHubErrors returns the API and GraphQL errors collected on the context and clears them,
+// so that each error is only reported once.
+func DrainGitHubErrors(ctx context.Context) ([]*GitHubAPIError, []*GitHubGraphQLError, error) {
+	val, ok := ctx.Value(GitHubErrorKey{}).(*GitHubCtxErrors)
+	if !ok {
+		return nil, nil, fmt.Errorf("context does not contain GitHubCtxErrors")
+	}
+	val.mu.Lock()
+	defer val.mu.Unlock()
+	api, graphQL := val.api, val.graphQL
+	val.api, val.graphQL = nil, nil
+	return api, graphQL, nil
+}
+
 func NewGitHubAPIErrorToCtx(ctx context.Context, message string, resp *github.Response, err error) (context.Context, error) {
 	apiErr := newGitHubAPIError(message, resp, err)
 	if ctx != nil {
@@ -92,7 +141,7 @@ func NewGitHubAPIErrorToCtx(ctx context.Context, message string, resp *github.Re
 
 func addGitHubAPIErrorToContext(ctx context.Context, err *GitHubAPIError) (context.Context, error) {
 	if val, ok := ctx.Value(GitHubErrorKey{}).(*GitHubCtxErrors); ok {
-		val.api = append(val.api, err) // append the error to the existing slice in the context
+		val.addAPIError(err) // append the error to the existing slice in the context
 		return ctx, nil
 	}
 	return nil, fmt.Errorf("context does not contain GitHubCtxErrors")
@@ -100,26 +149,32 @@ func addGitHubAPIErrorToContext(ctx context.Context, err *GitHubAPIError) (conte
 
 func addGitHubGraphQLErrorToContext(ctx context.Context, err *GitHubGraphQLError) (context.Context, error) {
 	if val, ok := ctx.Value(GitHubErrorKey{}).(*GitHubCtxErrors); ok {
-		val.graphQL = append(val.graphQL, err) // append the error to the existing slice in the context
+		val.addGraphQLError(err) // append the error to the existing slice in the context
 		return ctx, nil
 	}
 	return nil, fmt.Errorf("context does not contain GitHubCtxErrors")
 }
 
-// NewGitHubAPIErrorResponse returns an mcp.NewToolResultError and retains the error in the context for access via middleware
+// NewGitHubAPIErrorResponse returns an mcp.NewToolResultError with the structured ErrorDetails attached
+// and retains the error in the context for access via middleware
 func NewGitHubAPIErrorResponse(ctx context.Context, message string, resp *github.Response, err error) *mcp.CallToolResult {
 	apiErr := newGitHubAPIError(message, resp, err)
 	if ctx != nil {
 		_, _ = addGitHubAPIErrorToContext(ctx, apiErr) // Explicitly ignore error for graceful handling
 	}
-	return mcp.NewToolResultErrorFromErr(message, err)
+	result := mcp.NewToolResultErrorFromErr(message, err)
+	result.StructuredContent = apiErr.Details()
+	return result
 }
 
-// NewGitHubGraphQLErrorResponse returns an mcp.NewToolResultError and retains the error in the context for access via middleware
+// NewGitHubGraphQLErrorResponse returns an mcp.NewToolResultError with the structured ErrorDetails attached
+// and retains the error in the context for access via middleware
 func NewGitHubGraphQLErrorResponse(ctx context.Context, message string, err error) *mcp.CallToolResult {
 	graphQLErr := newGitHubGraphQLError(message, err)
 	if ctx != nil {
 		_, _ = addGitHubGraphQLErrorToContext(ctx, graphQLErr) // Explicitly ignore error for graceful handling
 	}
-	return mcp.NewToolResultErrorFromErr(message, err)
+	result := mcp.NewToolResultErrorFromErr(message, err)
+	result.StructuredContent = graphQLErr.Details()
+	return result
 }
@@ -0,0 +1,152 @@
+package errors
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Metrics receives one observation for every GitHub error reported after a tool call.
+type Metrics interface {
+	RecordGitHubError(tool string, source string, category ErrorCategory)
+}
+
+// ErrorCounter is a Metrics implementation that counts errors by tool, source and category.
+type ErrorCounter struct {
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+// NewErrorCounter creates an empty ErrorCounter.
+func NewErrorCounter() *ErrorCounter {
+	return &ErrorCounter{counts: make(map[string]int)}
+}
+
+// RecordGitHubError implements Metrics.
+func (c *ErrorCounter) RecordGitHubError(tool string, source string, category ErrorCategory) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.counts[tool+"/"+source+"/"+string(category)]++
+}
+
+// Counts returns a copy of the counts keyed by "tool/source/category".
+func (c *ErrorCounter) Counts() map[string]int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	counts := make(map[string]int, len(c.counts))
+	for k, v := range c.counts {
+		counts[k] = v
+	}
+	return counts
+}
+
+// ReportOptions configures the middleware returned by NewErrorReportingMiddleware.
+type ReportOptions struct {
+	// Logger receives one warning per error. Errors are not logged if it is nil.
+	Logger *slog.Logger
+	// Metrics is fed with every error, if set
+	Metrics Metrics
+	// AppendDiagnostics appends a compact summary of the errors to the tool result
+	AppendDiagnostics bool
+}
+
+// NewErrorReportingMiddleware creates a tool handler middleware that gives every call its own
+// collector of GitHub errors on the context, then drains it once the tool ran to log the errors
+// with their GitHub request IDs and feed them to metrics. Tool calls run concurrently, so the
+// errors of a call are never reported with the result of another.
+// When the context already carries a collector, e.g. one set up by an embedder with
+// ContextWithGitHubErrors, the errors of the call are still added to it and are left there.
+func NewErrorReportingMiddleware(opts ReportOptions) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			parent, _ := ctx.Value(GitHubErrorKey{}).(*GitHubCtxErrors)
+			ctx = context.WithValue(ctx, GitHubErrorKey{}, &GitHubCtxErrors{parent: parent})
+			result, err := next(ctx, request)
+			reportGitHubErrors(ctx, opts, request.Params.Name, result)
+			return result, err
+		}
+	}
+}
+
+// reportGitHubErrors drains the GitHub errors collected on the context while a tool ran and reports them.
+func reportGitHubErrors(ctx context.Context, opts ReportOptions, tool string, result *mcp.CallToolResult) {
+	apiErrors, graphQLErrors, err := DrainGitHubErrors(ctx)
+	if err != nil || len(apiErrors)+len(graphQLErrors) == 0 {
+		return
+	}
+
+	lines := make([]string, 0, len(apiErrors)+len(graphQLErrors))
+	for _, apiErr := range apiErrors {
+		details := apiErr.Details()
+		requestID := apiErr.RequestID()
+		if opts.Logger != nil {
+			opts.Logger.WarnContext(ctx, "github api error",
+				"tool", tool,
+				"category", details.Category,
+				"status", details.Status,
+				"request_id", requestID,
+				"error", apiErr.Error(),
+			)
+		}
+		if opts.Metrics != nil {
+			opts.Metrics.RecordGitHubError(tool, "rest", details.Category)
+		}
+		lines = append(lines, diagnosticLine(apiErr.Message, details, requestID))
+	}
+	for _, graphQLErr := range graphQLErrors {
+		details := graphQLErr.Details()
+		if opts.Logger != nil {
+			opts.Logger.WarnContext(ctx, "github graphql error",
+				"tool", tool,
+				"category", details.Category,
+				"status", details.Status,
+				"error", graphQLErr.Error(),
+			)
+		}
+		if opts.Metrics != nil {
+			opts.Metrics.RecordGitHubError(tool, "graphql", details.Category)
+		}
+		lines = append(lines, diagnosticLine(graphQLErr.Message, details, ""))
+	}
+
+	if opts.AppendDiagnostics && result != nil {
+		result.Content = append(result.Content, mcp.NewTextContent("GitHub API errors during this call:\n"+strings.Join(lines, "\n")))
+	}
+}
+
+// diagnosticLine formats an error as e.g. "- failed to get issue: 404 not_found (request id: ABCD:1234)".
+func diagnosticLine(message string, details *ErrorDetails, requestID string) string {
+	var b strings.Builder
+	b.WriteString("- ")
+	b.WriteString(message)
+	b.WriteString(": ")
+	if details.Status != 0 {
+		fmt.Fprintf(&b, "%d ", details.Status)
+	}
+	b.WriteString(string(details.Category))
+	if requestID != "" {
+		fmt.Fprintf(&b, " (request id: %s)", requestID)
+	}
+	return b.String()
+}
+
+// LogValue implements slog.LogValuer so the counts can be logged as a group, e.g. on shutdown.
+func (c *ErrorCounter) LogValue() slog.Value {
+	counts := c.Counts()
+	keys := make([]string, 0, len(counts))
+	for k := range counts {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	attrs := make([]slog.Attr, 0, len(keys))
+	for _, k := range keys {
+		attrs = append(attrs, slog.Int(k, counts[k]))
+	}
+	return slog.GroupValue(attrs...)
+}
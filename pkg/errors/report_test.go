@@ -0,0 +1,159 @@
+package errors
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/google/go-github/v77/github"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDrainGitHubErrors(t *testing.T) {
+	ctx := ContextWithGitHubErrors(context.Background())
+	_, _ = NewGitHubAPIErrorToCtx(ctx, "failed to get issue", nil, fmt.Errorf("boom"))
+	_ = NewGitHubGraphQLErrorResponse(ctx, "failed to query", fmt.Errorf("boom"))
+
+	apiErrors, graphQLErrors, err := DrainGitHubErrors(ctx)
+	require.NoError(t, err)
+	assert.Len(t, apiErrors, 1)
+	assert.Len(t, graphQLErrors, 1)
+
+	// Drained errors are not reported again
+	apiErrors, graphQLErrors, err = DrainGitHubErrors(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, apiErrors)
+	assert.Empty(t, graphQLErrors)
+
+	_, _, err = DrainGitHubErrors(context.Background())
+	assert.Error(t, err)
+}
+
+func TestErrorReportingMiddleware(t *testing.T) {
+	newRequest := func(tool string) mcp.CallToolRequest {
+		req := mcp.CallToolRequest{}
+		req.Params.Name = tool
+		return req
+	}
+	recordErrors := func(ctx context.Context, requestID string) {
+		resp := &github.Response{Response: newResponse(http.StatusNotFound, map[string]string{"X-GitHub-Request-Id": requestID})}
+		_ = NewGitHubAPIErrorResponse(ctx, "failed to get issue", resp, &github.ErrorResponse{Response: resp.Response, Message: "Not Found"})
+		_ = NewGitHubGraphQLErrorResponse(ctx, "failed to get issue type", fmt.Errorf("API rate limit exceeded"))
+	}
+	failingTool := func(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		recordErrors(ctx, "ABCD:1234")
+		return mcp.NewToolResultText("ok"), nil
+	}
+
+	t.Run("logs, counts and appends diagnostics", func(t *testing.T) {
+		var logs bytes.Buffer
+		counter := NewErrorCounter()
+		handler := NewErrorReportingMiddleware(ReportOptions{
+			Logger:            slog.New(slog.NewTextHandler(&logs, nil)),
+			Metrics:           counter,
+			AppendDiagnostics: true,
+		})(failingTool)
+
+		result, err := handler(context.Background(), newRequest("get_issue"))
+		require.NoError(t, err)
+
+		assert.Contains(t, logs.String(), "request_id=ABCD:1234")
+		assert.Contains(t, logs.String(), "tool=get_issue")
+		assert.Equal(t, map[string]int{
+			"get_issue/rest/not_found":       1,
+			"get_issue/graphql/rate_limited": 1,
+		}, counter.Counts())
+
+		require.Len(t, result.Content, 2)
+		diagnostics, ok := result.Content[1].(mcp.TextContent)
+		require.True(t, ok)
+		assert.Equal(t, "GitHub API errors during this call:\n"+
+			"- failed to get issue: 404 not_found (request id: ABCD:1234)\n"+
+			"- failed to get issue type: rate_limited", diagnostics.Text)
+	})
+
+	t.Run("leaves result untouched by default", func(t *testing.T) {
+		handler := NewErrorReportingMiddleware(ReportOptions{})(failingTool)
+
+		result, err := handler(context.Background(), newRequest("get_issue"))
+		require.NoError(t, err)
+		assert.Len(t, result.Content, 1)
+	})
+
+	t.Run("concurrent calls only report their own errors", func(t *testing.T) {
+		counter := NewErrorCounter()
+		failing := make(chan struct{})
+		succeeding := make(chan struct{})
+		handler := NewErrorReportingMiddleware(ReportOptions{Metrics: counter, AppendDiagnostics: true})(
+			func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				if request.Params.Name == "get_issue" {
+					// Record errors while the other call is in flight, and only finish after it started
+					<-succeeding
+					recordErrors(ctx, "ABCD:1234")
+					close(failing)
+					return mcp.NewToolResultText("ok"), nil
+				}
+				close(succeeding)
+				<-failing
+				return mcp.NewToolResultText("ok"), nil
+			},
+		)
+
+		var wg sync.WaitGroup
+		results := make(map[string]*mcp.CallToolResult)
+		var mu sync.Mutex
+		for _, tool := range []string{"get_issue", "get_me"} {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				result, err := handler(context.Background(), newRequest(tool))
+				assert.NoError(t, err)
+				mu.Lock()
+				results[tool] = result
+				mu.Unlock()
+			}()
+		}
+		wg.Wait()
+
+		assert.Len(t, results["get_issue"].Content, 2)
+		assert.Len(t, results["get_me"].Content, 1)
+		assert.Equal(t, map[string]int{
+			"get_issue/rest/not_found":       1,
+			"get_issue/graphql/rate_limited": 1,
+		}, counter.Counts())
+	})
+
+	t.Run("keeps the errors of an existing collector", func(t *testing.T) {
+		ctx := ContextWithGitHubErrors(context.Background())
+		_, _ = NewGitHubAPIErrorToCtx(ctx, "failed to get user", nil, fmt.Errorf("boom"))
+
+		counter := NewErrorCounter()
+		handler := NewErrorReportingMiddleware(ReportOptions{Metrics: counter, AppendDiagnostics: true})(failingTool)
+		result, err := handler(ctx, newRequest("get_issue"))
+		require.NoError(t, err)
+
+		// Only the errors of the call are reported
+		require.Len(t, result.Content, 2)
+		diagnostics, ok := result.Content[1].(mcp.TextContent)
+		require.True(t, ok)
+		assert.NotContains(t, diagnostics.Text, "failed to get user")
+		assert.Equal(t, map[string]int{
+			"get_issue/rest/not_found":       1,
+			"get_issue/graphql/rate_limited": 1,
+		}, counter.Counts())
+
+		// The embedder still sees its own errors and those of the call
+		apiErrors, graphQLErrors, err := DrainGitHubErrors(ctx)
+		require.NoError(t, err)
+		require.Len(t, apiErrors, 2)
+		assert.Equal(t, "failed to get user", apiErrors[0].Message)
+		assert.Equal(t, "failed to get issue", apiErrors[1].Message)
+		assert.Len(t, graphQLErrors, 1)
+	})
+}
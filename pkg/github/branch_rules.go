@@ -0,0 +1,614 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	ghErrors "github.com/github/github-mcp-server/pkg/errors"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/google/go-github/v77/github"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// BranchProtectionDetails is a compact explanation of the classic protection of a branch.
+type BranchProtectionDetails struct {
+	Branch       string   `json:"branch"`
+	Protected    bool     `json:"protected"`
+	Rules        []string `json:"rules,omitempty"`
+	BypassActors []string `json:"bypass_actors,omitempty"`
+	Message      string   `json:"message,omitempty"`
+}
+
+// RulesetSummary is a compact explanation of a repository ruleset.
+type RulesetSummary struct {
+	ID                   int64    `json:"id"`
+	Name                 string   `json:"name"`
+	Target               string   `json:"target,omitempty"`
+	Enforcement          string   `json:"enforcement"`
+	Source               string   `json:"source,omitempty"`
+	SourceType           string   `json:"source_type,omitempty"`
+	Include              []string `json:"include,omitempty"`
+	Exclude              []string `json:"exclude,omitempty"`
+	BypassActors         []string `json:"bypass_actors,omitempty"`
+	CurrentUserCanBypass string   `json:"current_user_can_bypass,omitempty"`
+	Rules                []string `json:"rules,omitempty"`
+}
+
+// EffectiveRule is a rule that applies to a branch, along with the ruleset it comes from.
+type EffectiveRule struct {
+	Type          string `json:"type"`
+	Description   string `json:"description"`
+	RulesetID     int64  `json:"ruleset_id"`
+	RulesetSource string `json:"ruleset_source,omitempty"`
+}
+
+// EffectiveBranchRules is the output type of get_effective_rules_for_branch.
+type EffectiveBranchRules struct {
+	Branch           string                   `json:"branch"`
+	Rules            []EffectiveRule          `json:"rules"`
+	Rulesets         []RulesetSummary         `json:"rulesets,omitempty"`
+	BranchProtection *BranchProtectionDetails `json:"branch_protection,omitempty"`
+}
+
+// rulesetRule is a rule of a ruleset as returned by the API, with its parameters left undecoded.
+type rulesetRule struct {
+	Type       string          `json:"type"`
+	Parameters json.RawMessage `json:"parameters,omitempty"`
+}
+
+// branchRule is a rule returned by the rules for a branch endpoint.
+type branchRule struct {
+	rulesetRule
+	RulesetSourceType string `json:"ruleset_source_type"`
+	RulesetSource     string `json:"ruleset_source"`
+	RulesetID         int64  `json:"ruleset_id"`
+}
+
+// describeRule explains a ruleset rule in one sentence.
+func describeRule(rule rulesetRule) string {
+	switch rule.Type {
+	case "creation":
+		return "Only bypass actors can create matching refs"
+	case "update":
+		return "Only bypass actors can push to matching refs"
+	case "deletion":
+		return "Matching refs can't be deleted"
+	case "non_fast_forward":
+		return "Force pushes are blocked"
+	case "required_linear_history":
+		return "Linear history is required (no merge commits)"
+	case "required_signatures":
+		return "Commits must be signed"
+	case "merge_queue":
+		var params github.MergeQueueRuleParameters
+		_ = json.Unmarshal(rule.Parameters, &params)
+		if params.MergeMethod != "" {
+			return fmt.Sprintf("Pull requests must be merged through a merge queue (%s)", strings.ToLower(string(params.MergeMethod)))
+		}
+		return "Pull requests must be merged through a merge queue"
+	case "pull_request":
+		var params github.PullRequestRuleParameters
+		_ = json.Unmarshal(rule.Parameters, &params)
+		return describePullRequestRule(params)
+	case "required_status_checks":
+		var params github.RequiredStatusChecksRuleParameters
+		_ = json.Unmarshal(rule.Parameters, &params)
+		checks := make([]string, 0, len(params.RequiredStatusChecks))
+		for _, check := range params.RequiredStatusChecks {
+			checks = append(checks, check.Context)
+		}
+		return describeStatusChecks(checks, params.StrictRequiredStatusChecksPolicy)
+	case "required_deployments":
+		var params github.RequiredDeploymentsRuleParameters
+		_ = json.Unmarshal(rule.Parameters, &params)
+		return "Deployments must succeed to: " + strings.Join(params.RequiredDeploymentEnvironments, ", ")
+	case "commit_message_pattern", "commit_author_email_pattern", "committer_email_pattern", "branch_name_pattern", "tag_name_pattern":
+		var params github.PatternRuleParameters
+		_ = json.Unmarshal(rule.Parameters, &params)
+		return describePatternRule(rule.Type, params)
+	case "file_path_restriction":
+		var params github.FilePathRestrictionRuleParameters
+		_ = json.Unmarshal(rule.Parameters, &params)
+		return "Pushes can't change: " + strings.Join(params.RestrictedFilePaths, ", ")
+	case "max_file_path_length":
+		var params github.MaxFilePathLengthRuleParameters
+		_ = json.Unmarshal(rule.Parameters, &params)
+		return fmt.Sprintf("File paths can't be longer than %d characters", params.MaxFilePathLength)
+	case "file_extension_restriction":
+		var params github.FileExtensionRestrictionRuleParameters
+		_ = json.Unmarshal(rule.Parameters, &params)
+		return "Pushes can't add files with extensions: " + strings.Join(params.RestrictedFileExtensions, ", ")
+	case "max_file_size":
+		var params github.MaxFileSizeRuleParameters
+		_ = json.Unmarshal(rule.Parameters, &params)
+		return fmt.Sprintf("Files can't be larger than %d MB", params.MaxFileSize)
+	case "workflows":
+		var params github.WorkflowsRuleParameters
+		_ = json.Unmarshal(rule.Parameters, &params)
+		workflows := make([]string, 0, len(params.Workflows))
+		for _, workflow := range params.Workflows {
+			workflows = append(workflows, workflow.Path)
+		}
+		return "Workflows must pass: " + strings.Join(workflows, ", ")
+	case "code_scanning":
+		var params github.CodeScanningRuleParameters
+		_ = json.Unmarshal(rule.Parameters, &params)
+		tools := make([]string, 0, len(params.CodeScanningTools))
+		for _, tool := range params.CodeScanningTools {
+			tools = append(tools, tool.Tool)
+		}
+		return "Code scanning results are required from: " + strings.Join(tools, ", ")
+	default:
+		return strings.ReplaceAll(rule.Type, "_", " ")
+	}
+}
+
+func describePullRequestRule(params github.PullRequestRuleParameters) string {
+	description := fmt.Sprintf("Changes must go through a pull request with %d approving review(s)", params.RequiredApprovingReviewCount)
+	var extras []string
+	if params.RequireCodeOwnerReview {
+		extras = append(extras, "code owner review required")
+	}
+	if params.RequireLastPushApproval {
+		extras = append(extras, "the last push must be approved by someone else")
+	}
+	if params.DismissStaleReviewsOnPush {
+		extras = append(extras, "stale reviews are dismissed on push")
+	}
+	if params.RequiredReviewThreadResolution {
+		extras = append(extras, "conversations must be resolved")
+	}
+	if len(params.AllowedMergeMethods) > 0 {
+		methods := make([]string, 0, len(params.AllowedMergeMethods))
+		for _, method := range params.AllowedMergeMethods {
+			methods = append(methods, string(method))
+		}
+		extras = append(extras, "allowed merge methods: "+strings.Join(methods, ", "))
+	}
+	if len(extras) > 0 {
+		description += "; " + strings.Join(extras, "; ")
+	}
+	return description
+}
+
+func describeStatusChecks(checks []string, strict bool) string {
+	description := "Status checks must pass"
+	if len(checks) > 0 {
+		description += ": " + strings.Join(checks, ", ")
+	}
+	if strict {
+		description += " (branch must be up to date)"
+	}
+	return description
+}
+
+func describePatternRule(ruleType string, params github.PatternRuleParameters) string {
+	subjects := map[string]string{
+		"commit_message_pattern":      "Commit messages",
+		"commit_author_email_pattern": "Commit author emails",
+		"committer_email_pattern":     "Committer emails",
+		"branch_name_pattern":         "Branch names",
+		"tag_name_pattern":            "Tag names",
+	}
+	verb := "must"
+	if params.Negate != nil && *params.Negate {
+		verb = "must not"
+	}
+	operators := map[github.PatternRuleOperator]string{
+		"starts_with": "start with",
+		"ends_with":   "end with",
+		"contains":    "contain",
+		"regex":       "match",
+	}
+	operator, ok := operators[params.Operator]
+	if !ok {
+		operator = string(params.Operator)
+	}
+	return fmt.Sprintf("%s %s %s %q", subjects[ruleType], verb, operator, params.Pattern)
+}
+
+// formatBypassActor formats a ruleset bypass actor, e.g. "Team 42 (always)".
+func formatBypassActor(actor *github.BypassActor) string {
+	var parts []string
+	if actor.ActorType != nil {
+		parts = append(parts, string(*actor.ActorType))
+	}
+	if actor.ActorID != nil && *actor.ActorID != 0 {
+		parts = append(parts, fmt.Sprintf("%d", *actor.ActorID))
+	}
+	if actor.BypassMode != nil {
+		parts = append(parts, fmt.Sprintf("(%s)", *actor.BypassMode))
+	}
+	return strings.Join(parts, " ")
+}
+
+// describeBranchProtection explains the classic protection of a branch.
+func describeBranchProtection(branch string, protection *github.Protection) BranchProtectionDetails {
+	details := BranchProtectionDetails{Branch: branch, Protected: true}
+	if reviews := protection.RequiredPullRequestReviews; reviews != nil {
+		details.Rules = append(details.Rules, describePullRequestRule(github.PullRequestRuleParameters{
+			RequiredApprovingReviewCount: reviews.RequiredApprovingReviewCount,
+			RequireCodeOwnerReview:       reviews.RequireCodeOwnerReviews,
+			RequireLastPushApproval:      reviews.RequireLastPushApproval,
+			DismissStaleReviewsOnPush:    reviews.DismissStaleReviews,
+		}))
+		if allowances := reviews.BypassPullRequestAllowances; allowances != nil {
+			for _, actor := range formatActors(allowances.Users, allowances.Teams, allowances.Apps) {
+				details.BypassActors = append(details.BypassActors, actor+" (pull request requirements)")
+			}
+		}
+	}
+	if checks := protection.RequiredStatusChecks; checks != nil {
+		var contexts []string
+		if checks.Checks != nil {
+			for _, check := range *checks.Checks {
+				contexts = append(contexts, check.Context)
+			}
+		} else if checks.Contexts != nil {
+			contexts = *checks.Contexts
+		}
+		details.Rules = append(details.Rules, describeStatusChecks(contexts, checks.Strict))
+	}
+	if protection.RequiredSignatures != nil && protection.RequiredSignatures.GetEnabled() {
+		details.Rules = append(details.Rules, "Commits must be signed")
+	}
+	if protection.RequireLinearHistory != nil && protection.RequireLinearHistory.Enabled {
+		details.Rules = append(details.Rules, "Linear history is required (no merge commits)")
+	}
+	if protection.RequiredConversationResolution != nil && protection.RequiredConversationResolution.Enabled {
+		details.Rules = append(details.Rules, "Review conversations must be resolved")
+	}
+	if protection.AllowForcePushes == nil || !protection.AllowForcePushes.Enabled {
+		details.Rules = append(details.Rules, "Force pushes are blocked")
+	}
+	if protection.AllowDeletions == nil || !protection.AllowDeletions.Enabled {
+		details.Rules = append(details.Rules, "The branch can't be deleted")
+	}
+	if protection.LockBranch != nil && protection.LockBranch.GetEnabled() {
+		details.Rules = append(details.Rules, "The branch is locked (read-only)")
+	}
+	if restrictions := protection.Restrictions; restrictions != nil {
+		details.Rules = append(details.Rules, "Only these actors can push: "+strings.Join(formatActors(restrictions.Users, restrictions.Teams, restrictions.Apps), ", "))
+	}
+	if protection.EnforceAdmins == nil || !protection.EnforceAdmins.Enabled {
+		details.BypassActors = append(details.BypassActors, "Repository administrators (always)")
+	}
+	return details
+}
+
+func formatActors(users []*github.User, teams []*github.Team, apps []*github.App) []string {
+	var actors []string
+	for _, user := range users {
+		actors = append(actors, "User "+user.GetLogin())
+	}
+	for _, team := range teams {
+		actors = append(actors, "Team "+team.GetSlug())
+	}
+	for _, app := range apps {
+		actors = append(actors, "App "+app.GetSlug())
+	}
+	return actors
+}
+
+// summarizeRuleset converts a GitHub API RepositoryRuleset to a RulesetSummary.
+func summarizeRuleset(ruleset *github.RepositoryRuleset) RulesetSummary {
+	summary := RulesetSummary{
+		ID:          ruleset.GetID(),
+		Name:        ruleset.Name,
+		Enforcement: string(ruleset.Enforcement),
+		Source:      ruleset.Source,
+	}
+	if ruleset.Target != nil {
+		summary.Target = string(*ruleset.Target)
+	}
+	if ruleset.SourceType != nil {
+		summary.SourceType = string(*ruleset.SourceType)
+	}
+	if ruleset.Conditions != nil && ruleset.Conditions.RefName != nil {
+		summary.Include = ruleset.Conditions.RefName.Include
+		summary.Exclude = ruleset.Conditions.RefName.Exclude
+	}
+	for _, actor := range ruleset.BypassActors {
+		summary.BypassActors = append(summary.BypassActors, formatBypassActor(actor))
+	}
+	if ruleset.CurrentUserCanBypass != nil {
+		summary.CurrentUserCanBypass = string(*ruleset.CurrentUserCanBypass)
+	}
+	if ruleset.Rules != nil {
+		// The typed rules marshal back to the API representation, which is simpler to describe
+		var rules []rulesetRule
+		if data, err := json.Marshal(ruleset.Rules); err == nil && json.Unmarshal(data, &rules) == nil {
+			for _, rule := range rules {
+				summary.Rules = append(summary.Rules, describeRule(rule))
+			}
+		}
+	}
+	return summary
+}
+
+// getBranchRules gets the ruleset rules that apply to a branch, from all the pages.
+func getBranchRules(ctx context.Context, client *github.Client, owner, repo, branch string) ([]branchRule, *github.Response, error) {
+	var rules []branchRule
+	page := 1
+	for {
+		req, err := client.NewRequest(http.MethodGet, fmt.Sprintf("repos/%s/%s/rules/branches/%s?per_page=100&page=%d", owner, repo, escapeBranchPath(branch), page), nil)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to create request: %w", err)
+		}
+		var pageRules []branchRule
+		resp, err := client.Do(ctx, req, &pageRules)
+		if err != nil {
+			return nil, resp, err
+		}
+		_ = resp.Body.Close()
+		rules = append(rules, pageRules...)
+		if resp.NextPage == 0 {
+			return rules, resp, nil
+		}
+		page = resp.NextPage
+	}
+}
+
+// blockingRulesHint explains the rules governing a branch, to help understand why a merge or push was
+// rejected. It is best effort and returns an empty string if the rules can't be determined.
+func blockingRulesHint(ctx context.Context, client *github.Client, owner, repo, branch string) string {
+	var lines []string
+	if rules, _, err := getBranchRules(ctx, client, owner, repo, branch); err == nil {
+		for _, rule := range rules {
+			lines = append(lines, fmt.Sprintf("- %s (ruleset %d from %s)", describeRule(rule.rulesetRule), rule.RulesetID, rule.RulesetSource))
+		}
+	}
+	if protection, resp, err := client.Repositories.GetBranchProtection(ctx, owner, repo, branch); err == nil {
+		_ = resp.Body.Close()
+		for _, rule := range describeBranchProtection(branch, protection).Rules {
+			lines = append(lines, fmt.Sprintf("- %s (branch protection)", rule))
+		}
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("The branch %s is governed by the following rules, which may be blocking this change:\n%s", branch, strings.Join(lines, "\n"))
+}
+
+// isRuleViolationStatus reports whether a status code may come from a rule rejecting a merge or push.
+func isRuleViolationStatus(statusCode int) bool {
+	switch statusCode {
+	case http.StatusForbidden, http.StatusMethodNotAllowed, http.StatusConflict, http.StatusUnprocessableEntity:
+		return true
+	}
+	return false
+}
+
+// withBlockingRulesHint appends the rules governing a branch to an error result, if there are any.
+func withBlockingRulesHint(ctx context.Context, client *github.Client, owner, repo, branch string, result *mcp.CallToolResult) *mcp.CallToolResult {
+	if hint := blockingRulesHint(ctx, client, owner, repo, branch); hint != "" {
+		result.Content = append(result.Content, mcp.NewTextContent(hint))
+	}
+	return result
+}
+
+// GetBranchProtection creates a tool to explain the classic branch protection of a branch.
+func GetBranchProtection(getClient GetClientFn, t translations.TranslationHelperFunc) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("get_branch_protection",
+			mcp.WithDescription(t("TOOL_GET_BRANCH_PROTECTION_DESCRIPTION", "Explain the classic branch protection of a branch: required checks, required reviews, signed commits, linear history and who can bypass them. Rulesets are covered by get_effective_rules_for_branch")),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:        t("TOOL_GET_BRANCH_PROTECTION_USER_TITLE", "Get branch protection"),
+				ReadOnlyHint: ToBoolPtr(true),
+			}),
+			mcp.WithString("owner",
+				mcp.Required(),
+				mcp.Description("Repository owner"),
+			),
+			mcp.WithString("repo",
+				mcp.Required(),
+				mcp.Description("Repository name"),
+			),
+			mcp.WithString("branch",
+				mcp.Required(),
+				mcp.Description("Branch name"),
+			),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			owner, err := RequiredParam[string](request, "owner")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			repo, err := RequiredParam[string](request, "repo")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			branch, err := RequiredParam[string](request, "branch")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			client, err := getClient(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get GitHub client: %w", err)
+			}
+
+			protection, resp, err := client.Repositories.GetBranchProtection(ctx, owner, repo, branch)
+			if errors.Is(err, github.ErrBranchNotProtected) {
+				return MarshalledTextResult(BranchProtectionDetails{
+					Branch:  branch,
+					Message: "The branch has no classic branch protection. Rulesets may still apply, see get_effective_rules_for_branch",
+				}), nil
+			}
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx,
+					"failed to get branch protection",
+					resp,
+					err,
+				), nil
+			}
+			defer func() { _ = resp.Body.Close() }()
+
+			return MarshalledTextResult(describeBranchProtection(branch, protection)), nil
+		}
+}
+
+// ListRulesets creates a tool to list the rulesets of a repository.
+func ListRulesets(getClient GetClientFn, t translations.TranslationHelperFunc) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("list_rulesets",
+			mcp.WithDescription(t("TOOL_LIST_RULESETS_DESCRIPTION", "List the rulesets of a GitHub repository, with the refs they target, their rules and bypass actors")),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:        t("TOOL_LIST_RULESETS_USER_TITLE", "List rulesets"),
+				ReadOnlyHint: ToBoolPtr(true),
+			}),
+			mcp.WithString("owner",
+				mcp.Required(),
+				mcp.Description("Repository owner"),
+			),
+			mcp.WithString("repo",
+				mcp.Required(),
+				mcp.Description("Repository name"),
+			),
+			mcp.WithBoolean("includes_parents",
+				mcp.Description("Include the rulesets configured at the organization or enterprise level (default true)"),
+			),
+			WithPagination(),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			owner, err := RequiredParam[string](request, "owner")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			repo, err := RequiredParam[string](request, "repo")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			includesParents, err := OptionalBoolParamWithDefault(request, "includes_parents", true)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			pagination, err := OptionalPaginationParams(request)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			client, err := getClient(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get GitHub client: %w", err)
+			}
+
+			rulesets, resp, err := client.Repositories.GetAllRulesets(ctx, owner, repo, &github.RepositoryListRulesetsOptions{
+				IncludesParents: github.Ptr(includesParents),
+				ListOptions: github.ListOptions{
+					Page:    pagination.Page,
+					PerPage: pagination.PerPage,
+				},
+			})
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx,
+					"failed to list rulesets",
+					resp,
+					err,
+				), nil
+			}
+			defer func() { _ = resp.Body.Close() }()
+
+			// The list doesn't include the rules and bypass actors, so each ruleset is fetched
+			summaries := make([]RulesetSummary, 0, len(rulesets))
+			for _, ruleset := range rulesets {
+				full, resp, err := client.Repositories.GetRuleset(ctx, owner, repo, ruleset.GetID(), true)
+				if err != nil {
+					return ghErrors.NewGitHubAPIErrorResponse(ctx,
+						fmt.Sprintf("failed to get ruleset %d", ruleset.GetID()),
+						resp,
+						err,
+					), nil
+				}
+				_ = resp.Body.Close()
+				summaries = append(summaries, summarizeRuleset(full))
+			}
+
+			return MarshalledTextResult(summaries), nil
+		}
+}
+
+// GetEffectiveRulesForBranch creates a tool to explain all the rules that apply to a branch.
+func GetEffectiveRulesForBranch(getClient GetClientFn, t translations.TranslationHelperFunc) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("get_effective_rules_for_branch",
+			mcp.WithDescription(t("TOOL_GET_EFFECTIVE_RULES_FOR_BRANCH_DESCRIPTION", "Explain every rule that applies to a branch, from the repository, organization and enterprise rulesets as well as the classic branch protection. Use this to understand why a push or merge is rejected")),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:        t("TOOL_GET_EFFECTIVE_RULES_FOR_BRANCH_USER_TITLE", "Get effective rules for branch"),
+				ReadOnlyHint: ToBoolPtr(true),
+			}),
+			mcp.WithString("owner",
+				mcp.Required(),
+				mcp.Description("Repository owner"),
+			),
+			mcp.WithString("repo",
+				mcp.Required(),
+				mcp.Description("Repository name"),
+			),
+			mcp.WithString("branch",
+				mcp.Required(),
+				mcp.Description("Branch name"),
+			),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			owner, err := RequiredParam[string](request, "owner")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			repo, err := RequiredParam[string](request, "repo")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			branch, err := RequiredParam[string](request, "branch")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			client, err := getClient(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get GitHub client: %w", err)
+			}
+
+			rules, resp, err := getBranchRules(ctx, client, owner, repo, branch)
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx,
+					"failed to get rules for branch",
+					resp,
+					err,
+				), nil
+			}
+
+			result := EffectiveBranchRules{Branch: branch, Rules: make([]EffectiveRule, 0, len(rules))}
+			seen := make(map[int64]bool)
+			for _, rule := range rules {
+				result.Rules = append(result.Rules, EffectiveRule{
+					Type:          rule.Type,
+					Description:   describeRule(rule.rulesetRule),
+					RulesetID:     rule.RulesetID,
+					RulesetSource: rule.RulesetSource,
+				})
+				if seen[rule.RulesetID] {
+					continue
+				}
+				seen[rule.RulesetID] = true
+				// The bypass actors are only visible to the users who can manage the ruleset, so this is best effort
+				ruleset, resp, err := client.Repositories.GetRuleset(ctx, owner, repo, rule.RulesetID, true)
+				if err != nil {
+					continue
+				}
+				_ = resp.Body.Close()
+				result.Rulesets = append(result.Rulesets, summarizeRuleset(ruleset))
+			}
+
+			// Reading the classic branch protection requires admin access, so it is best effort too
+			if protection, resp, err := client.Repositories.GetBranchProtection(ctx, owner, repo, branch); err == nil {
+				_ = resp.Body.Close()
+				details := describeBranchProtection(branch, protection)
+				result.BranchProtection = &details
+			}
+
+			return MarshalledTextResult(result), nil
+		}
+}
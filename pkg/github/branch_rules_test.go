@@ -0,0 +1,270 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/github/github-mcp-server/internal/toolsnaps"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/google/go-github/v77/github"
+	"github.com/migueleliasweb/go-github-mock/src/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_GetBranchProtection(t *testing.T) {
+	tool, _ := GetBranchProtection(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+	assert.True(t, *tool.Annotations.ReadOnlyHint)
+	assert.ElementsMatch(t, tool.InputSchema.Required, []string{"owner", "repo", "branch"})
+
+	tests := []struct {
+		name            string
+		mockedClient    *http.Client
+		expectError     string
+		expectedDetails BranchProtectionDetails
+	}{
+		{
+			name: "protected branch",
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(
+					mock.GetReposBranchesProtectionByOwnerByRepoByBranch,
+					expectPath(t, "/repos/owner/repo/branches/main/protection").andThen(
+						mockResponse(t, http.StatusOK, &github.Protection{
+							RequiredStatusChecks: &github.RequiredStatusChecks{
+								Strict: true,
+								Checks: &[]*github.RequiredStatusCheck{{Context: "build"}, {Context: "lint"}},
+							},
+							RequiredPullRequestReviews: &github.PullRequestReviewsEnforcement{
+								RequiredApprovingReviewCount: 2,
+								RequireCodeOwnerReviews:      true,
+								BypassPullRequestAllowances: &github.BypassPullRequestAllowances{
+									Users: []*github.User{{Login: github.Ptr("mona")}},
+									Teams: []*github.Team{{Slug: github.Ptr("release")}},
+								},
+							},
+							EnforceAdmins:        &github.AdminEnforcement{Enabled: false},
+							RequireLinearHistory: &github.RequireLinearHistory{Enabled: true},
+							RequiredSignatures:   &github.SignaturesProtectedBranch{Enabled: github.Ptr(true)},
+							AllowForcePushes:     &github.AllowForcePushes{Enabled: false},
+							AllowDeletions:       &github.AllowDeletions{Enabled: true},
+						}),
+					),
+				),
+			),
+			expectedDetails: BranchProtectionDetails{
+				Branch:    "main",
+				Protected: true,
+				Rules: []string{
+					"Changes must go through a pull request with 2 approving review(s); code owner review required",
+					"Status checks must pass: build, lint (branch must be up to date)",
+					"Commits must be signed",
+					"Linear history is required (no merge commits)",
+					"Force pushes are blocked",
+				},
+				BypassActors: []string{
+					"User mona (pull request requirements)",
+					"Team release (pull request requirements)",
+					"Repository administrators (always)",
+				},
+			},
+		},
+		{
+			name: "unprotected branch",
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(
+					mock.GetReposBranchesProtectionByOwnerByRepoByBranch,
+					mockResponse(t, http.StatusNotFound, map[string]string{"message": "Branch not protected"}),
+				),
+			),
+			expectedDetails: BranchProtectionDetails{
+				Branch:  "main",
+				Message: "The branch has no classic branch protection. Rulesets may still apply, see get_effective_rules_for_branch",
+			},
+		},
+		{
+			name: "missing branch",
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(
+					mock.GetReposBranchesProtectionByOwnerByRepoByBranch,
+					mockResponse(t, http.StatusNotFound, map[string]string{"message": "Branch not found"}),
+				),
+			),
+			expectError: "failed to get branch protection",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, handler := GetBranchProtection(stubGetClientFn(github.NewClient(tc.mockedClient)), translations.NullTranslationHelper)
+			result, err := handler(context.Background(), createMCPRequest(map[string]any{
+				"owner":  "owner",
+				"repo":   "repo",
+				"branch": "main",
+			}))
+			require.NoError(t, err)
+
+			if tc.expectError != "" {
+				assert.Contains(t, getErrorResult(t, result).Text, tc.expectError)
+				return
+			}
+			var details BranchProtectionDetails
+			require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &details))
+			assert.Equal(t, tc.expectedDetails, details)
+		})
+	}
+}
+
+func Test_ListRulesets(t *testing.T) {
+	tool, _ := ListRulesets(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+	assert.True(t, *tool.Annotations.ReadOnlyHint)
+	assert.ElementsMatch(t, tool.InputSchema.Required, []string{"owner", "repo"})
+
+	mockedClient := mock.NewMockedHTTPClient(
+		mock.WithRequestMatchHandler(
+			mock.GetReposRulesetsByOwnerByRepo,
+			expectQueryParams(t, map[string]string{"includes_parents": "true", "page": "1", "per_page": "30"}).andThen(
+				mockResponse(t, http.StatusOK, []map[string]any{
+					{"id": 1, "name": "main", "source": "owner/repo", "enforcement": "active"},
+				}),
+			),
+		),
+		mock.WithRequestMatchHandler(
+			mock.GetReposRulesetsByOwnerByRepoByRulesetId,
+			expectPath(t, "/repos/owner/repo/rulesets/1").andThen(
+				mockResponse(t, http.StatusOK, map[string]any{
+					"id":                      1,
+					"name":                    "main",
+					"target":                  "branch",
+					"source_type":             "Repository",
+					"source":                  "owner/repo",
+					"enforcement":             "active",
+					"current_user_can_bypass": "never",
+					"bypass_actors": []map[string]any{
+						{"actor_id": 5, "actor_type": "RepositoryRole", "bypass_mode": "always"},
+						{"actor_type": "OrganizationAdmin", "bypass_mode": "pull_request"},
+					},
+					"conditions": map[string]any{
+						"ref_name": map[string]any{"include": []string{"~DEFAULT_BRANCH"}, "exclude": []string{}},
+					},
+					"rules": []map[string]any{
+						{"type": "deletion"},
+						{"type": "non_fast_forward"},
+						{"type": "pull_request", "parameters": map[string]any{
+							"required_approving_review_count":   1,
+							"require_last_push_approval":        true,
+							"dismiss_stale_reviews_on_push":     false,
+							"require_code_owner_review":         false,
+							"required_review_thread_resolution": true,
+							"allowed_merge_methods":             []string{"squash"},
+						}},
+						{"type": "commit_message_pattern", "parameters": map[string]any{
+							"operator": "starts_with", "pattern": "[", "negate": false,
+						}},
+					},
+				}),
+			),
+		),
+	)
+	_, handler := ListRulesets(stubGetClientFn(github.NewClient(mockedClient)), translations.NullTranslationHelper)
+	result, err := handler(context.Background(), createMCPRequest(map[string]any{
+		"owner": "owner",
+		"repo":  "repo",
+	}))
+	require.NoError(t, err)
+
+	var rulesets []RulesetSummary
+	require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &rulesets))
+	assert.Equal(t, []RulesetSummary{{
+		ID:                   1,
+		Name:                 "main",
+		Target:               "branch",
+		Enforcement:          "active",
+		Source:               "owner/repo",
+		SourceType:           "Repository",
+		Include:              []string{"~DEFAULT_BRANCH"},
+		BypassActors:         []string{"RepositoryRole 5 (always)", "OrganizationAdmin (pull_request)"},
+		CurrentUserCanBypass: "never",
+		Rules: []string{
+			"Matching refs can't be deleted",
+			"Changes must go through a pull request with 1 approving review(s); the last push must be approved by someone else; conversations must be resolved; allowed merge methods: squash",
+			"Force pushes are blocked",
+			`Commit messages must start with "["`,
+		},
+	}}, rulesets)
+}
+
+func Test_GetEffectiveRulesForBranch(t *testing.T) {
+	tool, _ := GetEffectiveRulesForBranch(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+	assert.True(t, *tool.Annotations.ReadOnlyHint)
+	assert.ElementsMatch(t, tool.InputSchema.Required, []string{"owner", "repo", "branch"})
+
+	mockedClient := mock.NewMockedHTTPClient(
+		mock.WithRequestMatchHandler(
+			mock.GetReposRulesBranchesByOwnerByRepoByBranch,
+			// The branch name is escaped and the rules are served over two pages
+			http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/repos/owner/repo/rules/branches/release#1", r.URL.Path)
+				if r.URL.Query().Get("page") != "2" {
+					w.Header().Set("Link", `<https://api.github.com/repos/owner/repo/rules/branches/release%231?per_page=100&page=2>; rel="next"`)
+					mockResponse(t, http.StatusOK, []map[string]any{
+						{"type": "required_status_checks", "ruleset_source_type": "Repository", "ruleset_source": "owner/repo", "ruleset_id": 1, "parameters": map[string]any{
+							"strict_required_status_checks_policy": false,
+							"required_status_checks":               []map[string]any{{"context": "ci"}},
+						}},
+						{"type": "required_linear_history", "ruleset_source_type": "Repository", "ruleset_source": "owner/repo", "ruleset_id": 1},
+					})(w, r)
+					return
+				}
+				mockResponse(t, http.StatusOK, []map[string]any{
+					{"type": "required_deployments", "ruleset_source_type": "Organization", "ruleset_source": "owner", "ruleset_id": 2, "parameters": map[string]any{
+						"required_deployment_environments": []string{"staging"},
+					}},
+				})(w, r)
+			}),
+		),
+		mock.WithRequestMatchHandler(
+			mock.GetReposRulesetsByOwnerByRepoByRulesetId,
+			http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				// The organization ruleset isn't visible to the user
+				if r.URL.Path != "/repos/owner/repo/rulesets/1" {
+					mockResponse(t, http.StatusNotFound, map[string]string{"message": "Not Found"})(w, r)
+					return
+				}
+				mockResponse(t, http.StatusOK, map[string]any{
+					"id":          1,
+					"name":        "ci",
+					"source":      "owner/repo",
+					"enforcement": "active",
+				})(w, r)
+			}),
+		),
+		mock.WithRequestMatchHandler(
+			mock.GetReposBranchesProtectionByOwnerByRepoByBranch,
+			mockResponse(t, http.StatusNotFound, map[string]string{"message": "Branch not protected"}),
+		),
+	)
+	_, handler := GetEffectiveRulesForBranch(stubGetClientFn(github.NewClient(mockedClient)), translations.NullTranslationHelper)
+	result, err := handler(context.Background(), createMCPRequest(map[string]any{
+		"owner":  "owner",
+		"repo":   "repo",
+		"branch": "release#1",
+	}))
+	require.NoError(t, err)
+
+	var rules EffectiveBranchRules
+	require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &rules))
+	assert.Equal(t, EffectiveBranchRules{
+		Branch: "release#1",
+		Rules: []EffectiveRule{
+			{Type: "required_status_checks", Description: "Status checks must pass: ci", RulesetID: 1, RulesetSource: "owner/repo"},
+			{Type: "required_linear_history", Description: "Linear history is required (no merge commits)", RulesetID: 1, RulesetSource: "owner/repo"},
+			{Type: "required_deployments", Description: "Deployments must succeed to: staging", RulesetID: 2, RulesetSource: "owner"},
+		},
+		Rulesets: []RulesetSummary{{ID: 1, Name: "ci", Enforcement: "active", Source: "owner/repo"}},
+	}, rules)
+}
@@ -0,0 +1,465 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	ghErrors "github.com/github/github-mcp-server/pkg/errors"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/google/go-github/v77/github"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// BranchConflict describes why two branches can't be merged automatically.
+type BranchConflict struct {
+	Base         string `json:"base"`
+	Head         string `json:"head"`
+	MergeBaseSHA string `json:"merge_base_sha,omitempty"`
+	// BaseAheadBy and HeadAheadBy count the commits made on each side since the merge base.
+	BaseAheadBy int `json:"base_ahead_by"`
+	HeadAheadBy int `json:"head_ahead_by"`
+	// ConflictingFiles are the files changed on both sides since the merge base, the only ones that can conflict.
+	ConflictingFiles []string `json:"conflicting_files"`
+}
+
+// MergeBranchResult is the outcome of merging a branch into another.
+type MergeBranchResult struct {
+	Merged   bool            `json:"merged"`
+	SHA      string          `json:"sha,omitempty"`
+	HTMLURL  string          `json:"html_url,omitempty"`
+	Message  string          `json:"message"`
+	Conflict *BranchConflict `json:"conflict,omitempty"`
+}
+
+// SyncForkResult is the outcome of syncing a branch of a fork with its upstream repository.
+type SyncForkResult struct {
+	Synced     bool            `json:"synced"`
+	MergeType  string          `json:"merge_type,omitempty"`
+	BaseBranch string          `json:"base_branch,omitempty"`
+	Message    string          `json:"message"`
+	Conflict   *BranchConflict `json:"conflict,omitempty"`
+}
+
+// changedFiles returns the names of the files changed in a comparison, including the names they were renamed from.
+func changedFiles(comparison *github.CommitsComparison) map[string]bool {
+	files := make(map[string]bool, len(comparison.Files))
+	for _, f := range comparison.Files {
+		files[f.GetFilename()] = true
+		if f.GetPreviousFilename() != "" {
+			files[f.GetPreviousFilename()] = true
+		}
+	}
+	return files
+}
+
+// branchConflict compares base and head both ways to find the files changed on both sides since their merge base.
+// Either may be a branch of another repository of the network, as owner:branch.
+func branchConflict(ctx context.Context, client *github.Client, owner, repo, base, head string) (*BranchConflict, error) {
+	headChanges, resp, err := client.Repositories.CompareCommits(ctx, owner, repo, base, head, nil)
+	if err != nil {
+		_, _ = ghErrors.NewGitHubAPIErrorToCtx(ctx, "failed to compare branches", resp, err)
+		return nil, fmt.Errorf("failed to compare %s with %s: %w", base, head, err)
+	}
+	_ = resp.Body.Close()
+	baseChanges, resp, err := client.Repositories.CompareCommits(ctx, owner, repo, head, base, nil)
+	if err != nil {
+		_, _ = ghErrors.NewGitHubAPIErrorToCtx(ctx, "failed to compare branches", resp, err)
+		return nil, fmt.Errorf("failed to compare %s with %s: %w", head, base, err)
+	}
+	_ = resp.Body.Close()
+
+	conflict := &BranchConflict{
+		Base:             base,
+		Head:             head,
+		MergeBaseSHA:     headChanges.GetMergeBaseCommit().GetSHA(),
+		BaseAheadBy:      headChanges.GetBehindBy(),
+		HeadAheadBy:      headChanges.GetAheadBy(),
+		ConflictingFiles: []string{},
+	}
+	baseFiles := changedFiles(baseChanges)
+	for name := range changedFiles(headChanges) {
+		if baseFiles[name] {
+			conflict.ConflictingFiles = append(conflict.ConflictingFiles, name)
+		}
+	}
+	sort.Strings(conflict.ConflictingFiles)
+	return conflict, nil
+}
+
+// escapeBranchPath escapes a branch name for use in a URL path, keeping the slashes between its parts.
+func escapeBranchPath(branch string) string {
+	parts := strings.Split(branch, "/")
+	for i, part := range parts {
+		parts[i] = url.PathEscape(part)
+	}
+	return strings.Join(parts, "/")
+}
+
+// branchDeletionRule returns the ruleset rule that restricts the deletion of a branch, or nil if there is none.
+func branchDeletionRule(ctx context.Context, client *github.Client, owner, repo, branch string) (*github.BranchRuleMetadata, *github.Response, error) {
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		rules, resp, err := client.Repositories.GetRulesForBranch(ctx, owner, repo, escapeBranchPath(branch), opts)
+		if err != nil {
+			return nil, resp, err
+		}
+		_ = resp.Body.Close()
+		if rules != nil && len(rules.Deletion) > 0 {
+			return rules.Deletion[0], resp, nil
+		}
+		if resp.NextPage == 0 {
+			return nil, resp, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+// DeleteBranch creates a tool to delete a branch, refusing to delete the default branch, a protected branch
+// or a branch whose deletion a ruleset restricts.
+func DeleteBranch(getClient GetClientFn, t translations.TranslationHelperFunc) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("delete_branch",
+			mcp.WithDescription(t("TOOL_DELETE_BRANCH_DESCRIPTION", "Delete a branch of a GitHub repository. The default branch, protected branches and branches whose deletion a ruleset restricts can't be deleted")),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:           t("TOOL_DELETE_BRANCH_USER_TITLE", "Delete branch"),
+				ReadOnlyHint:    ToBoolPtr(false),
+				DestructiveHint: ToBoolPtr(true),
+			}),
+			mcp.WithString("owner",
+				mcp.Required(),
+				mcp.Description("Repository owner"),
+			),
+			mcp.WithString("repo",
+				mcp.Required(),
+				mcp.Description("Repository name"),
+			),
+			mcp.WithString("branch",
+				mcp.Required(),
+				mcp.Description("Branch to delete"),
+			),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			owner, err := RequiredParam[string](request, "owner")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			repo, err := RequiredParam[string](request, "repo")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			branch, err := RequiredParam[string](request, "branch")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			client, err := getClient(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get GitHub client: %w", err)
+			}
+
+			repository, resp, err := client.Repositories.Get(ctx, owner, repo)
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx,
+					"failed to get repository",
+					resp,
+					err,
+				), nil
+			}
+			_ = resp.Body.Close()
+			if repository.GetDefaultBranch() == branch {
+				return mcp.NewToolResultError(fmt.Sprintf("refusing to delete %s: it is the default branch of %s/%s", branch, owner, repo)), nil
+			}
+
+			b, resp, err := client.Repositories.GetBranch(ctx, owner, repo, branch, 0)
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx,
+					"failed to get branch",
+					resp,
+					err,
+				), nil
+			}
+			_ = resp.Body.Close()
+			if b.GetProtected() {
+				return mcp.NewToolResultError(fmt.Sprintf("refusing to delete %s: it is a protected branch", branch)), nil
+			}
+
+			// Rulesets don't make a branch protected, so their deletion rules are checked separately
+			rule, resp, err := branchDeletionRule(ctx, client, owner, repo, branch)
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx,
+					"failed to get rules for branch",
+					resp,
+					err,
+				), nil
+			}
+			if rule != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("refusing to delete %s: ruleset %d from %s restricts its deletion", branch, rule.RulesetID, rule.RulesetSource)), nil
+			}
+
+			resp, err = client.Git.DeleteRef(ctx, owner, repo, "heads/"+branch)
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx,
+					"failed to delete branch",
+					resp,
+					err,
+				), nil
+			}
+			defer func() { _ = resp.Body.Close() }()
+
+			return mcp.NewToolResultText(fmt.Sprintf("Deleted branch %s", branch)), nil
+		}
+}
+
+// RenameBranch creates a tool to rename a branch.
+func RenameBranch(getClient GetClientFn, t translations.TranslationHelperFunc) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("rename_branch",
+			mcp.WithDescription(t("TOOL_RENAME_BRANCH_DESCRIPTION", "Rename a branch of a GitHub repository. Open pull requests and branch protection rules follow the branch")),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:        t("TOOL_RENAME_BRANCH_USER_TITLE", "Rename branch"),
+				ReadOnlyHint: ToBoolPtr(false),
+			}),
+			mcp.WithString("owner",
+				mcp.Required(),
+				mcp.Description("Repository owner"),
+			),
+			mcp.WithString("repo",
+				mcp.Required(),
+				mcp.Description("Repository name"),
+			),
+			mcp.WithString("branch",
+				mcp.Required(),
+				mcp.Description("Branch to rename"),
+			),
+			mcp.WithString("new_name",
+				mcp.Required(),
+				mcp.Description("New name of the branch"),
+			),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			owner, err := RequiredParam[string](request, "owner")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			repo, err := RequiredParam[string](request, "repo")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			branch, err := RequiredParam[string](request, "branch")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			newName, err := RequiredParam[string](request, "new_name")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			client, err := getClient(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get GitHub client: %w", err)
+			}
+			renamed, resp, err := client.Repositories.RenameBranch(ctx, owner, repo, branch, newName)
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx,
+					"failed to rename branch",
+					resp,
+					err,
+				), nil
+			}
+			defer func() { _ = resp.Body.Close() }()
+
+			return MarshalledTextResult(convertToMinimalBranch(renamed)), nil
+		}
+}
+
+// MergeBranch creates a tool to merge a branch into another with the repository merges API.
+func MergeBranch(getClient GetClientFn, t translations.TranslationHelperFunc) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("merge_branch",
+			mcp.WithDescription(t("TOOL_MERGE_BRANCH_DESCRIPTION", "Merge a branch, tag or commit (head) into a branch (base) of a GitHub repository with a merge commit, without opening a pull request. When the merge conflicts, nothing is changed and the result lists the files changed on both sides since the merge base")),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:        t("TOOL_MERGE_BRANCH_USER_TITLE", "Merge branch"),
+				ReadOnlyHint: ToBoolPtr(false),
+			}),
+			mcp.WithString("owner",
+				mcp.Required(),
+				mcp.Description("Repository owner"),
+			),
+			mcp.WithString("repo",
+				mcp.Required(),
+				mcp.Description("Repository name"),
+			),
+			mcp.WithString("base",
+				mcp.Required(),
+				mcp.Description("Branch to merge into"),
+			),
+			mcp.WithString("head",
+				mcp.Required(),
+				mcp.Description("Branch, tag or commit SHA to merge"),
+			),
+			mcp.WithString("commit_message",
+				mcp.Description("Message of the merge commit (defaults to a generated one)"),
+			),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			owner, err := RequiredParam[string](request, "owner")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			repo, err := RequiredParam[string](request, "repo")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			base, err := RequiredParam[string](request, "base")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			head, err := RequiredParam[string](request, "head")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			commitMessage, err := OptionalParam[string](request, "commit_message")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			client, err := getClient(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get GitHub client: %w", err)
+			}
+
+			mergeRequest := &github.RepositoryMergeRequest{
+				Base: github.Ptr(base),
+				Head: github.Ptr(head),
+			}
+			if commitMessage != "" {
+				mergeRequest.CommitMessage = github.Ptr(commitMessage)
+			}
+			commit, resp, err := client.Repositories.Merge(ctx, owner, repo, mergeRequest)
+			if resp != nil && resp.StatusCode == http.StatusConflict {
+				_ = resp.Body.Close()
+				conflict, err := branchConflict(ctx, client, owner, repo, base, head)
+				if err != nil {
+					return mcp.NewToolResultError(err.Error()), nil
+				}
+				return MarshalledTextResult(MergeBranchResult{
+					Merged:   false,
+					Message:  fmt.Sprintf("merging %s into %s conflicts, nothing was changed", head, base),
+					Conflict: conflict,
+				}), nil
+			}
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx,
+					"failed to merge branch",
+					resp,
+					err,
+				), nil
+			}
+			defer func() { _ = resp.Body.Close() }()
+
+			if resp.StatusCode == http.StatusNoContent {
+				return MarshalledTextResult(MergeBranchResult{
+					Merged:  false,
+					Message: fmt.Sprintf("%s already contains %s, nothing to merge", base, head),
+				}), nil
+			}
+			return MarshalledTextResult(MergeBranchResult{
+				Merged:  true,
+				SHA:     commit.GetSHA(),
+				HTMLURL: commit.GetHTMLURL(),
+				Message: fmt.Sprintf("merged %s into %s", head, base),
+			}), nil
+		}
+}
+
+// SyncForkBranch creates a tool to sync a branch of a fork with the same branch of its upstream repository.
+func SyncForkBranch(getClient GetClientFn, t translations.TranslationHelperFunc) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("sync_fork_branch",
+			mcp.WithDescription(t("TOOL_SYNC_FORK_BRANCH_DESCRIPTION", "Sync a branch of a fork with the same branch of its upstream repository, fast-forwarding or merging the upstream changes. When the branches conflict, nothing is changed and the result lists the files changed on both sides since the merge base")),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:        t("TOOL_SYNC_FORK_BRANCH_USER_TITLE", "Sync fork branch"),
+				ReadOnlyHint: ToBoolPtr(false),
+			}),
+			mcp.WithString("owner",
+				mcp.Required(),
+				mcp.Description("Owner of the fork"),
+			),
+			mcp.WithString("repo",
+				mcp.Required(),
+				mcp.Description("Name of the fork"),
+			),
+			mcp.WithString("branch",
+				mcp.Description("Branch to sync (defaults to the default branch of the fork)"),
+			),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			owner, err := RequiredParam[string](request, "owner")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			repo, err := RequiredParam[string](request, "repo")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			branch, err := OptionalParam[string](request, "branch")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			client, err := getClient(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get GitHub client: %w", err)
+			}
+
+			repository, resp, err := client.Repositories.Get(ctx, owner, repo)
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx,
+					"failed to get repository",
+					resp,
+					err,
+				), nil
+			}
+			_ = resp.Body.Close()
+			if !repository.GetFork() || repository.GetParent() == nil {
+				return mcp.NewToolResultError(fmt.Sprintf("%s/%s is not a fork", owner, repo)), nil
+			}
+			if branch == "" {
+				branch = repository.GetDefaultBranch()
+			}
+
+			result, resp, err := client.Repositories.MergeUpstream(ctx, owner, repo, &github.RepoMergeUpstreamRequest{
+				Branch: github.Ptr(branch),
+			})
+			if resp != nil && resp.StatusCode == http.StatusConflict {
+				_ = resp.Body.Close()
+				// The owner:repo:branch form names the upstream repository even when its owner has other forks
+				upstream := strings.Replace(repository.GetParent().GetFullName(), "/", ":", 1) + ":" + branch
+				conflict, err := branchConflict(ctx, client, owner, repo, branch, upstream)
+				if err != nil {
+					return mcp.NewToolResultError(err.Error()), nil
+				}
+				return MarshalledTextResult(SyncForkResult{
+					Synced:   false,
+					Message:  fmt.Sprintf("%s conflicts with %s, nothing was changed", branch, upstream),
+					Conflict: conflict,
+				}), nil
+			}
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx,
+					"failed to sync fork branch",
+					resp,
+					err,
+				), nil
+			}
+			defer func() { _ = resp.Body.Close() }()
+
+			return MarshalledTextResult(SyncForkResult{
+				Synced:     true,
+				MergeType:  result.GetMergeType(),
+				BaseBranch: result.GetBaseBranch(),
+				Message:    result.GetMessage(),
+			}), nil
+		}
+}
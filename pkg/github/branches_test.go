@@ -0,0 +1,380 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/github/github-mcp-server/internal/toolsnaps"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/google/go-github/v77/github"
+	"github.com/migueleliasweb/go-github-mock/src/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockCompareBranches serves comparisons of main with feature: both changed a.go and renamed b.go, only feature changed c.go.
+func mockCompareBranches(t *testing.T, basehead map[string]string) mock.MockBackendOption {
+	return mock.WithRequestMatchHandler(
+		mock.GetReposCompareByOwnerByRepoByBasehead,
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			comparisons := map[string]*github.CommitsComparison{
+				basehead["base...head"]: {
+					AheadBy:         github.Ptr(2),
+					BehindBy:        github.Ptr(1),
+					MergeBaseCommit: &github.RepositoryCommit{SHA: github.Ptr("mergebase")},
+					Files: []*github.CommitFile{
+						{Filename: github.Ptr("a.go")},
+						{Filename: github.Ptr("b2.go"), PreviousFilename: github.Ptr("b.go")},
+						{Filename: github.Ptr("c.go")},
+					},
+				},
+				basehead["head...base"]: {
+					AheadBy:         github.Ptr(1),
+					BehindBy:        github.Ptr(2),
+					MergeBaseCommit: &github.RepositoryCommit{SHA: github.Ptr("mergebase")},
+					Files: []*github.CommitFile{
+						{Filename: github.Ptr("a.go")},
+						{Filename: github.Ptr("b.go")},
+					},
+				},
+			}
+			comparison, ok := comparisons[r.URL.Path]
+			if !assert.True(t, ok, "unexpected comparison %s", r.URL.Path) {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			data, _ := json.Marshal(comparison)
+			_, _ = w.Write(data)
+		}),
+	)
+}
+
+func Test_DeleteBranch(t *testing.T) {
+	tool, _ := DeleteBranch(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+	assert.True(t, *tool.Annotations.DestructiveHint)
+	assert.ElementsMatch(t, tool.InputSchema.Required, []string{"owner", "repo", "branch"})
+
+	getRepo := func() mock.MockBackendOption {
+		return mock.WithRequestMatch(
+			mock.GetReposByOwnerByRepo,
+			&github.Repository{DefaultBranch: github.Ptr("main")},
+		)
+	}
+	tests := []struct {
+		name         string
+		mockedClient *http.Client
+		branch       string
+		expectError  string
+		expectedText string
+	}{
+		{
+			name: "deletes a branch",
+			mockedClient: mock.NewMockedHTTPClient(
+				getRepo(),
+				mock.WithRequestMatch(
+					mock.GetReposBranchesByOwnerByRepoByBranch,
+					&github.Branch{Name: github.Ptr("feature"), Protected: github.Ptr(false)},
+				),
+				mock.WithRequestMatchHandler(
+					mock.GetReposRulesBranchesByOwnerByRepoByBranch,
+					expectPath(t, "/repos/owner/repo/rules/branches/feature").andThen(
+						mockResponse(t, http.StatusOK, []any{
+							map[string]any{"type": "non_fast_forward", "ruleset_source_type": "Repository", "ruleset_source": "owner/repo", "ruleset_id": 7},
+						}),
+					),
+				),
+				mock.WithRequestMatchHandler(
+					mock.DeleteReposGitRefsByOwnerByRepoByRef,
+					expectPath(t, "/repos/owner/repo/git/refs/heads/feature").andThen(
+						func(w http.ResponseWriter, _ *http.Request) {
+							w.WriteHeader(http.StatusNoContent)
+						},
+					),
+				),
+			),
+			branch:       "feature",
+			expectedText: "Deleted branch feature",
+		},
+		{
+			name:         "refuses the default branch",
+			mockedClient: mock.NewMockedHTTPClient(getRepo()),
+			branch:       "main",
+			expectError:  "refusing to delete main: it is the default branch of owner/repo",
+		},
+		{
+			name: "refuses a protected branch",
+			mockedClient: mock.NewMockedHTTPClient(
+				getRepo(),
+				mock.WithRequestMatch(
+					mock.GetReposBranchesByOwnerByRepoByBranch,
+					&github.Branch{Name: github.Ptr("release"), Protected: github.Ptr(true)},
+				),
+			),
+			branch:      "release",
+			expectError: "refusing to delete release: it is a protected branch",
+		},
+		{
+			name: "refuses a branch whose deletion a ruleset restricts",
+			mockedClient: mock.NewMockedHTTPClient(
+				getRepo(),
+				mock.WithRequestMatch(
+					mock.GetReposBranchesByOwnerByRepoByBranch,
+					&github.Branch{Name: github.Ptr("release"), Protected: github.Ptr(false)},
+				),
+				mock.WithRequestMatchHandler(
+					mock.GetReposRulesBranchesByOwnerByRepoByBranch,
+					mockResponse(t, http.StatusOK, []any{
+						map[string]any{"type": "deletion", "ruleset_source_type": "Organization", "ruleset_source": "owner", "ruleset_id": 42},
+					}),
+				),
+			),
+			branch:      "release",
+			expectError: "refusing to delete release: ruleset 42 from owner restricts its deletion",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, handler := DeleteBranch(stubGetClientFn(github.NewClient(tc.mockedClient)), translations.NullTranslationHelper)
+			result, err := handler(context.Background(), createMCPRequest(map[string]any{
+				"owner":  "owner",
+				"repo":   "repo",
+				"branch": tc.branch,
+			}))
+			require.NoError(t, err)
+
+			if tc.expectError != "" {
+				assert.Equal(t, tc.expectError, getErrorResult(t, result).Text)
+				return
+			}
+			assert.Equal(t, tc.expectedText, getTextResult(t, result).Text)
+		})
+	}
+}
+
+func Test_RenameBranch(t *testing.T) {
+	tool, _ := RenameBranch(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+	assert.ElementsMatch(t, tool.InputSchema.Required, []string{"owner", "repo", "branch", "new_name"})
+
+	mockedClient := mock.NewMockedHTTPClient(
+		mock.WithRequestMatchHandler(
+			mock.PostReposBranchesRenameByOwnerByRepoByBranch,
+			expect(t, expectations{
+				path:        "/repos/owner/repo/branches/old/rename",
+				requestBody: map[string]any{"new_name": "new"},
+			}).andThen(
+				mockResponse(t, http.StatusCreated, &github.Branch{
+					Name:   github.Ptr("new"),
+					Commit: &github.RepositoryCommit{SHA: github.Ptr("abc123")},
+				}),
+			),
+		),
+	)
+	_, handler := RenameBranch(stubGetClientFn(github.NewClient(mockedClient)), translations.NullTranslationHelper)
+	result, err := handler(context.Background(), createMCPRequest(map[string]any{
+		"owner":    "owner",
+		"repo":     "repo",
+		"branch":   "old",
+		"new_name": "new",
+	}))
+	require.NoError(t, err)
+
+	var branch MinimalBranch
+	require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &branch))
+	assert.Equal(t, "new", branch.Name)
+	assert.Equal(t, "abc123", branch.SHA)
+}
+
+func Test_MergeBranch(t *testing.T) {
+	tool, _ := MergeBranch(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+	assert.ElementsMatch(t, tool.InputSchema.Required, []string{"owner", "repo", "base", "head"})
+
+	tests := []struct {
+		name           string
+		mockedClient   *http.Client
+		expectedResult MergeBranchResult
+	}{
+		{
+			name: "merges",
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(
+					mock.PostReposMergesByOwnerByRepo,
+					expectRequestBody(t, map[string]any{"base": "main", "head": "feature", "commit_message": "Merge feature"}).andThen(
+						mockResponse(t, http.StatusCreated, &github.RepositoryCommit{
+							SHA:     github.Ptr("merged"),
+							HTMLURL: github.Ptr("https://github.com/owner/repo/commit/merged"),
+						}),
+					),
+				),
+			),
+			expectedResult: MergeBranchResult{
+				Merged:  true,
+				SHA:     "merged",
+				HTMLURL: "https://github.com/owner/repo/commit/merged",
+				Message: "merged feature into main",
+			},
+		},
+		{
+			name: "nothing to merge",
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(
+					mock.PostReposMergesByOwnerByRepo,
+					http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+						w.WriteHeader(http.StatusNoContent)
+					}),
+				),
+			),
+			expectedResult: MergeBranchResult{
+				Merged:  false,
+				Message: "main already contains feature, nothing to merge",
+			},
+		},
+		{
+			name: "conflict",
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(
+					mock.PostReposMergesByOwnerByRepo,
+					mockResponse(t, http.StatusConflict, map[string]string{"message": "Merge Conflict"}),
+				),
+				mockCompareBranches(t, map[string]string{
+					"base...head": "/repos/owner/repo/compare/main...feature",
+					"head...base": "/repos/owner/repo/compare/feature...main",
+				}),
+			),
+			expectedResult: MergeBranchResult{
+				Merged:  false,
+				Message: "merging feature into main conflicts, nothing was changed",
+				Conflict: &BranchConflict{
+					Base:             "main",
+					Head:             "feature",
+					MergeBaseSHA:     "mergebase",
+					BaseAheadBy:      1,
+					HeadAheadBy:      2,
+					ConflictingFiles: []string{"a.go", "b.go"},
+				},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, handler := MergeBranch(stubGetClientFn(github.NewClient(tc.mockedClient)), translations.NullTranslationHelper)
+			args := map[string]any{"owner": "owner", "repo": "repo", "base": "main", "head": "feature"}
+			if tc.expectedResult.Merged {
+				args["commit_message"] = "Merge feature"
+			}
+			result, err := handler(context.Background(), createMCPRequest(args))
+			require.NoError(t, err)
+
+			var merge MergeBranchResult
+			require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &merge))
+			assert.Equal(t, tc.expectedResult, merge)
+		})
+	}
+}
+
+func Test_SyncForkBranch(t *testing.T) {
+	tool, _ := SyncForkBranch(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+	assert.ElementsMatch(t, tool.InputSchema.Required, []string{"owner", "repo"})
+
+	fork := func() mock.MockBackendOption {
+		return mock.WithRequestMatch(
+			mock.GetReposByOwnerByRepo,
+			&github.Repository{
+				DefaultBranch: github.Ptr("main"),
+				Fork:          github.Ptr(true),
+				Parent:        &github.Repository{Owner: &github.User{Login: github.Ptr("upstream")}, Name: github.Ptr("upstream-repo"), FullName: github.Ptr("upstream/upstream-repo")},
+			},
+		)
+	}
+	tests := []struct {
+		name           string
+		mockedClient   *http.Client
+		expectError    string
+		expectedResult SyncForkResult
+	}{
+		{
+			name: "syncs the default branch",
+			mockedClient: mock.NewMockedHTTPClient(
+				fork(),
+				mock.WithRequestMatchHandler(
+					mock.PostReposMergeUpstreamByOwnerByRepo,
+					expectRequestBody(t, map[string]any{"branch": "main"}).andThen(
+						mockResponse(t, http.StatusOK, &github.RepoMergeUpstreamResult{
+							Message:    github.Ptr("Successfully fetched and fast-forwarded from upstream upstream:main."),
+							MergeType:  github.Ptr("fast-forward"),
+							BaseBranch: github.Ptr("upstream:main"),
+						}),
+					),
+				),
+			),
+			expectedResult: SyncForkResult{
+				Synced:     true,
+				MergeType:  "fast-forward",
+				BaseBranch: "upstream:main",
+				Message:    "Successfully fetched and fast-forwarded from upstream upstream:main.",
+			},
+		},
+		{
+			name: "conflict",
+			mockedClient: mock.NewMockedHTTPClient(
+				fork(),
+				mock.WithRequestMatchHandler(
+					mock.PostReposMergeUpstreamByOwnerByRepo,
+					mockResponse(t, http.StatusConflict, map[string]string{"message": "There are merge conflicts"}),
+				),
+				mockCompareBranches(t, map[string]string{
+					"base...head": "/repos/owner/repo/compare/main...upstream:upstream-repo:main",
+					"head...base": "/repos/owner/repo/compare/upstream:upstream-repo:main...main",
+				}),
+			),
+			expectedResult: SyncForkResult{
+				Synced:  false,
+				Message: "main conflicts with upstream:upstream-repo:main, nothing was changed",
+				Conflict: &BranchConflict{
+					Base:             "main",
+					Head:             "upstream:upstream-repo:main",
+					MergeBaseSHA:     "mergebase",
+					BaseAheadBy:      1,
+					HeadAheadBy:      2,
+					ConflictingFiles: []string{"a.go", "b.go"},
+				},
+			},
+		},
+		{
+			name: "not a fork",
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatch(
+					mock.GetReposByOwnerByRepo,
+					&github.Repository{DefaultBranch: github.Ptr("main"), Fork: github.Ptr(false)},
+				),
+			),
+			expectError: "owner/repo is not a fork",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, handler := SyncForkBranch(stubGetClientFn(github.NewClient(tc.mockedClient)), translations.NullTranslationHelper)
+			result, err := handler(context.Background(), createMCPRequest(map[string]any{
+				"owner": "owner",
+				"repo":  "repo",
+			}))
+			require.NoError(t, err)
+
+			if tc.expectError != "" {
+				assert.Equal(t, tc.expectError, getErrorResult(t, result).Text)
+				return
+			}
+			var sync SyncForkResult
+			require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &sync))
+			assert.Equal(t, tc.expectedResult, sync)
+		})
+	}
+}
@@ -0,0 +1,430 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+
+	ghErrors "github.com/github/github-mcp-server/pkg/errors"
+	"github.com/github/github-mcp-server/pkg/sanitize"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/google/go-github/v77/github"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/shurcooL/githubv4"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// maxChangelogCommits bounds the commits of a comparison looked up for pull requests.
+	maxChangelogCommits = 1000
+	// changelogCommitsPerQuery is the number of commits whose pull requests are looked up by one GraphQL query.
+	changelogCommitsPerQuery = 100
+)
+
+// releaseConfigPaths are the paths of the file configuring the release notes GitHub generates.
+var releaseConfigPaths = []string{".github/release.yml", ".github/release.yaml"}
+
+// changelogExclusions are the labels and authors of pull requests left out of a changelog or a category.
+type changelogExclusions struct {
+	Labels  []string `yaml:"labels"`
+	Authors []string `yaml:"authors"`
+}
+
+// excludes reports whether a pull request is excluded.
+func (e changelogExclusions) excludes(pr *github.PullRequest) bool {
+	if slices.Contains(e.Authors, pr.GetUser().GetLogin()) {
+		return true
+	}
+	for _, label := range pr.Labels {
+		if slices.Contains(e.Labels, label.GetName()) {
+			return true
+		}
+	}
+	return false
+}
+
+// changelogCategory is a section of a changelog, listing the pull requests with one of its labels.
+// The label "*" matches every pull request.
+type changelogCategory struct {
+	Title   string              `yaml:"title"`
+	Labels  []string            `yaml:"labels"`
+	Exclude changelogExclusions `yaml:"exclude"`
+}
+
+// matches reports whether a pull request belongs to the category.
+func (c changelogCategory) matches(pr *github.PullRequest) bool {
+	if c.Exclude.excludes(pr) {
+		return false
+	}
+	if slices.Contains(c.Labels, "*") {
+		return true
+	}
+	for _, label := range pr.Labels {
+		if slices.Contains(c.Labels, label.GetName()) {
+			return true
+		}
+	}
+	return false
+}
+
+// releaseConfig is the changelog configuration of .github/release.yml.
+type releaseConfig struct {
+	Changelog struct {
+		Exclude    changelogExclusions `yaml:"exclude"`
+		Categories []changelogCategory `yaml:"categories"`
+	} `yaml:"changelog"`
+}
+
+// changelogPullRequestsQuery looks up the pull requests associated with commits, given the node IDs of the commits.
+type changelogPullRequestsQuery struct {
+	Nodes []struct {
+		Commit struct {
+			AssociatedPullRequests struct {
+				Nodes []struct {
+					Number   githubv4.Int
+					Title    githubv4.String
+					URL      githubv4.String
+					MergedAt *githubv4.DateTime
+					Author   struct {
+						Login githubv4.String
+					}
+					Labels struct {
+						Nodes []struct {
+							Name githubv4.String
+						}
+					} `graphql:"labels(first: 100)"`
+				}
+			} `graphql:"associatedPullRequests(first: 10)"`
+		} `graphql:"... on Commit"`
+	} `graphql:"nodes(ids: $ids)"`
+}
+
+// getCommitPullRequests returns the pull requests associated with each commit, in the order of the commits.
+func getCommitPullRequests(ctx context.Context, client *githubv4.Client, commits []*github.RepositoryCommit) ([][]*github.PullRequest, error) {
+	commitPRs := make([][]*github.PullRequest, 0, len(commits))
+	for n := 0; n < len(commits); n += changelogCommitsPerQuery {
+		batch := commits[n:min(n+changelogCommitsPerQuery, len(commits))]
+		ids := make([]githubv4.ID, len(batch))
+		for i, commit := range batch {
+			ids[i] = githubv4.ID(commit.GetNodeID())
+		}
+		var query changelogPullRequestsQuery
+		if err := client.Query(ctx, &query, map[string]any{"ids": ids}); err != nil {
+			return nil, err
+		}
+		for i := range batch {
+			var associated []*github.PullRequest
+			if i < len(query.Nodes) {
+				for _, node := range query.Nodes[i].Commit.AssociatedPullRequests.Nodes {
+					pr := &github.PullRequest{
+						Number:  github.Ptr(int(node.Number)),
+						Title:   github.Ptr(string(node.Title)),
+						HTMLURL: github.Ptr(string(node.URL)),
+						User:    &github.User{Login: github.Ptr(string(node.Author.Login))},
+					}
+					if node.MergedAt != nil {
+						pr.MergedAt = &github.Timestamp{Time: node.MergedAt.Time}
+					}
+					for _, label := range node.Labels.Nodes {
+						pr.Labels = append(pr.Labels, &github.Label{Name: github.Ptr(string(label.Name))})
+					}
+					associated = append(associated, pr)
+				}
+			}
+			commitPRs = append(commitPRs, associated)
+		}
+	}
+	return commitPRs, nil
+}
+
+// getReleaseConfig reads the release notes configuration of a repository at a ref, returning nil if there is none.
+func getReleaseConfig(ctx context.Context, client *github.Client, owner, repo, ref string) (*releaseConfig, error) {
+	for _, path := range releaseConfigPaths {
+		file, _, resp, err := client.Repositories.GetContents(ctx, owner, repo, path, &github.RepositoryContentGetOptions{Ref: ref})
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			continue
+		}
+		if err != nil {
+			_, _ = ghErrors.NewGitHubAPIErrorToCtx(ctx, "failed to get release configuration", resp, err)
+			return nil, fmt.Errorf("failed to get %s: %w", path, err)
+		}
+		_ = resp.Body.Close()
+		if file == nil {
+			continue
+		}
+		content, err := file.GetContent()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %w", path, err)
+		}
+		var config releaseConfig
+		if err := yaml.Unmarshal([]byte(content), &config); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		return &config, nil
+	}
+	return nil, nil
+}
+
+// parseChangelogCategories parses the categories parameter of generate_changelog.
+func parseChangelogCategories(request mcp.CallToolRequest) ([]changelogCategory, error) {
+	raw, ok := request.GetArguments()["categories"]
+	if !ok || raw == nil {
+		return nil, nil
+	}
+	items, ok := raw.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("categories must be an array of objects")
+	}
+	categories := make([]changelogCategory, 0, len(items))
+	for i, item := range items {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("categories[%d] must be an object with title and labels", i)
+		}
+		var category changelogCategory
+		category.Title, _ = m["title"].(string)
+		if category.Title == "" {
+			return nil, fmt.Errorf("categories[%d]: title is required", i)
+		}
+		labels, _ := m["labels"].([]interface{})
+		for _, label := range labels {
+			if s, ok := label.(string); ok {
+				category.Labels = append(category.Labels, s)
+			}
+		}
+		if len(category.Labels) == 0 {
+			return nil, fmt.Errorf("categories[%d]: labels is required", i)
+		}
+		categories = append(categories, category)
+	}
+	return categories, nil
+}
+
+// changelogSection is a titled list of changelog entries.
+type changelogSection struct {
+	title   string
+	entries []string
+}
+
+// writeChangelog renders changelog sections as the markdown of GitHub generated release notes.
+func writeChangelog(sections []changelogSection, compareURL string, truncated bool) string {
+	var b strings.Builder
+	b.WriteString("## What's Changed\n")
+	for _, section := range sections {
+		if len(section.entries) == 0 {
+			continue
+		}
+		if section.title != "" {
+			fmt.Fprintf(&b, "### %s\n", section.title)
+		}
+		for _, entry := range section.entries {
+			fmt.Fprintf(&b, "* %s\n", entry)
+		}
+	}
+	if truncated {
+		fmt.Fprintf(&b, "\n_Only the first %d commits are included._\n", maxChangelogCommits)
+	}
+	fmt.Fprintf(&b, "\n**Full Changelog**: %s\n", compareURL)
+	return b.String()
+}
+
+// GenerateChangelog creates a tool to generate a markdown changelog of the pull requests merged between two refs.
+func GenerateChangelog(getClient GetClientFn, getGQLClient GetGQLClientFn, t translations.TranslationHelperFunc) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("generate_changelog",
+			mcp.WithDescription(t("TOOL_GENERATE_CHANGELOG_DESCRIPTION", "Generate a markdown changelog of the pull requests merged between two refs or tags, with their authors, grouped by label into categories. The categories and exclusions default to those of .github/release.yml. The pull requests are looked up with one GraphQL query per 100 commits, up to the first 1000 commits. The markdown can be used as the body of create_release or in a CHANGELOG file")),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:        t("TOOL_GENERATE_CHANGELOG_USER_TITLE", "Generate changelog"),
+				ReadOnlyHint: ToBoolPtr(true),
+			}),
+			mcp.WithString("owner",
+				mcp.Required(),
+				mcp.Description("Repository owner"),
+			),
+			mcp.WithString("repo",
+				mcp.Required(),
+				mcp.Description("Repository name"),
+			),
+			mcp.WithString("base",
+				mcp.Required(),
+				mcp.Description("Tag, branch or commit SHA of the previous release, whose changes are left out"),
+			),
+			mcp.WithString("head",
+				mcp.Required(),
+				mcp.Description("Tag, branch or commit SHA of the release"),
+			),
+			mcp.WithArray("categories",
+				mcp.Description("Sections of the changelog, in order. A pull request goes into the first category with one of its labels, the label \"*\" matches all of them. Uncategorized pull requests are listed under Other Changes. Defaults to the categories of .github/release.yml at head"),
+				mcp.Items(
+					map[string]interface{}{
+						"type":                 "object",
+						"additionalProperties": false,
+						"required":             []string{"title", "labels"},
+						"properties": map[string]interface{}{
+							"title": map[string]interface{}{
+								"type":        "string",
+								"description": "section title",
+							},
+							"labels": map[string]interface{}{
+								"type":        "array",
+								"items":       map[string]interface{}{"type": "string"},
+								"description": "labels of the pull requests of the section",
+							},
+						},
+					}),
+			),
+			mcp.WithArray("exclude_labels",
+				mcp.Description("Labels of pull requests to leave out. Adds to the exclusions of .github/release.yml"),
+				mcp.Items(map[string]interface{}{"type": "string"}),
+			),
+			mcp.WithBoolean("include_commits",
+				mcp.Description("List the commits made without a pull request (default true)"),
+			),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			owner, err := RequiredParam[string](request, "owner")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			repo, err := RequiredParam[string](request, "repo")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			base, err := RequiredParam[string](request, "base")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			head, err := RequiredParam[string](request, "head")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			categories, err := parseChangelogCategories(request)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			excludeLabels, err := OptionalStringArrayParam(request, "exclude_labels")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			includeCommits, err := OptionalBoolParamWithDefault(request, "include_commits", true)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			client, err := getClient(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get GitHub client: %w", err)
+			}
+
+			gqlClient, err := getGQLClient(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get GitHub GraphQL client: %w", err)
+			}
+
+			// The exclusions of release.yml apply even when the categories are given
+			exclude := changelogExclusions{Labels: excludeLabels}
+			config, err := getReleaseConfig(ctx, client, owner, repo, head)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			if config != nil {
+				exclude.Labels = append(exclude.Labels, config.Changelog.Exclude.Labels...)
+				exclude.Authors = config.Changelog.Exclude.Authors
+				if categories == nil {
+					categories = config.Changelog.Categories
+				}
+			}
+
+			// Collect the commits of the comparison, oldest first
+			var commits []*github.RepositoryCommit
+			var compareURL string
+			truncated := false
+			for page := 1; ; page++ {
+				comparison, resp, err := client.Repositories.CompareCommits(ctx, owner, repo, base, head, &github.ListOptions{Page: page, PerPage: 100})
+				if err != nil {
+					return ghErrors.NewGitHubAPIErrorResponse(ctx,
+						"failed to compare commits",
+						resp,
+						err,
+					), nil
+				}
+				_ = resp.Body.Close()
+				compareURL = comparison.GetHTMLURL()
+				commits = append(commits, comparison.Commits...)
+				if len(comparison.Commits) == 0 || len(commits) >= comparison.GetTotalCommits() {
+					break
+				}
+				if len(commits) >= maxChangelogCommits {
+					commits = commits[:maxChangelogCommits]
+					truncated = true
+					break
+				}
+			}
+
+			// Look up the pull requests that merged each commit
+			commitPRs, err := getCommitPullRequests(ctx, gqlClient, commits)
+			if err != nil {
+				return ghErrors.NewGitHubGraphQLErrorResponse(ctx,
+					"failed to list pull requests of commits",
+					err,
+				), nil
+			}
+
+			var prs []*github.PullRequest
+			var directCommits []string
+			seen := make(map[int]bool)
+			for i, commit := range commits {
+				merged := false
+				for _, pr := range commitPRs[i] {
+					if pr.MergedAt == nil {
+						continue
+					}
+					merged = true
+					if !seen[pr.GetNumber()] {
+						seen[pr.GetNumber()] = true
+						prs = append(prs, pr)
+					}
+				}
+				if !merged && includeCommits && len(commit.Parents) < 2 {
+					author := commit.GetCommit().GetAuthor().GetName()
+					if commit.GetAuthor().GetLogin() != "" {
+						author = "@" + commit.GetAuthor().GetLogin()
+					}
+					message, _, _ := strings.Cut(commit.GetCommit().GetMessage(), "\n")
+					// The changelog is markdown rather than JSON, so the result sanitizer doesn't cover it
+					message = sanitize.Markdown(message)
+					directCommits = append(directCommits, fmt.Sprintf("%s by %s in %s", message, author, commit.GetSHA()[:min(shortSHALength, len(commit.GetSHA()))]))
+				}
+			}
+			sort.SliceStable(prs, func(i, j int) bool {
+				return prs[i].GetMergedAt().Before(prs[j].GetMergedAt().Time)
+			})
+
+			sections := make([]changelogSection, len(categories))
+			for i, category := range categories {
+				sections[i].title = category.Title
+			}
+			other := changelogSection{}
+			if len(categories) > 0 {
+				other.title = "Other Changes"
+			}
+			for _, pr := range prs {
+				if exclude.excludes(pr) {
+					continue
+				}
+				entry := fmt.Sprintf("%s by @%s in %s", sanitize.Markdown(pr.GetTitle()), pr.GetUser().GetLogin(), pr.GetHTMLURL())
+				i := slices.IndexFunc(categories, func(c changelogCategory) bool { return c.matches(pr) })
+				if i < 0 {
+					other.entries = append(other.entries, entry)
+					continue
+				}
+				sections[i].entries = append(sections[i].entries, entry)
+			}
+			sections = append(sections, other, changelogSection{title: "Commits without a pull request", entries: directCommits})
+
+			return mcp.NewToolResultText(writeChangelog(sections, compareURL, truncated)), nil
+		}
+}
@@ -0,0 +1,262 @@
+package github
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/github/github-mcp-server/internal/githubv4mock"
+	"github.com/github/github-mcp-server/internal/toolsnaps"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/google/go-github/v77/github"
+	"github.com/migueleliasweb/go-github-mock/src/mock"
+	"github.com/shurcooL/githubv4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_GenerateChangelog(t *testing.T) {
+	tool, _ := GenerateChangelog(stubGetClientFn(github.NewClient(nil)), stubGetGQLClientFn(githubv4.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+	assert.True(t, *tool.Annotations.ReadOnlyHint)
+	assert.ElementsMatch(t, tool.InputSchema.Required, []string{"owner", "repo", "base", "head"})
+
+	pr := func(number int, title, login string, mergedDay int, labels ...string) *github.PullRequest {
+		pr := &github.PullRequest{
+			Number:   github.Ptr(number),
+			Title:    github.Ptr(title),
+			HTMLURL:  github.Ptr(fmt.Sprintf("https://github.com/owner/repo/pull/%d", number)),
+			User:     &github.User{Login: github.Ptr(login)},
+			MergedAt: &github.Timestamp{Time: time.Date(2024, 1, mergedDay, 0, 0, 0, 0, time.UTC)},
+		}
+		for _, label := range labels {
+			pr.Labels = append(pr.Labels, &github.Label{Name: github.Ptr(label)})
+		}
+		return pr
+	}
+	feature := pr(1, "Add search", "mona", 2, "enhancement")
+	fix := pr(2, "Fix crash", "hubot", 3, "bug")
+	chore := pr(3, "Bump deps", "dependabot", 4, "dependencies")
+	// Titles and messages are sanitized, as the markdown result is not covered by the result sanitizer
+	docs := pr(4, "Document [search](https://attacker.example/?q=secret)", "mona", 5)
+	openPR := &github.PullRequest{Number: github.Ptr(5), Title: github.Ptr("Still open")}
+	prsByCommit := map[string][]*github.PullRequest{
+		"c1": {feature},
+		"c2": {feature},
+		"c3": {fix, openPR},
+		"c6": {chore},
+		"c7": {docs},
+	}
+
+	commit := func(sha, message, login string, parents int) *github.RepositoryCommit {
+		c := &github.RepositoryCommit{
+			SHA:    github.Ptr(sha + "00000000"),
+			NodeID: github.Ptr("C_" + sha),
+			Commit: &github.Commit{Message: github.Ptr(message), Author: &github.CommitAuthor{Name: github.Ptr("Jane Doe")}},
+		}
+		if login != "" {
+			c.Author = &github.User{Login: github.Ptr(login)}
+		}
+		for range parents {
+			c.Parents = append(c.Parents, &github.Commit{})
+		}
+		return c
+	}
+	commits := []*github.RepositoryCommit{
+		commit("c1", "Add search", "mona", 1),
+		commit("c2", "Polish search", "mona", 1),
+		commit("c3", "Fix crash", "hubot", 1),
+		commit("c4", "Fix typo\u200B\n\nIn the README", "", 1),
+		commit("c5", "Merge branch 'main'", "mona", 2),
+		commit("c6", "Bump deps", "dependabot", 1),
+		commit("c7", "Document search", "mona", 1),
+	}
+
+	releaseConfig := `changelog:
+  exclude:
+    authors: [dependabot]
+  categories:
+    - title: Features
+      labels: [enhancement]
+    - title: Everything else
+      labels: ["*"]
+      exclude:
+        labels: [bug]
+`
+	mockedClient := func(releaseYML string) *http.Client {
+		return mock.NewMockedHTTPClient(
+			mock.WithRequestMatchHandler(
+				mock.GetReposCompareByOwnerByRepoByBasehead,
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					assert.Equal(t, "/repos/owner/repo/compare/v1.0.0...v1.1.0", r.URL.Path)
+					// Serve the commits four per page to exercise paging
+					page := 1
+					if r.URL.Query().Get("page") != "" {
+						page = int(r.URL.Query().Get("page")[0] - '0')
+					}
+					data, _ := json.Marshal(&github.CommitsComparison{
+						HTMLURL:      github.Ptr("https://github.com/owner/repo/compare/v1.0.0...v1.1.0"),
+						TotalCommits: github.Ptr(len(commits)),
+						Commits:      commits[min(len(commits), (page-1)*4):min(len(commits), page*4)],
+					})
+					_, _ = w.Write(data)
+				}),
+			),
+			mock.WithRequestMatchHandler(
+				mock.GetReposContentsByOwnerByRepoByPath,
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					if releaseYML == "" || r.URL.Path != "/repos/owner/repo/contents/.github/release.yml" {
+						w.WriteHeader(http.StatusNotFound)
+						_, _ = w.Write([]byte(`{"message": "Not Found"}`))
+						return
+					}
+					assert.Equal(t, "v1.1.0", r.URL.Query().Get("ref"))
+					data, _ := json.Marshal(&github.RepositoryContent{
+						Type:     github.Ptr("file"),
+						Encoding: github.Ptr("base64"),
+						Content:  github.Ptr(base64.StdEncoding.EncodeToString([]byte(releaseYML))),
+					})
+					_, _ = w.Write(data)
+				}),
+			),
+		)
+	}
+
+	// The pull requests of all the commits are looked up with a single query
+	ids := make([]githubv4.ID, len(commits))
+	nodes := make([]any, len(commits))
+	for i, c := range commits {
+		ids[i] = githubv4.ID(c.GetNodeID())
+		associated := []any{}
+		for _, pr := range prsByCommit[strings.TrimSuffix(c.GetSHA(), "00000000")] {
+			labels := []any{}
+			for _, label := range pr.Labels {
+				labels = append(labels, map[string]any{"name": label.GetName()})
+			}
+			var mergedAt any
+			if pr.MergedAt != nil {
+				mergedAt = pr.MergedAt.Format(time.RFC3339)
+			}
+			associated = append(associated, map[string]any{
+				"number":   pr.GetNumber(),
+				"title":    pr.GetTitle(),
+				"url":      pr.GetHTMLURL(),
+				"mergedAt": mergedAt,
+				"author":   map[string]any{"login": pr.GetUser().GetLogin()},
+				"labels":   map[string]any{"nodes": labels},
+			})
+		}
+		nodes[i] = map[string]any{"associatedPullRequests": map[string]any{"nodes": associated}}
+	}
+	gqlClient := githubv4mock.NewMockedHTTPClient(
+		githubv4mock.NewQueryMatcher(
+			changelogPullRequestsQuery{},
+			map[string]any{"ids": ids},
+			githubv4mock.DataResponse(map[string]any{"nodes": nodes}),
+		),
+	)
+
+	tests := []struct {
+		name             string
+		mockedClient     *http.Client
+		requestArgs      map[string]any
+		expectError      string
+		expectedMarkdown string
+	}{
+		{
+			name:         "without configuration",
+			mockedClient: mockedClient(""),
+			requestArgs:  map[string]any{},
+			expectedMarkdown: `## What's Changed
+* Add search by @mona in https://github.com/owner/repo/pull/1
+* Fix crash by @hubot in https://github.com/owner/repo/pull/2
+* Bump deps by @dependabot in https://github.com/owner/repo/pull/3
+* Document search by @mona in https://github.com/owner/repo/pull/4
+### Commits without a pull request
+* Fix typo by Jane Doe in c400000
+
+**Full Changelog**: https://github.com/owner/repo/compare/v1.0.0...v1.1.0
+`,
+		},
+		{
+			name:         "exclude labels given",
+			mockedClient: mockedClient(""),
+			requestArgs:  map[string]any{"exclude_labels": []any{"bug", "dependencies"}},
+			expectedMarkdown: `## What's Changed
+* Add search by @mona in https://github.com/owner/repo/pull/1
+* Document search by @mona in https://github.com/owner/repo/pull/4
+### Commits without a pull request
+* Fix typo by Jane Doe in c400000
+
+**Full Changelog**: https://github.com/owner/repo/compare/v1.0.0...v1.1.0
+`,
+		},
+		{
+			name:         "categories of release.yml",
+			mockedClient: mockedClient(releaseConfig),
+			requestArgs:  map[string]any{"include_commits": false},
+			expectedMarkdown: `## What's Changed
+### Features
+* Add search by @mona in https://github.com/owner/repo/pull/1
+### Everything else
+* Document search by @mona in https://github.com/owner/repo/pull/4
+### Other Changes
+* Fix crash by @hubot in https://github.com/owner/repo/pull/2
+
+**Full Changelog**: https://github.com/owner/repo/compare/v1.0.0...v1.1.0
+`,
+		},
+		{
+			name:         "categories given keep the exclusions of release.yml",
+			mockedClient: mockedClient(releaseConfig),
+			requestArgs: map[string]any{
+				"categories": []any{
+					map[string]any{"title": "Bug fixes", "labels": []any{"bug"}},
+					map[string]any{"title": "Features", "labels": []any{"enhancement", "feature"}},
+				},
+				"include_commits": false,
+			},
+			expectedMarkdown: `## What's Changed
+### Bug fixes
+* Fix crash by @hubot in https://github.com/owner/repo/pull/2
+### Features
+* Add search by @mona in https://github.com/owner/repo/pull/1
+### Other Changes
+* Document search by @mona in https://github.com/owner/repo/pull/4
+
+**Full Changelog**: https://github.com/owner/repo/compare/v1.0.0...v1.1.0
+`,
+		},
+		{
+			name:         "category without labels",
+			mockedClient: mockedClient(""),
+			requestArgs: map[string]any{
+				"categories": []any{map[string]any{"title": "Bug fixes"}},
+			},
+			expectError: "categories[0]: labels is required",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, handler := GenerateChangelog(stubGetClientFn(github.NewClient(tc.mockedClient)), stubGetGQLClientFn(githubv4.NewClient(gqlClient)), translations.NullTranslationHelper)
+			args := map[string]any{"owner": "owner", "repo": "repo", "base": "v1.0.0", "head": "v1.1.0"}
+			for k, v := range tc.requestArgs {
+				args[k] = v
+			}
+			result, err := handler(context.Background(), createMCPRequest(args))
+			require.NoError(t, err)
+
+			if tc.expectError != "" {
+				assert.Equal(t, tc.expectError, getErrorResult(t, result).Text)
+				return
+			}
+			assert.Equal(t, tc.expectedMarkdown, getTextResult(t, result).Text)
+		})
+	}
+}
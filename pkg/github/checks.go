@@ -0,0 +1,893 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	ghErrors "github.com/github/github-mcp-server/pkg/errors"
+	"github.com/github/github-mcp-server/pkg/sanitize"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/google/go-github/v77/github"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// maxCheckRunAnnotations is the maximum number of annotations GitHub accepts per check run request.
+const maxCheckRunAnnotations = 50
+
+// MinimalCheckRun is the output type of check runs.
+type MinimalCheckRun struct {
+	ID               int64  `json:"id"`
+	Name             string `json:"name"`
+	HeadSHA          string `json:"head_sha"`
+	Status           string `json:"status"`
+	Conclusion       string `json:"conclusion,omitempty"`
+	StartedAt        string `json:"started_at,omitempty"`
+	CompletedAt      string `json:"completed_at,omitempty"`
+	HTMLURL          string `json:"html_url,omitempty"`
+	DetailsURL       string `json:"details_url,omitempty"`
+	App              string `json:"app,omitempty"`
+	CheckSuiteID     int64  `json:"check_suite_id,omitempty"`
+	Title            string `json:"title,omitempty"`
+	AnnotationsCount int    `json:"annotations_count"`
+}
+
+// MinimalCheckRunAnnotation is an annotation of a check run, pointing to a line range of a file.
+type MinimalCheckRunAnnotation struct {
+	Path        string `json:"path"`
+	StartLine   int    `json:"start_line"`
+	EndLine     int    `json:"end_line"`
+	StartColumn int    `json:"start_column,omitempty"`
+	EndColumn   int    `json:"end_column,omitempty"`
+	Level       string `json:"annotation_level"`
+	Title       string `json:"title,omitempty"`
+	Message     string `json:"message"`
+	RawDetails  string `json:"raw_details,omitempty"`
+}
+
+// CheckRunDetails is the output type of get_check_run.
+type CheckRunDetails struct {
+	MinimalCheckRun
+	Summary     string                      `json:"summary,omitempty"`
+	Text        string                      `json:"text,omitempty"`
+	Annotations []MinimalCheckRunAnnotation `json:"annotations"`
+}
+
+// MinimalCheckSuite is the output type of check suites.
+type MinimalCheckSuite struct {
+	ID                   int64  `json:"id"`
+	HeadBranch           string `json:"head_branch,omitempty"`
+	HeadSHA              string `json:"head_sha"`
+	Status               string `json:"status"`
+	Conclusion           string `json:"conclusion,omitempty"`
+	App                  string `json:"app,omitempty"`
+	LatestCheckRunsCount int64  `json:"latest_check_runs_count"`
+	Rerequestable        bool   `json:"rerequestable"`
+	CreatedAt            string `json:"created_at,omitempty"`
+	UpdatedAt            string `json:"updated_at,omitempty"`
+}
+
+// MinimalCommitStatus is the output type of commit statuses.
+type MinimalCommitStatus struct {
+	ID          int64  `json:"id"`
+	State       string `json:"state"`
+	Context     string `json:"context"`
+	Description string `json:"description,omitempty"`
+	TargetURL   string `json:"target_url,omitempty"`
+	Creator     string `json:"creator,omitempty"`
+	CreatedAt   string `json:"created_at,omitempty"`
+}
+
+func convertToMinimalCheckRun(checkRun *github.CheckRun) MinimalCheckRun {
+	m := MinimalCheckRun{
+		ID:               checkRun.GetID(),
+		Name:             checkRun.GetName(),
+		HeadSHA:          checkRun.GetHeadSHA(),
+		Status:           checkRun.GetStatus(),
+		Conclusion:       checkRun.GetConclusion(),
+		HTMLURL:          checkRun.GetHTMLURL(),
+		DetailsURL:       checkRun.GetDetailsURL(),
+		App:              checkRun.GetApp().GetSlug(),
+		CheckSuiteID:     checkRun.GetCheckSuite().GetID(),
+		Title:            checkRun.GetOutput().GetTitle(),
+		AnnotationsCount: checkRun.GetOutput().GetAnnotationsCount(),
+	}
+	if checkRun.StartedAt != nil {
+		m.StartedAt = checkRun.StartedAt.Format("2006-01-02T15:04:05Z")
+	}
+	if checkRun.CompletedAt != nil {
+		m.CompletedAt = checkRun.CompletedAt.Format("2006-01-02T15:04:05Z")
+	}
+	return m
+}
+
+func convertToMinimalCheckRunAnnotation(annotation *github.CheckRunAnnotation) MinimalCheckRunAnnotation {
+	return MinimalCheckRunAnnotation{
+		Path:        annotation.GetPath(),
+		StartLine:   annotation.GetStartLine(),
+		EndLine:     annotation.GetEndLine(),
+		StartColumn: annotation.GetStartColumn(),
+		EndColumn:   annotation.GetEndColumn(),
+		Level:       annotation.GetAnnotationLevel(),
+		Title:       annotation.GetTitle(),
+		Message:     annotation.GetMessage(),
+		RawDetails:  annotation.GetRawDetails(),
+	}
+}
+
+func convertToMinimalCheckSuite(checkSuite *github.CheckSuite) MinimalCheckSuite {
+	m := MinimalCheckSuite{
+		ID:                   checkSuite.GetID(),
+		HeadBranch:           checkSuite.GetHeadBranch(),
+		HeadSHA:              checkSuite.GetHeadSHA(),
+		Status:               checkSuite.GetStatus(),
+		Conclusion:           checkSuite.GetConclusion(),
+		App:                  checkSuite.GetApp().GetSlug(),
+		LatestCheckRunsCount: checkSuite.GetLatestCheckRunsCount(),
+		Rerequestable:        checkSuite.GetRerequestable(),
+	}
+	if checkSuite.CreatedAt != nil {
+		m.CreatedAt = checkSuite.CreatedAt.Format("2006-01-02T15:04:05Z")
+	}
+	if checkSuite.UpdatedAt != nil {
+		m.UpdatedAt = checkSuite.UpdatedAt.Format("2006-01-02T15:04:05Z")
+	}
+	return m
+}
+
+func convertToMinimalCommitStatus(status *github.RepoStatus) MinimalCommitStatus {
+	m := MinimalCommitStatus{
+		ID:          status.GetID(),
+		State:       status.GetState(),
+		Context:     status.GetContext(),
+		Description: status.GetDescription(),
+		TargetURL:   status.GetTargetURL(),
+		Creator:     status.GetCreator().GetLogin(),
+	}
+	if status.CreatedAt != nil {
+		m.CreatedAt = status.CreatedAt.Format("2006-01-02T15:04:05Z")
+	}
+	return m
+}
+
+// withCheckRunOutputParams adds the parameters describing the output of a check run.
+func withCheckRunOutputParams() mcp.ToolOption {
+	return func(tool *mcp.Tool) {
+		mcp.WithString("title",
+			mcp.Description("Title of the output. Required with the other output parameters"),
+		)(tool)
+		mcp.WithString("summary",
+			mcp.Description("Summary of the output, in Markdown. Required with the other output parameters"),
+		)(tool)
+		mcp.WithString("text",
+			mcp.Description("Details of the output, in Markdown"),
+		)(tool)
+		mcp.WithArray("annotations",
+			mcp.Items(
+				map[string]interface{}{
+					"type":                 "object",
+					"additionalProperties": false,
+					"required":             []string{"path", "start_line", "end_line", "annotation_level", "message"},
+					"properties": map[string]interface{}{
+						"path": map[string]interface{}{
+							"type":        "string",
+							"description": "path of the file, relative to the root of the repository",
+						},
+						"start_line": map[string]interface{}{
+							"type":        "number",
+							"description": "first line of the annotation",
+						},
+						"end_line": map[string]interface{}{
+							"type":        "number",
+							"description": "last line of the annotation",
+						},
+						"annotation_level": map[string]interface{}{
+							"type":        "string",
+							"enum":        []string{"notice", "warning", "failure"},
+							"description": "level of the annotation",
+						},
+						"message": map[string]interface{}{
+							"type":        "string",
+							"description": "short description of the feedback for the lines",
+						},
+						"title": map[string]interface{}{
+							"type":        "string",
+							"description": "title of the annotation",
+						},
+					},
+				}),
+			mcp.Description(fmt.Sprintf("Annotations on lines of files, at most %d per request. Annotations are added to the existing ones", maxCheckRunAnnotations)),
+		)(tool)
+	}
+}
+
+// optionalCheckRunOutput gets the output of a check run from a request, or nil if none of its parameters are set.
+func optionalCheckRunOutput(request mcp.CallToolRequest) (*github.CheckRunOutput, error) {
+	output := &github.CheckRunOutput{}
+	isSet := false
+	for param, field := range map[string]**string{
+		"title":   &output.Title,
+		"summary": &output.Summary,
+		"text":    &output.Text,
+	} {
+		value, err := OptionalParam[string](request, param)
+		if err != nil {
+			return nil, err
+		}
+		if value != "" {
+			*field = github.Ptr(value)
+			isSet = true
+		}
+	}
+
+	if annotationsObj, ok := request.GetArguments()["annotations"]; ok && annotationsObj != nil {
+		annotations, ok := annotationsObj.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("annotations must be an array of objects")
+		}
+		if len(annotations) > maxCheckRunAnnotations {
+			return nil, fmt.Errorf("at most %d annotations can be added per request, got %d", maxCheckRunAnnotations, len(annotations))
+		}
+		for i, annotationObj := range annotations {
+			annotationMap, ok := annotationObj.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("annotation %d must be an object", i)
+			}
+			path, _ := annotationMap["path"].(string)
+			startLine, _ := annotationMap["start_line"].(float64)
+			endLine, _ := annotationMap["end_line"].(float64)
+			level, _ := annotationMap["annotation_level"].(string)
+			message, _ := annotationMap["message"].(string)
+			if path == "" || startLine < 1 || endLine < startLine || level == "" || message == "" {
+				return nil, fmt.Errorf("annotation %d must have a path, a start_line and end_line range, an annotation_level and a message", i)
+			}
+			annotation := &github.CheckRunAnnotation{
+				Path:            github.Ptr(path),
+				StartLine:       github.Ptr(int(startLine)),
+				EndLine:         github.Ptr(int(endLine)),
+				AnnotationLevel: github.Ptr(level),
+				Message:         github.Ptr(message),
+			}
+			if title, _ := annotationMap["title"].(string); title != "" {
+				annotation.Title = github.Ptr(title)
+			}
+			output.Annotations = append(output.Annotations, annotation)
+		}
+		isSet = isSet || len(output.Annotations) > 0
+	}
+
+	if !isSet {
+		return nil, nil
+	}
+	if output.Title == nil || output.Summary == nil {
+		return nil, fmt.Errorf("title and summary are required to set the output of a check run")
+	}
+	return output, nil
+}
+
+// ListCheckRuns creates a tool to list the check runs of a ref.
+func ListCheckRuns(getClient GetClientFn, t translations.TranslationHelperFunc) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("list_check_runs",
+			mcp.WithDescription(t("TOOL_LIST_CHECK_RUNS_DESCRIPTION", "List the check runs of a commit SHA, branch or tag of a GitHub repository, with their status and conclusion. Use get_check_run to read the annotations of a failed check run")),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:        t("TOOL_LIST_CHECK_RUNS_USER_TITLE", "List check runs"),
+				ReadOnlyHint: ToBoolPtr(true),
+			}),
+			mcp.WithString("owner",
+				mcp.Required(),
+				mcp.Description(DescriptionRepositoryOwner),
+			),
+			mcp.WithString("repo",
+				mcp.Required(),
+				mcp.Description(DescriptionRepositoryName),
+			),
+			mcp.WithString("ref",
+				mcp.Required(),
+				mcp.Description("Commit SHA, branch or tag name"),
+			),
+			mcp.WithString("check_name",
+				mcp.Description("Only check runs with this name"),
+			),
+			mcp.WithString("status",
+				mcp.Description("Only check runs with this status"),
+				mcp.Enum("queued", "in_progress", "completed"),
+			),
+			mcp.WithString("filter",
+				mcp.Description("Return only the latest check run of each check, or all of them including re-runs (default latest)"),
+				mcp.Enum("latest", "all"),
+			),
+			WithPagination(),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			owner, err := RequiredParam[string](request, "owner")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			repo, err := RequiredParam[string](request, "repo")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			ref, err := RequiredParam[string](request, "ref")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			pagination, err := OptionalPaginationParams(request)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			opts := &github.ListCheckRunsOptions{
+				ListOptions: github.ListOptions{
+					Page:    pagination.Page,
+					PerPage: pagination.PerPage,
+				},
+			}
+			for param, field := range map[string]**string{
+				"check_name": &opts.CheckName,
+				"status":     &opts.Status,
+				"filter":     &opts.Filter,
+			} {
+				value, err := OptionalParam[string](request, param)
+				if err != nil {
+					return mcp.NewToolResultError(err.Error()), nil
+				}
+				if value != "" {
+					*field = github.Ptr(value)
+				}
+			}
+
+			client, err := getClient(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get GitHub client: %w", err)
+			}
+
+			checkRuns, resp, err := client.Checks.ListCheckRunsForRef(ctx, owner, repo, ref, opts)
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx,
+					"failed to list check runs",
+					resp,
+					err,
+				), nil
+			}
+			defer func() { _ = resp.Body.Close() }()
+
+			result := make([]MinimalCheckRun, 0, len(checkRuns.CheckRuns))
+			for _, checkRun := range checkRuns.CheckRuns {
+				result = append(result, convertToMinimalCheckRun(checkRun))
+			}
+
+			return MarshalledTextResult(map[string]any{
+				"total_count": checkRuns.GetTotal(),
+				"check_runs":  result,
+			}), nil
+		}
+}
+
+// ListCheckSuites creates a tool to list the check suites of a ref.
+func ListCheckSuites(getClient GetClientFn, t translations.TranslationHelperFunc) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("list_check_suites",
+			mcp.WithDescription(t("TOOL_LIST_CHECK_SUITES_DESCRIPTION", "List the check suites of a commit SHA, branch or tag of a GitHub repository. A check suite groups the check runs of one GitHub App")),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:        t("TOOL_LIST_CHECK_SUITES_USER_TITLE", "List check suites"),
+				ReadOnlyHint: ToBoolPtr(true),
+			}),
+			mcp.WithString("owner",
+				mcp.Required(),
+				mcp.Description(DescriptionRepositoryOwner),
+			),
+			mcp.WithString("repo",
+				mcp.Required(),
+				mcp.Description(DescriptionRepositoryName),
+			),
+			mcp.WithString("ref",
+				mcp.Required(),
+				mcp.Description("Commit SHA, branch or tag name"),
+			),
+			mcp.WithString("check_name",
+				mcp.Description("Only check suites with a check run of this name"),
+			),
+			WithPagination(),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			owner, err := RequiredParam[string](request, "owner")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			repo, err := RequiredParam[string](request, "repo")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			ref, err := RequiredParam[string](request, "ref")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			checkName, err := OptionalParam[string](request, "check_name")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			pagination, err := OptionalPaginationParams(request)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			opts := &github.ListCheckSuiteOptions{
+				ListOptions: github.ListOptions{
+					Page:    pagination.Page,
+					PerPage: pagination.PerPage,
+				},
+			}
+			if checkName != "" {
+				opts.CheckName = github.Ptr(checkName)
+			}
+
+			client, err := getClient(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get GitHub client: %w", err)
+			}
+
+			checkSuites, resp, err := client.Checks.ListCheckSuitesForRef(ctx, owner, repo, ref, opts)
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx,
+					"failed to list check suites",
+					resp,
+					err,
+				), nil
+			}
+			defer func() { _ = resp.Body.Close() }()
+
+			result := make([]MinimalCheckSuite, 0, len(checkSuites.CheckSuites))
+			for _, checkSuite := range checkSuites.CheckSuites {
+				result = append(result, convertToMinimalCheckSuite(checkSuite))
+			}
+
+			return MarshalledTextResult(map[string]any{
+				"total_count":  checkSuites.GetTotal(),
+				"check_suites": result,
+			}), nil
+		}
+}
+
+// GetCheckRun creates a tool to get a check run with its output and annotations.
+func GetCheckRun(getClient GetClientFn, t translations.TranslationHelperFunc) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("get_check_run",
+			mcp.WithDescription(t("TOOL_GET_CHECK_RUN_DESCRIPTION", "Get a check run of a GitHub repository with its output summary and its annotations, which point to the files and lines the check reported problems on. The annotations are paginated")),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:        t("TOOL_GET_CHECK_RUN_USER_TITLE", "Get check run"),
+				ReadOnlyHint: ToBoolPtr(true),
+			}),
+			mcp.WithString("owner",
+				mcp.Required(),
+				mcp.Description(DescriptionRepositoryOwner),
+			),
+			mcp.WithString("repo",
+				mcp.Required(),
+				mcp.Description(DescriptionRepositoryName),
+			),
+			mcp.WithNumber("check_run_id",
+				mcp.Required(),
+				mcp.Description("ID of the check run"),
+			),
+			WithPagination(),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			owner, err := RequiredParam[string](request, "owner")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			repo, err := RequiredParam[string](request, "repo")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			checkRunID, err := RequiredBigInt(request, "check_run_id")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			pagination, err := OptionalPaginationParams(request)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			client, err := getClient(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get GitHub client: %w", err)
+			}
+
+			checkRun, resp, err := client.Checks.GetCheckRun(ctx, owner, repo, checkRunID)
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx,
+					"failed to get check run",
+					resp,
+					err,
+				), nil
+			}
+			defer func() { _ = resp.Body.Close() }()
+
+			// The output is markdown written by the app that ran the check. text is not one of the markdown
+			// fields of the result sanitizer, as grep results use it for lines of code, so both are sanitized here
+			details := CheckRunDetails{
+				MinimalCheckRun: convertToMinimalCheckRun(checkRun),
+				Summary:         sanitize.Markdown(checkRun.GetOutput().GetSummary()),
+				Text:            sanitize.Markdown(checkRun.GetOutput().GetText()),
+				Annotations:     []MinimalCheckRunAnnotation{},
+			}
+			if details.AnnotationsCount > 0 {
+				annotations, resp, err := client.Checks.ListCheckRunAnnotations(ctx, owner, repo, checkRunID, &github.ListOptions{
+					Page:    pagination.Page,
+					PerPage: pagination.PerPage,
+				})
+				if err != nil {
+					return ghErrors.NewGitHubAPIErrorResponse(ctx,
+						"failed to list check run annotations",
+						resp,
+						err,
+					), nil
+				}
+				defer func() { _ = resp.Body.Close() }()
+				for _, annotation := range annotations {
+					details.Annotations = append(details.Annotations, convertToMinimalCheckRunAnnotation(annotation))
+				}
+			}
+
+			return MarshalledTextResult(details), nil
+		}
+}
+
+// CreateCheckRun creates a tool to create a check run for a commit.
+func CreateCheckRun(getClient GetClientFn, t translations.TranslationHelperFunc) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("create_check_run",
+			mcp.WithDescription(t("TOOL_CREATE_CHECK_RUN_DESCRIPTION", "Create a check run for a commit of a GitHub repository, optionally with an output and annotations on lines of files. Only GitHub Apps can create check runs, use create_commit_status otherwise")),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:        t("TOOL_CREATE_CHECK_RUN_USER_TITLE", "Create check run"),
+				ReadOnlyHint: ToBoolPtr(false),
+			}),
+			mcp.WithString("owner",
+				mcp.Required(),
+				mcp.Description(DescriptionRepositoryOwner),
+			),
+			mcp.WithString("repo",
+				mcp.Required(),
+				mcp.Description(DescriptionRepositoryName),
+			),
+			mcp.WithString("name",
+				mcp.Required(),
+				mcp.Description("Name of the check"),
+			),
+			mcp.WithString("head_sha",
+				mcp.Required(),
+				mcp.Description("SHA of the commit"),
+			),
+			mcp.WithString("status",
+				mcp.Description("Status of the check run (default queued)"),
+				mcp.Enum("queued", "in_progress", "completed"),
+			),
+			mcp.WithString("conclusion",
+				mcp.Description("Conclusion of the check run. Completes the check run"),
+				mcp.Enum("action_required", "cancelled", "failure", "neutral", "success", "skipped", "timed_out"),
+			),
+			mcp.WithString("details_url",
+				mcp.Description("URL of the full details of the check"),
+			),
+			mcp.WithString("external_id",
+				mcp.Description("Reference of the check run in the system running it"),
+			),
+			withCheckRunOutputParams(),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			owner, err := RequiredParam[string](request, "owner")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			repo, err := RequiredParam[string](request, "repo")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			name, err := RequiredParam[string](request, "name")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			headSHA, err := RequiredParam[string](request, "head_sha")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			opts := github.CreateCheckRunOptions{Name: name, HeadSHA: headSHA}
+			for param, field := range map[string]**string{
+				"status":      &opts.Status,
+				"conclusion":  &opts.Conclusion,
+				"details_url": &opts.DetailsURL,
+				"external_id": &opts.ExternalID,
+			} {
+				value, err := OptionalParam[string](request, param)
+				if err != nil {
+					return mcp.NewToolResultError(err.Error()), nil
+				}
+				if value != "" {
+					*field = github.Ptr(value)
+				}
+			}
+			if opts.Conclusion != nil {
+				opts.CompletedAt = &github.Timestamp{Time: time.Now()}
+			}
+			opts.Output, err = optionalCheckRunOutput(request)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			client, err := getClient(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get GitHub client: %w", err)
+			}
+
+			checkRun, resp, err := client.Checks.CreateCheckRun(ctx, owner, repo, opts)
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx,
+					"failed to create check run",
+					resp,
+					err,
+				), nil
+			}
+			defer func() { _ = resp.Body.Close() }()
+
+			return MarshalledTextResult(convertToMinimalCheckRun(checkRun)), nil
+		}
+}
+
+// UpdateCheckRun creates a tool to update a check run.
+func UpdateCheckRun(getClient GetClientFn, t translations.TranslationHelperFunc) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("update_check_run",
+			mcp.WithDescription(t("TOOL_UPDATE_CHECK_RUN_DESCRIPTION", "Update a check run of a GitHub repository, to report its progress, complete it or add annotations. Only the GitHub App that created the check run can update it")),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:        t("TOOL_UPDATE_CHECK_RUN_USER_TITLE", "Update check run"),
+				ReadOnlyHint: ToBoolPtr(false),
+			}),
+			mcp.WithString("owner",
+				mcp.Required(),
+				mcp.Description(DescriptionRepositoryOwner),
+			),
+			mcp.WithString("repo",
+				mcp.Required(),
+				mcp.Description(DescriptionRepositoryName),
+			),
+			mcp.WithNumber("check_run_id",
+				mcp.Required(),
+				mcp.Description("ID of the check run"),
+			),
+			mcp.WithString("name",
+				mcp.Description("New name of the check"),
+			),
+			mcp.WithString("status",
+				mcp.Description("New status of the check run"),
+				mcp.Enum("queued", "in_progress", "completed"),
+			),
+			mcp.WithString("conclusion",
+				mcp.Description("Conclusion of the check run. Completes the check run"),
+				mcp.Enum("action_required", "cancelled", "failure", "neutral", "success", "skipped", "timed_out"),
+			),
+			mcp.WithString("details_url",
+				mcp.Description("URL of the full details of the check"),
+			),
+			mcp.WithString("external_id",
+				mcp.Description("Reference of the check run in the system running it"),
+			),
+			withCheckRunOutputParams(),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			owner, err := RequiredParam[string](request, "owner")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			repo, err := RequiredParam[string](request, "repo")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			checkRunID, err := RequiredBigInt(request, "check_run_id")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			name, err := OptionalParam[string](request, "name")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			opts := github.UpdateCheckRunOptions{Name: name}
+			isSet := false
+			for param, field := range map[string]**string{
+				"status":      &opts.Status,
+				"conclusion":  &opts.Conclusion,
+				"details_url": &opts.DetailsURL,
+				"external_id": &opts.ExternalID,
+			} {
+				value, err := OptionalParam[string](request, param)
+				if err != nil {
+					return mcp.NewToolResultError(err.Error()), nil
+				}
+				if value != "" {
+					*field = github.Ptr(value)
+					isSet = true
+				}
+			}
+			if opts.Conclusion != nil {
+				opts.CompletedAt = &github.Timestamp{Time: time.Now()}
+			}
+			opts.Output, err = optionalCheckRunOutput(request)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			if name == "" && !isSet && opts.Output == nil {
+				return mcp.NewToolResultError("No update parameters provided."), nil
+			}
+
+			client, err := getClient(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get GitHub client: %w", err)
+			}
+
+			// The name is always sent by go-github, so the current one is kept when no new name is given
+			if opts.Name == "" {
+				checkRun, resp, err := client.Checks.GetCheckRun(ctx, owner, repo, checkRunID)
+				if err != nil {
+					return ghErrors.NewGitHubAPIErrorResponse(ctx,
+						"failed to get check run",
+						resp,
+						err,
+					), nil
+				}
+				_ = resp.Body.Close()
+				opts.Name = checkRun.GetName()
+			}
+
+			checkRun, resp, err := client.Checks.UpdateCheckRun(ctx, owner, repo, checkRunID, opts)
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx,
+					"failed to update check run",
+					resp,
+					err,
+				), nil
+			}
+			defer func() { _ = resp.Body.Close() }()
+
+			return MarshalledTextResult(convertToMinimalCheckRun(checkRun)), nil
+		}
+}
+
+// CreateCommitStatus creates a tool to create a status for a commit.
+func CreateCommitStatus(getClient GetClientFn, t translations.TranslationHelperFunc) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("create_commit_status",
+			mcp.WithDescription(t("TOOL_CREATE_COMMIT_STATUS_DESCRIPTION", "Create a status for a commit of a GitHub repository. A new status with the same context replaces the previous one, which is how a status is updated")),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:        t("TOOL_CREATE_COMMIT_STATUS_USER_TITLE", "Create commit status"),
+				ReadOnlyHint: ToBoolPtr(false),
+			}),
+			mcp.WithString("owner",
+				mcp.Required(),
+				mcp.Description(DescriptionRepositoryOwner),
+			),
+			mcp.WithString("repo",
+				mcp.Required(),
+				mcp.Description(DescriptionRepositoryName),
+			),
+			mcp.WithString("sha",
+				mcp.Required(),
+				mcp.Description("SHA of the commit"),
+			),
+			mcp.WithString("state",
+				mcp.Required(),
+				mcp.Description("State of the status"),
+				mcp.Enum("error", "failure", "pending", "success"),
+			),
+			mcp.WithString("context",
+				mcp.Description("Label identifying the status among the statuses of other systems (default \"default\")"),
+			),
+			mcp.WithString("description",
+				mcp.Description("Short description of the status"),
+			),
+			mcp.WithString("target_url",
+				mcp.Description("URL of the details of the status"),
+			),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			owner, err := RequiredParam[string](request, "owner")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			repo, err := RequiredParam[string](request, "repo")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			sha, err := RequiredParam[string](request, "sha")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			state, err := RequiredParam[string](request, "state")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			status := github.RepoStatus{State: github.Ptr(state)}
+			for param, field := range map[string]**string{
+				"context":     &status.Context,
+				"description": &status.Description,
+				"target_url":  &status.TargetURL,
+			} {
+				value, err := OptionalParam[string](request, param)
+				if err != nil {
+					return mcp.NewToolResultError(err.Error()), nil
+				}
+				if value != "" {
+					*field = github.Ptr(value)
+				}
+			}
+
+			client, err := getClient(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get GitHub client: %w", err)
+			}
+
+			created, resp, err := client.Repositories.CreateStatus(ctx, owner, repo, sha, status)
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx,
+					"failed to create commit status",
+					resp,
+					err,
+				), nil
+			}
+			defer func() { _ = resp.Body.Close() }()
+
+			return MarshalledTextResult(convertToMinimalCommitStatus(created)), nil
+		}
+}
+
+// RerequestCheckSuite creates a tool to re-run the check runs of a check suite.
+func RerequestCheckSuite(getClient GetClientFn, t translations.TranslationHelperFunc) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("rerequest_check_suite",
+			mcp.WithDescription(t("TOOL_REREQUEST_CHECK_SUITE_DESCRIPTION", "Re-request a check suite of a GitHub repository, so that its GitHub App runs its checks again on the same commit")),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:        t("TOOL_REREQUEST_CHECK_SUITE_USER_TITLE", "Re-request check suite"),
+				ReadOnlyHint: ToBoolPtr(false),
+			}),
+			mcp.WithString("owner",
+				mcp.Required(),
+				mcp.Description(DescriptionRepositoryOwner),
+			),
+			mcp.WithString("repo",
+				mcp.Required(),
+				mcp.Description(DescriptionRepositoryName),
+			),
+			mcp.WithNumber("check_suite_id",
+				mcp.Required(),
+				mcp.Description("ID of the check suite, from list_check_suites or the check_suite_id of a check run"),
+			),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			owner, err := RequiredParam[string](request, "owner")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			repo, err := RequiredParam[string](request, "repo")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			checkSuiteID, err := RequiredBigInt(request, "check_suite_id")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			client, err := getClient(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get GitHub client: %w", err)
+			}
+
+			resp, err := client.Checks.ReRequestCheckSuite(ctx, owner, repo, checkSuiteID)
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx,
+					"failed to re-request check suite",
+					resp,
+					err,
+				), nil
+			}
+			defer func() { _ = resp.Body.Close() }()
+
+			return mcp.NewToolResultText(fmt.Sprintf("Re-requested check suite %d", checkSuiteID)), nil
+		}
+}
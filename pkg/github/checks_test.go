@@ -0,0 +1,435 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/github/github-mcp-server/internal/toolsnaps"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/google/go-github/v77/github"
+	"github.com/migueleliasweb/go-github-mock/src/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ListCheckRuns(t *testing.T) {
+	tool, _ := ListCheckRuns(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+	assert.True(t, *tool.Annotations.ReadOnlyHint)
+	assert.ElementsMatch(t, tool.InputSchema.Required, []string{"owner", "repo", "ref"})
+
+	mockedClient := mock.NewMockedHTTPClient(
+		mock.WithRequestMatchHandler(
+			mock.GetReposCommitsCheckRunsByOwnerByRepoByRef,
+			expect(t, expectations{
+				path:        "/repos/owner/repo/commits/main/check-runs",
+				queryParams: map[string]string{"status": "completed", "filter": "all", "page": "1", "per_page": "30"},
+			}).andThen(
+				mockResponse(t, http.StatusOK, &github.ListCheckRunsResults{
+					Total: github.Ptr(1),
+					CheckRuns: []*github.CheckRun{{
+						ID:         github.Ptr(int64(4)),
+						Name:       github.Ptr("lint"),
+						HeadSHA:    github.Ptr("abc123"),
+						Status:     github.Ptr("completed"),
+						Conclusion: github.Ptr("failure"),
+						App:        &github.App{Slug: github.Ptr("github-actions")},
+						CheckSuite: &github.CheckSuite{ID: github.Ptr(int64(8))},
+						Output:     &github.CheckRunOutput{Title: github.Ptr("2 errors"), AnnotationsCount: github.Ptr(2)},
+					}},
+				}),
+			),
+		),
+	)
+
+	_, handler := ListCheckRuns(stubGetClientFn(github.NewClient(mockedClient)), translations.NullTranslationHelper)
+	result, err := handler(context.Background(), createMCPRequest(map[string]any{
+		"owner":  "owner",
+		"repo":   "repo",
+		"ref":    "main",
+		"status": "completed",
+		"filter": "all",
+	}))
+	require.NoError(t, err)
+
+	var returned struct {
+		TotalCount int               `json:"total_count"`
+		CheckRuns  []MinimalCheckRun `json:"check_runs"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &returned))
+	assert.Equal(t, 1, returned.TotalCount)
+	assert.Equal(t, []MinimalCheckRun{{
+		ID:               4,
+		Name:             "lint",
+		HeadSHA:          "abc123",
+		Status:           "completed",
+		Conclusion:       "failure",
+		App:              "github-actions",
+		CheckSuiteID:     8,
+		Title:            "2 errors",
+		AnnotationsCount: 2,
+	}}, returned.CheckRuns)
+}
+
+func Test_ListCheckSuites(t *testing.T) {
+	tool, _ := ListCheckSuites(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+	assert.True(t, *tool.Annotations.ReadOnlyHint)
+	assert.ElementsMatch(t, tool.InputSchema.Required, []string{"owner", "repo", "ref"})
+
+	mockedClient := mock.NewMockedHTTPClient(
+		mock.WithRequestMatchHandler(
+			mock.GetReposCommitsCheckSuitesByOwnerByRepoByRef,
+			expectQueryParams(t, map[string]string{"check_name": "lint", "page": "1", "per_page": "30"}).andThen(
+				mockResponse(t, http.StatusOK, &github.ListCheckSuiteResults{
+					Total: github.Ptr(1),
+					CheckSuites: []*github.CheckSuite{{
+						ID:                   github.Ptr(int64(8)),
+						HeadBranch:           github.Ptr("main"),
+						HeadSHA:              github.Ptr("abc123"),
+						Status:               github.Ptr("completed"),
+						Conclusion:           github.Ptr("failure"),
+						App:                  &github.App{Slug: github.Ptr("github-actions")},
+						LatestCheckRunsCount: github.Ptr(int64(3)),
+						Rerequestable:        github.Ptr(true),
+					}},
+				}),
+			),
+		),
+	)
+
+	_, handler := ListCheckSuites(stubGetClientFn(github.NewClient(mockedClient)), translations.NullTranslationHelper)
+	result, err := handler(context.Background(), createMCPRequest(map[string]any{"owner": "owner", "repo": "repo", "ref": "main", "check_name": "lint"}))
+	require.NoError(t, err)
+
+	var returned struct {
+		TotalCount  int                 `json:"total_count"`
+		CheckSuites []MinimalCheckSuite `json:"check_suites"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &returned))
+	assert.Equal(t, 1, returned.TotalCount)
+	assert.Equal(t, []MinimalCheckSuite{{
+		ID:                   8,
+		HeadBranch:           "main",
+		HeadSHA:              "abc123",
+		Status:               "completed",
+		Conclusion:           "failure",
+		App:                  "github-actions",
+		LatestCheckRunsCount: 3,
+		Rerequestable:        true,
+	}}, returned.CheckSuites)
+}
+
+func Test_GetCheckRun(t *testing.T) {
+	tool, _ := GetCheckRun(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+	assert.True(t, *tool.Annotations.ReadOnlyHint)
+	assert.ElementsMatch(t, tool.InputSchema.Required, []string{"owner", "repo", "check_run_id"})
+
+	checkRun := &github.CheckRun{
+		ID:         github.Ptr(int64(4)),
+		Name:       github.Ptr("lint"),
+		HeadSHA:    github.Ptr("abc123"),
+		Status:     github.Ptr("completed"),
+		Conclusion: github.Ptr("failure"),
+		Output: &github.CheckRunOutput{
+			Title:            github.Ptr("1 error"),
+			Summary:          github.Ptr("golangci-lint found [1 issue](https://attacker.example/?q=secret)"),
+			Text:             github.Ptr("<script>alert(1)</script>See the **annotations**"),
+			AnnotationsCount: github.Ptr(1),
+		},
+	}
+
+	tests := []struct {
+		name         string
+		mockedClient *http.Client
+		expected     CheckRunDetails
+	}{
+		{
+			name: "check run with annotations",
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatch(mock.GetReposCheckRunsByOwnerByRepoByCheckRunId, checkRun),
+				mock.WithRequestMatchHandler(
+					mock.GetReposCheckRunsAnnotationsByOwnerByRepoByCheckRunId,
+					expectQueryParams(t, map[string]string{"page": "2", "per_page": "10"}).andThen(
+						mockResponse(t, http.StatusOK, []*github.CheckRunAnnotation{{
+							Path:            github.Ptr("pkg/server.go"),
+							StartLine:       github.Ptr(12),
+							EndLine:         github.Ptr(12),
+							AnnotationLevel: github.Ptr("failure"),
+							Title:           github.Ptr("errcheck"),
+							Message:         github.Ptr("Error return value is not checked"),
+						}}),
+					),
+				),
+			),
+			expected: CheckRunDetails{
+				MinimalCheckRun: MinimalCheckRun{
+					ID:               4,
+					Name:             "lint",
+					HeadSHA:          "abc123",
+					Status:           "completed",
+					Conclusion:       "failure",
+					Title:            "1 error",
+					AnnotationsCount: 1,
+				},
+				Summary: "golangci-lint found 1 issue",
+				Text:    "See the **annotations**",
+				Annotations: []MinimalCheckRunAnnotation{{
+					Path:      "pkg/server.go",
+					StartLine: 12,
+					EndLine:   12,
+					Level:     "failure",
+					Title:     "errcheck",
+					Message:   "Error return value is not checked",
+				}},
+			},
+		},
+		{
+			name: "check run without annotations",
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatch(mock.GetReposCheckRunsByOwnerByRepoByCheckRunId, &github.CheckRun{
+					ID:     github.Ptr(int64(5)),
+					Name:   github.Ptr("build"),
+					Status: github.Ptr("in_progress"),
+				}),
+			),
+			expected: CheckRunDetails{
+				MinimalCheckRun: MinimalCheckRun{ID: 5, Name: "build", Status: "in_progress"},
+				Annotations:     []MinimalCheckRunAnnotation{},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, handler := GetCheckRun(stubGetClientFn(github.NewClient(tc.mockedClient)), translations.NullTranslationHelper)
+			result, err := handler(context.Background(), createMCPRequest(map[string]any{
+				"owner":        "owner",
+				"repo":         "repo",
+				"check_run_id": float64(4),
+				"page":         float64(2),
+				"perPage":      float64(10),
+			}))
+			require.NoError(t, err)
+
+			var returned CheckRunDetails
+			require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &returned))
+			assert.Equal(t, tc.expected, returned)
+		})
+	}
+}
+
+func Test_CreateCheckRun(t *testing.T) {
+	tool, _ := CreateCheckRun(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+	assert.False(t, *tool.Annotations.ReadOnlyHint)
+	assert.ElementsMatch(t, tool.InputSchema.Required, []string{"owner", "repo", "name", "head_sha"})
+
+	tests := []struct {
+		name         string
+		mockedClient *http.Client
+		requestArgs  map[string]any
+		expectError  string
+	}{
+		{
+			name: "completed check run with annotations",
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(
+					mock.PostReposCheckRunsByOwnerByRepo,
+					http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+						var body map[string]any
+						require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+						assert.Equal(t, "coverage", body["name"])
+						assert.Equal(t, "abc123", body["head_sha"])
+						assert.Equal(t, "failure", body["conclusion"])
+						assert.NotEmpty(t, body["completed_at"])
+						assert.Equal(t, map[string]any{
+							"title":   "Coverage dropped",
+							"summary": "Coverage is 70%",
+							"annotations": []any{map[string]any{
+								"path":             "pkg/server.go",
+								"start_line":       float64(3),
+								"end_line":         float64(5),
+								"annotation_level": "warning",
+								"message":          "Not covered",
+							}},
+						}, body["output"])
+						mockResponse(t, http.StatusCreated, &github.CheckRun{
+							ID:         github.Ptr(int64(4)),
+							Name:       github.Ptr("coverage"),
+							HeadSHA:    github.Ptr("abc123"),
+							Status:     github.Ptr("completed"),
+							Conclusion: github.Ptr("failure"),
+						})(w, r)
+					}),
+				),
+			),
+			requestArgs: map[string]any{
+				"conclusion": "failure",
+				"title":      "Coverage dropped",
+				"summary":    "Coverage is 70%",
+				"annotations": []any{map[string]any{
+					"path":             "pkg/server.go",
+					"start_line":       float64(3),
+					"end_line":         float64(5),
+					"annotation_level": "warning",
+					"message":          "Not covered",
+				}},
+			},
+		},
+		{
+			name:         "output without summary",
+			mockedClient: mock.NewMockedHTTPClient(),
+			requestArgs:  map[string]any{"title": "Coverage dropped"},
+			expectError:  "title and summary are required to set the output of a check run",
+		},
+		{
+			name:         "invalid annotation",
+			mockedClient: mock.NewMockedHTTPClient(),
+			requestArgs: map[string]any{
+				"title":       "Coverage dropped",
+				"summary":     "Coverage is 70%",
+				"annotations": []any{map[string]any{"path": "pkg/server.go", "start_line": float64(5), "end_line": float64(3), "annotation_level": "warning", "message": "Not covered"}},
+			},
+			expectError: "annotation 0 must have a path, a start_line and end_line range, an annotation_level and a message",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, handler := CreateCheckRun(stubGetClientFn(github.NewClient(tc.mockedClient)), translations.NullTranslationHelper)
+			args := map[string]any{"owner": "owner", "repo": "repo", "name": "coverage", "head_sha": "abc123"}
+			for k, v := range tc.requestArgs {
+				args[k] = v
+			}
+			result, err := handler(context.Background(), createMCPRequest(args))
+			require.NoError(t, err)
+
+			if tc.expectError != "" {
+				assert.Equal(t, tc.expectError, getErrorResult(t, result).Text)
+				return
+			}
+			var returned MinimalCheckRun
+			require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &returned))
+			assert.Equal(t, MinimalCheckRun{ID: 4, Name: "coverage", HeadSHA: "abc123", Status: "completed", Conclusion: "failure"}, returned)
+		})
+	}
+}
+
+func Test_UpdateCheckRun(t *testing.T) {
+	tool, _ := UpdateCheckRun(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+	assert.False(t, *tool.Annotations.ReadOnlyHint)
+	assert.ElementsMatch(t, tool.InputSchema.Required, []string{"owner", "repo", "check_run_id"})
+
+	tests := []struct {
+		name         string
+		mockedClient *http.Client
+		requestArgs  map[string]any
+		expectError  string
+	}{
+		{
+			name: "keeps the current name",
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatch(mock.GetReposCheckRunsByOwnerByRepoByCheckRunId, &github.CheckRun{ID: github.Ptr(int64(4)), Name: github.Ptr("coverage")}),
+				mock.WithRequestMatchHandler(
+					mock.PatchReposCheckRunsByOwnerByRepoByCheckRunId,
+					expectRequestBody(t, map[string]any{"name": "coverage", "status": "in_progress"}).andThen(
+						mockResponse(t, http.StatusOK, &github.CheckRun{ID: github.Ptr(int64(4)), Name: github.Ptr("coverage"), Status: github.Ptr("in_progress")}),
+					),
+				),
+			),
+			requestArgs: map[string]any{"status": "in_progress"},
+		},
+		{
+			name:         "no update parameters",
+			mockedClient: mock.NewMockedHTTPClient(),
+			requestArgs:  map[string]any{},
+			expectError:  "No update parameters provided.",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, handler := UpdateCheckRun(stubGetClientFn(github.NewClient(tc.mockedClient)), translations.NullTranslationHelper)
+			args := map[string]any{"owner": "owner", "repo": "repo", "check_run_id": float64(4)}
+			for k, v := range tc.requestArgs {
+				args[k] = v
+			}
+			result, err := handler(context.Background(), createMCPRequest(args))
+			require.NoError(t, err)
+
+			if tc.expectError != "" {
+				assert.Equal(t, tc.expectError, getErrorResult(t, result).Text)
+				return
+			}
+			var returned MinimalCheckRun
+			require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &returned))
+			assert.Equal(t, MinimalCheckRun{ID: 4, Name: "coverage", Status: "in_progress"}, returned)
+		})
+	}
+}
+
+func Test_CreateCommitStatus(t *testing.T) {
+	tool, _ := CreateCommitStatus(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+	assert.False(t, *tool.Annotations.ReadOnlyHint)
+	assert.ElementsMatch(t, tool.InputSchema.Required, []string{"owner", "repo", "sha", "state"})
+
+	mockedClient := mock.NewMockedHTTPClient(
+		mock.WithRequestMatchHandler(
+			mock.PostReposStatusesByOwnerByRepoBySha,
+			expect(t, expectations{
+				path:        "/repos/owner/repo/statuses/abc123",
+				requestBody: map[string]any{"state": "success", "context": "ci/e2e", "target_url": "https://example.com/e2e"},
+			}).andThen(
+				mockResponse(t, http.StatusCreated, &github.RepoStatus{
+					ID:        github.Ptr(int64(3)),
+					State:     github.Ptr("success"),
+					Context:   github.Ptr("ci/e2e"),
+					TargetURL: github.Ptr("https://example.com/e2e"),
+					Creator:   &github.User{Login: github.Ptr("mona")},
+				}),
+			),
+		),
+	)
+
+	_, handler := CreateCommitStatus(stubGetClientFn(github.NewClient(mockedClient)), translations.NullTranslationHelper)
+	result, err := handler(context.Background(), createMCPRequest(map[string]any{
+		"owner":      "owner",
+		"repo":       "repo",
+		"sha":        "abc123",
+		"state":      "success",
+		"context":    "ci/e2e",
+		"target_url": "https://example.com/e2e",
+	}))
+	require.NoError(t, err)
+
+	var returned MinimalCommitStatus
+	require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &returned))
+	assert.Equal(t, MinimalCommitStatus{ID: 3, State: "success", Context: "ci/e2e", TargetURL: "https://example.com/e2e", Creator: "mona"}, returned)
+}
+
+func Test_RerequestCheckSuite(t *testing.T) {
+	tool, _ := RerequestCheckSuite(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+	assert.False(t, *tool.Annotations.ReadOnlyHint)
+	assert.ElementsMatch(t, tool.InputSchema.Required, []string{"owner", "repo", "check_suite_id"})
+
+	mockedClient := mock.NewMockedHTTPClient(
+		mock.WithRequestMatchHandler(
+			mock.PostReposCheckSuitesRerequestByOwnerByRepoByCheckSuiteId,
+			expectPath(t, "/repos/owner/repo/check-suites/8/rerequest").andThen(
+				mockResponse(t, http.StatusCreated, map[string]any{}),
+			),
+		),
+	)
+
+	_, handler := RerequestCheckSuite(stubGetClientFn(github.NewClient(mockedClient)), translations.NullTranslationHelper)
+	result, err := handler(context.Background(), createMCPRequest(map[string]any{"owner": "owner", "repo": "repo", "check_suite_id": float64(8)}))
+	require.NoError(t, err)
+	assert.Equal(t, "Re-requested check suite 8", getTextResult(t, result).Text)
+}
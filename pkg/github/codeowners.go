@@ -0,0 +1,385 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+
+	ghErrors "github.com/github/github-mcp-server/pkg/errors"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/google/go-github/v77/github"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// codeownersPaths are the locations of the CODEOWNERS file, in the order GitHub looks for them.
+var codeownersPaths = []string{".github/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS"}
+
+// maxCodeOwnersPullRequestFiles is the maximum number of files of a pull request whose owners are resolved.
+const maxCodeOwnersPullRequestFiles = 3000
+
+// codeownersRule is a line of a CODEOWNERS file.
+type codeownersRule struct {
+	Pattern string
+	Owners  []string
+	Line    int
+	matcher *regexp.Regexp
+}
+
+// FileCodeOwners are the owners of a file, along with the CODEOWNERS rule they come from.
+type FileCodeOwners struct {
+	Path    string   `json:"path"`
+	Owners  []string `json:"owners"`
+	Pattern string   `json:"pattern,omitempty"`
+	Line    int      `json:"line,omitempty"`
+}
+
+// CodeOwnersResult is the output type of get_code_owners.
+type CodeOwnersResult struct {
+	CodeownersPath string                    `json:"codeowners_path,omitempty"`
+	Ref            string                    `json:"ref,omitempty"`
+	Files          []FileCodeOwners          `json:"files"`
+	Owners         []string                  `json:"owners"`
+	Errors         []*github.CodeownersError `json:"errors,omitempty"`
+	Message        string                    `json:"message,omitempty"`
+}
+
+// parseCodeowners parses the content of a CODEOWNERS file. Lines that can't be parsed as a pattern are
+// skipped, the codeowners errors endpoint reports them.
+func parseCodeowners(content string) []codeownersRule {
+	var rules []codeownersRule
+	for i, line := range strings.Split(content, "\n") {
+		line = stripCodeownersComment(line)
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		pattern := strings.ReplaceAll(fields[0], `\#`, "#")
+		matcher, err := codeownersPatternToRegexp(pattern)
+		if err != nil {
+			continue
+		}
+		rules = append(rules, codeownersRule{
+			Pattern: pattern,
+			Owners:  fields[1:],
+			Line:    i + 1,
+			matcher: matcher,
+		})
+	}
+	return rules
+}
+
+// stripCodeownersComment removes the comment of a CODEOWNERS line. A "#" starts a comment unless it is escaped.
+func stripCodeownersComment(line string) string {
+	for i := 0; i < len(line); i++ {
+		if line[i] == '#' && (i == 0 || line[i-1] != '\\') {
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// codeownersPatternToRegexp converts a CODEOWNERS pattern to a regular expression. The patterns follow the
+// gitignore rules, except that negation and character ranges aren't supported.
+func codeownersPatternToRegexp(pattern string) (*regexp.Regexp, error) {
+	if strings.HasPrefix(pattern, "!") || strings.ContainsAny(pattern, "[]") || strings.Trim(pattern, "/") == "" {
+		return nil, fmt.Errorf("unsupported pattern %s", pattern)
+	}
+	directory := strings.HasSuffix(pattern, "/")
+	trimmed := strings.Trim(pattern, "/")
+	// A pattern with a slash at the beginning or in the middle is relative to the root of the repository
+	anchored := strings.HasPrefix(pattern, "/") || strings.Contains(trimmed, "/")
+
+	var expr strings.Builder
+	expr.WriteString("^")
+	if !anchored {
+		expr.WriteString("(?:.*/)?")
+	}
+	segments := strings.Split(trimmed, "/")
+	for i, segment := range segments {
+		last := i == len(segments)-1
+		if segment == "**" {
+			switch {
+			case last:
+				expr.WriteString(".*")
+			default:
+				expr.WriteString("(?:.*/)?")
+			}
+			continue
+		}
+		for _, r := range segment {
+			switch r {
+			case '*':
+				expr.WriteString("[^/]*")
+			case '?':
+				expr.WriteString("[^/]")
+			default:
+				expr.WriteString(regexp.QuoteMeta(string(r)))
+			}
+		}
+		if !last {
+			expr.WriteString("/")
+		}
+	}
+	last := segments[len(segments)-1]
+	switch {
+	case directory:
+		expr.WriteString("/.*")
+	case strings.Contains(last, "*"):
+		// A wildcard only matches the files of a directory, not the ones of its subdirectories
+	default:
+		// Matching a directory matches everything below it
+		expr.WriteString("(?:/.*)?")
+	}
+	expr.WriteString("$")
+	return regexp.Compile(expr.String())
+}
+
+// resolveCodeOwners returns the owners of a file. As on GitHub, the last matching rule takes precedence.
+func resolveCodeOwners(rules []codeownersRule, path string) FileCodeOwners {
+	path = strings.TrimPrefix(path, "/")
+	for i := len(rules) - 1; i >= 0; i-- {
+		if rules[i].matcher.MatchString(path) {
+			return FileCodeOwners{
+				Path:    path,
+				Owners:  rules[i].Owners,
+				Pattern: rules[i].Pattern,
+				Line:    rules[i].Line,
+			}
+		}
+	}
+	return FileCodeOwners{Path: path, Owners: []string{}}
+}
+
+// getCodeowners gets the CODEOWNERS file of a repository, returning an empty path if there is none.
+func getCodeowners(ctx context.Context, client *github.Client, owner, repo, ref string) (string, []codeownersRule, error) {
+	for _, path := range codeownersPaths {
+		file, _, resp, err := client.Repositories.GetContents(ctx, owner, repo, path, &github.RepositoryContentGetOptions{Ref: ref})
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			continue
+		}
+		if err != nil {
+			_, _ = ghErrors.NewGitHubAPIErrorToCtx(ctx, "failed to get CODEOWNERS", resp, err)
+			return "", nil, fmt.Errorf("failed to get %s: %w", path, err)
+		}
+		_ = resp.Body.Close()
+		if file == nil {
+			continue
+		}
+		content, err := file.GetContent()
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to decode %s: %w", path, err)
+		}
+		return path, parseCodeowners(content), nil
+	}
+	return "", nil, nil
+}
+
+// listPullRequestFilePaths lists the paths of the files changed by a pull request.
+func listPullRequestFilePaths(ctx context.Context, client *github.Client, owner, repo string, pullNumber int) ([]string, error) {
+	var paths []string
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		files, resp, err := client.PullRequests.ListFiles(ctx, owner, repo, pullNumber, opts)
+		if err != nil {
+			_, _ = ghErrors.NewGitHubAPIErrorToCtx(ctx, "failed to list pull request files", resp, err)
+			return nil, fmt.Errorf("failed to list pull request files: %w", err)
+		}
+		_ = resp.Body.Close()
+		for _, file := range files {
+			paths = append(paths, file.GetFilename())
+			// The owners of the previous path of a renamed file must review its removal too
+			if file.GetPreviousFilename() != "" {
+				paths = append(paths, file.GetPreviousFilename())
+			}
+		}
+		if resp.NextPage == 0 || len(paths) >= maxCodeOwnersPullRequestFiles {
+			return paths, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+// codeOwnersOf resolves the owners of the given paths with the CODEOWNERS file of ref.
+func codeOwnersOf(ctx context.Context, client *github.Client, owner, repo, ref string, paths []string) (*CodeOwnersResult, error) {
+	codeownersPath, rules, err := getCodeowners(ctx, client, owner, repo, ref)
+	if err != nil {
+		return nil, err
+	}
+	result := &CodeOwnersResult{
+		CodeownersPath: codeownersPath,
+		Ref:            ref,
+		Files:          make([]FileCodeOwners, 0, len(paths)),
+		Owners:         []string{},
+	}
+	if codeownersPath == "" {
+		result.Message = "No CODEOWNERS file found in .github/, the root or docs/"
+		return result, nil
+	}
+	owners := make(map[string]bool)
+	for _, path := range paths {
+		file := resolveCodeOwners(rules, path)
+		result.Files = append(result.Files, file)
+		for _, o := range file.Owners {
+			if !owners[o] {
+				owners[o] = true
+				result.Owners = append(result.Owners, o)
+			}
+		}
+	}
+	sort.Strings(result.Owners)
+	return result, nil
+}
+
+// codeOwnerReviewers splits code owners into the users and the teams of the repository owner to request
+// reviews from. Email owners, the author and teams of other organizations can't be requested.
+func codeOwnerReviewers(owners []string, repoOwner, author string) github.ReviewersRequest {
+	reviewers := github.ReviewersRequest{Reviewers: []string{}, TeamReviewers: []string{}}
+	for _, o := range owners {
+		if !strings.HasPrefix(o, "@") {
+			continue
+		}
+		name := strings.TrimPrefix(o, "@")
+		if org, team, ok := strings.Cut(name, "/"); ok {
+			if strings.EqualFold(org, repoOwner) {
+				reviewers.TeamReviewers = append(reviewers.TeamReviewers, team)
+			}
+			continue
+		}
+		if !strings.EqualFold(name, author) {
+			reviewers.Reviewers = append(reviewers.Reviewers, name)
+		}
+	}
+	return reviewers
+}
+
+// requestCodeOwnerReviewers requests reviews from the code owners of the files changed by a newly created
+// pull request. It returns an error result if they can't be requested, nil otherwise.
+func requestCodeOwnerReviewers(ctx context.Context, client *github.Client, owner, repo string, pr *github.PullRequest) *mcp.CallToolResult {
+	paths, err := listPullRequestFilePaths(ctx, client, owner, repo, pr.GetNumber())
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("pull request %s was created, but its code owners couldn't be resolved: %v", pr.GetHTMLURL(), err))
+	}
+	codeOwners, err := codeOwnersOf(ctx, client, owner, repo, pr.GetBase().GetRef(), paths)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("pull request %s was created, but its code owners couldn't be resolved: %v", pr.GetHTMLURL(), err))
+	}
+	reviewers := codeOwnerReviewers(codeOwners.Owners, owner, pr.GetUser().GetLogin())
+	if len(reviewers.Reviewers) == 0 && len(reviewers.TeamReviewers) == 0 {
+		return nil
+	}
+	_, resp, err := client.PullRequests.RequestReviewers(ctx, owner, repo, pr.GetNumber(), reviewers)
+	if err != nil {
+		return ghErrors.NewGitHubAPIErrorResponse(ctx,
+			fmt.Sprintf("pull request %s was created, but failed to request reviews from its code owners", pr.GetHTMLURL()),
+			resp,
+			err,
+		)
+	}
+	_ = resp.Body.Close()
+	return nil
+}
+
+// GetCodeOwners creates a tool to resolve the code owners of files or of a pull request.
+func GetCodeOwners(getClient GetClientFn, t translations.TranslationHelperFunc) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("get_code_owners",
+			mcp.WithDescription(t("TOOL_GET_CODE_OWNERS_DESCRIPTION", "Get the code owners of files or of the files changed by a pull request, following the CODEOWNERS file of the repository with GitHub's precedence rules. Also reports the syntax errors of the CODEOWNERS file")),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:        t("TOOL_GET_CODE_OWNERS_USER_TITLE", "Get code owners"),
+				ReadOnlyHint: ToBoolPtr(true),
+			}),
+			mcp.WithString("owner",
+				mcp.Required(),
+				mcp.Description("Repository owner"),
+			),
+			mcp.WithString("repo",
+				mcp.Required(),
+				mcp.Description("Repository name"),
+			),
+			mcp.WithArray("paths",
+				mcp.Description("Paths of the files to get the owners of. Either paths or pullNumber is required"),
+				mcp.Items(map[string]interface{}{
+					"type": "string",
+				}),
+			),
+			mcp.WithNumber("pullNumber",
+				mcp.Description("Pull request number, to get the owners of the files it changes"),
+			),
+			mcp.WithString("ref",
+				mcp.Description("Branch, tag or commit of the CODEOWNERS file. Defaults to the default branch, or to the base branch of the pull request"),
+			),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			owner, err := RequiredParam[string](request, "owner")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			repo, err := RequiredParam[string](request, "repo")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			paths, err := OptionalStringArrayParam(request, "paths")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			pullNumber, err := OptionalIntParam(request, "pullNumber")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			ref, err := OptionalParam[string](request, "ref")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			if len(paths) == 0 && pullNumber == 0 {
+				return mcp.NewToolResultError("either paths or pullNumber is required"), nil
+			}
+
+			client, err := getClient(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get GitHub client: %w", err)
+			}
+
+			if pullNumber != 0 {
+				pr, resp, err := client.PullRequests.Get(ctx, owner, repo, pullNumber)
+				if err != nil {
+					return ghErrors.NewGitHubAPIErrorResponse(ctx,
+						"failed to get pull request",
+						resp,
+						err,
+					), nil
+				}
+				_ = resp.Body.Close()
+				// The CODEOWNERS file of the base branch decides who must review a pull request
+				if ref == "" {
+					ref = pr.GetBase().GetRef()
+				}
+				files, err := listPullRequestFilePaths(ctx, client, owner, repo, pullNumber)
+				if err != nil {
+					return mcp.NewToolResultError(err.Error()), nil
+				}
+				paths = append(paths, files...)
+			}
+
+			result, err := codeOwnersOf(ctx, client, owner, repo, ref, paths)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			if result.CodeownersPath != "" {
+				codeownersErrors, resp, err := client.Repositories.GetCodeownersErrors(ctx, owner, repo, &github.GetCodeownersErrorsOptions{Ref: ref})
+				if err != nil {
+					return ghErrors.NewGitHubAPIErrorResponse(ctx,
+						"failed to get CODEOWNERS errors",
+						resp,
+						err,
+					), nil
+				}
+				_ = resp.Body.Close()
+				result.Errors = codeownersErrors.Errors
+			}
+
+			return MarshalledTextResult(result), nil
+		}
+}
@@ -0,0 +1,204 @@
+package github
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/github/github-mcp-server/internal/toolsnaps"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/google/go-github/v77/github"
+	"github.com/migueleliasweb/go-github-mock/src/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ResolveCodeOwners(t *testing.T) {
+	// The example of the GitHub documentation on CODEOWNERS
+	rules := parseCodeowners(`# This is a comment.
+*       @global-owner1 @global-owner2
+*.js    @js-owner #This is an inline comment.
+*.go docs@example.com
+*.txt @octo-org/octocats
+/build/logs/ @doctocat
+docs/*  docs@example.com
+apps/ @octocat
+/docs/ @doctocat
+/scripts/ @doctocat @octocat
+**/logs @octocat
+/apps/ @octocat
+/apps/github
+/src/ @doctocat
+\#notes @mona
+`)
+
+	tests := []struct {
+		path           string
+		expectedOwners []string
+		expectedLine   int
+	}{
+		{path: "README.md", expectedOwners: []string{"@global-owner1", "@global-owner2"}, expectedLine: 2},
+		{path: "src/index.js", expectedOwners: []string{"@doctocat"}, expectedLine: 14},
+		{path: "lib/index.js", expectedOwners: []string{"@js-owner"}, expectedLine: 3},
+		{path: "main.go", expectedOwners: []string{"docs@example.com"}, expectedLine: 4},
+		{path: "notes/todo.txt", expectedOwners: []string{"@octo-org/octocats"}, expectedLine: 5},
+		{path: "build/logs/out.log", expectedOwners: []string{"@octocat"}, expectedLine: 11},
+		{path: "docs/getting-started.md", expectedOwners: []string{"@doctocat"}, expectedLine: 9},
+		{path: "docs/build-app/troubleshooting.md", expectedOwners: []string{"@doctocat"}, expectedLine: 9},
+		{path: "pkg/docs/readme.md", expectedOwners: []string{"@global-owner1", "@global-owner2"}, expectedLine: 2},
+		{path: "web/apps/main.rb", expectedOwners: []string{"@octocat"}, expectedLine: 8},
+		{path: "apps/main.rb", expectedOwners: []string{"@octocat"}, expectedLine: 12},
+		{path: "apps/github/main.rb", expectedOwners: []string{}, expectedLine: 13},
+		{path: "deep/logs/app/x.log", expectedOwners: []string{"@octocat"}, expectedLine: 11},
+		{path: "#notes", expectedOwners: []string{"@mona"}, expectedLine: 15},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.path, func(t *testing.T) {
+			owners := resolveCodeOwners(rules, tc.path)
+			assert.Equal(t, tc.expectedOwners, owners.Owners)
+			assert.Equal(t, tc.expectedLine, owners.Line)
+		})
+	}
+}
+
+func Test_GetCodeOwners(t *testing.T) {
+	tool, _ := GetCodeOwners(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+	assert.True(t, *tool.Annotations.ReadOnlyHint)
+	assert.ElementsMatch(t, tool.InputSchema.Required, []string{"owner", "repo"})
+
+	codeowners := "* @mona\n/pkg/ @owner/backend\n"
+	// Only the root CODEOWNERS exists
+	getContents := mock.WithRequestMatchHandler(
+		mock.GetReposContentsByOwnerByRepoByPath,
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/repos/owner/repo/contents/CODEOWNERS" {
+				mockResponse(t, http.StatusNotFound, map[string]string{"message": "Not Found"})(w, r)
+				return
+			}
+			assert.Equal(t, "main", r.URL.Query().Get("ref"))
+			mockResponse(t, http.StatusOK, &github.RepositoryContent{
+				Type:     github.Ptr("file"),
+				Encoding: github.Ptr("base64"),
+				Content:  github.Ptr(base64.StdEncoding.EncodeToString([]byte(codeowners))),
+			})(w, r)
+		}),
+	)
+	codeownersErrors := []*github.CodeownersError{{
+		Line:    3,
+		Column:  1,
+		Kind:    "Invalid owner",
+		Source:  "/docs/ @unknown",
+		Message: "Invalid owner on line 3",
+		Path:    "CODEOWNERS",
+	}}
+
+	tests := []struct {
+		name           string
+		mockedClient   *http.Client
+		requestArgs    map[string]any
+		expectError    string
+		expectedResult CodeOwnersResult
+	}{
+		{
+			name: "owners of paths",
+			mockedClient: mock.NewMockedHTTPClient(
+				getContents,
+				mock.WithRequestMatchHandler(
+					mock.GetReposCodeownersErrorsByOwnerByRepo,
+					expectQueryParams(t, map[string]string{"ref": "main"}).andThen(
+						mockResponse(t, http.StatusOK, &github.CodeownersErrors{Errors: codeownersErrors}),
+					),
+				),
+			),
+			requestArgs: map[string]any{
+				"paths": []any{"README.md", "pkg/server.go"},
+				"ref":   "main",
+			},
+			expectedResult: CodeOwnersResult{
+				CodeownersPath: "CODEOWNERS",
+				Ref:            "main",
+				Files: []FileCodeOwners{
+					{Path: "README.md", Owners: []string{"@mona"}, Pattern: "*", Line: 1},
+					{Path: "pkg/server.go", Owners: []string{"@owner/backend"}, Pattern: "/pkg/", Line: 2},
+				},
+				Owners: []string{"@mona", "@owner/backend"},
+				Errors: codeownersErrors,
+			},
+		},
+		{
+			name: "owners of a pull request",
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatch(
+					mock.GetReposPullsByOwnerByRepoByPullNumber,
+					&github.PullRequest{Number: github.Ptr(42), Base: &github.PullRequestBranch{Ref: github.Ptr("main")}},
+				),
+				mock.WithRequestMatch(
+					mock.GetReposPullsFilesByOwnerByRepoByPullNumber,
+					[]*github.CommitFile{
+						{Filename: github.Ptr("pkg/new.go"), PreviousFilename: github.Ptr("lib/old.go")},
+					},
+				),
+				getContents,
+				mock.WithRequestMatch(
+					mock.GetReposCodeownersErrorsByOwnerByRepo,
+					&github.CodeownersErrors{Errors: []*github.CodeownersError{}},
+				),
+			),
+			requestArgs: map[string]any{"pullNumber": float64(42)},
+			expectedResult: CodeOwnersResult{
+				CodeownersPath: "CODEOWNERS",
+				Ref:            "main",
+				Files: []FileCodeOwners{
+					{Path: "pkg/new.go", Owners: []string{"@owner/backend"}, Pattern: "/pkg/", Line: 2},
+					{Path: "lib/old.go", Owners: []string{"@mona"}, Pattern: "*", Line: 1},
+				},
+				Owners: []string{"@mona", "@owner/backend"},
+			},
+		},
+		{
+			name: "no CODEOWNERS",
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(
+					mock.GetReposContentsByOwnerByRepoByPath,
+					mockResponse(t, http.StatusNotFound, map[string]string{"message": "Not Found"}),
+				),
+			),
+			requestArgs: map[string]any{"paths": []any{"README.md"}},
+			expectedResult: CodeOwnersResult{
+				Files:   []FileCodeOwners{},
+				Owners:  []string{},
+				Message: "No CODEOWNERS file found in .github/, the root or docs/",
+			},
+		},
+		{
+			name:         "neither paths nor pull request",
+			mockedClient: mock.NewMockedHTTPClient(),
+			requestArgs:  map[string]any{},
+			expectError:  "either paths or pullNumber is required",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, handler := GetCodeOwners(stubGetClientFn(github.NewClient(tc.mockedClient)), translations.NullTranslationHelper)
+			args := map[string]any{"owner": "owner", "repo": "repo"}
+			for k, v := range tc.requestArgs {
+				args[k] = v
+			}
+			result, err := handler(context.Background(), createMCPRequest(args))
+			require.NoError(t, err)
+
+			if tc.expectError != "" {
+				assert.Equal(t, tc.expectError, getErrorResult(t, result).Text)
+				return
+			}
+			var codeOwners CodeOwnersResult
+			require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &codeOwners))
+			assert.Equal(t, tc.expectedResult, codeOwners)
+		})
+	}
+}
@@ -0,0 +1,523 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	ghErrors "github.com/github/github-mcp-server/pkg/errors"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/google/go-github/v77/github"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// repositoryPermissionDescription describes the permission parameters of the repository access tools.
+const repositoryPermissionDescription = "Permission to grant: pull, triage, push, maintain, admin, or the name of a custom repository role of the organization"
+
+// RepositoryCollaborator is the output type of list_repository_collaborators.
+type RepositoryCollaborator struct {
+	Login      string `json:"login"`
+	ID         int64  `json:"id"`
+	Type       string `json:"type,omitempty"`
+	Permission string `json:"permission"`
+	ProfileURL string `json:"profile_url,omitempty"`
+}
+
+// CollaboratorPermission is the output type of get_collaborator_permission.
+type CollaboratorPermission struct {
+	Login string `json:"login"`
+	// Permission is the legacy permission: admin, write, read or none
+	Permission string `json:"permission"`
+	// RoleName is the name of the role, which can be a custom repository role
+	RoleName string `json:"role_name,omitempty"`
+}
+
+// RepositoryTeam is the output type of list_repository_teams.
+type RepositoryTeam struct {
+	ID          int64  `json:"id"`
+	Slug        string `json:"slug"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Privacy     string `json:"privacy,omitempty"`
+	Permission  string `json:"permission"`
+	Parent      string `json:"parent,omitempty"`
+}
+
+// highestPermission returns the highest permission of a permissions map of the API.
+func highestPermission(permissions map[string]bool) string {
+	for _, permission := range []string{"admin", "maintain", "push", "triage", "pull"} {
+		if permissions[permission] {
+			return permission
+		}
+	}
+	return ""
+}
+
+// ListRepositoryCollaborators creates a tool to list the collaborators of a repository.
+func ListRepositoryCollaborators(getClient GetClientFn, t translations.TranslationHelperFunc) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("list_repository_collaborators",
+			mcp.WithDescription(t("TOOL_LIST_REPOSITORY_COLLABORATORS_DESCRIPTION", "List the collaborators of a GitHub repository and their permission, including organization members and outside collaborators")),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:        t("TOOL_LIST_REPOSITORY_COLLABORATORS_USER_TITLE", "List repository collaborators"),
+				ReadOnlyHint: ToBoolPtr(true),
+			}),
+			mcp.WithString("owner",
+				mcp.Required(),
+				mcp.Description("Repository owner"),
+			),
+			mcp.WithString("repo",
+				mcp.Required(),
+				mcp.Description("Repository name"),
+			),
+			mcp.WithString("affiliation",
+				mcp.Description("Filter by affiliation: outside collaborators, direct collaborators (with permission on the repository itself) or all (default)"),
+				mcp.Enum("outside", "direct", "all"),
+			),
+			mcp.WithString("permission",
+				mcp.Description("Only list the collaborators with this permission"),
+				mcp.Enum("pull", "triage", "push", "maintain", "admin"),
+			),
+			WithPagination(),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			owner, err := RequiredParam[string](request, "owner")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			repo, err := RequiredParam[string](request, "repo")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			affiliation, err := OptionalParam[string](request, "affiliation")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			permission, err := OptionalParam[string](request, "permission")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			pagination, err := OptionalPaginationParams(request)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			client, err := getClient(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get GitHub client: %w", err)
+			}
+
+			users, resp, err := client.Repositories.ListCollaborators(ctx, owner, repo, &github.ListCollaboratorsOptions{
+				Affiliation: affiliation,
+				Permission:  permission,
+				ListOptions: github.ListOptions{
+					Page:    pagination.Page,
+					PerPage: pagination.PerPage,
+				},
+			})
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx,
+					"failed to list collaborators",
+					resp,
+					err,
+				), nil
+			}
+			defer func() { _ = resp.Body.Close() }()
+
+			collaborators := make([]RepositoryCollaborator, 0, len(users))
+			for _, user := range users {
+				collaborator := RepositoryCollaborator{
+					Login:      user.GetLogin(),
+					ID:         user.GetID(),
+					Type:       user.GetType(),
+					Permission: user.GetRoleName(),
+					ProfileURL: user.GetHTMLURL(),
+				}
+				if collaborator.Permission == "" {
+					collaborator.Permission = highestPermission(user.GetPermissions())
+				}
+				collaborators = append(collaborators, collaborator)
+			}
+
+			return MarshalledTextResult(collaborators), nil
+		}
+}
+
+// GetCollaboratorPermission creates a tool to get the permission of a user on a repository.
+func GetCollaboratorPermission(getClient GetClientFn, t translations.TranslationHelperFunc) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("get_collaborator_permission",
+			mcp.WithDescription(t("TOOL_GET_COLLABORATOR_PERMISSION_DESCRIPTION", "Get the permission of a user on a GitHub repository, whether it comes from being a collaborator, a team or the organization")),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:        t("TOOL_GET_COLLABORATOR_PERMISSION_USER_TITLE", "Get collaborator permission"),
+				ReadOnlyHint: ToBoolPtr(true),
+			}),
+			mcp.WithString("owner",
+				mcp.Required(),
+				mcp.Description("Repository owner"),
+			),
+			mcp.WithString("repo",
+				mcp.Required(),
+				mcp.Description("Repository name"),
+			),
+			mcp.WithString("username",
+				mcp.Required(),
+				mcp.Description("Username of the user"),
+			),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			owner, err := RequiredParam[string](request, "owner")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			repo, err := RequiredParam[string](request, "repo")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			username, err := RequiredParam[string](request, "username")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			client, err := getClient(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get GitHub client: %w", err)
+			}
+
+			level, resp, err := client.Repositories.GetPermissionLevel(ctx, owner, repo, username)
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx,
+					"failed to get collaborator permission",
+					resp,
+					err,
+				), nil
+			}
+			defer func() { _ = resp.Body.Close() }()
+
+			return MarshalledTextResult(CollaboratorPermission{
+				Login:      level.GetUser().GetLogin(),
+				Permission: level.GetPermission(),
+				RoleName:   level.GetRoleName(),
+			}), nil
+		}
+}
+
+// ListRepositoryTeams creates a tool to list the teams with access to a repository.
+func ListRepositoryTeams(getClient GetClientFn, t translations.TranslationHelperFunc) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("list_repository_teams",
+			mcp.WithDescription(t("TOOL_LIST_REPOSITORY_TEAMS_DESCRIPTION", "List the teams with access to a GitHub repository and their permission")),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:        t("TOOL_LIST_REPOSITORY_TEAMS_USER_TITLE", "List repository teams"),
+				ReadOnlyHint: ToBoolPtr(true),
+			}),
+			mcp.WithString("owner",
+				mcp.Required(),
+				mcp.Description("Repository owner"),
+			),
+			mcp.WithString("repo",
+				mcp.Required(),
+				mcp.Description("Repository name"),
+			),
+			WithPagination(),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			owner, err := RequiredParam[string](request, "owner")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			repo, err := RequiredParam[string](request, "repo")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			pagination, err := OptionalPaginationParams(request)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			client, err := getClient(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get GitHub client: %w", err)
+			}
+
+			teams, resp, err := client.Repositories.ListTeams(ctx, owner, repo, &github.ListOptions{
+				Page:    pagination.Page,
+				PerPage: pagination.PerPage,
+			})
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx,
+					"failed to list repository teams",
+					resp,
+					err,
+				), nil
+			}
+			defer func() { _ = resp.Body.Close() }()
+
+			result := make([]RepositoryTeam, 0, len(teams))
+			for _, team := range teams {
+				result = append(result, RepositoryTeam{
+					ID:          team.GetID(),
+					Slug:        team.GetSlug(),
+					Name:        team.GetName(),
+					Description: team.GetDescription(),
+					Privacy:     team.GetPrivacy(),
+					Permission:  team.GetPermission(),
+					Parent:      team.GetParent().GetSlug(),
+				})
+			}
+
+			return MarshalledTextResult(result), nil
+		}
+}
+
+// AddCollaborator creates a tool to invite a user to collaborate on a repository.
+func AddCollaborator(getClient GetClientFn, t translations.TranslationHelperFunc) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("add_collaborator",
+			mcp.WithDescription(t("TOOL_ADD_COLLABORATOR_DESCRIPTION", "Invite a user to collaborate on a GitHub repository. The user gets access once they accept the invitation. If the user already is a collaborator, their permission is set to the given permission instead, which may lower it")),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:        t("TOOL_ADD_COLLABORATOR_USER_TITLE", "Add collaborator"),
+				ReadOnlyHint: ToBoolPtr(false),
+			}),
+			mcp.WithString("owner",
+				mcp.Required(),
+				mcp.Description("Repository owner"),
+			),
+			mcp.WithString("repo",
+				mcp.Required(),
+				mcp.Description("Repository name"),
+			),
+			mcp.WithString("username",
+				mcp.Required(),
+				mcp.Description("Username of the user to add"),
+			),
+			mcp.WithString("permission",
+				mcp.Description(repositoryPermissionDescription+". Defaults to push for a new collaborator, and is required when the user already is a collaborator. Only applies to organization repositories"),
+			),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			owner, err := RequiredParam[string](request, "owner")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			repo, err := RequiredParam[string](request, "repo")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			username, err := RequiredParam[string](request, "username")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			permission, err := OptionalParam[string](request, "permission")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			client, err := getClient(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get GitHub client: %w", err)
+			}
+
+			// Without a permission the API applies push, which would lower the access of an admin or maintainer
+			if permission == "" {
+				isCollaborator, resp, err := client.Repositories.IsCollaborator(ctx, owner, repo, username)
+				if err != nil {
+					return ghErrors.NewGitHubAPIErrorResponse(ctx,
+						"failed to check collaborator",
+						resp,
+						err,
+					), nil
+				}
+				if isCollaborator {
+					return mcp.NewToolResultError(fmt.Sprintf("%s already has access to %s/%s, pass the permission to change it. get_collaborator_permission returns their current permission", username, owner, repo)), nil
+				}
+			}
+
+			invitation, resp, err := client.Repositories.AddCollaborator(ctx, owner, repo, username, &github.RepositoryAddCollaboratorOptions{
+				Permission: permission,
+			})
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx,
+					"failed to add collaborator",
+					resp,
+					err,
+				), nil
+			}
+			defer func() { _ = resp.Body.Close() }()
+
+			// The API answers 204 when the user already is a collaborator or an organization member
+			if resp.StatusCode == http.StatusNoContent {
+				return mcp.NewToolResultText(fmt.Sprintf("%s already has access to %s/%s, their permission was updated", username, owner, repo)), nil
+			}
+			return mcp.NewToolResultText(fmt.Sprintf("Invited %s to %s/%s with %s permission (invitation %d)", username, owner, repo, invitation.GetPermissions(), invitation.GetID())), nil
+		}
+}
+
+// RemoveCollaborator creates a tool to remove a collaborator from a repository.
+func RemoveCollaborator(getClient GetClientFn, t translations.TranslationHelperFunc) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("remove_collaborator",
+			mcp.WithDescription(t("TOOL_REMOVE_COLLABORATOR_DESCRIPTION", "Remove a collaborator from a GitHub repository and cancel their pending invitations to it. Access granted through teams or the organization isn't affected")),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:           t("TOOL_REMOVE_COLLABORATOR_USER_TITLE", "Remove collaborator"),
+				ReadOnlyHint:    ToBoolPtr(false),
+				DestructiveHint: ToBoolPtr(true),
+			}),
+			mcp.WithString("owner",
+				mcp.Required(),
+				mcp.Description("Repository owner"),
+			),
+			mcp.WithString("repo",
+				mcp.Required(),
+				mcp.Description("Repository name"),
+			),
+			mcp.WithString("username",
+				mcp.Required(),
+				mcp.Description("Username of the collaborator to remove"),
+			),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			owner, err := RequiredParam[string](request, "owner")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			repo, err := RequiredParam[string](request, "repo")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			username, err := RequiredParam[string](request, "username")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			client, err := getClient(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get GitHub client: %w", err)
+			}
+
+			// A pending invitation would still let the user in after the removal
+			cancelled := 0
+			opts := &github.ListOptions{PerPage: 100}
+			for {
+				invitations, resp, err := client.Repositories.ListInvitations(ctx, owner, repo, opts)
+				if err != nil {
+					return ghErrors.NewGitHubAPIErrorResponse(ctx,
+						"failed to list invitations",
+						resp,
+						err,
+					), nil
+				}
+				_ = resp.Body.Close()
+				for _, invitation := range invitations {
+					if !strings.EqualFold(invitation.GetInvitee().GetLogin(), username) {
+						continue
+					}
+					resp, err := client.Repositories.DeleteInvitation(ctx, owner, repo, invitation.GetID())
+					if err != nil {
+						return ghErrors.NewGitHubAPIErrorResponse(ctx,
+							fmt.Sprintf("failed to cancel invitation %d", invitation.GetID()),
+							resp,
+							err,
+						), nil
+					}
+					_ = resp.Body.Close()
+					cancelled++
+				}
+				if resp.NextPage == 0 {
+					break
+				}
+				opts.Page = resp.NextPage
+			}
+
+			resp, err := client.Repositories.RemoveCollaborator(ctx, owner, repo, username)
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx,
+					"failed to remove collaborator",
+					resp,
+					err,
+				), nil
+			}
+			defer func() { _ = resp.Body.Close() }()
+
+			message := fmt.Sprintf("Removed %s from the collaborators of %s/%s", username, owner, repo)
+			if cancelled > 0 {
+				message += fmt.Sprintf(" and cancelled %d pending invitation(s)", cancelled)
+			}
+			return mcp.NewToolResultText(message), nil
+		}
+}
+
+// SetTeamRepositoryPermission creates a tool to grant a team access to a repository.
+func SetTeamRepositoryPermission(getClient GetClientFn, t translations.TranslationHelperFunc) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("set_team_repository_permission",
+			mcp.WithDescription(t("TOOL_SET_TEAM_REPOSITORY_PERMISSION_DESCRIPTION", "Set the permission of an organization team on a repository of the organization, replacing its current permission. Use the permission none to remove the team's access")),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:           t("TOOL_SET_TEAM_REPOSITORY_PERMISSION_USER_TITLE", "Set team repository permission"),
+				ReadOnlyHint:    ToBoolPtr(false),
+				DestructiveHint: ToBoolPtr(true),
+			}),
+			mcp.WithString("owner",
+				mcp.Required(),
+				mcp.Description("Repository owner, the organization of the team"),
+			),
+			mcp.WithString("repo",
+				mcp.Required(),
+				mcp.Description("Repository name"),
+			),
+			mcp.WithString("team_slug",
+				mcp.Required(),
+				mcp.Description("Slug of the team"),
+			),
+			mcp.WithString("permission",
+				mcp.Required(),
+				mcp.Description(repositoryPermissionDescription+", or none"),
+			),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			owner, err := RequiredParam[string](request, "owner")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			repo, err := RequiredParam[string](request, "repo")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			teamSlug, err := RequiredParam[string](request, "team_slug")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			permission, err := RequiredParam[string](request, "permission")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			client, err := getClient(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get GitHub client: %w", err)
+			}
+
+			if permission == "none" {
+				resp, err := client.Teams.RemoveTeamRepoBySlug(ctx, owner, teamSlug, owner, repo)
+				if err != nil {
+					return ghErrors.NewGitHubAPIErrorResponse(ctx,
+						"failed to remove team from repository",
+						resp,
+						err,
+					), nil
+				}
+				defer func() { _ = resp.Body.Close() }()
+				return mcp.NewToolResultText(fmt.Sprintf("Removed the access of team %s to %s/%s", teamSlug, owner, repo)), nil
+			}
+
+			resp, err := client.Teams.AddTeamRepoBySlug(ctx, owner, teamSlug, owner, repo, &github.TeamAddTeamRepoOptions{
+				Permission: permission,
+			})
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx,
+					"failed to set team repository permission",
+					resp,
+					err,
+				), nil
+			}
+			defer func() { _ = resp.Body.Close() }()
+
+			return mcp.NewToolResultText(fmt.Sprintf("Set the permission of team %s on %s/%s to %s", teamSlug, owner, repo, permission)), nil
+		}
+}
@@ -0,0 +1,330 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/github/github-mcp-server/internal/toolsnaps"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/google/go-github/v77/github"
+	"github.com/migueleliasweb/go-github-mock/src/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ListRepositoryCollaborators(t *testing.T) {
+	tool, _ := ListRepositoryCollaborators(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+	assert.True(t, *tool.Annotations.ReadOnlyHint)
+	assert.ElementsMatch(t, tool.InputSchema.Required, []string{"owner", "repo"})
+
+	mockedClient := mock.NewMockedHTTPClient(
+		mock.WithRequestMatchHandler(
+			mock.GetReposCollaboratorsByOwnerByRepo,
+			expectQueryParams(t, map[string]string{"affiliation": "outside", "page": "1", "per_page": "30"}).andThen(
+				mockResponse(t, http.StatusOK, []*github.User{
+					{Login: github.Ptr("mona"), ID: github.Ptr(int64(1)), Type: github.Ptr("User"), RoleName: github.Ptr("maintain")},
+					{Login: github.Ptr("hubot"), ID: github.Ptr(int64(2)), Type: github.Ptr("Bot"), Permissions: map[string]bool{"pull": true, "triage": true, "push": true}},
+				}),
+			),
+		),
+	)
+	_, handler := ListRepositoryCollaborators(stubGetClientFn(github.NewClient(mockedClient)), translations.NullTranslationHelper)
+	result, err := handler(context.Background(), createMCPRequest(map[string]any{
+		"owner":       "owner",
+		"repo":        "repo",
+		"affiliation": "outside",
+	}))
+	require.NoError(t, err)
+
+	var collaborators []RepositoryCollaborator
+	require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &collaborators))
+	assert.Equal(t, []RepositoryCollaborator{
+		{Login: "mona", ID: 1, Type: "User", Permission: "maintain"},
+		{Login: "hubot", ID: 2, Type: "Bot", Permission: "push"},
+	}, collaborators)
+}
+
+func Test_GetCollaboratorPermission(t *testing.T) {
+	tool, _ := GetCollaboratorPermission(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+	assert.True(t, *tool.Annotations.ReadOnlyHint)
+	assert.ElementsMatch(t, tool.InputSchema.Required, []string{"owner", "repo", "username"})
+
+	mockedClient := mock.NewMockedHTTPClient(
+		mock.WithRequestMatchHandler(
+			mock.GetReposCollaboratorsPermissionByOwnerByRepoByUsername,
+			expectPath(t, "/repos/owner/repo/collaborators/mona/permission").andThen(
+				mockResponse(t, http.StatusOK, &github.RepositoryPermissionLevel{
+					Permission: github.Ptr("write"),
+					RoleName:   github.Ptr("maintain"),
+					User:       &github.User{Login: github.Ptr("mona")},
+				}),
+			),
+		),
+	)
+	_, handler := GetCollaboratorPermission(stubGetClientFn(github.NewClient(mockedClient)), translations.NullTranslationHelper)
+	result, err := handler(context.Background(), createMCPRequest(map[string]any{
+		"owner":    "owner",
+		"repo":     "repo",
+		"username": "mona",
+	}))
+	require.NoError(t, err)
+
+	var permission CollaboratorPermission
+	require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &permission))
+	assert.Equal(t, CollaboratorPermission{Login: "mona", Permission: "write", RoleName: "maintain"}, permission)
+}
+
+func Test_ListRepositoryTeams(t *testing.T) {
+	tool, _ := ListRepositoryTeams(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+	assert.True(t, *tool.Annotations.ReadOnlyHint)
+	assert.ElementsMatch(t, tool.InputSchema.Required, []string{"owner", "repo"})
+
+	mockedClient := mock.NewMockedHTTPClient(
+		mock.WithRequestMatch(
+			mock.GetReposTeamsByOwnerByRepo,
+			[]*github.Team{{
+				ID:         github.Ptr(int64(7)),
+				Slug:       github.Ptr("backend"),
+				Name:       github.Ptr("Backend"),
+				Privacy:    github.Ptr("closed"),
+				Permission: github.Ptr("push"),
+				Parent:     &github.Team{Slug: github.Ptr("engineering")},
+			}},
+		),
+	)
+	_, handler := ListRepositoryTeams(stubGetClientFn(github.NewClient(mockedClient)), translations.NullTranslationHelper)
+	result, err := handler(context.Background(), createMCPRequest(map[string]any{
+		"owner": "owner",
+		"repo":  "repo",
+	}))
+	require.NoError(t, err)
+
+	var teams []RepositoryTeam
+	require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &teams))
+	assert.Equal(t, []RepositoryTeam{
+		{ID: 7, Slug: "backend", Name: "Backend", Privacy: "closed", Permission: "push", Parent: "engineering"},
+	}, teams)
+}
+
+func Test_AddCollaborator(t *testing.T) {
+	tool, _ := AddCollaborator(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+	assert.False(t, *tool.Annotations.ReadOnlyHint)
+	assert.ElementsMatch(t, tool.InputSchema.Required, []string{"owner", "repo", "username"})
+
+	tests := []struct {
+		name            string
+		mockedClient    *http.Client
+		permission      string
+		expectError     string
+		expectedMessage string
+	}{
+		{
+			name:       "invites a new collaborator",
+			permission: "triage",
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(
+					mock.PutReposCollaboratorsByOwnerByRepoByUsername,
+					expectRequestBody(t, map[string]any{"permission": "triage"}).andThen(
+						mockResponse(t, http.StatusCreated, &github.CollaboratorInvitation{
+							ID:          github.Ptr(int64(3)),
+							Permissions: github.Ptr("triage"),
+						}),
+					),
+				),
+			),
+			expectedMessage: "Invited mona to owner/repo with triage permission (invitation 3)",
+		},
+		{
+			name:       "updates an existing collaborator",
+			permission: "triage",
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(
+					mock.PutReposCollaboratorsByOwnerByRepoByUsername,
+					http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+						w.WriteHeader(http.StatusNoContent)
+					}),
+				),
+			),
+			expectedMessage: "mona already has access to owner/repo, their permission was updated",
+		},
+		{
+			name: "invites a new collaborator with the default permission",
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(
+					mock.GetReposCollaboratorsByOwnerByRepoByUsername,
+					mockResponse(t, http.StatusNotFound, `{"message": "Not Found"}`),
+				),
+				mock.WithRequestMatch(
+					mock.PutReposCollaboratorsByOwnerByRepoByUsername,
+					&github.CollaboratorInvitation{ID: github.Ptr(int64(4)), Permissions: github.Ptr("write")},
+				),
+			),
+			expectedMessage: "Invited mona to owner/repo with write permission (invitation 4)",
+		},
+		{
+			name: "permission is required for an existing collaborator",
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(
+					mock.GetReposCollaboratorsByOwnerByRepoByUsername,
+					mockResponse(t, http.StatusNoContent, nil),
+				),
+			),
+			expectError: "mona already has access to owner/repo, pass the permission to change it",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, handler := AddCollaborator(stubGetClientFn(github.NewClient(tc.mockedClient)), translations.NullTranslationHelper)
+			args := map[string]any{"owner": "owner", "repo": "repo", "username": "mona"}
+			if tc.permission != "" {
+				args["permission"] = tc.permission
+			}
+			result, err := handler(context.Background(), createMCPRequest(args))
+			require.NoError(t, err)
+			if tc.expectError != "" {
+				assert.Contains(t, getErrorResult(t, result).Text, tc.expectError)
+				return
+			}
+			assert.Equal(t, tc.expectedMessage, getTextResult(t, result).Text)
+		})
+	}
+}
+
+func Test_RemoveCollaborator(t *testing.T) {
+	tool, _ := RemoveCollaborator(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+	assert.False(t, *tool.Annotations.ReadOnlyHint)
+	assert.True(t, *tool.Annotations.DestructiveHint)
+	assert.ElementsMatch(t, tool.InputSchema.Required, []string{"owner", "repo", "username"})
+
+	tests := []struct {
+		name            string
+		mockedClient    *http.Client
+		expectError     string
+		expectedMessage string
+	}{
+		{
+			name: "removes the collaborator and their invitation",
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatch(
+					mock.GetReposInvitationsByOwnerByRepo,
+					[]*github.RepositoryInvitation{
+						{ID: github.Ptr(int64(1)), Invitee: &github.User{Login: github.Ptr("hubot")}},
+						{ID: github.Ptr(int64(2)), Invitee: &github.User{Login: github.Ptr("Mona")}},
+					},
+				),
+				mock.WithRequestMatchHandler(
+					mock.DeleteReposInvitationsByOwnerByRepoByInvitationId,
+					expectPath(t, "/repos/owner/repo/invitations/2").andThen(
+						mockResponse(t, http.StatusNoContent, nil),
+					),
+				),
+				mock.WithRequestMatchHandler(
+					mock.DeleteReposCollaboratorsByOwnerByRepoByUsername,
+					expectPath(t, "/repos/owner/repo/collaborators/mona").andThen(
+						mockResponse(t, http.StatusNoContent, nil),
+					),
+				),
+			),
+			expectedMessage: "Removed mona from the collaborators of owner/repo and cancelled 1 pending invitation(s)",
+		},
+		{
+			name: "removal fails",
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatch(
+					mock.GetReposInvitationsByOwnerByRepo,
+					[]*github.RepositoryInvitation{},
+				),
+				mock.WithRequestMatchHandler(
+					mock.DeleteReposCollaboratorsByOwnerByRepoByUsername,
+					mockResponse(t, http.StatusForbidden, map[string]string{"message": "Must have admin rights to Repository."}),
+				),
+			),
+			expectError: "failed to remove collaborator",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, handler := RemoveCollaborator(stubGetClientFn(github.NewClient(tc.mockedClient)), translations.NullTranslationHelper)
+			result, err := handler(context.Background(), createMCPRequest(map[string]any{
+				"owner":    "owner",
+				"repo":     "repo",
+				"username": "mona",
+			}))
+			require.NoError(t, err)
+
+			if tc.expectError != "" {
+				assert.Contains(t, getErrorResult(t, result).Text, tc.expectError)
+				return
+			}
+			assert.Equal(t, tc.expectedMessage, getTextResult(t, result).Text)
+		})
+	}
+}
+
+func Test_SetTeamRepositoryPermission(t *testing.T) {
+	tool, _ := SetTeamRepositoryPermission(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+	assert.False(t, *tool.Annotations.ReadOnlyHint)
+	assert.True(t, *tool.Annotations.DestructiveHint)
+	assert.ElementsMatch(t, tool.InputSchema.Required, []string{"owner", "repo", "team_slug", "permission"})
+
+	tests := []struct {
+		name            string
+		mockedClient    *http.Client
+		permission      string
+		expectedMessage string
+	}{
+		{
+			name: "sets the permission",
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(
+					mock.PutOrgsTeamsReposByOrgByTeamSlugByOwnerByRepo,
+					expect(t, expectations{
+						path:        "/orgs/owner/teams/backend/repos/owner/repo",
+						requestBody: map[string]any{"permission": "maintain"},
+					}).andThen(
+						mockResponse(t, http.StatusNoContent, nil),
+					),
+				),
+			),
+			permission:      "maintain",
+			expectedMessage: "Set the permission of team backend on owner/repo to maintain",
+		},
+		{
+			name: "removes the access",
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(
+					mock.DeleteOrgsTeamsReposByOrgByTeamSlugByOwnerByRepo,
+					expectPath(t, "/orgs/owner/teams/backend/repos/owner/repo").andThen(
+						mockResponse(t, http.StatusNoContent, nil),
+					),
+				),
+			),
+			permission:      "none",
+			expectedMessage: "Removed the access of team backend to owner/repo",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, handler := SetTeamRepositoryPermission(stubGetClientFn(github.NewClient(tc.mockedClient)), translations.NullTranslationHelper)
+			result, err := handler(context.Background(), createMCPRequest(map[string]any{
+				"owner":      "owner",
+				"repo":       "repo",
+				"team_slug":  "backend",
+				"permission": tc.permission,
+			}))
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedMessage, getTextResult(t, result).Text)
+		})
+	}
+}
@@ -6,6 +6,7 @@ import (
 
 	ghErrors "github.com/github/github-mcp-server/pkg/errors"
 	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/google/go-github/v77/github"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 	"github.com/shurcooL/githubv4"
@@ -249,3 +250,130 @@ func GetTeamMembers(getGQLClient GetGQLClientFn, t translations.TranslationHelpe
 			return MarshalledTextResult(members), nil
 		}
 }
+
+// AccessCheck is the outcome of probing a single area of a repository with the current token.
+type AccessCheck struct {
+	Area        string                      `json:"area"`
+	Accessible  bool                        `json:"accessible"`
+	Status      int                         `json:"status,omitempty"`
+	Category    ghErrors.ErrorCategory      `json:"category,omitempty"`
+	Message     string                      `json:"message,omitempty"`
+	Access      *ghErrors.AccessDiagnostics `json:"access,omitempty"`
+	Remediation string                      `json:"remediation,omitempty"`
+}
+
+// AccessReport summarizes what the current token can read in a repository.
+type AccessReport struct {
+	Owner       string        `json:"owner"`
+	Repo        string        `json:"repo"`
+	TokenScopes []string      `json:"token_scopes,omitempty"`
+	Checks      []AccessCheck `json:"checks"`
+}
+
+type accessProbe struct {
+	area  string
+	probe func(ctx context.Context, client *github.Client, owner, repo string) (*github.Response, error)
+}
+
+// accessProbes request a single item from each area, which is enough to tell whether the token can read it.
+var accessProbes = []accessProbe{
+	{"repository", func(ctx context.Context, client *github.Client, owner, repo string) (*github.Response, error) {
+		_, resp, err := client.Repositories.Get(ctx, owner, repo)
+		return resp, err
+	}},
+	{"contents", func(ctx context.Context, client *github.Client, owner, repo string) (*github.Response, error) {
+		_, resp, err := client.Repositories.ListCommits(ctx, owner, repo, &github.CommitsListOptions{ListOptions: github.ListOptions{PerPage: 1}})
+		return resp, err
+	}},
+	{"issues", func(ctx context.Context, client *github.Client, owner, repo string) (*github.Response, error) {
+		_, resp, err := client.Issues.ListByRepo(ctx, owner, repo, &github.IssueListByRepoOptions{ListOptions: github.ListOptions{PerPage: 1}})
+		return resp, err
+	}},
+	{"pull_requests", func(ctx context.Context, client *github.Client, owner, repo string) (*github.Response, error) {
+		_, resp, err := client.PullRequests.List(ctx, owner, repo, &github.PullRequestListOptions{ListOptions: github.ListOptions{PerPage: 1}})
+		return resp, err
+	}},
+	{"actions", func(ctx context.Context, client *github.Client, owner, repo string) (*github.Response, error) {
+		_, resp, err := client.Actions.ListWorkflows(ctx, owner, repo, &github.ListOptions{PerPage: 1})
+		return resp, err
+	}},
+	{"code_scanning", func(ctx context.Context, client *github.Client, owner, repo string) (*github.Response, error) {
+		_, resp, err := client.CodeScanning.ListAlertsForRepo(ctx, owner, repo, &github.AlertListOptions{ListOptions: github.ListOptions{PerPage: 1}})
+		return resp, err
+	}},
+	{"secret_scanning", func(ctx context.Context, client *github.Client, owner, repo string) (*github.Response, error) {
+		_, resp, err := client.SecretScanning.ListAlertsForRepo(ctx, owner, repo, &github.SecretScanningAlertListOptions{ListOptions: github.ListOptions{PerPage: 1}})
+		return resp, err
+	}},
+	{"dependabot", func(ctx context.Context, client *github.Client, owner, repo string) (*github.Response, error) {
+		_, resp, err := client.Dependabot.ListRepoAlerts(ctx, owner, repo, &github.ListAlertsOptions{ListOptions: github.ListOptions{PerPage: 1}})
+		return resp, err
+	}},
+}
+
+// DiagnoseRepositoryAccess creates a tool that checks which areas of a repository the current token can access.
+func DiagnoseRepositoryAccess(getClient GetClientFn, t translations.TranslationHelperFunc) (mcp.Tool, server.ToolHandlerFunc) {
+	return mcp.NewTool("diagnose_access",
+			mcp.WithDescription(t("TOOL_DIAGNOSE_ACCESS_DESCRIPTION", "Check whether the current token can access a repository's issues, pull requests, contents, actions and security alerts. For each area that fails, reports the missing OAuth scope, fine-grained permission or SAML SSO authorization. Use this when tool calls fail with unexpected 403 or 404 errors.")),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:        t("TOOL_DIAGNOSE_ACCESS_USER_TITLE", "Diagnose repository access"),
+				ReadOnlyHint: ToBoolPtr(true),
+			}),
+			mcp.WithString("owner",
+				mcp.Required(),
+				mcp.Description("Repository owner"),
+			),
+			mcp.WithString("repo",
+				mcp.Required(),
+				mcp.Description("Repository name"),
+			),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			owner, err := RequiredParam[string](request, "owner")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			repo, err := RequiredParam[string](request, "repo")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			client, err := getClient(ctx)
+			if err != nil {
+				return mcp.NewToolResultErrorFromErr("failed to get GitHub client", err), nil
+			}
+
+			report := AccessReport{
+				Owner:  owner,
+				Repo:   repo,
+				Checks: make([]AccessCheck, 0, len(accessProbes)),
+			}
+			for _, p := range accessProbes {
+				resp, err := p.probe(ctx, client, owner, repo)
+				if resp != nil {
+					_ = resp.Body.Close()
+					if d := ghErrors.DiagnoseAccess(resp.Response); d != nil && report.TokenScopes == nil {
+						report.TokenScopes = d.TokenScopes
+					}
+				}
+				if err == nil {
+					report.Checks = append(report.Checks, AccessCheck{Area: p.area, Accessible: true, Status: resp.StatusCode})
+					continue
+				}
+
+				// Failed probes are the expected outcome of this tool, so they are not recorded
+				// on the context like the errors of other tools.
+				details := (&ghErrors.GitHubAPIError{Message: "failed to access " + p.area, Response: resp, Err: err}).Details()
+				report.Checks = append(report.Checks, AccessCheck{
+					Area:        p.area,
+					Status:      details.Status,
+					Category:    details.Category,
+					Message:     details.Message,
+					Access:      details.Access,
+					Remediation: details.Remediation,
+				})
+			}
+
+			return MarshalledTextResult(report), nil
+		}
+}
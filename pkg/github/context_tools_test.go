@@ -4,11 +4,13 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/http"
 	"testing"
 	"time"
 
 	"github.com/github/github-mcp-server/internal/githubv4mock"
 	"github.com/github/github-mcp-server/internal/toolsnaps"
+	ghErrors "github.com/github/github-mcp-server/pkg/errors"
 	"github.com/github/github-mcp-server/pkg/translations"
 	"github.com/google/go-github/v77/github"
 	"github.com/migueleliasweb/go-github-mock/src/mock"
@@ -497,3 +499,86 @@ func Test_GetTeamMembers(t *testing.T) {
 		})
 	}
 }
+
+func Test_DiagnoseRepositoryAccess(t *testing.T) {
+	t.Parallel()
+
+	tool, _ := DiagnoseRepositoryAccess(nil, translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+
+	assert.Equal(t, "diagnose_access", tool.Name)
+	assert.True(t, *tool.Annotations.ReadOnlyHint, "diagnose_access tool should be read-only")
+	assert.ElementsMatch(t, tool.InputSchema.Required, []string{"owner", "repo"})
+
+	allowed := func(body any) http.HandlerFunc {
+		return func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("X-OAuth-Scopes", "repo, read:org")
+			w.WriteHeader(http.StatusOK)
+			_ = json.NewEncoder(w).Encode(body)
+		}
+	}
+	denied := func(headers map[string]string) http.HandlerFunc {
+		return func(w http.ResponseWriter, _ *http.Request) {
+			for k, v := range headers {
+				w.Header().Set(k, v)
+			}
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"message": "Resource not accessible by personal access token"}`))
+		}
+	}
+
+	client := github.NewClient(mock.NewMockedHTTPClient(
+		mock.WithRequestMatchHandler(mock.GetReposByOwnerByRepo, allowed(map[string]any{"name": "repo"})),
+		mock.WithRequestMatchHandler(mock.GetReposCommitsByOwnerByRepo, allowed([]any{})),
+		mock.WithRequestMatchHandler(mock.GetReposIssuesByOwnerByRepo, allowed([]any{})),
+		mock.WithRequestMatchHandler(mock.GetReposPullsByOwnerByRepo, allowed([]any{})),
+		mock.WithRequestMatchHandler(mock.GetReposActionsWorkflowsByOwnerByRepo, denied(map[string]string{
+			"X-Accepted-GitHub-Permissions": "actions=read",
+		})),
+		mock.WithRequestMatchHandler(mock.GetReposCodeScanningAlertsByOwnerByRepo, denied(map[string]string{
+			"X-OAuth-Scopes":          "read:org",
+			"X-Accepted-OAuth-Scopes": "security_events, repo",
+		})),
+		mock.WithRequestMatchHandler(mock.GetReposSecretScanningAlertsByOwnerByRepo, denied(map[string]string{
+			"X-GitHub-SSO": "required; url=https://github.com/orgs/owner/sso?authorization_request=abc",
+		})),
+		mock.WithRequestMatchHandler(mock.GetReposDependabotAlertsByOwnerByRepo, allowed([]any{})),
+	))
+	_, handler := DiagnoseRepositoryAccess(stubGetClientFn(client), translations.NullTranslationHelper)
+
+	result, err := handler(context.Background(), createMCPRequest(map[string]any{
+		"owner": "owner",
+		"repo":  "repo",
+	}))
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+
+	var report AccessReport
+	require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &report))
+	assert.Equal(t, []string{"repo", "read:org"}, report.TokenScopes)
+	require.Len(t, report.Checks, len(accessProbes))
+
+	checks := make(map[string]AccessCheck)
+	for _, c := range report.Checks {
+		checks[c.Area] = c
+	}
+	for _, area := range []string{"repository", "contents", "issues", "pull_requests", "dependabot"} {
+		assert.True(t, checks[area].Accessible, area)
+	}
+
+	actions := checks["actions"]
+	assert.False(t, actions.Accessible)
+	assert.Equal(t, http.StatusForbidden, actions.Status)
+	assert.Equal(t, ghErrors.CategoryPermissionDenied, actions.Category)
+	require.NotNil(t, actions.Access)
+	assert.Equal(t, [][]string{{"actions=read"}}, actions.Access.AcceptedPermissions)
+	assert.Contains(t, actions.Remediation, "actions=read")
+
+	codeScanning := checks["code_scanning"]
+	require.NotNil(t, codeScanning.Access)
+	assert.Equal(t, []string{"security_events", "repo"}, codeScanning.Access.MissingScopes)
+
+	secretScanning := checks["secret_scanning"]
+	assert.Equal(t, ghErrors.CategorySSORequired, secretScanning.Category)
+	assert.Contains(t, secretScanning.Remediation, "https://github.com/orgs/owner/sso?authorization_request=abc")
+}
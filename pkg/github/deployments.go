@@ -0,0 +1,951 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	ghErrors "github.com/github/github-mcp-server/pkg/errors"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/google/go-github/v77/github"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// EnvironmentReviewer is a user or a team required to approve deployments to an environment.
+type EnvironmentReviewer struct {
+	Type string `json:"type"`
+	Name string `json:"name"`
+}
+
+// EnvironmentProtectionRule is a protection rule of an environment.
+type EnvironmentProtectionRule struct {
+	ID                int64                 `json:"id"`
+	Type              string                `json:"type"`
+	WaitTimer         int                   `json:"wait_timer,omitempty"`
+	PreventSelfReview bool                  `json:"prevent_self_review,omitempty"`
+	Reviewers         []EnvironmentReviewer `json:"reviewers,omitempty"`
+}
+
+// EnvironmentDetails is the output type of the environment tools.
+type EnvironmentDetails struct {
+	ID              int64                       `json:"id"`
+	Name            string                      `json:"name"`
+	HTMLURL         string                      `json:"html_url,omitempty"`
+	CreatedAt       string                      `json:"created_at,omitempty"`
+	UpdatedAt       string                      `json:"updated_at,omitempty"`
+	CanAdminsBypass bool                        `json:"can_admins_bypass"`
+	ProtectionRules []EnvironmentProtectionRule `json:"protection_rules"`
+	// DeploymentBranches is "all", "protected" or "custom"
+	DeploymentBranches       string   `json:"deployment_branches"`
+	DeploymentBranchPolicies []string `json:"deployment_branch_policies,omitempty"`
+}
+
+// MinimalDeploymentStatus is the output type of deployment statuses.
+type MinimalDeploymentStatus struct {
+	ID             int64  `json:"id"`
+	State          string `json:"state"`
+	Description    string `json:"description,omitempty"`
+	Environment    string `json:"environment,omitempty"`
+	EnvironmentURL string `json:"environment_url,omitempty"`
+	LogURL         string `json:"log_url,omitempty"`
+	Creator        string `json:"creator,omitempty"`
+	CreatedAt      string `json:"created_at,omitempty"`
+}
+
+// MinimalDeployment is the output type of deployments.
+type MinimalDeployment struct {
+	ID           int64                    `json:"id"`
+	SHA          string                   `json:"sha"`
+	Ref          string                   `json:"ref"`
+	Task         string                   `json:"task,omitempty"`
+	Environment  string                   `json:"environment"`
+	Description  string                   `json:"description,omitempty"`
+	Payload      json.RawMessage          `json:"payload,omitempty"`
+	Creator      string                   `json:"creator,omitempty"`
+	CreatedAt    string                   `json:"created_at,omitempty"`
+	UpdatedAt    string                   `json:"updated_at,omitempty"`
+	LatestStatus *MinimalDeploymentStatus `json:"latest_status,omitempty"`
+}
+
+// PendingDeploymentDetails is an environment a workflow run waits for before deploying.
+type PendingDeploymentDetails struct {
+	EnvironmentID         int64                 `json:"environment_id"`
+	EnvironmentName       string                `json:"environment_name"`
+	WaitTimer             int64                 `json:"wait_timer,omitempty"`
+	WaitTimerStartedAt    string                `json:"wait_timer_started_at,omitempty"`
+	CurrentUserCanApprove bool                  `json:"current_user_can_approve"`
+	Reviewers             []EnvironmentReviewer `json:"reviewers,omitempty"`
+}
+
+func convertToEnvironmentReviewers(reviewers []*github.RequiredReviewer) []EnvironmentReviewer {
+	result := make([]EnvironmentReviewer, 0, len(reviewers))
+	for _, reviewer := range reviewers {
+		switch r := reviewer.Reviewer.(type) {
+		case *github.User:
+			result = append(result, EnvironmentReviewer{Type: "User", Name: r.GetLogin()})
+		case *github.Team:
+			name := r.GetSlug()
+			if org := r.GetOrganization().GetLogin(); org != "" {
+				name = org + "/" + name
+			}
+			result = append(result, EnvironmentReviewer{Type: "Team", Name: name})
+		}
+	}
+	return result
+}
+
+func convertToEnvironmentDetails(environment *github.Environment) EnvironmentDetails {
+	details := EnvironmentDetails{
+		ID:                 environment.GetID(),
+		Name:               environment.GetName(),
+		HTMLURL:            environment.GetHTMLURL(),
+		CanAdminsBypass:    environment.GetCanAdminsBypass(),
+		ProtectionRules:    make([]EnvironmentProtectionRule, 0, len(environment.ProtectionRules)),
+		DeploymentBranches: "all",
+	}
+	if environment.CreatedAt != nil {
+		details.CreatedAt = environment.CreatedAt.Format("2006-01-02T15:04:05Z")
+	}
+	if environment.UpdatedAt != nil {
+		details.UpdatedAt = environment.UpdatedAt.Format("2006-01-02T15:04:05Z")
+	}
+	for _, rule := range environment.ProtectionRules {
+		details.ProtectionRules = append(details.ProtectionRules, EnvironmentProtectionRule{
+			ID:                rule.GetID(),
+			Type:              rule.GetType(),
+			WaitTimer:         rule.GetWaitTimer(),
+			PreventSelfReview: rule.GetPreventSelfReview(),
+			Reviewers:         convertToEnvironmentReviewers(rule.Reviewers),
+		})
+	}
+	if policy := environment.GetDeploymentBranchPolicy(); policy != nil {
+		if policy.GetProtectedBranches() {
+			details.DeploymentBranches = "protected"
+		} else if policy.GetCustomBranchPolicies() {
+			details.DeploymentBranches = "custom"
+		}
+	}
+	return details
+}
+
+func convertToMinimalDeploymentStatus(status *github.DeploymentStatus) *MinimalDeploymentStatus {
+	m := &MinimalDeploymentStatus{
+		ID:             status.GetID(),
+		State:          status.GetState(),
+		Description:    status.GetDescription(),
+		Environment:    status.GetEnvironment(),
+		EnvironmentURL: status.GetEnvironmentURL(),
+		LogURL:         status.GetLogURL(),
+		Creator:        status.GetCreator().GetLogin(),
+	}
+	if status.CreatedAt != nil {
+		m.CreatedAt = status.CreatedAt.Format("2006-01-02T15:04:05Z")
+	}
+	return m
+}
+
+func convertToMinimalDeployment(deployment *github.Deployment) MinimalDeployment {
+	m := MinimalDeployment{
+		ID:          deployment.GetID(),
+		SHA:         deployment.GetSHA(),
+		Ref:         deployment.GetRef(),
+		Task:        deployment.GetTask(),
+		Environment: deployment.GetEnvironment(),
+		Description: deployment.GetDescription(),
+		Creator:     deployment.GetCreator().GetLogin(),
+	}
+	// An empty payload is returned as an empty object or string
+	if payload := strings.TrimSpace(string(deployment.Payload)); payload != "" && payload != "{}" && payload != `""` && payload != "null" {
+		m.Payload = deployment.Payload
+	}
+	if deployment.CreatedAt != nil {
+		m.CreatedAt = deployment.CreatedAt.Format("2006-01-02T15:04:05Z")
+	}
+	if deployment.UpdatedAt != nil {
+		m.UpdatedAt = deployment.UpdatedAt.Format("2006-01-02T15:04:05Z")
+	}
+	return m
+}
+
+func convertToPendingDeploymentDetails(pending *github.PendingDeployment) PendingDeploymentDetails {
+	details := PendingDeploymentDetails{
+		EnvironmentID:         pending.GetEnvironment().GetID(),
+		EnvironmentName:       pending.GetEnvironment().GetName(),
+		WaitTimer:             pending.GetWaitTimer(),
+		CurrentUserCanApprove: pending.GetCurrentUserCanApprove(),
+		Reviewers:             convertToEnvironmentReviewers(pending.Reviewers),
+	}
+	if pending.WaitTimerStartedAt != nil {
+		details.WaitTimerStartedAt = pending.WaitTimerStartedAt.Format("2006-01-02T15:04:05Z")
+	}
+	return details
+}
+
+// ListEnvironments creates a tool to list the deployment environments of a repository.
+func ListEnvironments(getClient GetClientFn, t translations.TranslationHelperFunc) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("list_environments",
+			mcp.WithDescription(t("TOOL_LIST_ENVIRONMENTS_DESCRIPTION", "List the deployment environments of a GitHub repository with their protection rules, such as required reviewers and wait timers")),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:        t("TOOL_LIST_ENVIRONMENTS_USER_TITLE", "List environments"),
+				ReadOnlyHint: ToBoolPtr(true),
+			}),
+			mcp.WithString("owner",
+				mcp.Required(),
+				mcp.Description(DescriptionRepositoryOwner),
+			),
+			mcp.WithString("repo",
+				mcp.Required(),
+				mcp.Description(DescriptionRepositoryName),
+			),
+			WithPagination(),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			owner, err := RequiredParam[string](request, "owner")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			repo, err := RequiredParam[string](request, "repo")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			pagination, err := OptionalPaginationParams(request)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			client, err := getClient(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get GitHub client: %w", err)
+			}
+
+			environments, resp, err := client.Repositories.ListEnvironments(ctx, owner, repo, &github.EnvironmentListOptions{
+				ListOptions: github.ListOptions{
+					Page:    pagination.Page,
+					PerPage: pagination.PerPage,
+				},
+			})
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx,
+					"failed to list environments",
+					resp,
+					err,
+				), nil
+			}
+			defer func() { _ = resp.Body.Close() }()
+
+			result := make([]EnvironmentDetails, 0, len(environments.Environments))
+			for _, environment := range environments.Environments {
+				result = append(result, convertToEnvironmentDetails(environment))
+			}
+
+			return MarshalledTextResult(map[string]any{
+				"total_count":  environments.GetTotalCount(),
+				"environments": result,
+			}), nil
+		}
+}
+
+// GetEnvironment creates a tool to get a deployment environment of a repository.
+func GetEnvironment(getClient GetClientFn, t translations.TranslationHelperFunc) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("get_environment",
+			mcp.WithDescription(t("TOOL_GET_ENVIRONMENT_DESCRIPTION", "Get a deployment environment of a GitHub repository with its protection rules and the branches allowed to deploy to it")),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:        t("TOOL_GET_ENVIRONMENT_USER_TITLE", "Get environment"),
+				ReadOnlyHint: ToBoolPtr(true),
+			}),
+			mcp.WithString("owner",
+				mcp.Required(),
+				mcp.Description(DescriptionRepositoryOwner),
+			),
+			mcp.WithString("repo",
+				mcp.Required(),
+				mcp.Description(DescriptionRepositoryName),
+			),
+			mcp.WithString("environment",
+				mcp.Required(),
+				mcp.Description("Name of the environment"),
+			),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			owner, err := RequiredParam[string](request, "owner")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			repo, err := RequiredParam[string](request, "repo")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			name, err := RequiredParam[string](request, "environment")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			client, err := getClient(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get GitHub client: %w", err)
+			}
+
+			environment, resp, err := client.Repositories.GetEnvironment(ctx, owner, repo, name)
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx,
+					"failed to get environment",
+					resp,
+					err,
+				), nil
+			}
+			defer func() { _ = resp.Body.Close() }()
+
+			details := convertToEnvironmentDetails(environment)
+			if details.DeploymentBranches == "custom" {
+				// The branch policies are a detail, the environment is returned without them if they can't be listed
+				policies, resp, err := client.Repositories.ListDeploymentBranchPolicies(ctx, owner, repo, name)
+				if err != nil {
+					_, _ = ghErrors.NewGitHubAPIErrorToCtx(ctx, "failed to list deployment branch policies", resp, err)
+				} else {
+					_ = resp.Body.Close()
+					for _, policy := range policies.BranchPolicies {
+						if policy.GetType() == "tag" {
+							details.DeploymentBranchPolicies = append(details.DeploymentBranchPolicies, "tag:"+policy.GetName())
+							continue
+						}
+						details.DeploymentBranchPolicies = append(details.DeploymentBranchPolicies, policy.GetName())
+					}
+				}
+			}
+
+			return MarshalledTextResult(details), nil
+		}
+}
+
+// ListDeployments creates a tool to list the deployments of a repository.
+func ListDeployments(getClient GetClientFn, t translations.TranslationHelperFunc) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("list_deployments",
+			mcp.WithDescription(t("TOOL_LIST_DEPLOYMENTS_DESCRIPTION", "List the deployments of a GitHub repository, most recent first. Filter by SHA to find out where a commit is deployed, or by environment to find out what is deployed to it")),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:        t("TOOL_LIST_DEPLOYMENTS_USER_TITLE", "List deployments"),
+				ReadOnlyHint: ToBoolPtr(true),
+			}),
+			mcp.WithString("owner",
+				mcp.Required(),
+				mcp.Description(DescriptionRepositoryOwner),
+			),
+			mcp.WithString("repo",
+				mcp.Required(),
+				mcp.Description(DescriptionRepositoryName),
+			),
+			mcp.WithString("sha",
+				mcp.Description("Only deployments of this commit SHA"),
+			),
+			mcp.WithString("ref",
+				mcp.Description("Only deployments of this branch, tag or SHA"),
+			),
+			mcp.WithString("task",
+				mcp.Description("Only deployments of this task, such as deploy or deploy:migrations"),
+			),
+			mcp.WithString("environment",
+				mcp.Description("Only deployments to this environment"),
+			),
+			mcp.WithBoolean("include_latest_status",
+				mcp.Description("Include the latest status of each deployment. Makes one more request per deployment (default false)"),
+			),
+			WithPagination(),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			owner, err := RequiredParam[string](request, "owner")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			repo, err := RequiredParam[string](request, "repo")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			sha, err := OptionalParam[string](request, "sha")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			ref, err := OptionalParam[string](request, "ref")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			task, err := OptionalParam[string](request, "task")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			environment, err := OptionalParam[string](request, "environment")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			includeLatestStatus, err := OptionalParam[bool](request, "include_latest_status")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			pagination, err := OptionalPaginationParams(request)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			client, err := getClient(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get GitHub client: %w", err)
+			}
+
+			deployments, resp, err := client.Repositories.ListDeployments(ctx, owner, repo, &github.DeploymentsListOptions{
+				SHA:         sha,
+				Ref:         ref,
+				Task:        task,
+				Environment: environment,
+				ListOptions: github.ListOptions{
+					Page:    pagination.Page,
+					PerPage: pagination.PerPage,
+				},
+			})
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx,
+					"failed to list deployments",
+					resp,
+					err,
+				), nil
+			}
+			defer func() { _ = resp.Body.Close() }()
+
+			result := make([]MinimalDeployment, 0, len(deployments))
+			for _, deployment := range deployments {
+				minimalDeployment := convertToMinimalDeployment(deployment)
+				if includeLatestStatus {
+					statuses, resp, err := client.Repositories.ListDeploymentStatuses(ctx, owner, repo, deployment.GetID(), &github.ListOptions{PerPage: 1})
+					if err != nil {
+						return ghErrors.NewGitHubAPIErrorResponse(ctx,
+							fmt.Sprintf("failed to list statuses of deployment %d", deployment.GetID()),
+							resp,
+							err,
+						), nil
+					}
+					_ = resp.Body.Close()
+					if len(statuses) > 0 {
+						minimalDeployment.LatestStatus = convertToMinimalDeploymentStatus(statuses[0])
+					}
+				}
+				result = append(result, minimalDeployment)
+			}
+
+			return MarshalledTextResult(result), nil
+		}
+}
+
+// GetDeployment creates a tool to get a deployment of a repository.
+func GetDeployment(getClient GetClientFn, t translations.TranslationHelperFunc) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("get_deployment",
+			mcp.WithDescription(t("TOOL_GET_DEPLOYMENT_DESCRIPTION", "Get a deployment of a GitHub repository with its latest status")),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:        t("TOOL_GET_DEPLOYMENT_USER_TITLE", "Get deployment"),
+				ReadOnlyHint: ToBoolPtr(true),
+			}),
+			mcp.WithString("owner",
+				mcp.Required(),
+				mcp.Description(DescriptionRepositoryOwner),
+			),
+			mcp.WithString("repo",
+				mcp.Required(),
+				mcp.Description(DescriptionRepositoryName),
+			),
+			mcp.WithNumber("deployment_id",
+				mcp.Required(),
+				mcp.Description("ID of the deployment"),
+			),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			owner, err := RequiredParam[string](request, "owner")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			repo, err := RequiredParam[string](request, "repo")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			deploymentID, err := RequiredBigInt(request, "deployment_id")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			client, err := getClient(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get GitHub client: %w", err)
+			}
+
+			deployment, resp, err := client.Repositories.GetDeployment(ctx, owner, repo, deploymentID)
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx,
+					"failed to get deployment",
+					resp,
+					err,
+				), nil
+			}
+			defer func() { _ = resp.Body.Close() }()
+
+			result := convertToMinimalDeployment(deployment)
+			statuses, statusesResp, err := client.Repositories.ListDeploymentStatuses(ctx, owner, repo, deploymentID, &github.ListOptions{PerPage: 1})
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx,
+					"failed to list deployment statuses",
+					statusesResp,
+					err,
+				), nil
+			}
+			defer func() { _ = statusesResp.Body.Close() }()
+			if len(statuses) > 0 {
+				result.LatestStatus = convertToMinimalDeploymentStatus(statuses[0])
+			}
+
+			return MarshalledTextResult(result), nil
+		}
+}
+
+// ListDeploymentStatuses creates a tool to list the statuses of a deployment.
+func ListDeploymentStatuses(getClient GetClientFn, t translations.TranslationHelperFunc) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("list_deployment_statuses",
+			mcp.WithDescription(t("TOOL_LIST_DEPLOYMENT_STATUSES_DESCRIPTION", "List the statuses of a deployment of a GitHub repository, most recent first")),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:        t("TOOL_LIST_DEPLOYMENT_STATUSES_USER_TITLE", "List deployment statuses"),
+				ReadOnlyHint: ToBoolPtr(true),
+			}),
+			mcp.WithString("owner",
+				mcp.Required(),
+				mcp.Description(DescriptionRepositoryOwner),
+			),
+			mcp.WithString("repo",
+				mcp.Required(),
+				mcp.Description(DescriptionRepositoryName),
+			),
+			mcp.WithNumber("deployment_id",
+				mcp.Required(),
+				mcp.Description("ID of the deployment"),
+			),
+			WithPagination(),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			owner, err := RequiredParam[string](request, "owner")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			repo, err := RequiredParam[string](request, "repo")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			deploymentID, err := RequiredBigInt(request, "deployment_id")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			pagination, err := OptionalPaginationParams(request)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			client, err := getClient(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get GitHub client: %w", err)
+			}
+
+			statuses, resp, err := client.Repositories.ListDeploymentStatuses(ctx, owner, repo, deploymentID, &github.ListOptions{
+				Page:    pagination.Page,
+				PerPage: pagination.PerPage,
+			})
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx,
+					"failed to list deployment statuses",
+					resp,
+					err,
+				), nil
+			}
+			defer func() { _ = resp.Body.Close() }()
+
+			result := make([]*MinimalDeploymentStatus, 0, len(statuses))
+			for _, status := range statuses {
+				result = append(result, convertToMinimalDeploymentStatus(status))
+			}
+
+			return MarshalledTextResult(result), nil
+		}
+}
+
+// GetPendingDeployments creates a tool to list the environments a workflow run waits for.
+func GetPendingDeployments(getClient GetClientFn, t translations.TranslationHelperFunc) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("get_pending_deployments",
+			mcp.WithDescription(t("TOOL_GET_PENDING_DEPLOYMENTS_DESCRIPTION", "List the environments a workflow run is waiting for before deploying, with their required reviewers and whether the current user can approve them")),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:        t("TOOL_GET_PENDING_DEPLOYMENTS_USER_TITLE", "Get pending deployments of a workflow run"),
+				ReadOnlyHint: ToBoolPtr(true),
+			}),
+			mcp.WithString("owner",
+				mcp.Required(),
+				mcp.Description(DescriptionRepositoryOwner),
+			),
+			mcp.WithString("repo",
+				mcp.Required(),
+				mcp.Description(DescriptionRepositoryName),
+			),
+			mcp.WithNumber("run_id",
+				mcp.Required(),
+				mcp.Description("The unique identifier of the workflow run"),
+			),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			owner, err := RequiredParam[string](request, "owner")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			repo, err := RequiredParam[string](request, "repo")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			runID, err := RequiredBigInt(request, "run_id")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			client, err := getClient(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get GitHub client: %w", err)
+			}
+
+			pending, resp, err := client.Actions.GetPendingDeployments(ctx, owner, repo, runID)
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx,
+					"failed to get pending deployments",
+					resp,
+					err,
+				), nil
+			}
+			defer func() { _ = resp.Body.Close() }()
+
+			result := make([]PendingDeploymentDetails, 0, len(pending))
+			for _, p := range pending {
+				result = append(result, convertToPendingDeploymentDetails(p))
+			}
+
+			return MarshalledTextResult(result), nil
+		}
+}
+
+// CreateDeployment creates a tool to create a deployment of a ref.
+func CreateDeployment(getClient GetClientFn, t translations.TranslationHelperFunc) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("create_deployment",
+			mcp.WithDescription(t("TOOL_CREATE_DEPLOYMENT_DESCRIPTION", "Create a deployment of a branch, tag or SHA of a GitHub repository to an environment. By default GitHub checks that the commit statuses of the ref are successful and merges the default branch into the ref first")),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:        t("TOOL_CREATE_DEPLOYMENT_USER_TITLE", "Create deployment"),
+				ReadOnlyHint: ToBoolPtr(false),
+			}),
+			mcp.WithString("owner",
+				mcp.Required(),
+				mcp.Description(DescriptionRepositoryOwner),
+			),
+			mcp.WithString("repo",
+				mcp.Required(),
+				mcp.Description(DescriptionRepositoryName),
+			),
+			mcp.WithString("ref",
+				mcp.Required(),
+				mcp.Description("Branch, tag or SHA to deploy"),
+			),
+			mcp.WithString("environment",
+				mcp.Description("Environment to deploy to (default production)"),
+			),
+			mcp.WithString("task",
+				mcp.Description("Task to execute (default deploy)"),
+			),
+			mcp.WithString("description",
+				mcp.Description("Short description of the deployment"),
+			),
+			mcp.WithObject("payload",
+				mcp.Description("Extra information for the deployment system"),
+			),
+			mcp.WithBoolean("auto_merge",
+				mcp.Description("Merge the default branch into the ref before deploying (default true)"),
+			),
+			mcp.WithArray("required_contexts",
+				mcp.Description("Status check contexts that must be successful before deploying. Defaults to all of them, pass an empty array to skip the checks"),
+				mcp.Items(map[string]interface{}{
+					"type": "string",
+				}),
+			),
+			mcp.WithBoolean("transient_environment",
+				mcp.Description("Whether the environment is specific to the deployment and goes away in the future"),
+			),
+			mcp.WithBoolean("production_environment",
+				mcp.Description("Whether the environment is one that end users interact with"),
+			),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			owner, err := RequiredParam[string](request, "owner")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			repo, err := RequiredParam[string](request, "repo")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			ref, err := RequiredParam[string](request, "ref")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			deploymentRequest := &github.DeploymentRequest{Ref: github.Ptr(ref)}
+			for param, field := range map[string]**string{
+				"environment": &deploymentRequest.Environment,
+				"task":        &deploymentRequest.Task,
+				"description": &deploymentRequest.Description,
+			} {
+				value, err := OptionalParam[string](request, param)
+				if err != nil {
+					return mcp.NewToolResultError(err.Error()), nil
+				}
+				if value != "" {
+					*field = github.Ptr(value)
+				}
+			}
+			for param, field := range map[string]**bool{
+				"auto_merge":             &deploymentRequest.AutoMerge,
+				"transient_environment":  &deploymentRequest.TransientEnvironment,
+				"production_environment": &deploymentRequest.ProductionEnvironment,
+			} {
+				value, ok, err := OptionalParamOK[bool](request, param)
+				if err != nil {
+					return mcp.NewToolResultError(err.Error()), nil
+				}
+				if ok {
+					*field = github.Ptr(value)
+				}
+			}
+			if payload, ok := request.GetArguments()["payload"].(map[string]interface{}); ok {
+				deploymentRequest.Payload = payload
+			}
+			// An empty array is meaningful: it skips the status checks
+			if _, ok := request.GetArguments()["required_contexts"]; ok {
+				requiredContexts, err := OptionalStringArrayParam(request, "required_contexts")
+				if err != nil {
+					return mcp.NewToolResultError(err.Error()), nil
+				}
+				deploymentRequest.RequiredContexts = &requiredContexts
+			}
+
+			client, err := getClient(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get GitHub client: %w", err)
+			}
+
+			deployment, resp, err := client.Repositories.CreateDeployment(ctx, owner, repo, deploymentRequest)
+			if err != nil {
+				// GitHub answers 202 Accepted without creating the deployment when it merged the default branch into the ref
+				var acceptedError *github.AcceptedError
+				if errors.As(err, &acceptedError) {
+					var message struct {
+						Message string `json:"message"`
+					}
+					_ = json.Unmarshal(acceptedError.Raw, &message)
+					return mcp.NewToolResultText(fmt.Sprintf("The deployment was not created: %s. Create the deployment again to deploy the updated ref.", strings.TrimSuffix(message.Message, "."))), nil
+				}
+				return ghErrors.NewGitHubAPIErrorResponse(ctx,
+					"failed to create deployment",
+					resp,
+					err,
+				), nil
+			}
+			defer func() { _ = resp.Body.Close() }()
+
+			return MarshalledTextResult(convertToMinimalDeployment(deployment)), nil
+		}
+}
+
+// CreateDeploymentStatus creates a tool to create a status for a deployment.
+func CreateDeploymentStatus(getClient GetClientFn, t translations.TranslationHelperFunc) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("create_deployment_status",
+			mcp.WithDescription(t("TOOL_CREATE_DEPLOYMENT_STATUS_DESCRIPTION", "Create a status for a deployment of a GitHub repository, to report its progress or result")),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:        t("TOOL_CREATE_DEPLOYMENT_STATUS_USER_TITLE", "Create deployment status"),
+				ReadOnlyHint: ToBoolPtr(false),
+			}),
+			mcp.WithString("owner",
+				mcp.Required(),
+				mcp.Description(DescriptionRepositoryOwner),
+			),
+			mcp.WithString("repo",
+				mcp.Required(),
+				mcp.Description(DescriptionRepositoryName),
+			),
+			mcp.WithNumber("deployment_id",
+				mcp.Required(),
+				mcp.Description("ID of the deployment"),
+			),
+			mcp.WithString("state",
+				mcp.Required(),
+				mcp.Description("State of the deployment"),
+				mcp.Enum("queued", "pending", "in_progress", "success", "failure", "error", "inactive"),
+			),
+			mcp.WithString("description",
+				mcp.Description("Short description of the status"),
+			),
+			mcp.WithString("environment",
+				mcp.Description("Name of the environment that was deployed to, if it changed"),
+			),
+			mcp.WithString("environment_url",
+				mcp.Description("URL to access the deployment"),
+			),
+			mcp.WithString("log_url",
+				mcp.Description("URL of the output of the deployment"),
+			),
+			mcp.WithBoolean("auto_inactive",
+				mcp.Description("Mark the previous successful deployments to the environment as inactive when the state is success (default true)"),
+			),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			owner, err := RequiredParam[string](request, "owner")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			repo, err := RequiredParam[string](request, "repo")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			deploymentID, err := RequiredBigInt(request, "deployment_id")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			state, err := RequiredParam[string](request, "state")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			statusRequest := &github.DeploymentStatusRequest{State: github.Ptr(state)}
+			for param, field := range map[string]**string{
+				"description":     &statusRequest.Description,
+				"environment":     &statusRequest.Environment,
+				"environment_url": &statusRequest.EnvironmentURL,
+				"log_url":         &statusRequest.LogURL,
+			} {
+				value, err := OptionalParam[string](request, param)
+				if err != nil {
+					return mcp.NewToolResultError(err.Error()), nil
+				}
+				if value != "" {
+					*field = github.Ptr(value)
+				}
+			}
+			autoInactive, ok, err := OptionalParamOK[bool](request, "auto_inactive")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			if ok {
+				statusRequest.AutoInactive = github.Ptr(autoInactive)
+			}
+
+			client, err := getClient(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get GitHub client: %w", err)
+			}
+
+			status, resp, err := client.Repositories.CreateDeploymentStatus(ctx, owner, repo, deploymentID, statusRequest)
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx,
+					"failed to create deployment status",
+					resp,
+					err,
+				), nil
+			}
+			defer func() { _ = resp.Body.Close() }()
+
+			return MarshalledTextResult(convertToMinimalDeploymentStatus(status)), nil
+		}
+}
+
+// ReviewPendingDeployments creates a tool to approve or reject the pending deployments of a workflow run.
+func ReviewPendingDeployments(getClient GetClientFn, t translations.TranslationHelperFunc) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("review_pending_deployments",
+			mcp.WithDescription(t("TOOL_REVIEW_PENDING_DEPLOYMENTS_DESCRIPTION", "Approve or reject the deployments of a workflow run that are waiting for a review, so that its jobs deploying to protected environments run or fail")),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:        t("TOOL_REVIEW_PENDING_DEPLOYMENTS_USER_TITLE", "Review pending deployments"),
+				ReadOnlyHint: ToBoolPtr(false),
+			}),
+			mcp.WithString("owner",
+				mcp.Required(),
+				mcp.Description(DescriptionRepositoryOwner),
+			),
+			mcp.WithString("repo",
+				mcp.Required(),
+				mcp.Description(DescriptionRepositoryName),
+			),
+			mcp.WithNumber("run_id",
+				mcp.Required(),
+				mcp.Description("The unique identifier of the workflow run"),
+			),
+			mcp.WithString("state",
+				mcp.Required(),
+				mcp.Description("Whether to approve or reject the deployments"),
+				mcp.Enum("approved", "rejected"),
+			),
+			mcp.WithString("comment",
+				mcp.Description("Comment on the review"),
+			),
+			mcp.WithArray("environment_ids",
+				mcp.Required(),
+				mcp.Description("IDs of the environments to review, as listed by get_pending_deployments (e.g. [\"161088068\"])"),
+				mcp.WithStringItems(),
+			),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			owner, err := RequiredParam[string](request, "owner")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			repo, err := RequiredParam[string](request, "repo")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			runID, err := RequiredBigInt(request, "run_id")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			state, err := RequiredParam[string](request, "state")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			comment, err := OptionalParam[string](request, "comment")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			environmentIDs, err := OptionalBigIntArrayParam(request, "environment_ids")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			if len(environmentIDs) == 0 {
+				return mcp.NewToolResultError("missing required parameter: environment_ids, get the environments waiting for a review with get_pending_deployments"), nil
+			}
+
+			client, err := getClient(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get GitHub client: %w", err)
+			}
+
+			deployments, resp, err := client.Actions.PendingDeployments(ctx, owner, repo, runID, &github.PendingDeploymentsRequest{
+				EnvironmentIDs: environmentIDs,
+				State:          state,
+				Comment:        comment,
+			})
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx,
+					"failed to review pending deployments",
+					resp,
+					err,
+				), nil
+			}
+			defer func() { _ = resp.Body.Close() }()
+
+			result := make([]MinimalDeployment, 0, len(deployments))
+			for _, deployment := range deployments {
+				result = append(result, convertToMinimalDeployment(deployment))
+			}
+
+			return MarshalledTextResult(result), nil
+		}
+}
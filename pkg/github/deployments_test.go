@@ -0,0 +1,534 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/github/github-mcp-server/internal/toolsnaps"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/google/go-github/v77/github"
+	"github.com/migueleliasweb/go-github-mock/src/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var mockProductionEnvironment = map[string]any{
+	"id":                1,
+	"name":              "production",
+	"html_url":          "https://github.com/owner/repo/deployments/activity_log?environments_filter=production",
+	"can_admins_bypass": false,
+	"protection_rules": []any{
+		map[string]any{"id": 10, "type": "wait_timer", "wait_timer": 30},
+		map[string]any{
+			"id":                  11,
+			"type":                "required_reviewers",
+			"prevent_self_review": true,
+			"reviewers": []any{
+				map[string]any{"type": "User", "reviewer": map[string]any{"login": "mona"}},
+				map[string]any{"type": "Team", "reviewer": map[string]any{"slug": "release", "organization": map[string]any{"login": "owner"}}},
+			},
+		},
+	},
+	"deployment_branch_policy": map[string]any{"protected_branches": false, "custom_branch_policies": true},
+}
+
+var expectedProductionEnvironment = EnvironmentDetails{
+	ID:              1,
+	Name:            "production",
+	HTMLURL:         "https://github.com/owner/repo/deployments/activity_log?environments_filter=production",
+	CanAdminsBypass: false,
+	ProtectionRules: []EnvironmentProtectionRule{
+		{ID: 10, Type: "wait_timer", WaitTimer: 30},
+		{ID: 11, Type: "required_reviewers", PreventSelfReview: true, Reviewers: []EnvironmentReviewer{
+			{Type: "User", Name: "mona"},
+			{Type: "Team", Name: "owner/release"},
+		}},
+	},
+	DeploymentBranches: "custom",
+}
+
+func Test_ListEnvironments(t *testing.T) {
+	tool, _ := ListEnvironments(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+	assert.True(t, *tool.Annotations.ReadOnlyHint)
+	assert.ElementsMatch(t, tool.InputSchema.Required, []string{"owner", "repo"})
+
+	mockedClient := mock.NewMockedHTTPClient(
+		mock.WithRequestMatchHandler(
+			mock.GetReposEnvironmentsByOwnerByRepo,
+			expectQueryParams(t, map[string]string{"page": "1", "per_page": "30"}).andThen(
+				mockResponse(t, http.StatusOK, map[string]any{
+					"total_count":  1,
+					"environments": []any{mockProductionEnvironment},
+				}),
+			),
+		),
+	)
+
+	_, handler := ListEnvironments(stubGetClientFn(github.NewClient(mockedClient)), translations.NullTranslationHelper)
+	result, err := handler(context.Background(), createMCPRequest(map[string]any{"owner": "owner", "repo": "repo"}))
+	require.NoError(t, err)
+
+	var returned struct {
+		TotalCount   int                  `json:"total_count"`
+		Environments []EnvironmentDetails `json:"environments"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &returned))
+	assert.Equal(t, 1, returned.TotalCount)
+	assert.Equal(t, []EnvironmentDetails{expectedProductionEnvironment}, returned.Environments)
+}
+
+func Test_GetEnvironment(t *testing.T) {
+	tool, _ := GetEnvironment(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+	assert.True(t, *tool.Annotations.ReadOnlyHint)
+	assert.ElementsMatch(t, tool.InputSchema.Required, []string{"owner", "repo", "environment"})
+
+	tests := []struct {
+		name         string
+		mockedClient *http.Client
+		expected     EnvironmentDetails
+		expectError  string
+	}{
+		{
+			name: "environment with custom branch policies",
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatch(mock.GetReposEnvironmentsByOwnerByRepoByEnvironmentName, mockProductionEnvironment),
+				mock.WithRequestMatch(
+					mock.GetReposEnvironmentsDeploymentBranchPoliciesByOwnerByRepoByEnvironmentName,
+					map[string]any{
+						"total_count": 2,
+						"branch_policies": []any{
+							map[string]any{"id": 1, "name": "release/*", "type": "branch"},
+							map[string]any{"id": 2, "name": "v*", "type": "tag"},
+						},
+					},
+				),
+			),
+			expected: func() EnvironmentDetails {
+				expected := expectedProductionEnvironment
+				expected.DeploymentBranchPolicies = []string{"release/*", "tag:v*"}
+				return expected
+			}(),
+		},
+		{
+			name: "environment not found",
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(
+					mock.GetReposEnvironmentsByOwnerByRepoByEnvironmentName,
+					mockResponse(t, http.StatusNotFound, map[string]string{"message": "Not Found"}),
+				),
+			),
+			expectError: "failed to get environment",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, handler := GetEnvironment(stubGetClientFn(github.NewClient(tc.mockedClient)), translations.NullTranslationHelper)
+			result, err := handler(context.Background(), createMCPRequest(map[string]any{"owner": "owner", "repo": "repo", "environment": "production"}))
+			require.NoError(t, err)
+
+			if tc.expectError != "" {
+				assert.Contains(t, getErrorResult(t, result).Text, tc.expectError)
+				return
+			}
+			var returned EnvironmentDetails
+			require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &returned))
+			assert.Equal(t, tc.expected, returned)
+		})
+	}
+}
+
+func Test_ListDeployments(t *testing.T) {
+	tool, _ := ListDeployments(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+	assert.True(t, *tool.Annotations.ReadOnlyHint)
+	assert.ElementsMatch(t, tool.InputSchema.Required, []string{"owner", "repo"})
+
+	mockedClient := mock.NewMockedHTTPClient(
+		mock.WithRequestMatchHandler(
+			mock.GetReposDeploymentsByOwnerByRepo,
+			expectQueryParams(t, map[string]string{"sha": "abc123", "page": "1", "per_page": "30"}).andThen(
+				mockResponse(t, http.StatusOK, []*github.Deployment{
+					{
+						ID:          github.Ptr(int64(1)),
+						SHA:         github.Ptr("abc123"),
+						Ref:         github.Ptr("main"),
+						Task:        github.Ptr("deploy"),
+						Environment: github.Ptr("production"),
+						Payload:     json.RawMessage(`{}`),
+						Creator:     &github.User{Login: github.Ptr("mona")},
+					},
+					{
+						ID:          github.Ptr(int64(2)),
+						SHA:         github.Ptr("abc123"),
+						Ref:         github.Ptr("main"),
+						Environment: github.Ptr("staging"),
+						Payload:     json.RawMessage(`{"region":"eu"}`),
+					},
+				}),
+			),
+		),
+		mock.WithRequestMatchHandler(
+			mock.GetReposDeploymentsStatusesByOwnerByRepoByDeploymentId,
+			expectQueryParams(t, map[string]string{"per_page": "1"}).andThen(
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					if r.URL.Path == "/repos/owner/repo/deployments/2/statuses" {
+						mockResponse(t, http.StatusOK, []*github.DeploymentStatus{})(w, r)
+						return
+					}
+					mockResponse(t, http.StatusOK, []*github.DeploymentStatus{{
+						ID:             github.Ptr(int64(5)),
+						State:          github.Ptr("success"),
+						EnvironmentURL: github.Ptr("https://example.com"),
+					}})(w, r)
+				}),
+			),
+		),
+	)
+
+	_, handler := ListDeployments(stubGetClientFn(github.NewClient(mockedClient)), translations.NullTranslationHelper)
+	result, err := handler(context.Background(), createMCPRequest(map[string]any{
+		"owner":                 "owner",
+		"repo":                  "repo",
+		"sha":                   "abc123",
+		"include_latest_status": true,
+	}))
+	require.NoError(t, err)
+
+	var returned []MinimalDeployment
+	require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &returned))
+	assert.Equal(t, []MinimalDeployment{
+		{
+			ID:           1,
+			SHA:          "abc123",
+			Ref:          "main",
+			Task:         "deploy",
+			Environment:  "production",
+			Creator:      "mona",
+			LatestStatus: &MinimalDeploymentStatus{ID: 5, State: "success", EnvironmentURL: "https://example.com"},
+		},
+		{
+			ID:          2,
+			SHA:         "abc123",
+			Ref:         "main",
+			Environment: "staging",
+			Payload:     json.RawMessage(`{"region":"eu"}`),
+		},
+	}, returned)
+}
+
+func Test_GetDeployment(t *testing.T) {
+	tool, _ := GetDeployment(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+	assert.True(t, *tool.Annotations.ReadOnlyHint)
+	assert.ElementsMatch(t, tool.InputSchema.Required, []string{"owner", "repo", "deployment_id"})
+
+	mockedClient := mock.NewMockedHTTPClient(
+		mock.WithRequestMatchHandler(
+			mock.GetReposDeploymentsByOwnerByRepoByDeploymentId,
+			expectPath(t, "/repos/owner/repo/deployments/1").andThen(
+				mockResponse(t, http.StatusOK, &github.Deployment{
+					ID:          github.Ptr(int64(1)),
+					SHA:         github.Ptr("abc123"),
+					Ref:         github.Ptr("v1.0.0"),
+					Environment: github.Ptr("production"),
+				}),
+			),
+		),
+		mock.WithRequestMatch(
+			mock.GetReposDeploymentsStatusesByOwnerByRepoByDeploymentId,
+			[]*github.DeploymentStatus{{ID: github.Ptr(int64(5)), State: github.Ptr("in_progress")}},
+		),
+	)
+
+	_, handler := GetDeployment(stubGetClientFn(github.NewClient(mockedClient)), translations.NullTranslationHelper)
+	result, err := handler(context.Background(), createMCPRequest(map[string]any{"owner": "owner", "repo": "repo", "deployment_id": float64(1)}))
+	require.NoError(t, err)
+
+	var returned MinimalDeployment
+	require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &returned))
+	assert.Equal(t, MinimalDeployment{
+		ID:           1,
+		SHA:          "abc123",
+		Ref:          "v1.0.0",
+		Environment:  "production",
+		LatestStatus: &MinimalDeploymentStatus{ID: 5, State: "in_progress"},
+	}, returned)
+}
+
+func Test_ListDeploymentStatuses(t *testing.T) {
+	tool, _ := ListDeploymentStatuses(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+	assert.True(t, *tool.Annotations.ReadOnlyHint)
+	assert.ElementsMatch(t, tool.InputSchema.Required, []string{"owner", "repo", "deployment_id"})
+
+	mockedClient := mock.NewMockedHTTPClient(
+		mock.WithRequestMatchHandler(
+			mock.GetReposDeploymentsStatusesByOwnerByRepoByDeploymentId,
+			expectQueryParams(t, map[string]string{"page": "2", "per_page": "5"}).andThen(
+				mockResponse(t, http.StatusOK, []*github.DeploymentStatus{
+					{ID: github.Ptr(int64(6)), State: github.Ptr("failure"), Description: github.Ptr("Health check failed"), LogURL: github.Ptr("https://example.com/logs")},
+					{ID: github.Ptr(int64(5)), State: github.Ptr("in_progress"), Creator: &github.User{Login: github.Ptr("deploy-bot")}},
+				}),
+			),
+		),
+	)
+
+	_, handler := ListDeploymentStatuses(stubGetClientFn(github.NewClient(mockedClient)), translations.NullTranslationHelper)
+	result, err := handler(context.Background(), createMCPRequest(map[string]any{
+		"owner":         "owner",
+		"repo":          "repo",
+		"deployment_id": float64(1),
+		"page":          float64(2),
+		"perPage":       float64(5),
+	}))
+	require.NoError(t, err)
+
+	var returned []MinimalDeploymentStatus
+	require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &returned))
+	assert.Equal(t, []MinimalDeploymentStatus{
+		{ID: 6, State: "failure", Description: "Health check failed", LogURL: "https://example.com/logs"},
+		{ID: 5, State: "in_progress", Creator: "deploy-bot"},
+	}, returned)
+}
+
+func Test_GetPendingDeployments(t *testing.T) {
+	tool, _ := GetPendingDeployments(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+	assert.True(t, *tool.Annotations.ReadOnlyHint)
+	assert.ElementsMatch(t, tool.InputSchema.Required, []string{"owner", "repo", "run_id"})
+
+	mockedClient := mock.NewMockedHTTPClient(
+		mock.WithRequestMatchHandler(
+			mock.GetReposActionsRunsPendingDeploymentsByOwnerByRepoByRunId,
+			expectPath(t, "/repos/owner/repo/actions/runs/42/pending_deployments").andThen(
+				mockResponse(t, http.StatusOK, []any{
+					map[string]any{
+						"environment":              map[string]any{"id": 1, "name": "production"},
+						"wait_timer":               0,
+						"current_user_can_approve": true,
+						"reviewers": []any{
+							map[string]any{"type": "User", "reviewer": map[string]any{"login": "mona"}},
+						},
+					},
+				}),
+			),
+		),
+	)
+
+	_, handler := GetPendingDeployments(stubGetClientFn(github.NewClient(mockedClient)), translations.NullTranslationHelper)
+	result, err := handler(context.Background(), createMCPRequest(map[string]any{"owner": "owner", "repo": "repo", "run_id": float64(42)}))
+	require.NoError(t, err)
+
+	var returned []PendingDeploymentDetails
+	require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &returned))
+	assert.Equal(t, []PendingDeploymentDetails{{
+		EnvironmentID:         1,
+		EnvironmentName:       "production",
+		CurrentUserCanApprove: true,
+		Reviewers:             []EnvironmentReviewer{{Type: "User", Name: "mona"}},
+	}}, returned)
+}
+
+func Test_CreateDeployment(t *testing.T) {
+	tool, _ := CreateDeployment(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+	assert.False(t, *tool.Annotations.ReadOnlyHint)
+	assert.ElementsMatch(t, tool.InputSchema.Required, []string{"owner", "repo", "ref"})
+
+	tests := []struct {
+		name           string
+		mockedClient   *http.Client
+		requestArgs    map[string]any
+		expectedText   string
+		expectedResult *MinimalDeployment
+	}{
+		{
+			name: "deployment created",
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(
+					mock.PostReposDeploymentsByOwnerByRepo,
+					expectRequestBody(t, map[string]any{
+						"ref":               "main",
+						"environment":       "staging",
+						"auto_merge":        false,
+						"required_contexts": []any{},
+						"payload":           map[string]any{"region": "eu"},
+					}).andThen(
+						mockResponse(t, http.StatusCreated, &github.Deployment{
+							ID:          github.Ptr(int64(3)),
+							SHA:         github.Ptr("abc123"),
+							Ref:         github.Ptr("main"),
+							Environment: github.Ptr("staging"),
+							Payload:     json.RawMessage(`{"region":"eu"}`),
+						}),
+					),
+				),
+			),
+			requestArgs: map[string]any{
+				"ref":               "main",
+				"environment":       "staging",
+				"auto_merge":        false,
+				"required_contexts": []any{},
+				"payload":           map[string]any{"region": "eu"},
+			},
+			expectedResult: &MinimalDeployment{
+				ID:          3,
+				SHA:         "abc123",
+				Ref:         "main",
+				Environment: "staging",
+				Payload:     json.RawMessage(`{"region":"eu"}`),
+			},
+		},
+		{
+			name: "default branch merged into the ref",
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(
+					mock.PostReposDeploymentsByOwnerByRepo,
+					mockResponse(t, http.StatusAccepted, map[string]string{"message": "Auto-merged main into topic-branch on deployment."}),
+				),
+			),
+			requestArgs:  map[string]any{"ref": "topic-branch"},
+			expectedText: "The deployment was not created: Auto-merged main into topic-branch on deployment. Create the deployment again to deploy the updated ref.",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, handler := CreateDeployment(stubGetClientFn(github.NewClient(tc.mockedClient)), translations.NullTranslationHelper)
+			args := map[string]any{"owner": "owner", "repo": "repo"}
+			for k, v := range tc.requestArgs {
+				args[k] = v
+			}
+			result, err := handler(context.Background(), createMCPRequest(args))
+			require.NoError(t, err)
+
+			text := getTextResult(t, result).Text
+			if tc.expectedResult == nil {
+				assert.Equal(t, tc.expectedText, text)
+				return
+			}
+			var returned MinimalDeployment
+			require.NoError(t, json.Unmarshal([]byte(text), &returned))
+			assert.Equal(t, *tc.expectedResult, returned)
+		})
+	}
+}
+
+func Test_CreateDeploymentStatus(t *testing.T) {
+	tool, _ := CreateDeploymentStatus(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+	assert.False(t, *tool.Annotations.ReadOnlyHint)
+	assert.ElementsMatch(t, tool.InputSchema.Required, []string{"owner", "repo", "deployment_id", "state"})
+
+	mockedClient := mock.NewMockedHTTPClient(
+		mock.WithRequestMatchHandler(
+			mock.PostReposDeploymentsStatusesByOwnerByRepoByDeploymentId,
+			expectRequestBody(t, map[string]any{
+				"state":           "success",
+				"environment_url": "https://example.com",
+				"auto_inactive":   false,
+			}).andThen(
+				mockResponse(t, http.StatusCreated, &github.DeploymentStatus{
+					ID:             github.Ptr(int64(7)),
+					State:          github.Ptr("success"),
+					EnvironmentURL: github.Ptr("https://example.com"),
+				}),
+			),
+		),
+	)
+
+	_, handler := CreateDeploymentStatus(stubGetClientFn(github.NewClient(mockedClient)), translations.NullTranslationHelper)
+	result, err := handler(context.Background(), createMCPRequest(map[string]any{
+		"owner":           "owner",
+		"repo":            "repo",
+		"deployment_id":   float64(1),
+		"state":           "success",
+		"environment_url": "https://example.com",
+		"auto_inactive":   false,
+	}))
+	require.NoError(t, err)
+
+	var returned MinimalDeploymentStatus
+	require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &returned))
+	assert.Equal(t, MinimalDeploymentStatus{ID: 7, State: "success", EnvironmentURL: "https://example.com"}, returned)
+}
+
+func Test_ReviewPendingDeployments(t *testing.T) {
+	tool, _ := ReviewPendingDeployments(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+	assert.False(t, *tool.Annotations.ReadOnlyHint)
+	assert.ElementsMatch(t, tool.InputSchema.Required, []string{"owner", "repo", "run_id", "state", "environment_ids"})
+
+	approved := []*github.Deployment{{ID: github.Ptr(int64(9)), Ref: github.Ptr("main"), Environment: github.Ptr("production")}}
+
+	tests := []struct {
+		name         string
+		mockedClient *http.Client
+		requestArgs  map[string]any
+		expectError  string
+	}{
+		{
+			name: "approve the given environments",
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(
+					mock.PostReposActionsRunsPendingDeploymentsByOwnerByRepoByRunId,
+					expectRequestBody(t, map[string]any{
+						"environment_ids": []any{float64(1), float64(2)},
+						"state":           "approved",
+						"comment":         "Ship it",
+					}).andThen(
+						mockResponse(t, http.StatusOK, approved),
+					),
+				),
+			),
+			requestArgs: map[string]any{"state": "approved", "comment": "Ship it", "environment_ids": []any{"1", "2"}},
+		},
+		{
+			name: "reject the given environments",
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(
+					mock.PostReposActionsRunsPendingDeploymentsByOwnerByRepoByRunId,
+					expectRequestBody(t, map[string]any{
+						"environment_ids": []any{float64(1)},
+						"state":           "rejected",
+						"comment":         "",
+					}).andThen(
+						mockResponse(t, http.StatusOK, approved),
+					),
+				),
+			),
+			requestArgs: map[string]any{"state": "rejected", "environment_ids": []any{"1"}},
+		},
+		{
+			name:         "environments not given",
+			mockedClient: mock.NewMockedHTTPClient(),
+			requestArgs:  map[string]any{"state": "approved", "environment_ids": []any{}},
+			expectError:  "missing required parameter: environment_ids, get the environments waiting for a review with get_pending_deployments",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, handler := ReviewPendingDeployments(stubGetClientFn(github.NewClient(tc.mockedClient)), translations.NullTranslationHelper)
+			args := map[string]any{"owner": "owner", "repo": "repo", "run_id": float64(42)}
+			for k, v := range tc.requestArgs {
+				args[k] = v
+			}
+			result, err := handler(context.Background(), createMCPRequest(args))
+			require.NoError(t, err)
+
+			if tc.expectError != "" {
+				assert.Equal(t, tc.expectError, getErrorResult(t, result).Text)
+				return
+			}
+			var returned []MinimalDeployment
+			require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &returned))
+			assert.Equal(t, []MinimalDeployment{{ID: 9, Ref: "main", Environment: "production"}}, returned)
+		})
+	}
+}
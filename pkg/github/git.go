@@ -1,12 +1,18 @@
 package github
 
 import (
+	"bytes"
 	"context"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
 	"strings"
+	"unicode/utf8"
 
 	ghErrors "github.com/github/github-mcp-server/pkg/errors"
+	"github.com/github/github-mcp-server/pkg/sniff"
 	"github.com/github/github-mcp-server/pkg/translations"
 	"github.com/google/go-github/v77/github"
 	"github.com/mark3labs/mcp-go/mcp"
@@ -158,3 +164,1236 @@ func GetRepositoryTree(getClient GetClientFn, t translations.TranslationHelperFu
 			return mcp.NewToolResultText(string(r)), nil
 		}
 }
+
+// Git file modes of tree entries
+const (
+	gitModeFile       = "100644"
+	gitModeExecutable = "100755"
+	gitModeSymlink    = "120000"
+)
+
+// fileChange is one operation of a commit_changes call.
+type fileChange struct {
+	Operation  string
+	Path       string
+	NewPath    string
+	Content    *string
+	Encoding   string
+	Executable *bool
+	Target     string
+}
+
+// parseFileChanges parses the changes parameter of commit_changes.
+func parseFileChanges(request mcp.CallToolRequest) ([]fileChange, error) {
+	items, ok := request.GetArguments()["changes"].([]interface{})
+	if !ok || len(items) == 0 {
+		return nil, errors.New("changes parameter must be a non-empty array of objects with operation and path")
+	}
+
+	changes := make([]fileChange, 0, len(items))
+	// touched guards against changing a path twice, as the changes are applied to the same base tree
+	touched := map[string]bool{}
+	touch := func(path string) error {
+		if touched[path] {
+			return fmt.Errorf("%s is changed more than once", path)
+		}
+		touched[path] = true
+		return nil
+	}
+	for _, item := range items {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, errors.New("each change must be an object with operation and path")
+		}
+		var c fileChange
+		c.Operation, _ = m["operation"].(string)
+		c.Path, _ = m["path"].(string)
+		c.Path = strings.Trim(c.Path, "/")
+		if c.Path == "" {
+			return nil, errors.New("each change must have a path")
+		}
+		if err := touch(c.Path); err != nil {
+			return nil, err
+		}
+		if content, ok := m["content"].(string); ok {
+			c.Content = &content
+		}
+		if executable, ok := m["executable"].(bool); ok {
+			c.Executable = &executable
+		}
+		c.Encoding, _ = m["encoding"].(string)
+		c.NewPath, _ = m["new_path"].(string)
+		c.NewPath = strings.Trim(c.NewPath, "/")
+		c.Target, _ = m["target"].(string)
+
+		switch c.Operation {
+		case "add", "modify":
+			if c.Content == nil {
+				return nil, fmt.Errorf("%s: content is required to %s a file", c.Path, c.Operation)
+			}
+			switch c.Encoding {
+			case "", "utf-8":
+				c.Encoding = "utf-8"
+			case "base64":
+				if _, err := base64.StdEncoding.DecodeString(*c.Content); err != nil {
+					return nil, fmt.Errorf("%s: content is not valid base64: %w", c.Path, err)
+				}
+			default:
+				return nil, fmt.Errorf("%s: encoding must be utf-8 or base64", c.Path)
+			}
+		case "rename":
+			if c.NewPath == "" {
+				return nil, fmt.Errorf("%s: new_path is required to rename a file", c.Path)
+			}
+			if err := touch(c.NewPath); err != nil {
+				return nil, err
+			}
+		case "symlink":
+			if c.Target == "" {
+				return nil, fmt.Errorf("%s: target is required to create a symbolic link", c.Path)
+			}
+		case "delete", "chmod":
+		default:
+			return nil, fmt.Errorf("%s: unknown operation %q", c.Path, c.Operation)
+		}
+		changes = append(changes, c)
+	}
+	return changes, nil
+}
+
+// treeIndex looks up paths in a tree, loading only the directories on the way.
+type treeIndex struct {
+	client      *github.Client
+	owner, repo string
+	// trees holds the loaded directories by path, the root being ""
+	trees map[string]*github.Tree
+}
+
+func newTreeIndex(client *github.Client, owner, repo, rootSHA string) *treeIndex {
+	return &treeIndex{
+		client: client,
+		owner:  owner,
+		repo:   repo,
+		trees:  map[string]*github.Tree{"": {SHA: github.Ptr(rootSHA)}},
+	}
+}
+
+func (ix *treeIndex) load(ctx context.Context, dir string) (*github.Tree, error) {
+	tree := ix.trees[dir]
+	if tree.Entries != nil {
+		return tree, nil
+	}
+	loaded, resp, err := ix.client.Git.GetTree(ctx, ix.owner, ix.repo, tree.GetSHA(), false)
+	if err != nil {
+		_, _ = ghErrors.NewGitHubAPIErrorToCtx(ctx, "failed to get tree", resp, err)
+		return nil, fmt.Errorf("failed to get tree of %q: %w", dir, err)
+	}
+	_ = resp.Body.Close()
+	if loaded.Entries == nil {
+		loaded.Entries = []*github.TreeEntry{}
+	}
+	ix.trees[dir] = loaded
+	return loaded, nil
+}
+
+// entry returns the entry at path, or nil if there is none.
+func (ix *treeIndex) entry(ctx context.Context, path string) (*github.TreeEntry, error) {
+	dir := ""
+	parts := strings.Split(path, "/")
+	for i, name := range parts {
+		tree, err := ix.load(ctx, dir)
+		if err != nil {
+			return nil, err
+		}
+		var found *github.TreeEntry
+		for _, e := range tree.Entries {
+			if e.GetPath() == name {
+				found = e
+				break
+			}
+		}
+		if found == nil || i == len(parts)-1 {
+			return found, nil
+		}
+		if found.GetType() != "tree" {
+			return nil, nil
+		}
+		dir = strings.Join(parts[:i+1], "/")
+		if _, ok := ix.trees[dir]; !ok {
+			ix.trees[dir] = &github.Tree{SHA: found.SHA}
+		}
+	}
+	return nil, nil
+}
+
+// existingFile returns the file or symbolic link at path, failing if there is none.
+func (ix *treeIndex) existingFile(ctx context.Context, path string) (*github.TreeEntry, error) {
+	e, err := ix.entry(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	if e == nil {
+		return nil, fmt.Errorf("%s does not exist", path)
+	}
+	if e.GetType() != "blob" {
+		return nil, fmt.Errorf("%s is not a file", path)
+	}
+	return e, nil
+}
+
+// absent fails if there is a file or directory at path.
+func (ix *treeIndex) absent(ctx context.Context, path string) error {
+	e, err := ix.entry(ctx, path)
+	if err != nil {
+		return err
+	}
+	if e != nil {
+		return fmt.Errorf("%s already exists", path)
+	}
+	return nil
+}
+
+// treeEntries converts changes to the entries of a new tree based on the tree in ix.
+func treeEntries(ctx context.Context, ix *treeIndex, changes []fileChange) ([]*github.TreeEntry, error) {
+	var entries []*github.TreeEntry
+	blobEntry := func(path, mode string) *github.TreeEntry {
+		return &github.TreeEntry{Path: github.Ptr(path), Mode: github.Ptr(mode), Type: github.Ptr("blob")}
+	}
+	for _, c := range changes {
+		switch c.Operation {
+		case "add", "modify":
+			mode := gitModeFile
+			if c.Operation == "add" {
+				if err := ix.absent(ctx, c.Path); err != nil {
+					return nil, fmt.Errorf("%w, use the modify operation to change it", err)
+				}
+			} else {
+				existing, err := ix.existingFile(ctx, c.Path)
+				if err != nil {
+					return nil, err
+				}
+				if existing.GetMode() == gitModeSymlink {
+					return nil, fmt.Errorf("%s is a symbolic link, use the symlink operation to change its target", c.Path)
+				}
+				mode = existing.GetMode()
+			}
+			if c.Executable != nil {
+				mode = gitModeFile
+				if *c.Executable {
+					mode = gitModeExecutable
+				}
+			}
+
+			entry := blobEntry(c.Path, mode)
+			if c.Encoding == "base64" {
+				blob, resp, err := ix.client.Git.CreateBlob(ctx, ix.owner, ix.repo, github.Blob{Content: c.Content, Encoding: github.Ptr("base64")})
+				if err != nil {
+					_, _ = ghErrors.NewGitHubAPIErrorToCtx(ctx, "failed to create blob", resp, err)
+					return nil, fmt.Errorf("failed to create blob for %s: %w", c.Path, err)
+				}
+				_ = resp.Body.Close()
+				entry.SHA = blob.SHA
+			} else {
+				entry.Content = c.Content
+			}
+			entries = append(entries, entry)
+		case "delete":
+			existing, err := ix.existingFile(ctx, c.Path)
+			if err != nil {
+				return nil, err
+			}
+			// An entry with neither content nor SHA deletes the file
+			entries = append(entries, blobEntry(c.Path, existing.GetMode()))
+		case "rename":
+			existing, err := ix.existingFile(ctx, c.Path)
+			if err != nil {
+				return nil, err
+			}
+			if err := ix.absent(ctx, c.NewPath); err != nil {
+				return nil, err
+			}
+			renamed := blobEntry(c.NewPath, existing.GetMode())
+			renamed.SHA = existing.SHA
+			entries = append(entries, renamed, blobEntry(c.Path, existing.GetMode()))
+		case "chmod":
+			existing, err := ix.existingFile(ctx, c.Path)
+			if err != nil {
+				return nil, err
+			}
+			if existing.GetMode() == gitModeSymlink {
+				return nil, fmt.Errorf("%s is a symbolic link, which has no executable bit", c.Path)
+			}
+			mode := gitModeExecutable
+			if c.Executable != nil && !*c.Executable {
+				mode = gitModeFile
+			}
+			entry := blobEntry(c.Path, mode)
+			entry.SHA = existing.SHA
+			entries = append(entries, entry)
+		case "symlink":
+			existing, err := ix.entry(ctx, c.Path)
+			if err != nil {
+				return nil, err
+			}
+			if existing.GetType() == "tree" {
+				return nil, fmt.Errorf("%s is a directory", c.Path)
+			}
+			entry := blobEntry(c.Path, gitModeSymlink)
+			entry.Content = github.Ptr(c.Target)
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+// CommitChangesResult describes the commit created by commit_changes.
+type CommitChangesResult struct {
+	SHA       string `json:"sha"`
+	HTMLURL   string `json:"html_url"`
+	Branch    string `json:"branch"`
+	ParentSHA string `json:"parent_sha"`
+	TreeSHA   string `json:"tree_sha"`
+}
+
+// CommitChanges creates a tool to add, modify, delete, rename and change the mode of files in a single commit.
+func CommitChanges(getClient GetClientFn, t translations.TranslationHelperFunc) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("commit_changes",
+			mcp.WithDescription(t("TOOL_COMMIT_CHANGES_DESCRIPTION", "Commit a set of file changes to a branch as a single commit: add or modify files with text or base64 content, delete, rename, make files executable or not, and create symbolic links. Pass expected_head_sha to make sure the branch didn't move since its content was read; the commit fails cleanly if it did.")),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:           t("TOOL_COMMIT_CHANGES_USER_TITLE", "Commit file changes"),
+				ReadOnlyHint:    ToBoolPtr(false),
+				DestructiveHint: ToBoolPtr(true),
+			}),
+			mcp.WithString("owner",
+				mcp.Required(),
+				mcp.Description("Repository owner"),
+			),
+			mcp.WithString("repo",
+				mcp.Required(),
+				mcp.Description("Repository name"),
+			),
+			mcp.WithString("branch",
+				mcp.Required(),
+				mcp.Description("Branch to commit to"),
+			),
+			mcp.WithString("message",
+				mcp.Required(),
+				mcp.Description("Commit message"),
+			),
+			mcp.WithArray("changes",
+				mcp.Required(),
+				mcp.Items(
+					map[string]interface{}{
+						"type":                 "object",
+						"additionalProperties": false,
+						"required":             []string{"operation", "path"},
+						"properties": map[string]interface{}{
+							"operation": map[string]interface{}{
+								"type":        "string",
+								"enum":        []string{"add", "modify", "delete", "rename", "chmod", "symlink"},
+								"description": "add a new file, modify an existing one, delete a file, rename a file to new_path, chmod to set whether a file is executable, or create or replace a symlink to target",
+							},
+							"path": map[string]interface{}{
+								"type":        "string",
+								"description": "path to the file",
+							},
+							"content": map[string]interface{}{
+								"type":        "string",
+								"description": "file content, for add and modify. Symbolic links are changed with symlink instead of modify",
+							},
+							"encoding": map[string]interface{}{
+								"type":        "string",
+								"enum":        []string{"utf-8", "base64"},
+								"description": "encoding of content, base64 for binary files. Defaults to utf-8",
+							},
+							"executable": map[string]interface{}{
+								"type":        "boolean",
+								"description": "whether the file is executable, for add, modify and chmod. add creates regular files and modify keeps the mode by default, chmod makes files executable",
+							},
+							"new_path": map[string]interface{}{
+								"type":        "string",
+								"description": "new path of the file, for rename",
+							},
+							"target": map[string]interface{}{
+								"type":        "string",
+								"description": "path the link points to, for symlink",
+							},
+						},
+					}),
+				mcp.Description("Changes to commit. Each path can only be changed once"),
+			),
+			mcp.WithString("expected_head_sha",
+				mcp.Description("SHA the branch is expected to point to. The commit fails if the branch moved"),
+			),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			owner, err := RequiredParam[string](request, "owner")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			repo, err := RequiredParam[string](request, "repo")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			branch, err := RequiredParam[string](request, "branch")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			message, err := RequiredParam[string](request, "message")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			expectedHeadSHA, err := OptionalParam[string](request, "expected_head_sha")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			changes, err := parseFileChanges(request)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			client, err := getClient(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get GitHub client: %w", err)
+			}
+
+			ref, resp, err := client.Git.GetRef(ctx, owner, repo, "refs/heads/"+branch)
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx,
+					"failed to get branch reference",
+					resp,
+					err,
+				), nil
+			}
+			defer func() { _ = resp.Body.Close() }()
+
+			headSHA := ref.GetObject().GetSHA()
+			if expectedHeadSHA != "" && headSHA != expectedHeadSHA {
+				return mcp.NewToolResultError(fmt.Sprintf("branch %s moved: it is at %s instead of the expected %s. Read the new changes and retry", branch, headSHA, expectedHeadSHA)), nil
+			}
+
+			baseCommit, resp, err := client.Git.GetCommit(ctx, owner, repo, headSHA)
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx,
+					"failed to get base commit",
+					resp,
+					err,
+				), nil
+			}
+			defer func() { _ = resp.Body.Close() }()
+
+			baseTreeSHA := baseCommit.GetTree().GetSHA()
+			entries, err := treeEntries(ctx, newTreeIndex(client, owner, repo, baseTreeSHA), changes)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			newTree, resp, err := client.Git.CreateTree(ctx, owner, repo, baseTreeSHA, entries)
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx,
+					"failed to create tree",
+					resp,
+					err,
+				), nil
+			}
+			defer func() { _ = resp.Body.Close() }()
+			if newTree.GetSHA() == baseTreeSHA {
+				return mcp.NewToolResultError("nothing to commit, the changes leave the files as they are"), nil
+			}
+
+			newCommit, resp, err := client.Git.CreateCommit(ctx, owner, repo, github.Commit{
+				Message: github.Ptr(message),
+				Tree:    newTree,
+				Parents: []*github.Commit{{SHA: github.Ptr(headSHA)}},
+			}, nil)
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx,
+					"failed to create commit",
+					resp,
+					err,
+				), nil
+			}
+			defer func() { _ = resp.Body.Close() }()
+
+			// The update is not forced, so it fails if the branch moved since it was read
+			_, resp, err = client.Git.UpdateRef(ctx, owner, repo, ref.GetRef(), github.UpdateRef{
+				SHA:   newCommit.GetSHA(),
+				Force: github.Ptr(false),
+			})
+			if err != nil {
+				errResult := ghErrors.NewGitHubAPIErrorResponse(ctx,
+					"failed to update reference",
+					resp,
+					err,
+				)
+				if resp == nil || !isRuleViolationStatus(resp.StatusCode) {
+					return errResult, nil
+				}
+				// A 422 is returned both when the branch moved and when a rule blocks the update
+				current, _, refErr := client.Git.GetRef(ctx, owner, repo, ref.GetRef())
+				if refErr == nil && current.GetObject().GetSHA() != headSHA {
+					return mcp.NewToolResultError(fmt.Sprintf("branch %s moved while the commit was created, so it was not updated to commit %s. Read the new changes and retry", branch, newCommit.GetSHA())), nil
+				}
+				return withBlockingRulesHint(ctx, client, owner, repo, branch, errResult), nil
+			}
+			defer func() { _ = resp.Body.Close() }()
+
+			return MarshalledTextResult(CommitChangesResult{
+				SHA:       newCommit.GetSHA(),
+				HTMLURL:   newCommit.GetHTMLURL(),
+				Branch:    branch,
+				ParentSHA: headSHA,
+				TreeSHA:   newTree.GetSHA(),
+			}), nil
+		}
+}
+
+// GitBlob is the content of a Git blob. Content is decoded to UTF-8 for text, and left base64 encoded otherwise.
+type GitBlob struct {
+	SHA      string `json:"sha"`
+	Size     int    `json:"size"`
+	Encoding string `json:"encoding"`
+	Content  string `json:"content"`
+	// Truncated is set when text content was cut at the inline size limit
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// GetGitBlob creates a tool to get a Git blob by SHA.
+func GetGitBlob(getClient GetClientFn, t translations.TranslationHelperFunc, maxInlineSize int) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	if maxInlineSize <= 0 {
+		maxInlineSize = DefaultMaxInlineSize
+	}
+	return mcp.NewTool("get_git_blob",
+			mcp.WithDescription(t("TOOL_GET_GIT_BLOB_DESCRIPTION", "Get a Git blob by its SHA. Text content is returned as UTF-8, binary content base64 encoded. Text larger than the inline size limit is cut short, and larger binary content is replaced by a summary like get_file_contents does")),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:        t("TOOL_GET_GIT_BLOB_USER_TITLE", "Get Git blob"),
+				ReadOnlyHint: ToBoolPtr(true),
+			}),
+			mcp.WithString("owner",
+				mcp.Required(),
+				mcp.Description("Repository owner (username or organization)"),
+			),
+			mcp.WithString("repo",
+				mcp.Required(),
+				mcp.Description("Repository name"),
+			),
+			mcp.WithString("sha",
+				mcp.Required(),
+				mcp.Description("SHA of the blob"),
+			),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			owner, err := RequiredParam[string](request, "owner")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			repo, err := RequiredParam[string](request, "repo")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			sha, err := RequiredParam[string](request, "sha")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			client, err := getClient(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get GitHub client: %w", err)
+			}
+			blob, resp, err := client.Git.GetBlob(ctx, owner, repo, sha)
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx,
+					"failed to get blob",
+					resp,
+					err,
+				), nil
+			}
+			defer func() { _ = resp.Body.Close() }()
+
+			result := GitBlob{SHA: blob.GetSHA(), Size: blob.GetSize(), Encoding: blob.GetEncoding(), Content: blob.GetContent()}
+			if result.Encoding == "base64" {
+				// The API wraps base64 content in lines
+				content, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(result.Content, "\n", ""))
+				if err != nil {
+					return nil, fmt.Errorf("failed to decode blob content: %w", err)
+				}
+				format := sniff.Detect(content[:min(len(content), sniff.SampleSize)])
+				switch {
+				case format.IsText() && utf8.Valid(content):
+					if len(content) > maxInlineSize {
+						n := maxInlineSize
+						for n > 0 && !utf8.RuneStart(content[n]) {
+							n--
+						}
+						content, result.Truncated = content[:n], true
+					}
+					result.Encoding, result.Content = "utf-8", string(content)
+				case len(content) > maxInlineSize:
+					return binaryFileResult(bytes.NewReader(content), format, format.MIMEType, len(content), "", result.SHA, nil, maxInlineSize, nil), nil
+				default:
+					result.Content = base64.StdEncoding.EncodeToString(content)
+				}
+			}
+			return MarshalledTextResult(result), nil
+		}
+}
+
+// GetGitCommit creates a tool to get a Git commit object by SHA.
+func GetGitCommit(getClient GetClientFn, t translations.TranslationHelperFunc) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("get_git_commit",
+			mcp.WithDescription(t("TOOL_GET_GIT_COMMIT_DESCRIPTION", "Get a Git commit object by its SHA: message, author, committer, tree, parents and signature verification")),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:        t("TOOL_GET_GIT_COMMIT_USER_TITLE", "Get Git commit"),
+				ReadOnlyHint: ToBoolPtr(true),
+			}),
+			mcp.WithString("owner",
+				mcp.Required(),
+				mcp.Description("Repository owner (username or organization)"),
+			),
+			mcp.WithString("repo",
+				mcp.Required(),
+				mcp.Description("Repository name"),
+			),
+			mcp.WithString("sha",
+				mcp.Required(),
+				mcp.Description("SHA of the commit"),
+			),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			owner, err := RequiredParam[string](request, "owner")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			repo, err := RequiredParam[string](request, "repo")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			sha, err := RequiredParam[string](request, "sha")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			client, err := getClient(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get GitHub client: %w", err)
+			}
+			commit, resp, err := client.Git.GetCommit(ctx, owner, repo, sha)
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx,
+					"failed to get commit",
+					resp,
+					err,
+				), nil
+			}
+			defer func() { _ = resp.Body.Close() }()
+
+			return MarshalledTextResult(convertToMinimalGitCommit(commit)), nil
+		}
+}
+
+// GetGitRef creates a tool to get a Git reference.
+func GetGitRef(getClient GetClientFn, t translations.TranslationHelperFunc) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("get_git_ref",
+			mcp.WithDescription(t("TOOL_GET_GIT_REF_DESCRIPTION", "Get a Git reference, such as a branch or tag, and the SHA it points to")),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:        t("TOOL_GET_GIT_REF_USER_TITLE", "Get Git reference"),
+				ReadOnlyHint: ToBoolPtr(true),
+			}),
+			mcp.WithString("owner",
+				mcp.Required(),
+				mcp.Description("Repository owner (username or organization)"),
+			),
+			mcp.WithString("repo",
+				mcp.Required(),
+				mcp.Description("Repository name"),
+			),
+			mcp.WithString("ref",
+				mcp.Required(),
+				mcp.Description("Fully qualified reference, e.g. `heads/main` or `refs/tags/v1.0.0`"),
+			),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			owner, err := RequiredParam[string](request, "owner")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			repo, err := RequiredParam[string](request, "repo")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			ref, err := RequiredParam[string](request, "ref")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			client, err := getClient(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get GitHub client: %w", err)
+			}
+			reference, resp, err := client.Git.GetRef(ctx, owner, repo, ref)
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx,
+					"failed to get reference",
+					resp,
+					err,
+				), nil
+			}
+			defer func() { _ = resp.Body.Close() }()
+
+			return MarshalledTextResult(convertToMinimalGitRef(reference)), nil
+		}
+}
+
+// ListGitRefs creates a tool to list the Git references that start with a prefix.
+func ListGitRefs(getClient GetClientFn, t translations.TranslationHelperFunc) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("list_git_refs",
+			mcp.WithDescription(t("TOOL_LIST_GIT_REFS_DESCRIPTION", "List the Git references of a repository that start with a prefix, such as `heads/` for all branches or `tags/v1.` for the v1 tags")),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:        t("TOOL_LIST_GIT_REFS_USER_TITLE", "List Git references"),
+				ReadOnlyHint: ToBoolPtr(true),
+			}),
+			mcp.WithString("owner",
+				mcp.Required(),
+				mcp.Description("Repository owner (username or organization)"),
+			),
+			mcp.WithString("repo",
+				mcp.Required(),
+				mcp.Description("Repository name"),
+			),
+			mcp.WithString("prefix",
+				mcp.Description("Prefix of the references to list, e.g. `heads/feature-` or `tags/`. Lists all references if empty"),
+			),
+			WithPagination(),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			owner, err := RequiredParam[string](request, "owner")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			repo, err := RequiredParam[string](request, "repo")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			prefix, err := OptionalParam[string](request, "prefix")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			pagination, err := OptionalPaginationParams(request)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			client, err := getClient(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get GitHub client: %w", err)
+			}
+			refs, resp, err := client.Git.ListMatchingRefs(ctx, owner, repo, &github.ReferenceListOptions{
+				Ref: prefix,
+				ListOptions: github.ListOptions{
+					Page:    pagination.Page,
+					PerPage: pagination.PerPage,
+				},
+			})
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx,
+					"failed to list references",
+					resp,
+					err,
+				), nil
+			}
+			defer func() { _ = resp.Body.Close() }()
+
+			minimalRefs := make([]MinimalGitRef, 0, len(refs))
+			for _, ref := range refs {
+				minimalRefs = append(minimalRefs, convertToMinimalGitRef(ref))
+			}
+			return MarshalledTextResult(minimalRefs), nil
+		}
+}
+
+// GetGitTag creates a tool to get an annotated Git tag object by SHA.
+func GetGitTag(getClient GetClientFn, t translations.TranslationHelperFunc) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("get_git_tag",
+			mcp.WithDescription(t("TOOL_GET_GIT_TAG_DESCRIPTION", "Get an annotated Git tag object by its SHA: name, message, tagger, tagged object and signature verification. The SHA is the one a tag reference of type tag points to")),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:        t("TOOL_GET_GIT_TAG_USER_TITLE", "Get Git tag"),
+				ReadOnlyHint: ToBoolPtr(true),
+			}),
+			mcp.WithString("owner",
+				mcp.Required(),
+				mcp.Description("Repository owner (username or organization)"),
+			),
+			mcp.WithString("repo",
+				mcp.Required(),
+				mcp.Description("Repository name"),
+			),
+			mcp.WithString("sha",
+				mcp.Required(),
+				mcp.Description("SHA of the tag object"),
+			),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			owner, err := RequiredParam[string](request, "owner")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			repo, err := RequiredParam[string](request, "repo")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			sha, err := RequiredParam[string](request, "sha")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			client, err := getClient(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get GitHub client: %w", err)
+			}
+			tag, resp, err := client.Git.GetTag(ctx, owner, repo, sha)
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx,
+					"failed to get tag",
+					resp,
+					err,
+				), nil
+			}
+			defer func() { _ = resp.Body.Close() }()
+
+			return MarshalledTextResult(MinimalGitTag{
+				SHA:          tag.GetSHA(),
+				Tag:          tag.GetTag(),
+				Message:      tag.GetMessage(),
+				Tagger:       convertToMinimalCommitAuthor(tag.Tagger),
+				ObjectSHA:    tag.GetObject().GetSHA(),
+				ObjectType:   tag.GetObject().GetType(),
+				Verification: convertToMinimalGitVerification(tag.Verification),
+			}), nil
+		}
+}
+
+// CreateBlob creates a tool to create a Git blob.
+func CreateBlob(getClient GetClientFn, t translations.TranslationHelperFunc) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("create_blob",
+			mcp.WithDescription(t("TOOL_CREATE_BLOB_DESCRIPTION", "Create a Git blob from text or base64 content, to reference from a tree created with create_tree")),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:        t("TOOL_CREATE_BLOB_USER_TITLE", "Create Git blob"),
+				ReadOnlyHint: ToBoolPtr(false),
+			}),
+			mcp.WithString("owner",
+				mcp.Required(),
+				mcp.Description("Repository owner (username or organization)"),
+			),
+			mcp.WithString("repo",
+				mcp.Required(),
+				mcp.Description("Repository name"),
+			),
+			mcp.WithString("content",
+				mcp.Required(),
+				mcp.Description("Content of the blob"),
+			),
+			mcp.WithString("encoding",
+				mcp.Description("Encoding of content, base64 for binary content"),
+				mcp.Enum("utf-8", "base64"),
+				mcp.DefaultString("utf-8"),
+			),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			owner, err := RequiredParam[string](request, "owner")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			repo, err := RequiredParam[string](request, "repo")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			// Empty blobs are valid, so content is only required to be present
+			content, err := OptionalParam[string](request, "content")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			if _, ok := request.GetArguments()["content"]; !ok {
+				return mcp.NewToolResultError("missing required parameter: content"), nil
+			}
+			encoding, err := OptionalParam[string](request, "encoding")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			switch encoding {
+			case "":
+				encoding = "utf-8"
+			case "utf-8":
+			case "base64":
+				if _, err := base64.StdEncoding.DecodeString(content); err != nil {
+					return mcp.NewToolResultError(fmt.Sprintf("content is not valid base64: %s", err)), nil
+				}
+			default:
+				return mcp.NewToolResultError("encoding must be utf-8 or base64"), nil
+			}
+
+			client, err := getClient(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get GitHub client: %w", err)
+			}
+			blob, resp, err := client.Git.CreateBlob(ctx, owner, repo, github.Blob{
+				Content:  github.Ptr(content),
+				Encoding: github.Ptr(encoding),
+			})
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx,
+					"failed to create blob",
+					resp,
+					err,
+				), nil
+			}
+			defer func() { _ = resp.Body.Close() }()
+
+			return MarshalledTextResult(MinimalResponse{ID: blob.GetSHA(), URL: blob.GetURL()}), nil
+		}
+}
+
+// CreateTree creates a tool to create a Git tree.
+func CreateTree(getClient GetClientFn, t translations.TranslationHelperFunc) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("create_tree",
+			mcp.WithDescription(t("TOOL_CREATE_TREE_DESCRIPTION", "Create a Git tree from entries, optionally on top of a base tree. The entries of the base tree that aren't changed are kept")),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:        t("TOOL_CREATE_TREE_USER_TITLE", "Create Git tree"),
+				ReadOnlyHint: ToBoolPtr(false),
+			}),
+			mcp.WithString("owner",
+				mcp.Required(),
+				mcp.Description("Repository owner (username or organization)"),
+			),
+			mcp.WithString("repo",
+				mcp.Required(),
+				mcp.Description("Repository name"),
+			),
+			mcp.WithString("base_tree",
+				mcp.Description("SHA of the tree to base the new tree on. Without it, the tree only holds the given entries"),
+			),
+			mcp.WithArray("entries",
+				mcp.Required(),
+				mcp.Items(
+					map[string]interface{}{
+						"type":                 "object",
+						"additionalProperties": false,
+						"required":             []string{"path", "mode", "type"},
+						"properties": map[string]interface{}{
+							"path": map[string]interface{}{
+								"type":        "string",
+								"description": "path of the entry, which can contain slashes to change nested directories",
+							},
+							"mode": map[string]interface{}{
+								"type":        "string",
+								"enum":        []string{gitModeFile, gitModeExecutable, "040000", "160000", gitModeSymlink},
+								"description": "100644 for a file, 100755 for an executable, 040000 for a directory, 160000 for a submodule, 120000 for a symlink",
+							},
+							"type": map[string]interface{}{
+								"type": "string",
+								"enum": []string{"blob", "tree", "commit"},
+							},
+							"sha": map[string]interface{}{
+								"type":        "string",
+								"description": "SHA of the blob, tree or commit",
+							},
+							"content": map[string]interface{}{
+								"type":        "string",
+								"description": "UTF-8 content of a blob to create, instead of sha",
+							},
+						},
+					}),
+				mcp.Description("Entries of the tree. An entry with neither sha nor content deletes the path from the base tree"),
+			),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			owner, err := RequiredParam[string](request, "owner")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			repo, err := RequiredParam[string](request, "repo")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			baseTree, err := OptionalParam[string](request, "base_tree")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			items, ok := request.GetArguments()["entries"].([]interface{})
+			if !ok || len(items) == 0 {
+				return mcp.NewToolResultError("entries parameter must be a non-empty array of objects with path, mode and type"), nil
+			}
+			entries := make([]*github.TreeEntry, 0, len(items))
+			for _, item := range items {
+				m, ok := item.(map[string]interface{})
+				if !ok {
+					return mcp.NewToolResultError("each entry must be an object with path, mode and type"), nil
+				}
+				path, _ := m["path"].(string)
+				mode, _ := m["mode"].(string)
+				entryType, _ := m["type"].(string)
+				if path == "" || mode == "" || entryType == "" {
+					return mcp.NewToolResultError("each entry must have a path, mode and type"), nil
+				}
+				entry := &github.TreeEntry{Path: github.Ptr(path), Mode: github.Ptr(mode), Type: github.Ptr(entryType)}
+				if sha, ok := m["sha"].(string); ok && sha != "" {
+					entry.SHA = github.Ptr(sha)
+				}
+				if content, ok := m["content"].(string); ok {
+					entry.Content = github.Ptr(content)
+				}
+				if entry.SHA != nil && entry.Content != nil {
+					return mcp.NewToolResultError(fmt.Sprintf("%s: pass either sha or content, not both", path)), nil
+				}
+				if entry.SHA == nil && entry.Content == nil && baseTree == "" {
+					return mcp.NewToolResultError(fmt.Sprintf("%s: entries without sha or content delete paths from base_tree, which is not set", path)), nil
+				}
+				entries = append(entries, entry)
+			}
+
+			client, err := getClient(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get GitHub client: %w", err)
+			}
+			tree, resp, err := client.Git.CreateTree(ctx, owner, repo, baseTree, entries)
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx,
+					"failed to create tree",
+					resp,
+					err,
+				), nil
+			}
+			defer func() { _ = resp.Body.Close() }()
+
+			return MarshalledTextResult(MinimalResponse{ID: tree.GetSHA(), URL: fmt.Sprintf("%srepos/%s/%s/git/trees/%s", client.BaseURL, owner, repo, tree.GetSHA())}), nil
+		}
+}
+
+// CreateCommit creates a tool to create a Git commit object.
+func CreateCommit(getClient GetClientFn, t translations.TranslationHelperFunc) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("create_commit",
+			mcp.WithDescription(t("TOOL_CREATE_COMMIT_DESCRIPTION", "Create a Git commit object from a tree and parent commits. The commit isn't on any branch until a reference is updated to it with update_ref")),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:        t("TOOL_CREATE_COMMIT_USER_TITLE", "Create Git commit"),
+				ReadOnlyHint: ToBoolPtr(false),
+			}),
+			mcp.WithString("owner",
+				mcp.Required(),
+				mcp.Description("Repository owner (username or organization)"),
+			),
+			mcp.WithString("repo",
+				mcp.Required(),
+				mcp.Description("Repository name"),
+			),
+			mcp.WithString("message",
+				mcp.Required(),
+				mcp.Description("Commit message"),
+			),
+			mcp.WithString("tree",
+				mcp.Required(),
+				mcp.Description("SHA of the tree of the commit"),
+			),
+			mcp.WithArray("parents",
+				mcp.Description("SHAs of the parent commits: one for a regular commit, several for a merge commit, none for a root commit"),
+				mcp.WithStringItems(),
+			),
+			mcp.WithString("author_name",
+				mcp.Description("Name of the author. Defaults to the authenticated user"),
+			),
+			mcp.WithString("author_email",
+				mcp.Description("Email of the author, required with author_name"),
+			),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			owner, err := RequiredParam[string](request, "owner")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			repo, err := RequiredParam[string](request, "repo")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			message, err := RequiredParam[string](request, "message")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			tree, err := RequiredParam[string](request, "tree")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			parents, err := OptionalStringArrayParam(request, "parents")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			authorName, err := OptionalParam[string](request, "author_name")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			authorEmail, err := OptionalParam[string](request, "author_email")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			if (authorName == "") != (authorEmail == "") {
+				return mcp.NewToolResultError("author_name and author_email must be passed together"), nil
+			}
+
+			commit := github.Commit{
+				Message: github.Ptr(message),
+				Tree:    &github.Tree{SHA: github.Ptr(tree)},
+				Parents: []*github.Commit{},
+			}
+			for _, parent := range parents {
+				commit.Parents = append(commit.Parents, &github.Commit{SHA: github.Ptr(parent)})
+			}
+			if authorName != "" {
+				commit.Author = &github.CommitAuthor{Name: github.Ptr(authorName), Email: github.Ptr(authorEmail)}
+			}
+
+			client, err := getClient(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get GitHub client: %w", err)
+			}
+			created, resp, err := client.Git.CreateCommit(ctx, owner, repo, commit, nil)
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx,
+					"failed to create commit",
+					resp,
+					err,
+				), nil
+			}
+			defer func() { _ = resp.Body.Close() }()
+
+			return MarshalledTextResult(convertToMinimalGitCommit(created)), nil
+		}
+}
+
+// UpdateRef creates a tool to point a Git reference to another commit.
+func UpdateRef(getClient GetClientFn, t translations.TranslationHelperFunc) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("update_ref",
+			mcp.WithDescription(t("TOOL_UPDATE_REF_DESCRIPTION", "Point a Git reference, such as a branch, to another commit. Only fast forwards are allowed unless force is set")),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:           t("TOOL_UPDATE_REF_USER_TITLE", "Update Git reference"),
+				ReadOnlyHint:    ToBoolPtr(false),
+				DestructiveHint: ToBoolPtr(true),
+			}),
+			mcp.WithString("owner",
+				mcp.Required(),
+				mcp.Description("Repository owner (username or organization)"),
+			),
+			mcp.WithString("repo",
+				mcp.Required(),
+				mcp.Description("Repository name"),
+			),
+			mcp.WithString("ref",
+				mcp.Required(),
+				mcp.Description("Fully qualified reference, e.g. `heads/main`"),
+			),
+			mcp.WithString("sha",
+				mcp.Required(),
+				mcp.Description("SHA of the commit to point the reference to"),
+			),
+			mcp.WithBoolean("force",
+				mcp.Description("Update the reference even if it is not a fast forward, discarding the commits only it pointed to"),
+				mcp.DefaultBool(false),
+			),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			owner, err := RequiredParam[string](request, "owner")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			repo, err := RequiredParam[string](request, "repo")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			ref, err := RequiredParam[string](request, "ref")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			sha, err := RequiredParam[string](request, "sha")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			force, err := OptionalBoolParamWithDefault(request, "force", false)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			client, err := getClient(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get GitHub client: %w", err)
+			}
+			reference, resp, err := client.Git.UpdateRef(ctx, owner, repo, ref, github.UpdateRef{
+				SHA:   sha,
+				Force: github.Ptr(force),
+			})
+			if err != nil {
+				if !force && resp != nil && resp.StatusCode == http.StatusUnprocessableEntity {
+					_, _ = ghErrors.NewGitHubAPIErrorToCtx(ctx, "failed to update reference", resp, err)
+					return mcp.NewToolResultError(fmt.Sprintf("failed to update reference: %s. If %s is not a descendant of the commit %s points to, pass force to overwrite it", err, sha, ref)), nil
+				}
+				return ghErrors.NewGitHubAPIErrorResponse(ctx,
+					"failed to update reference",
+					resp,
+					err,
+				), nil
+			}
+			defer func() { _ = resp.Body.Close() }()
+
+			return MarshalledTextResult(convertToMinimalGitRef(reference)), nil
+		}
+}
+
+// DeleteRef creates a tool to delete a Git reference.
+func DeleteRef(getClient GetClientFn, t translations.TranslationHelperFunc) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("delete_ref",
+			mcp.WithDescription(t("TOOL_DELETE_REF_DESCRIPTION", "Delete a Git reference, such as a branch or tag")),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:           t("TOOL_DELETE_REF_USER_TITLE", "Delete Git reference"),
+				ReadOnlyHint:    ToBoolPtr(false),
+				DestructiveHint: ToBoolPtr(true),
+			}),
+			mcp.WithString("owner",
+				mcp.Required(),
+				mcp.Description("Repository owner (username or organization)"),
+			),
+			mcp.WithString("repo",
+				mcp.Required(),
+				mcp.Description("Repository name"),
+			),
+			mcp.WithString("ref",
+				mcp.Required(),
+				mcp.Description("Fully qualified reference, e.g. `heads/feature` or `tags/v1.0.0`"),
+			),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			owner, err := RequiredParam[string](request, "owner")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			repo, err := RequiredParam[string](request, "repo")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			ref, err := RequiredParam[string](request, "ref")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			client, err := getClient(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get GitHub client: %w", err)
+			}
+			resp, err := client.Git.DeleteRef(ctx, owner, repo, ref)
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx,
+					"failed to delete reference",
+					resp,
+					err,
+				), nil
+			}
+			defer func() { _ = resp.Body.Close() }()
+
+			return mcp.NewToolResultText(fmt.Sprintf("Deleted reference %s", ref)), nil
+		}
+}
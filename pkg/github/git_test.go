@@ -0,0 +1,675 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/github/github-mcp-server/internal/toolsnaps"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/google/go-github/v77/github"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/migueleliasweb/go-github-mock/src/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockGitTrees serves the trees of a small repository by SHA.
+func mockGitTrees() mock.MockBackendOption {
+	trees := map[string][]map[string]any{
+		"tree-root": {
+			{"path": "README.md", "mode": "100644", "type": "blob", "sha": "readme-sha"},
+			{"path": "script.sh", "mode": "100644", "type": "blob", "sha": "script-sha"},
+			{"path": "current", "mode": "120000", "type": "blob", "sha": "current-sha"},
+			{"path": "src", "mode": "040000", "type": "tree", "sha": "tree-src"},
+		},
+		"tree-src": {
+			{"path": "main.go", "mode": "100644", "type": "blob", "sha": "main-sha"},
+			{"path": "old.go", "mode": "100755", "type": "blob", "sha": "old-sha"},
+		},
+	}
+	return mock.WithRequestMatchHandler(
+		mock.GetReposGitTreesByOwnerByRepoByTreeSha,
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			sha := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+			entries, ok := trees[sha]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			data, _ := json.Marshal(map[string]any{"sha": sha, "tree": entries, "truncated": false})
+			_, _ = w.Write(data)
+		}),
+	)
+}
+
+func Test_CommitChanges(t *testing.T) {
+	tool, _ := CommitChanges(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+	assert.False(t, *tool.Annotations.ReadOnlyHint)
+	assert.True(t, *tool.Annotations.DestructiveHint)
+	assert.ElementsMatch(t, tool.InputSchema.Required, []string{"owner", "repo", "branch", "message", "changes"})
+
+	// The mocks are functions, as mocked responses are consumed by the first client that serves them
+	getRef := func() mock.MockBackendOption {
+		return mock.WithRequestMatch(
+			mock.GetReposGitRefByOwnerByRepoByRef,
+			&github.Reference{Ref: github.Ptr("refs/heads/main"), Object: &github.GitObject{SHA: github.Ptr("head-sha")}},
+		)
+	}
+	getCommit := func() mock.MockBackendOption {
+		return mock.WithRequestMatch(
+			mock.GetReposGitCommitsByOwnerByRepoByCommitSha,
+			&github.Commit{SHA: github.Ptr("head-sha"), Tree: &github.Tree{SHA: github.Ptr("tree-root")}},
+		)
+	}
+	changes := []any{
+		map[string]any{"operation": "add", "path": "docs/new.md", "content": "# New\n"},
+		map[string]any{"operation": "modify", "path": "src/main.go", "content": "package main\n"},
+		map[string]any{"operation": "delete", "path": "README.md"},
+		map[string]any{"operation": "rename", "path": "src/old.go", "new_path": "src/renamed.go"},
+		map[string]any{"operation": "chmod", "path": "script.sh"},
+		map[string]any{"operation": "symlink", "path": "latest", "target": "src/main.go"},
+		map[string]any{"operation": "add", "path": "logo.png", "content": "iVBORw0KGgo=", "encoding": "base64"},
+	}
+
+	tests := []struct {
+		name           string
+		mockedClient   *http.Client
+		requestArgs    map[string]any
+		expectError    string
+		expectHint     string
+		expectedResult CommitChangesResult
+	}{
+		{
+			name: "commits all kinds of changes",
+			mockedClient: mock.NewMockedHTTPClient(
+				getRef(),
+				getCommit(),
+				mockGitTrees(),
+				mock.WithRequestMatchHandler(
+					mock.PostReposGitBlobsByOwnerByRepo,
+					expectRequestBody(t, map[string]any{"content": "iVBORw0KGgo=", "encoding": "base64"}).andThen(
+						mockResponse(t, http.StatusCreated, &github.Blob{SHA: github.Ptr("logo-sha")}),
+					),
+				),
+				mock.WithRequestMatchHandler(
+					mock.PostReposGitTreesByOwnerByRepo,
+					expectRequestBody(t, map[string]any{
+						"base_tree": "tree-root",
+						"tree": []any{
+							map[string]any{"path": "docs/new.md", "mode": "100644", "type": "blob", "content": "# New\n"},
+							map[string]any{"path": "src/main.go", "mode": "100644", "type": "blob", "content": "package main\n"},
+							map[string]any{"path": "README.md", "mode": "100644", "type": "blob", "sha": nil},
+							map[string]any{"path": "src/renamed.go", "mode": "100755", "type": "blob", "sha": "old-sha"},
+							map[string]any{"path": "src/old.go", "mode": "100755", "type": "blob", "sha": nil},
+							map[string]any{"path": "script.sh", "mode": "100755", "type": "blob", "sha": "script-sha"},
+							map[string]any{"path": "latest", "mode": "120000", "type": "blob", "content": "src/main.go"},
+							map[string]any{"path": "logo.png", "mode": "100644", "type": "blob", "sha": "logo-sha"},
+						},
+					}).andThen(
+						mockResponse(t, http.StatusCreated, &github.Tree{SHA: github.Ptr("tree-new")}),
+					),
+				),
+				mock.WithRequestMatchHandler(
+					mock.PostReposGitCommitsByOwnerByRepo,
+					expectRequestBody(t, map[string]any{"message": "Reorganize", "tree": "tree-new", "parents": []any{"head-sha"}}).andThen(
+						mockResponse(t, http.StatusCreated, &github.Commit{SHA: github.Ptr("new-sha"), HTMLURL: github.Ptr("https://github.com/owner/repo/commit/new-sha")}),
+					),
+				),
+				mock.WithRequestMatchHandler(
+					mock.PatchReposGitRefsByOwnerByRepoByRef,
+					expectRequestBody(t, map[string]any{"sha": "new-sha", "force": false}).andThen(
+						mockResponse(t, http.StatusOK, &github.Reference{Ref: github.Ptr("refs/heads/main")}),
+					),
+				),
+			),
+			requestArgs: map[string]any{"expected_head_sha": "head-sha", "changes": changes},
+			expectedResult: CommitChangesResult{
+				SHA:       "new-sha",
+				HTMLURL:   "https://github.com/owner/repo/commit/new-sha",
+				Branch:    "main",
+				ParentSHA: "head-sha",
+				TreeSHA:   "tree-new",
+			},
+		},
+		{
+			name:         "branch moved before the commit",
+			mockedClient: mock.NewMockedHTTPClient(getRef()),
+			requestArgs:  map[string]any{"expected_head_sha": "old-head", "changes": changes},
+			expectError:  "branch main moved: it is at head-sha instead of the expected old-head",
+		},
+		{
+			name: "branch moved while committing",
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatch(
+					mock.GetReposGitRefByOwnerByRepoByRef,
+					&github.Reference{Ref: github.Ptr("refs/heads/main"), Object: &github.GitObject{SHA: github.Ptr("head-sha")}},
+					&github.Reference{Ref: github.Ptr("refs/heads/main"), Object: &github.GitObject{SHA: github.Ptr("other-sha")}},
+				),
+				getCommit(),
+				mockGitTrees(),
+				mock.WithRequestMatch(mock.PostReposGitTreesByOwnerByRepo, &github.Tree{SHA: github.Ptr("tree-new")}),
+				mock.WithRequestMatch(mock.PostReposGitCommitsByOwnerByRepo, &github.Commit{SHA: github.Ptr("new-sha")}),
+				mock.WithRequestMatchHandler(
+					mock.PatchReposGitRefsByOwnerByRepoByRef,
+					mockResponse(t, http.StatusUnprocessableEntity, `{"message": "Update is not a fast forward"}`),
+				),
+			),
+			requestArgs: map[string]any{"changes": []any{map[string]any{"operation": "delete", "path": "README.md"}}},
+			expectError: "branch main moved while the commit was created, so it was not updated to commit new-sha",
+		},
+		{
+			name: "rule blocks the update",
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatch(
+					mock.GetReposGitRefByOwnerByRepoByRef,
+					&github.Reference{Ref: github.Ptr("refs/heads/main"), Object: &github.GitObject{SHA: github.Ptr("head-sha")}},
+					&github.Reference{Ref: github.Ptr("refs/heads/main"), Object: &github.GitObject{SHA: github.Ptr("head-sha")}},
+				),
+				getCommit(),
+				mockGitTrees(),
+				mock.WithRequestMatch(mock.PostReposGitTreesByOwnerByRepo, &github.Tree{SHA: github.Ptr("tree-new")}),
+				mock.WithRequestMatch(mock.PostReposGitCommitsByOwnerByRepo, &github.Commit{SHA: github.Ptr("new-sha")}),
+				mock.WithRequestMatchHandler(
+					mock.PatchReposGitRefsByOwnerByRepoByRef,
+					mockResponse(t, http.StatusUnprocessableEntity, `{"message": "Repository rule violations found"}`),
+				),
+				mock.WithRequestMatch(
+					mock.GetReposRulesBranchesByOwnerByRepoByBranch,
+					[]any{map[string]any{"type": "pull_request", "ruleset_source_type": "Repository", "ruleset_source": "owner/repo", "ruleset_id": 1}},
+				),
+			),
+			requestArgs: map[string]any{"changes": []any{map[string]any{"operation": "delete", "path": "README.md"}}},
+			expectError: "failed to update reference: PATCH",
+			expectHint:  "The branch main is governed by the following rules, which may be blocking this change:",
+		},
+		{
+			name:         "adding an existing file",
+			mockedClient: mock.NewMockedHTTPClient(getRef(), getCommit(), mockGitTrees()),
+			requestArgs:  map[string]any{"changes": []any{map[string]any{"operation": "add", "path": "src/main.go", "content": "x"}}},
+			expectError:  "src/main.go already exists, use the modify operation to change it",
+		},
+		{
+			name: "modifying keeps the mode of the file",
+			mockedClient: mock.NewMockedHTTPClient(
+				getRef(),
+				getCommit(),
+				mockGitTrees(),
+				mock.WithRequestMatchHandler(
+					mock.PostReposGitTreesByOwnerByRepo,
+					expectRequestBody(t, map[string]any{
+						"base_tree": "tree-root",
+						"tree": []any{
+							map[string]any{"path": "src/old.go", "mode": "100755", "type": "blob", "content": "package old\n"},
+						},
+					}).andThen(
+						mockResponse(t, http.StatusCreated, &github.Tree{SHA: github.Ptr("tree-new")}),
+					),
+				),
+				mock.WithRequestMatch(mock.PostReposGitCommitsByOwnerByRepo, &github.Commit{SHA: github.Ptr("new-sha")}),
+				mock.WithRequestMatch(mock.PatchReposGitRefsByOwnerByRepoByRef, &github.Reference{Ref: github.Ptr("refs/heads/main")}),
+			),
+			requestArgs: map[string]any{"changes": []any{map[string]any{"operation": "modify", "path": "src/old.go", "content": "package old\n"}}},
+			expectedResult: CommitChangesResult{
+				SHA:       "new-sha",
+				Branch:    "main",
+				ParentSHA: "head-sha",
+				TreeSHA:   "tree-new",
+			},
+		},
+		{
+			name:         "modifying a symbolic link",
+			mockedClient: mock.NewMockedHTTPClient(getRef(), getCommit(), mockGitTrees()),
+			requestArgs:  map[string]any{"changes": []any{map[string]any{"operation": "modify", "path": "current", "content": "src/main.go"}}},
+			expectError:  "current is a symbolic link, use the symlink operation to change its target",
+		},
+		{
+			name:         "deleting a missing file",
+			mockedClient: mock.NewMockedHTTPClient(getRef(), getCommit(), mockGitTrees()),
+			requestArgs:  map[string]any{"changes": []any{map[string]any{"operation": "delete", "path": "src/nope.go"}}},
+			expectError:  "src/nope.go does not exist",
+		},
+		{
+			name:         "deleting a directory",
+			mockedClient: mock.NewMockedHTTPClient(getRef(), getCommit(), mockGitTrees()),
+			requestArgs:  map[string]any{"changes": []any{map[string]any{"operation": "delete", "path": "src"}}},
+			expectError:  "src is not a file",
+		},
+		{
+			name:         "changing a path twice",
+			mockedClient: mock.NewMockedHTTPClient(),
+			requestArgs: map[string]any{"changes": []any{
+				map[string]any{"operation": "rename", "path": "a.go", "new_path": "b.go"},
+				map[string]any{"operation": "delete", "path": "b.go"},
+			}},
+			expectError: "b.go is changed more than once",
+		},
+		{
+			name:         "invalid base64",
+			mockedClient: mock.NewMockedHTTPClient(),
+			requestArgs:  map[string]any{"changes": []any{map[string]any{"operation": "add", "path": "a.bin", "content": "not base64!", "encoding": "base64"}}},
+			expectError:  "a.bin: content is not valid base64",
+		},
+		{
+			name:         "unknown operation",
+			mockedClient: mock.NewMockedHTTPClient(),
+			requestArgs:  map[string]any{"changes": []any{map[string]any{"operation": "copy", "path": "a.go"}}},
+			expectError:  `a.go: unknown operation "copy"`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			client := github.NewClient(tc.mockedClient)
+			_, handler := CommitChanges(stubGetClientFn(client), translations.NullTranslationHelper)
+			args := map[string]any{"owner": "owner", "repo": "repo", "branch": "main", "message": "Reorganize"}
+			for k, v := range tc.requestArgs {
+				args[k] = v
+			}
+
+			result, err := handler(context.Background(), createMCPRequest(args))
+			require.NoError(t, err)
+
+			if tc.expectHint != "" {
+				require.True(t, result.IsError)
+				require.Len(t, result.Content, 2)
+				assert.Contains(t, result.Content[0].(mcp.TextContent).Text, tc.expectError)
+				assert.Contains(t, result.Content[1].(mcp.TextContent).Text, tc.expectHint)
+				return
+			}
+			if tc.expectError != "" {
+				assert.Contains(t, getErrorResult(t, result).Text, tc.expectError)
+				return
+			}
+			var commit CommitChangesResult
+			require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &commit))
+			assert.Equal(t, tc.expectedResult, commit)
+		})
+	}
+}
+
+func Test_GetGitBlob(t *testing.T) {
+	tool, _ := GetGitBlob(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper, DefaultMaxInlineSize)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+	assert.True(t, *tool.Annotations.ReadOnlyHint)
+	assert.ElementsMatch(t, tool.InputSchema.Required, []string{"owner", "repo", "sha"})
+
+	tests := []struct {
+		name          string
+		blob          *github.Blob
+		maxInlineSize int
+		expected      GitBlob
+	}{
+		{
+			name:     "text is decoded",
+			blob:     &github.Blob{SHA: github.Ptr("text-sha"), Size: github.Ptr(6), Encoding: github.Ptr("base64"), Content: github.Ptr("aGVs\nbG8K\n")},
+			expected: GitBlob{SHA: "text-sha", Size: 6, Encoding: "utf-8", Content: "hello\n"},
+		},
+		{
+			name:     "binary stays base64",
+			blob:     &github.Blob{SHA: github.Ptr("bin-sha"), Size: github.Ptr(3), Encoding: github.Ptr("base64"), Content: github.Ptr("AAEC\n")},
+			expected: GitBlob{SHA: "bin-sha", Size: 3, Encoding: "base64", Content: "AAEC"},
+		},
+		{
+			name:          "large text is cut at a character boundary",
+			blob:          &github.Blob{SHA: github.Ptr("long-sha"), Size: github.Ptr(6), Encoding: github.Ptr("base64"), Content: github.Ptr("aMOpbGxv\n")},
+			maxInlineSize: 2,
+			expected:      GitBlob{SHA: "long-sha", Size: 6, Encoding: "utf-8", Content: "h", Truncated: true},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			client := github.NewClient(mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(
+					mock.GetReposGitBlobsByOwnerByRepoByFileSha,
+					expectPath(t, "/repos/owner/repo/git/blobs/"+tc.expected.SHA).andThen(mockResponse(t, http.StatusOK, tc.blob)),
+				),
+			))
+			_, handler := GetGitBlob(stubGetClientFn(client), translations.NullTranslationHelper, tc.maxInlineSize)
+			result, err := handler(context.Background(), createMCPRequest(map[string]any{"owner": "owner", "repo": "repo", "sha": tc.expected.SHA}))
+			require.NoError(t, err)
+
+			var blob GitBlob
+			require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &blob))
+			assert.Equal(t, tc.expected, blob)
+		})
+	}
+
+	t.Run("large binary is summarized", func(t *testing.T) {
+		content := append([]byte("%PDF-1.7\n"), bytes.Repeat([]byte{0}, 100)...)
+		client := github.NewClient(mock.NewMockedHTTPClient(
+			mock.WithRequestMatch(
+				mock.GetReposGitBlobsByOwnerByRepoByFileSha,
+				&github.Blob{SHA: github.Ptr("pdf-sha"), Size: github.Ptr(len(content)), Encoding: github.Ptr("base64"), Content: github.Ptr(base64.StdEncoding.EncodeToString(content))},
+			),
+		))
+		_, handler := GetGitBlob(stubGetClientFn(client), translations.NullTranslationHelper, 50)
+		result, err := handler(context.Background(), createMCPRequest(map[string]any{"owner": "owner", "repo": "repo", "sha": "pdf-sha"}))
+		require.NoError(t, err)
+
+		var summary BinaryFileSummary
+		require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &summary))
+		assert.Equal(t, "pdf-sha", summary.SHA)
+		assert.Equal(t, "pdf", summary.Format.Name)
+		assert.Equal(t, int64(len(content)), summary.Size)
+		assert.Equal(t, "content omitted because the file is larger than the inline size limit of 50 bytes", summary.Note)
+	})
+}
+
+func Test_GetGitCommit(t *testing.T) {
+	tool, _ := GetGitCommit(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+	assert.True(t, *tool.Annotations.ReadOnlyHint)
+
+	client := github.NewClient(mock.NewMockedHTTPClient(
+		mock.WithRequestMatch(
+			mock.GetReposGitCommitsByOwnerByRepoByCommitSha,
+			&github.Commit{
+				SHA:          github.Ptr("abc123"),
+				Message:      github.Ptr("Fix the build"),
+				Author:       &github.CommitAuthor{Name: github.Ptr("Mona"), Email: github.Ptr("mona@example.com")},
+				Tree:         &github.Tree{SHA: github.Ptr("tree-sha")},
+				Parents:      []*github.Commit{{SHA: github.Ptr("parent-sha")}},
+				Verification: &github.SignatureVerification{Verified: github.Ptr(true), Reason: github.Ptr("valid")},
+			},
+		),
+	))
+	_, handler := GetGitCommit(stubGetClientFn(client), translations.NullTranslationHelper)
+	result, err := handler(context.Background(), createMCPRequest(map[string]any{"owner": "owner", "repo": "repo", "sha": "abc123"}))
+	require.NoError(t, err)
+
+	var commit MinimalGitCommit
+	require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &commit))
+	assert.Equal(t, MinimalGitCommit{
+		SHA:          "abc123",
+		Message:      "Fix the build",
+		Author:       &MinimalCommitAuthor{Name: "Mona", Email: "mona@example.com"},
+		TreeSHA:      "tree-sha",
+		ParentSHAs:   []string{"parent-sha"},
+		Verification: &MinimalGitVerification{Verified: true, Reason: "valid"},
+	}, commit)
+}
+
+func Test_GetGitRef(t *testing.T) {
+	tool, _ := GetGitRef(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+	assert.True(t, *tool.Annotations.ReadOnlyHint)
+
+	client := github.NewClient(mock.NewMockedHTTPClient(
+		mock.WithRequestMatchHandler(
+			mock.GetReposGitRefByOwnerByRepoByRef,
+			http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/repos/owner/repo/git/ref/tags/v1.0.0" {
+					w.WriteHeader(http.StatusNotFound)
+					_, _ = w.Write([]byte(`{"message": "Not Found"}`))
+					return
+				}
+				_, _ = w.Write([]byte(`{"ref": "refs/tags/v1.0.0", "object": {"sha": "tag-sha", "type": "tag"}}`))
+			}),
+		),
+	))
+	_, handler := GetGitRef(stubGetClientFn(client), translations.NullTranslationHelper)
+
+	result, err := handler(context.Background(), createMCPRequest(map[string]any{"owner": "owner", "repo": "repo", "ref": "refs/tags/v1.0.0"}))
+	require.NoError(t, err)
+	var ref MinimalGitRef
+	require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &ref))
+	assert.Equal(t, MinimalGitRef{Ref: "refs/tags/v1.0.0", SHA: "tag-sha", Type: "tag"}, ref)
+
+	result, err = handler(context.Background(), createMCPRequest(map[string]any{"owner": "owner", "repo": "repo", "ref": "heads/nope"}))
+	require.NoError(t, err)
+	assert.Contains(t, getErrorResult(t, result).Text, "failed to get reference")
+}
+
+func Test_ListGitRefs(t *testing.T) {
+	tool, _ := ListGitRefs(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+	assert.True(t, *tool.Annotations.ReadOnlyHint)
+	assert.ElementsMatch(t, tool.InputSchema.Required, []string{"owner", "repo"})
+
+	// The pattern of the mock package doesn't match refs with slashes
+	matchingRefs := mock.EndpointPattern{Pattern: "/repos/{owner}/{repo}/git/matching-refs/{ref:.*}", Method: "GET"}
+	client := github.NewClient(mock.NewMockedHTTPClient(
+		mock.WithRequestMatchHandler(
+			matchingRefs,
+			expect(t, expectations{
+				path:        "/repos/owner/repo/git/matching-refs/tags/v1.",
+				queryParams: map[string]string{"page": "2", "per_page": "10"},
+			}).andThen(mockResponse(t, http.StatusOK, []*github.Reference{
+				{Ref: github.Ptr("refs/tags/v1.0.0"), Object: &github.GitObject{SHA: github.Ptr("sha1"), Type: github.Ptr("tag")}},
+				{Ref: github.Ptr("refs/tags/v1.1.0"), Object: &github.GitObject{SHA: github.Ptr("sha2"), Type: github.Ptr("commit")}},
+			})),
+		),
+	))
+	_, handler := ListGitRefs(stubGetClientFn(client), translations.NullTranslationHelper)
+	result, err := handler(context.Background(), createMCPRequest(map[string]any{"owner": "owner", "repo": "repo", "prefix": "refs/tags/v1.", "page": float64(2), "perPage": float64(10)}))
+	require.NoError(t, err)
+
+	var refs []MinimalGitRef
+	require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &refs))
+	assert.Equal(t, []MinimalGitRef{
+		{Ref: "refs/tags/v1.0.0", SHA: "sha1", Type: "tag"},
+		{Ref: "refs/tags/v1.1.0", SHA: "sha2", Type: "commit"},
+	}, refs)
+}
+
+func Test_GetGitTag(t *testing.T) {
+	tool, _ := GetGitTag(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+	assert.True(t, *tool.Annotations.ReadOnlyHint)
+
+	client := github.NewClient(mock.NewMockedHTTPClient(
+		mock.WithRequestMatch(
+			mock.GetReposGitTagsByOwnerByRepoByTagSha,
+			&github.Tag{
+				SHA:     github.Ptr("tag-sha"),
+				Tag:     github.Ptr("v1.0.0"),
+				Message: github.Ptr("First release"),
+				Tagger:  &github.CommitAuthor{Name: github.Ptr("Mona"), Email: github.Ptr("mona@example.com")},
+				Object:  &github.GitObject{SHA: github.Ptr("commit-sha"), Type: github.Ptr("commit")},
+			},
+		),
+	))
+	_, handler := GetGitTag(stubGetClientFn(client), translations.NullTranslationHelper)
+	result, err := handler(context.Background(), createMCPRequest(map[string]any{"owner": "owner", "repo": "repo", "sha": "tag-sha"}))
+	require.NoError(t, err)
+
+	var tag MinimalGitTag
+	require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &tag))
+	assert.Equal(t, MinimalGitTag{
+		SHA:        "tag-sha",
+		Tag:        "v1.0.0",
+		Message:    "First release",
+		Tagger:     &MinimalCommitAuthor{Name: "Mona", Email: "mona@example.com"},
+		ObjectSHA:  "commit-sha",
+		ObjectType: "commit",
+	}, tag)
+}
+
+func Test_CreateBlob(t *testing.T) {
+	tool, _ := CreateBlob(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+	assert.False(t, *tool.Annotations.ReadOnlyHint)
+	assert.ElementsMatch(t, tool.InputSchema.Required, []string{"owner", "repo", "content"})
+
+	client := github.NewClient(mock.NewMockedHTTPClient(
+		mock.WithRequestMatchHandler(
+			mock.PostReposGitBlobsByOwnerByRepo,
+			expectRequestBody(t, map[string]any{"content": "", "encoding": "utf-8"}).andThen(
+				mockResponse(t, http.StatusCreated, &github.Blob{SHA: github.Ptr("empty-sha"), URL: github.Ptr("https://api.github.com/repos/owner/repo/git/blobs/empty-sha")}),
+			),
+		),
+	))
+	_, handler := CreateBlob(stubGetClientFn(client), translations.NullTranslationHelper)
+
+	result, err := handler(context.Background(), createMCPRequest(map[string]any{"owner": "owner", "repo": "repo", "content": ""}))
+	require.NoError(t, err)
+	var blob MinimalResponse
+	require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &blob))
+	assert.Equal(t, "empty-sha", blob.ID)
+
+	result, err = handler(context.Background(), createMCPRequest(map[string]any{"owner": "owner", "repo": "repo", "content": "%%%", "encoding": "base64"}))
+	require.NoError(t, err)
+	assert.Contains(t, getErrorResult(t, result).Text, "content is not valid base64")
+}
+
+func Test_CreateTree(t *testing.T) {
+	tool, _ := CreateTree(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+	assert.False(t, *tool.Annotations.ReadOnlyHint)
+	assert.ElementsMatch(t, tool.InputSchema.Required, []string{"owner", "repo", "entries"})
+
+	client := github.NewClient(mock.NewMockedHTTPClient(
+		mock.WithRequestMatchHandler(
+			mock.PostReposGitTreesByOwnerByRepo,
+			expectRequestBody(t, map[string]any{
+				"base_tree": "base-sha",
+				"tree": []any{
+					map[string]any{"path": "a.txt", "mode": "100644", "type": "blob", "content": "a"},
+					map[string]any{"path": "lib", "mode": "040000", "type": "tree", "sha": "lib-sha"},
+					map[string]any{"path": "old.txt", "mode": "100644", "type": "blob", "sha": nil},
+				},
+			}).andThen(mockResponse(t, http.StatusCreated, &github.Tree{SHA: github.Ptr("new-tree")})),
+		),
+	))
+	_, handler := CreateTree(stubGetClientFn(client), translations.NullTranslationHelper)
+
+	result, err := handler(context.Background(), createMCPRequest(map[string]any{
+		"owner":     "owner",
+		"repo":      "repo",
+		"base_tree": "base-sha",
+		"entries": []any{
+			map[string]any{"path": "a.txt", "mode": "100644", "type": "blob", "content": "a"},
+			map[string]any{"path": "lib", "mode": "040000", "type": "tree", "sha": "lib-sha"},
+			map[string]any{"path": "old.txt", "mode": "100644", "type": "blob"},
+		},
+	}))
+	require.NoError(t, err)
+	var tree MinimalResponse
+	require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &tree))
+	assert.Equal(t, "new-tree", tree.ID)
+
+	t.Run("deletion without base tree", func(t *testing.T) {
+		result, err := handler(context.Background(), createMCPRequest(map[string]any{
+			"owner":   "owner",
+			"repo":    "repo",
+			"entries": []any{map[string]any{"path": "old.txt", "mode": "100644", "type": "blob"}},
+		}))
+		require.NoError(t, err)
+		assert.Contains(t, getErrorResult(t, result).Text, "base_tree, which is not set")
+	})
+}
+
+func Test_CreateCommit(t *testing.T) {
+	tool, _ := CreateCommit(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+	assert.False(t, *tool.Annotations.ReadOnlyHint)
+	assert.ElementsMatch(t, tool.InputSchema.Required, []string{"owner", "repo", "message", "tree"})
+
+	client := github.NewClient(mock.NewMockedHTTPClient(
+		mock.WithRequestMatchHandler(
+			mock.PostReposGitCommitsByOwnerByRepo,
+			expectRequestBody(t, map[string]any{
+				"message": "Merge",
+				"tree":    "tree-sha",
+				"parents": []any{"p1", "p2"},
+				"author":  map[string]any{"name": "Mona", "email": "mona@example.com"},
+			}).andThen(mockResponse(t, http.StatusCreated, &github.Commit{
+				SHA:     github.Ptr("new-sha"),
+				Message: github.Ptr("Merge"),
+				Tree:    &github.Tree{SHA: github.Ptr("tree-sha")},
+				Parents: []*github.Commit{{SHA: github.Ptr("p1")}, {SHA: github.Ptr("p2")}},
+			})),
+		),
+	))
+	_, handler := CreateCommit(stubGetClientFn(client), translations.NullTranslationHelper)
+
+	result, err := handler(context.Background(), createMCPRequest(map[string]any{
+		"owner":        "owner",
+		"repo":         "repo",
+		"message":      "Merge",
+		"tree":         "tree-sha",
+		"parents":      []any{"p1", "p2"},
+		"author_name":  "Mona",
+		"author_email": "mona@example.com",
+	}))
+	require.NoError(t, err)
+	var commit MinimalGitCommit
+	require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &commit))
+	assert.Equal(t, MinimalGitCommit{SHA: "new-sha", Message: "Merge", TreeSHA: "tree-sha", ParentSHAs: []string{"p1", "p2"}}, commit)
+
+	result, err = handler(context.Background(), createMCPRequest(map[string]any{"owner": "owner", "repo": "repo", "message": "m", "tree": "t", "author_name": "Mona"}))
+	require.NoError(t, err)
+	assert.Contains(t, getErrorResult(t, result).Text, "author_name and author_email must be passed together")
+}
+
+func Test_UpdateRef(t *testing.T) {
+	tool, _ := UpdateRef(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+	assert.False(t, *tool.Annotations.ReadOnlyHint)
+	assert.True(t, *tool.Annotations.DestructiveHint)
+
+	tests := []struct {
+		name        string
+		force       bool
+		handler     http.HandlerFunc
+		expectError string
+	}{
+		{
+			name:  "forced update",
+			force: true,
+			handler: expect(t, expectations{
+				path:        "/repos/owner/repo/git/refs/heads/main",
+				requestBody: map[string]any{"sha": "new-sha", "force": true},
+			}).andThen(mockResponse(t, http.StatusOK, `{"ref": "refs/heads/main", "object": {"sha": "new-sha", "type": "commit"}}`)),
+		},
+		{
+			name:        "not a fast forward",
+			handler:     mockResponse(t, http.StatusUnprocessableEntity, `{"message": "Update is not a fast forward"}`),
+			expectError: "pass force to overwrite it",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			client := github.NewClient(mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(mock.PatchReposGitRefsByOwnerByRepoByRef, tc.handler),
+			))
+			_, handler := UpdateRef(stubGetClientFn(client), translations.NullTranslationHelper)
+			result, err := handler(context.Background(), createMCPRequest(map[string]any{"owner": "owner", "repo": "repo", "ref": "heads/main", "sha": "new-sha", "force": tc.force}))
+			require.NoError(t, err)
+
+			if tc.expectError != "" {
+				assert.Contains(t, getErrorResult(t, result).Text, tc.expectError)
+				return
+			}
+			var ref MinimalGitRef
+			require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &ref))
+			assert.Equal(t, MinimalGitRef{Ref: "refs/heads/main", SHA: "new-sha", Type: "commit"}, ref)
+		})
+	}
+}
+
+func Test_DeleteRef(t *testing.T) {
+	tool, _ := DeleteRef(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+	assert.False(t, *tool.Annotations.ReadOnlyHint)
+	assert.True(t, *tool.Annotations.DestructiveHint)
+
+	client := github.NewClient(mock.NewMockedHTTPClient(
+		mock.WithRequestMatchHandler(
+			mock.DeleteReposGitRefsByOwnerByRepoByRef,
+			expectPath(t, "/repos/owner/repo/git/refs/heads/feature").andThen(mockResponse(t, http.StatusNoContent, nil)),
+		),
+	))
+	_, handler := DeleteRef(stubGetClientFn(client), translations.NullTranslationHelper)
+	result, err := handler(context.Background(), createMCPRequest(map[string]any{"owner": "owner", "repo": "repo", "ref": "refs/heads/feature"}))
+	require.NoError(t, err)
+	assert.Equal(t, "Deleted reference refs/heads/feature", getTextResult(t, result).Text)
+}
@@ -0,0 +1,472 @@
+package github
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	ghErrors "github.com/github/github-mcp-server/pkg/errors"
+	"github.com/github/github-mcp-server/pkg/raw"
+	"github.com/github/github-mcp-server/pkg/sniff"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/google/go-github/v77/github"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// maxGrepLineLength is the number of bytes of a matching or context line included in grep results.
+const maxGrepLineLength = 500
+
+// compileGlob compiles a path glob to a regular expression, following .gitignore conventions: patterns
+// without a slash other than a trailing one match at any depth unless they start with a slash, `*` and `?`
+// don't match slashes, `**` matches any number of directories, and a trailing slash matches everything
+// under a directory.
+func compileGlob(pattern string) (*regexp.Regexp, error) {
+	anchored := strings.HasPrefix(pattern, "/")
+	pattern = strings.TrimPrefix(pattern, "/")
+	if pattern == "" {
+		return nil, errors.New("empty glob pattern")
+	}
+	if strings.HasSuffix(pattern, "/") {
+		pattern += "**"
+	}
+	if !anchored && !strings.Contains(strings.TrimSuffix(pattern, "/**"), "/") {
+		pattern = "**/" + pattern
+	}
+
+	var b strings.Builder
+	b.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; c {
+		case '*':
+			switch {
+			case strings.HasPrefix(pattern[i:], "**/"):
+				b.WriteString("(?:.*/)?")
+				i += 2
+			case strings.HasPrefix(pattern[i:], "**"):
+				b.WriteString(".*")
+				i++
+			default:
+				b.WriteString("[^/]*")
+			}
+		case '?':
+			b.WriteString("[^/]")
+		case '[':
+			end := strings.IndexByte(pattern[i+1:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("invalid glob pattern %q: unterminated character class", pattern)
+			}
+			class := pattern[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			b.WriteString("[" + class + "]")
+			i += end + 1
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteString("$")
+
+	re, err := regexp.Compile(b.String())
+	if err != nil {
+		return nil, fmt.Errorf("invalid glob pattern %q: %w", pattern, err)
+	}
+	return re, nil
+}
+
+// pathFilter selects paths by include and exclude globs.
+type pathFilter struct {
+	include []*regexp.Regexp
+	exclude []*regexp.Regexp
+}
+
+func newPathFilter(include, exclude []string) (*pathFilter, error) {
+	f := &pathFilter{}
+	for _, pattern := range include {
+		re, err := compileGlob(pattern)
+		if err != nil {
+			return nil, err
+		}
+		f.include = append(f.include, re)
+	}
+	for _, pattern := range exclude {
+		re, err := compileGlob(pattern)
+		if err != nil {
+			return nil, err
+		}
+		f.exclude = append(f.exclude, re)
+	}
+	return f, nil
+}
+
+// match reports whether path matches any include glob, or there are none, and no exclude glob.
+func (f *pathFilter) match(path string) bool {
+	for _, re := range f.exclude {
+		if re.MatchString(path) {
+			return false
+		}
+	}
+	if len(f.include) == 0 {
+		return true
+	}
+	for _, re := range f.include {
+		if re.MatchString(path) {
+			return true
+		}
+	}
+	return false
+}
+
+// GrepMatch is a line of a file that matches a grep pattern.
+type GrepMatch struct {
+	Path string `json:"path"`
+	Line int    `json:"line"`
+	Text string `json:"text"`
+	// Before and After hold the context lines around the match
+	Before []string `json:"before,omitempty"`
+	After  []string `json:"after,omitempty"`
+}
+
+// grepLines returns the lines of r that match re, with up to contextLines lines of context before and
+// after each. It stops after maxMatches matches, reporting whether there are more.
+func grepLines(r io.Reader, path string, re *regexp.Regexp, contextLines, maxMatches int) ([]GrepMatch, bool, error) {
+	var matches []GrepMatch
+	var before []string
+	// pending holds the indexes of matches that are still collecting their after context
+	var pending []int
+	more := false
+
+	br := bufio.NewReader(r)
+	for lineNumber := 1; ; lineNumber++ {
+		line, err := br.ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, false, err
+		}
+		if line == "" && err != nil {
+			return matches, more, nil
+		}
+		line = strings.TrimRight(line, "\r\n")
+		matched := re.MatchString(line)
+		line = truncateLine(line)
+
+		still := pending[:0]
+		for _, i := range pending {
+			matches[i].After = append(matches[i].After, line)
+			if len(matches[i].After) < contextLines {
+				still = append(still, i)
+			}
+		}
+		pending = still
+
+		if matched {
+			if len(matches) < maxMatches {
+				matches = append(matches, GrepMatch{Path: path, Line: lineNumber, Text: line, Before: append([]string(nil), before...)})
+				if contextLines > 0 {
+					pending = append(pending, len(matches)-1)
+				}
+			} else {
+				more = true
+			}
+		}
+		if more && len(pending) == 0 {
+			return matches, true, nil
+		}
+
+		if contextLines > 0 {
+			before = append(before, line)
+			if len(before) > contextLines {
+				before = before[1:]
+			}
+		}
+		if err != nil {
+			return matches, more, nil
+		}
+	}
+}
+
+// truncateLine cuts a line to maxGrepLineLength bytes without splitting a multi-byte character.
+func truncateLine(line string) string {
+	if len(line) <= maxGrepLineLength {
+		return line
+	}
+	n := maxGrepLineLength
+	for n > 0 && !utf8.RuneStart(line[n]) {
+		n--
+	}
+	return line[:n] + "…"
+}
+
+const (
+	// grepRepositoryConcurrency bounds the number of files grep_repository fetches at once
+	grepRepositoryConcurrency = 8
+	// maxGrepRepositoryFiles bounds the number of files a grep_repository call fetches, so that
+	// searches of large repositories return a cursor instead of running for minutes
+	maxGrepRepositoryFiles = 500
+)
+
+// RepositoryGrepResult holds the lines of a repository that match a grep pattern, and where to continue the search.
+type RepositoryGrepResult struct {
+	GrepResult
+	// Cursor is passed as `cursor` to continue the search where it stopped
+	Cursor string `json:"cursor,omitempty"`
+	// TreeTruncated is set when the repository has too many files for its tree to be listed in full,
+	// so some files were not searched
+	TreeTruncated bool `json:"tree_truncated,omitempty"`
+}
+
+// grepCursor is the position a grep_repository search continues from. It pins the commit, so that
+// all pages of a search see the same files.
+type grepCursor struct {
+	SHA string `json:"sha"`
+	// Path is the next file to search
+	Path string `json:"path"`
+	// Skip is the number of matches in Path that were already returned
+	Skip int `json:"skip,omitempty"`
+}
+
+func (c grepCursor) encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeGrepCursor(s string) (grepCursor, error) {
+	var c grepCursor
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err == nil {
+		err = json.Unmarshal(data, &c)
+	}
+	if err != nil || c.SHA == "" || c.Path == "" || c.Skip < 0 {
+		return grepCursor{}, errors.New("invalid cursor, pass the cursor of a previous grep_repository result")
+	}
+	return c, nil
+}
+
+// grepFileResult is the outcome of searching one file of a repository.
+type grepFileResult struct {
+	matches []GrepMatch
+	more    bool
+	skipped bool
+	err     error
+}
+
+// grepRawFile fetches a file at a commit through the raw client and searches it, returning up to maxMatches
+// matches. Binary files are skipped.
+func grepRawFile(ctx context.Context, rawClient *raw.Client, owner, repo, sha, path string, opts *grepOptions, maxMatches int) grepFileResult {
+	resp, err := rawClient.GetRawContent(ctx, owner, repo, path, &raw.ContentOpts{SHA: sha})
+	if err != nil {
+		return grepFileResult{err: fmt.Errorf("failed to get raw content of %s: %w", path, err)}
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return grepFileResult{err: fmt.Errorf("failed to get raw content of %s: status %d", path, resp.StatusCode)}
+	}
+
+	sniffed := bufio.NewReaderSize(resp.Body, sniff.SampleSize)
+	sample, err := sniffed.Peek(sniff.SampleSize)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return grepFileResult{err: fmt.Errorf("failed to read %s: %w", path, err)}
+	}
+	if !sniff.Detect(sample).IsText() {
+		return grepFileResult{skipped: true}
+	}
+
+	matches, more, err := grepLines(io.LimitReader(sniffed, maxGrepFileSize), path, opts.re, opts.contextLines, maxMatches)
+	if err != nil {
+		return grepFileResult{err: fmt.Errorf("failed to read %s: %w", path, err)}
+	}
+	return grepFileResult{matches: matches, more: more}
+}
+
+// GrepRepository creates a tool to search the files of a repository at a ref with a regular expression.
+func GrepRepository(getClient GetClientFn, getRawClient raw.GetRawClientFn, t translations.TranslationHelperFunc) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("grep_repository",
+			mcp.WithDescription(t("TOOL_GREP_REPOSITORY_DESCRIPTION", fmt.Sprintf("Search the text files of a repository at any ref for lines matching a regular expression, returning the matches with line numbers and context. Unlike search_code, it works on any branch, tag or commit, supports regular expressions and doesn't depend on the code search index. Files are fetched one by one, so narrow the search with include and exclude globs. A call searches at most %d files; when the result has a cursor, pass it to continue the search.", maxGrepRepositoryFiles))),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:        t("TOOL_GREP_REPOSITORY_USER_TITLE", "Search repository files"),
+				ReadOnlyHint: ToBoolPtr(true),
+			}),
+			mcp.WithString("owner",
+				mcp.Required(),
+				mcp.Description("Repository owner (username or organization)"),
+			),
+			mcp.WithString("repo",
+				mcp.Required(),
+				mcp.Description("Repository name"),
+			),
+			mcp.WithString("ref",
+				mcp.Description("Git ref such as `main`, `refs/tags/{tag}` or `refs/pull/{pr_number}/head`. Defaults to the default branch"),
+			),
+			mcp.WithString("sha",
+				mcp.Description("Commit SHA. If specified, it will be used instead of ref"),
+			),
+			withGrepParams(),
+			mcp.WithString("cursor",
+				mcp.Description("Cursor from a previous result, to continue that search. The other parameters must be the same as in the previous call"),
+			),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			owner, err := RequiredParam[string](request, "owner")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			repo, err := RequiredParam[string](request, "repo")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			ref, err := OptionalParam[string](request, "ref")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			sha, err := OptionalParam[string](request, "sha")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			cursorParam, err := OptionalParam[string](request, "cursor")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			opts, err := parseGrepParams(request)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			client, err := getClient(ctx)
+			if err != nil {
+				return mcp.NewToolResultError("failed to get GitHub client"), nil
+			}
+			rawClient, err := getRawClient(ctx)
+			if err != nil {
+				return mcp.NewToolResultError("failed to get GitHub raw content client"), nil
+			}
+
+			var cursor grepCursor
+			if cursorParam != "" {
+				cursor, err = decodeGrepCursor(cursorParam)
+				if err != nil {
+					return mcp.NewToolResultError(err.Error()), nil
+				}
+				ref, sha = "", cursor.SHA
+			}
+			cursor.SHA, err = resolveCommitSHA(ctx, client, owner, repo, ref, sha)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			tree, resp, err := client.Git.GetTree(ctx, owner, repo, cursor.SHA, true)
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx,
+					"failed to get repository tree",
+					resp,
+					err,
+				), nil
+			}
+			defer func() { _ = resp.Body.Close() }()
+
+			result := RepositoryGrepResult{
+				GrepResult:    GrepResult{SHA: cursor.SHA, Matches: []GrepMatch{}},
+				TreeTruncated: tree.GetTruncated(),
+			}
+
+			// Files are searched in path order, which is what cursors point into
+			var files []*github.TreeEntry
+			for _, entry := range tree.Entries {
+				// Symbolic links and submodules have no content to search
+				if entry.GetType() != "blob" || entry.GetMode() == "120000" || !opts.filter.match(entry.GetPath()) {
+					continue
+				}
+				files = append(files, entry)
+			}
+			sort.Slice(files, func(i, j int) bool { return files[i].GetPath() < files[j].GetPath() })
+			start := sort.Search(len(files), func(i int) bool { return files[i].GetPath() >= cursor.Path })
+			if start < len(files) && files[start].GetPath() != cursor.Path {
+				cursor.Skip = 0
+			}
+			files = files[start:]
+
+			// next returns the cursor of the search after i matches of the file at index n
+			next := func(n, i int) string {
+				if i == 0 && n == len(files) {
+					return ""
+				}
+				path := files[n].GetPath()
+				return grepCursor{SHA: cursor.SHA, Path: path, Skip: i}.encode()
+			}
+
+			fetched := 0
+			for n := 0; n < len(files); {
+				if fetched == maxGrepRepositoryFiles {
+					result.Cursor = next(n, 0)
+					break
+				}
+
+				// Fetch a batch of files concurrently, then merge their matches in order
+				batch := files[n:min(n+grepRepositoryConcurrency, len(files), n+maxGrepRepositoryFiles-fetched)]
+				room := opts.maxResults - len(result.Matches)
+				results := make([]grepFileResult, len(batch))
+				var wg sync.WaitGroup
+				for i, entry := range batch {
+					if entry.GetSize() > maxGrepFileSize {
+						results[i] = grepFileResult{skipped: true}
+						continue
+					}
+					skip := 0
+					if n+i == 0 {
+						skip = cursor.Skip
+					}
+					wg.Add(1)
+					go func() {
+						defer wg.Done()
+						results[i] = grepRawFile(ctx, rawClient, owner, repo, cursor.SHA, entry.GetPath(), opts, skip+room)
+					}()
+				}
+				wg.Wait()
+				fetched += len(batch)
+
+				for i, r := range results {
+					if r.err != nil {
+						return mcp.NewToolResultError(r.err.Error()), nil
+					}
+					if r.skipped {
+						result.FilesSkipped++
+						continue
+					}
+					result.FilesSearched++
+
+					skip := 0
+					if n+i == 0 {
+						skip = min(cursor.Skip, len(r.matches))
+					}
+					matches := r.matches[skip:]
+					room := opts.maxResults - len(result.Matches)
+					if len(matches) > room || (len(matches) == room && r.more) {
+						result.Matches = append(result.Matches, matches[:room]...)
+						result.Cursor = next(n+i, skip+room)
+						break
+					}
+					result.Matches = append(result.Matches, matches...)
+					if len(result.Matches) == opts.maxResults {
+						result.Cursor = next(n+i+1, 0)
+						break
+					}
+				}
+				if result.Cursor != "" {
+					break
+				}
+				n += len(batch)
+			}
+			result.Truncated = result.Cursor != ""
+
+			return MarshalledTextResult(result), nil
+		}
+}
@@ -0,0 +1,260 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/github/github-mcp-server/internal/toolsnaps"
+	"github.com/github/github-mcp-server/pkg/raw"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/google/go-github/v77/github"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/migueleliasweb/go-github-mock/src/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_CompileGlob(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		matches  []string
+		excludes []string
+	}{
+		{
+			pattern:  "*.go",
+			matches:  []string{"main.go", "pkg/github/tools.go"},
+			excludes: []string{"main.go.orig", "README.md"},
+		},
+		{
+			pattern:  "pkg/*.go",
+			matches:  []string{"pkg/main.go"},
+			excludes: []string{"pkg/github/tools.go", "main.go"},
+		},
+		{
+			pattern:  "docs/**/*.md",
+			matches:  []string{"docs/README.md", "docs/a/b/c.md"},
+			excludes: []string{"README.md", "other/docs/a.md"},
+		},
+		{
+			pattern:  "vendor/",
+			matches:  []string{"vendor/a.go", "third_party/vendor/b/c.go"},
+			excludes: []string{"vendored.go"},
+		},
+		{
+			pattern:  "/cmd/**",
+			matches:  []string{"cmd/main.go", "cmd/a/b.go"},
+			excludes: []string{"pkg/cmd/main.go"},
+		},
+		{
+			pattern:  "file[0-9].tx?",
+			matches:  []string{"file1.txt", "a/file2.txs"},
+			excludes: []string{"fileA.txt", "file1.t/t"},
+		},
+		{
+			pattern:  "[!_]*.go",
+			matches:  []string{"main.go"},
+			excludes: []string{"_test.go"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.pattern, func(t *testing.T) {
+			re, err := compileGlob(tc.pattern)
+			require.NoError(t, err)
+			for _, path := range tc.matches {
+				assert.True(t, re.MatchString(path), "%s should match %s", tc.pattern, path)
+			}
+			for _, path := range tc.excludes {
+				assert.False(t, re.MatchString(path), "%s should not match %s", tc.pattern, path)
+			}
+		})
+	}
+
+	_, err := compileGlob("file[0-9")
+	assert.Error(t, err)
+	_, err = compileGlob("")
+	assert.Error(t, err)
+}
+
+func Test_PathFilter(t *testing.T) {
+	filter, err := newPathFilter([]string{"*.go"}, []string{"*_test.go", "vendor/"})
+	require.NoError(t, err)
+	assert.True(t, filter.match("pkg/main.go"))
+	assert.False(t, filter.match("pkg/main_test.go"))
+	assert.False(t, filter.match("vendor/lib.go"))
+	assert.False(t, filter.match("README.md"))
+
+	all, err := newPathFilter(nil, nil)
+	require.NoError(t, err)
+	assert.True(t, all.match("anything"))
+}
+
+func Test_GrepLines(t *testing.T) {
+	input := "one\nfoo two\nthree\nfour\nfoo five\nsix\r\nfoo seven"
+	re := regexp.MustCompile("foo")
+
+	t.Run("matches with context", func(t *testing.T) {
+		matches, more, err := grepLines(strings.NewReader(input), "a.txt", re, 1, 10)
+		require.NoError(t, err)
+		assert.False(t, more)
+		assert.Equal(t, []GrepMatch{
+			{Path: "a.txt", Line: 2, Text: "foo two", Before: []string{"one"}, After: []string{"three"}},
+			{Path: "a.txt", Line: 5, Text: "foo five", Before: []string{"four"}, After: []string{"six"}},
+			{Path: "a.txt", Line: 7, Text: "foo seven", Before: []string{"six"}},
+		}, matches)
+	})
+
+	t.Run("without context", func(t *testing.T) {
+		matches, _, err := grepLines(strings.NewReader(input), "a.txt", re, 0, 10)
+		require.NoError(t, err)
+		require.Len(t, matches, 3)
+		assert.Nil(t, matches[0].Before)
+		assert.Nil(t, matches[0].After)
+	})
+
+	t.Run("capped", func(t *testing.T) {
+		matches, more, err := grepLines(strings.NewReader(input), "a.txt", re, 2, 1)
+		require.NoError(t, err)
+		assert.True(t, more)
+		assert.Equal(t, []GrepMatch{
+			{Path: "a.txt", Line: 2, Text: "foo two", Before: []string{"one"}, After: []string{"three", "four"}},
+		}, matches)
+	})
+
+	t.Run("long lines are truncated after matching", func(t *testing.T) {
+		long := strings.Repeat("é", maxGrepLineLength) + "foo"
+		matches, _, err := grepLines(strings.NewReader(long), "a.txt", re, 0, 10)
+		require.NoError(t, err)
+		require.Len(t, matches, 1)
+		assert.Equal(t, strings.Repeat("é", maxGrepLineLength/2)+"…", matches[0].Text)
+	})
+}
+
+func Test_GrepRepository(t *testing.T) {
+	tool, _ := GrepRepository(stubGetClientFn(github.NewClient(nil)), stubGetRawClientFn(nil), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+	assert.True(t, *tool.Annotations.ReadOnlyHint)
+	assert.ElementsMatch(t, tool.InputSchema.Required, []string{"owner", "repo", "pattern"})
+
+	files := map[string]string{"bin/tool": "TODO\x00binary"}
+	for k, v := range testArchiveFiles {
+		files[k] = v
+	}
+	tree := []map[string]any{
+		{"path": "vendor", "type": "tree", "mode": "040000"},
+		{"path": "vendor/lib/lib.go", "type": "blob", "mode": "100644", "size": len(files["vendor/lib/lib.go"])},
+		{"path": "cmd/main_test.go", "type": "blob", "mode": "100644", "size": len(files["cmd/main_test.go"])},
+		{"path": "cmd/main.go", "type": "blob", "mode": "100644", "size": len(files["cmd/main.go"])},
+		{"path": "README.md", "type": "blob", "mode": "100644", "size": len(files["README.md"])},
+		{"path": "bin/tool", "type": "blob", "mode": "100755", "size": len(files["bin/tool"])},
+		{"path": "docs", "type": "blob", "mode": "120000", "size": 9},
+		{"path": "huge.txt", "type": "blob", "mode": "100644", "size": maxGrepFileSize + 1},
+	}
+
+	mockedClient := mock.NewMockedHTTPClient(
+		mock.WithRequestMatchHandler(
+			mock.GetReposGitRefByOwnerByRepoByRef,
+			mockResponse(t, http.StatusOK, `{"ref": "refs/heads/main", "object": {"sha": "`+testArchiveSHA+`"}}`),
+		),
+		mock.WithRequestMatchHandler(
+			mock.GetReposCommitsByOwnerByRepoByRef,
+			testCommitSHAHandler(t),
+		),
+		mock.WithRequestMatchHandler(
+			mock.GetReposGitTreesByOwnerByRepoByTreeSha,
+			http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/repos/octocat/hello/git/trees/"+testArchiveSHA, r.URL.Path)
+				assert.Equal(t, "1", r.URL.Query().Get("recursive"))
+				data, _ := json.Marshal(map[string]any{"sha": "tree123", "truncated": false, "tree": tree})
+				_, _ = w.Write(data)
+			}),
+		),
+		mock.WithRequestMatchHandler(
+			raw.GetRawReposContentsByOwnerByRepoBySHAByPath,
+			http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				path := strings.TrimPrefix(r.URL.Path, "/octocat/hello/"+testArchiveSHA+"/")
+				content, ok := files[path]
+				if !ok {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				_, _ = w.Write([]byte(content))
+			}),
+		),
+	)
+	client := github.NewClient(mockedClient)
+	rawClient := raw.NewClient(client, &url.URL{Scheme: "https", Host: "raw.example.com", Path: "/"})
+	_, handler := GrepRepository(stubGetClientFn(client), stubGetRawClientFn(rawClient), translations.NullTranslationHelper)
+	call := func(args map[string]any) *mcp.CallToolResult {
+		args["owner"], args["repo"], args["ref"] = "octocat", "hello", "refs/heads/main"
+		result, err := handler(context.Background(), createMCPRequest(args))
+		require.NoError(t, err)
+		return result
+	}
+	grep := func(args map[string]any) RepositoryGrepResult {
+		var result RepositoryGrepResult
+		require.NoError(t, json.Unmarshal([]byte(getTextResult(t, call(args)).Text), &result))
+		return result
+	}
+
+	t.Run("matches with context", func(t *testing.T) {
+		result := grep(map[string]any{"pattern": `todo: \w+`, "ignore_case": true, "include": []any{"*.go"}, "exclude": []any{"vendor/"}, "context_lines": float64(1)})
+		assert.Equal(t, RepositoryGrepResult{
+			GrepResult: GrepResult{
+				SHA: testArchiveSHA,
+				Matches: []GrepMatch{
+					{Path: "cmd/main.go", Line: 4, Text: "\t// TODO: implement", Before: []string{"func main() {"}, After: []string{"}"}},
+					{Path: "cmd/main_test.go", Line: 3, Text: "// TODO: test", Before: []string{""}},
+				},
+				FilesSearched: 2,
+			},
+		}, result)
+	})
+
+	t.Run("binary, large files and symbolic links are skipped", func(t *testing.T) {
+		// huge.txt isn't served, so fetching it would fail the search
+		result := grep(map[string]any{"pattern": "TODO"})
+		assert.Len(t, result.Matches, 4)
+		assert.Equal(t, 4, result.FilesSearched)
+		assert.Equal(t, 2, result.FilesSkipped)
+		assert.Empty(t, result.Cursor)
+		assert.False(t, result.Truncated)
+	})
+
+	t.Run("pages with a cursor", func(t *testing.T) {
+		all := grep(map[string]any{"pattern": "TODO|package"}).Matches
+		require.Len(t, all, 6)
+
+		var paged []GrepMatch
+		args := map[string]any{"pattern": "TODO|package", "max_results": float64(2)}
+		for pages := 1; ; pages++ {
+			require.LessOrEqual(t, pages, 3)
+			result := grep(args)
+			paged = append(paged, result.Matches...)
+			if result.Cursor == "" {
+				assert.False(t, result.Truncated)
+				break
+			}
+			assert.True(t, result.Truncated)
+			args = map[string]any{"pattern": "TODO|package", "max_results": float64(2), "cursor": result.Cursor}
+		}
+		assert.Equal(t, all, paged)
+	})
+
+	t.Run("invalid cursor", func(t *testing.T) {
+		result := call(map[string]any{"pattern": "TODO", "cursor": "nope"})
+		assert.Contains(t, getErrorResult(t, result).Text, "invalid cursor")
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		tree = append(tree, map[string]any{"path": "gone.txt", "type": "blob", "mode": "100644", "size": 1})
+		result := call(map[string]any{"pattern": "TODO", "include": []any{"gone.txt"}})
+		assert.Equal(t, fmt.Sprintf("failed to get raw content of gone.txt: status %d", http.StatusNotFound), getErrorResult(t, result).Text)
+	})
+}
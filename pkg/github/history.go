@@ -0,0 +1,412 @@
+package github
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	ghErrors "github.com/github/github-mcp-server/pkg/errors"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/google/go-github/v77/github"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/shurcooL/githubv4"
+)
+
+// shortSHALength is the length of the abbreviated commit SHAs in blame and history results.
+const shortSHALength = 7
+
+// BlameHunk is a range of lines last changed by the same commit.
+type BlameHunk struct {
+	StartLine int    `json:"start_line"`
+	EndLine   int    `json:"end_line"`
+	SHA       string `json:"sha"`
+	Author    string `json:"author"`
+	Date      string `json:"date"`
+	Message   string `json:"message"`
+}
+
+// FileBlame is the blame of a file at a commit.
+type FileBlame struct {
+	Path  string      `json:"path"`
+	SHA   string      `json:"sha"`
+	Hunks []BlameHunk `json:"hunks"`
+}
+
+// blameActor is the author of a commit, as returned by the GraphQL API.
+type blameActor struct {
+	Name githubv4.String
+	Date githubv4.GitTimestamp
+	User *struct {
+		Login githubv4.String
+	}
+}
+
+// displayName returns the login of the author if they have a GitHub account, and their Git name otherwise.
+func (a blameActor) displayName() string {
+	if a.User != nil && a.User.Login != "" {
+		return string(a.User.Login)
+	}
+	return string(a.Name)
+}
+
+// GetFileBlame creates a tool to get the blame of a file.
+func GetFileBlame(getGQLClient GetGQLClientFn, t translations.TranslationHelperFunc) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("get_file_blame",
+			mcp.WithDescription(t("TOOL_GET_FILE_BLAME_DESCRIPTION", "Get the blame of a file at a ref: for each range of lines, the commit that last changed it with its short SHA, author, date and message. Use start_line and end_line to blame only some lines")),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:        t("TOOL_GET_FILE_BLAME_USER_TITLE", "Get file blame"),
+				ReadOnlyHint: ToBoolPtr(true),
+			}),
+			mcp.WithString("owner",
+				mcp.Required(),
+				mcp.Description("Repository owner (username or organization)"),
+			),
+			mcp.WithString("repo",
+				mcp.Required(),
+				mcp.Description("Repository name"),
+			),
+			mcp.WithString("path",
+				mcp.Required(),
+				mcp.Description("Path of the file"),
+			),
+			mcp.WithString("ref",
+				mcp.Description("Branch, tag or commit SHA to blame the file at. Defaults to the default branch"),
+			),
+			mcp.WithNumber("start_line",
+				mcp.Description("First line to blame, starting at 1"),
+				mcp.Min(1),
+			),
+			mcp.WithNumber("end_line",
+				mcp.Description("Last line to blame, inclusive"),
+				mcp.Min(1),
+			),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			owner, err := RequiredParam[string](request, "owner")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			repo, err := RequiredParam[string](request, "repo")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			path, err := RequiredParam[string](request, "path")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			ref, err := OptionalParam[string](request, "ref")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			startLine, err := OptionalIntParam(request, "start_line")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			endLine, err := OptionalIntParam(request, "end_line")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			if startLine < 0 || endLine < 0 {
+				return mcp.NewToolResultError("start_line and end_line must be positive"), nil
+			}
+			if endLine > 0 && endLine < startLine {
+				return mcp.NewToolResultError("end_line must not be before start_line"), nil
+			}
+			if ref == "" {
+				ref = "HEAD"
+			}
+
+			client, err := getGQLClient(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get GitHub GQL client: %w", err)
+			}
+
+			var q struct {
+				Repository struct {
+					Object struct {
+						Commit struct {
+							OID   githubv4.GitObjectID `graphql:"oid"`
+							Blame struct {
+								Ranges []struct {
+									StartingLine githubv4.Int
+									EndingLine   githubv4.Int
+									Commit       struct {
+										AbbreviatedOID  githubv4.String `graphql:"abbreviatedOid"`
+										MessageHeadline githubv4.String
+										Author          blameActor
+									}
+								}
+							} `graphql:"blame(path: $path)"`
+						} `graphql:"... on Commit"`
+					} `graphql:"object(expression: $ref)"`
+				} `graphql:"repository(owner: $owner, name: $repo)"`
+			}
+			vars := map[string]interface{}{
+				"owner": githubv4.String(owner),
+				"repo":  githubv4.String(repo),
+				"ref":   githubv4.String(ref),
+				"path":  githubv4.String(path),
+			}
+			if err := client.Query(ctx, &q, vars); err != nil {
+				return ghErrors.NewGitHubGraphQLErrorResponse(ctx, "failed to get file blame", err), nil
+			}
+			commit := q.Repository.Object.Commit
+			if commit.OID == "" {
+				return mcp.NewToolResultError(fmt.Sprintf("could not resolve %q to a commit", ref)), nil
+			}
+
+			result := FileBlame{Path: path, SHA: string(commit.OID), Hunks: []BlameHunk{}}
+			for _, r := range commit.Blame.Ranges {
+				hunk := BlameHunk{
+					StartLine: int(r.StartingLine),
+					EndLine:   int(r.EndingLine),
+					SHA:       string(r.Commit.AbbreviatedOID),
+					Author:    r.Commit.Author.displayName(),
+					Date:      r.Commit.Author.Date.Format("2006-01-02T15:04:05Z07:00"),
+					Message:   string(r.Commit.MessageHeadline),
+				}
+				if (endLine > 0 && hunk.StartLine > endLine) || hunk.EndLine < startLine {
+					continue
+				}
+				hunk.StartLine = max(hunk.StartLine, startLine)
+				if endLine > 0 {
+					hunk.EndLine = min(hunk.EndLine, endLine)
+				}
+				result.Hunks = append(result.Hunks, hunk)
+			}
+
+			return MarshalledTextResult(result), nil
+		}
+}
+
+// FileHistoryCommit is a commit that changed a file.
+type FileHistoryCommit struct {
+	SHA     string `json:"sha"`
+	Author  string `json:"author"`
+	Date    string `json:"date"`
+	Message string `json:"message"`
+	// Path is the path of the file in the commit, which differs from the requested path before a rename
+	Path string `json:"path"`
+}
+
+// FileRename is a commit that renamed a file.
+type FileRename struct {
+	SHA  string `json:"sha"`
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// FileHistory is a page of the commits that changed a file.
+type FileHistory struct {
+	Commits []FileHistoryCommit `json:"commits"`
+	// Renames lists the renames that were followed in this page
+	Renames []FileRename `json:"renames,omitempty"`
+	// Cursor is passed as `cursor` to get the next page
+	Cursor string `json:"cursor,omitempty"`
+}
+
+// historyCursor is the position a list_file_history listing continues from.
+type historyCursor struct {
+	Path string `json:"path"`
+	SHA  string `json:"sha,omitempty"`
+	Page int    `json:"page"`
+}
+
+func (c historyCursor) encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeHistoryCursor(s string) (historyCursor, error) {
+	var c historyCursor
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err == nil {
+		err = json.Unmarshal(data, &c)
+	}
+	if err != nil || c.Path == "" || c.Page < 1 {
+		return historyCursor{}, errors.New("invalid cursor, pass the cursor of a previous list_file_history result")
+	}
+	return c, nil
+}
+
+// ListFileHistory creates a tool to list the commits that changed a file.
+func ListFileHistory(getClient GetClientFn, t translations.TranslationHelperFunc) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("list_file_history",
+			mcp.WithDescription(t("TOOL_LIST_FILE_HISTORY_DESCRIPTION", "List the commits that changed a file, newest first, with their short SHA, author, date and message. Renames are followed on a best effort basis: when the history of the path ends with a rename, the listing continues with the previous path. When the result has a cursor, pass it to get the next page")),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:        t("TOOL_LIST_FILE_HISTORY_USER_TITLE", "List file history"),
+				ReadOnlyHint: ToBoolPtr(true),
+			}),
+			mcp.WithString("owner",
+				mcp.Required(),
+				mcp.Description("Repository owner (username or organization)"),
+			),
+			mcp.WithString("repo",
+				mcp.Required(),
+				mcp.Description("Repository name"),
+			),
+			mcp.WithString("path",
+				mcp.Required(),
+				mcp.Description("Path of the file"),
+			),
+			mcp.WithString("ref",
+				mcp.Description("Branch, tag or commit SHA to list the history from. Defaults to the default branch"),
+			),
+			mcp.WithBoolean("follow_renames",
+				mcp.Description("Continue with the previous path of the file when its history ends with a rename. Default is true"),
+				mcp.DefaultBool(true),
+			),
+			mcp.WithNumber("perPage",
+				mcp.Description("Number of commits per page (min 1, max 100)"),
+				mcp.Min(1),
+				mcp.Max(100),
+			),
+			mcp.WithString("cursor",
+				mcp.Description("Cursor from a previous result, to get the next page"),
+			),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			owner, err := RequiredParam[string](request, "owner")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			repo, err := RequiredParam[string](request, "repo")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			path, err := RequiredParam[string](request, "path")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			ref, err := OptionalParam[string](request, "ref")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			followRenames, err := OptionalBoolParamWithDefault(request, "follow_renames", true)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			perPage, err := OptionalIntParamWithDefault(request, "perPage", 30)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			if perPage < 1 || perPage > 100 {
+				return mcp.NewToolResultError("perPage must be between 1 and 100"), nil
+			}
+			cursorParam, err := OptionalParam[string](request, "cursor")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			cursor := historyCursor{Path: strings.Trim(path, "/"), SHA: ref, Page: 1}
+			if cursorParam != "" {
+				cursor, err = decodeHistoryCursor(cursorParam)
+				if err != nil {
+					return mcp.NewToolResultError(err.Error()), nil
+				}
+			}
+
+			client, err := getClient(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get GitHub client: %w", err)
+			}
+
+			result := FileHistory{Commits: []FileHistoryCommit{}}
+			// A page is filled from the histories of successive paths of the file, following renames
+			for len(result.Commits) < perPage {
+				commits, resp, err := client.Repositories.ListCommits(ctx, owner, repo, &github.CommitsListOptions{
+					SHA:  cursor.SHA,
+					Path: cursor.Path,
+					ListOptions: github.ListOptions{
+						Page:    cursor.Page,
+						PerPage: perPage,
+					},
+				})
+				if err != nil {
+					return ghErrors.NewGitHubAPIErrorResponse(ctx,
+						fmt.Sprintf("failed to list commits of %s", cursor.Path),
+						resp,
+						err,
+					), nil
+				}
+				_ = resp.Body.Close()
+
+				room := perPage - len(result.Commits)
+				for _, commit := range commits[:min(len(commits), room)] {
+					result.Commits = append(result.Commits, fileHistoryCommit(commit, cursor.Path))
+				}
+				if len(commits) > room {
+					// The rest of the commits didn't fit after those of a renamed path, so the next page starts at them
+					result.Cursor = historyCursor{Path: cursor.Path, SHA: commits[room].GetSHA(), Page: 1}.encode()
+					break
+				}
+				if len(commits) == perPage {
+					cursor.Page++
+					result.Cursor = cursor.encode()
+					break
+				}
+
+				// The history of the path ends here; it either was added or renamed in its oldest commit
+				if !followRenames || len(commits) == 0 {
+					break
+				}
+				oldest := commits[len(commits)-1]
+				rename, parent, err := findRename(ctx, client, owner, repo, oldest.GetSHA(), cursor.Path)
+				if err != nil {
+					return mcp.NewToolResultError(err.Error()), nil
+				}
+				if rename == nil || parent == "" {
+					break
+				}
+				result.Renames = append(result.Renames, *rename)
+				cursor = historyCursor{Path: rename.From, SHA: parent, Page: 1}
+				if len(result.Commits) == perPage {
+					result.Cursor = cursor.encode()
+				}
+			}
+
+			return MarshalledTextResult(result), nil
+		}
+}
+
+func fileHistoryCommit(commit *github.RepositoryCommit, path string) FileHistoryCommit {
+	c := FileHistoryCommit{
+		SHA:     commit.GetSHA()[:min(len(commit.GetSHA()), shortSHALength)],
+		Author:  commit.GetAuthor().GetLogin(),
+		Message: strings.SplitN(commit.GetCommit().GetMessage(), "\n", 2)[0],
+		Path:    path,
+	}
+	if c.Author == "" {
+		c.Author = commit.GetCommit().GetAuthor().GetName()
+	}
+	if date := commit.GetCommit().GetAuthor().Date; date != nil {
+		c.Date = date.Format("2006-01-02T15:04:05Z07:00")
+	}
+	return c
+}
+
+// findRename returns the rename of path in a commit and the SHA of the first parent of the commit,
+// or a nil rename if the commit didn't rename path.
+func findRename(ctx context.Context, client *github.Client, owner, repo, sha, path string) (*FileRename, string, error) {
+	commit, resp, err := client.Repositories.GetCommit(ctx, owner, repo, sha, &github.ListOptions{PerPage: 100})
+	if err != nil {
+		_, _ = ghErrors.NewGitHubAPIErrorToCtx(ctx, "failed to get commit", resp, err)
+		return nil, "", fmt.Errorf("failed to get commit %s: %w", sha, err)
+	}
+	_ = resp.Body.Close()
+
+	parent := ""
+	if len(commit.Parents) > 0 {
+		parent = commit.Parents[0].GetSHA()
+	}
+	for _, file := range commit.Files {
+		if file.GetFilename() == path && file.GetStatus() == "renamed" && file.GetPreviousFilename() != "" {
+			return &FileRename{SHA: sha[:min(len(sha), shortSHALength)], From: file.GetPreviousFilename(), To: path}, parent, nil
+		}
+	}
+	return nil, parent, nil
+}
@@ -0,0 +1,222 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/github/github-mcp-server/internal/githubv4mock"
+	"github.com/github/github-mcp-server/internal/toolsnaps"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/google/go-github/v77/github"
+	"github.com/migueleliasweb/go-github-mock/src/mock"
+	"github.com/shurcooL/githubv4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_GetFileBlame(t *testing.T) {
+	tool, _ := GetFileBlame(stubGetGQLClientFn(githubv4.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+	assert.True(t, *tool.Annotations.ReadOnlyHint)
+	assert.ElementsMatch(t, tool.InputSchema.Required, []string{"owner", "repo", "path"})
+
+	qBlame := "query($owner:String!$path:String!$ref:String!$repo:String!){repository(owner: $owner, name: $repo){object(expression: $ref){... on Commit{oid,blame(path: $path){ranges{startingLine,endingLine,commit{abbreviatedOid,messageHeadline,author{name,date,user{login}}}}}}}}}"
+	vars := map[string]any{"owner": "owner", "repo": "repo", "ref": "HEAD", "path": "main.go"}
+	blameResponse := githubv4mock.DataResponse(map[string]any{
+		"repository": map[string]any{"object": map[string]any{
+			"oid": "abcdef1234567890",
+			"blame": map[string]any{"ranges": []any{
+				map[string]any{"startingLine": 1, "endingLine": 3, "commit": map[string]any{
+					"abbreviatedOid": "1111111", "messageHeadline": "Initial commit",
+					"author": map[string]any{"name": "Mona Lisa", "date": "2024-01-02T03:04:05Z", "user": map[string]any{"login": "monalisa"}},
+				}},
+				map[string]any{"startingLine": 4, "endingLine": 9, "commit": map[string]any{
+					"abbreviatedOid": "2222222", "messageHeadline": "Fix the loop",
+					"author": map[string]any{"name": "Hubot", "date": "2024-02-03T04:05:06Z", "user": nil},
+				}},
+				map[string]any{"startingLine": 10, "endingLine": 12, "commit": map[string]any{
+					"abbreviatedOid": "1111111", "messageHeadline": "Initial commit",
+					"author": map[string]any{"name": "Mona Lisa", "date": "2024-01-02T03:04:05Z", "user": map[string]any{"login": "monalisa"}},
+				}},
+			}},
+		}},
+	})
+
+	tests := []struct {
+		name           string
+		response       githubv4mock.GQLResponse
+		requestArgs    map[string]any
+		expectError    string
+		expectedResult FileBlame
+	}{
+		{
+			name:        "whole file",
+			response:    blameResponse,
+			requestArgs: map[string]any{},
+			expectedResult: FileBlame{Path: "main.go", SHA: "abcdef1234567890", Hunks: []BlameHunk{
+				{StartLine: 1, EndLine: 3, SHA: "1111111", Author: "monalisa", Date: "2024-01-02T03:04:05Z", Message: "Initial commit"},
+				{StartLine: 4, EndLine: 9, SHA: "2222222", Author: "Hubot", Date: "2024-02-03T04:05:06Z", Message: "Fix the loop"},
+				{StartLine: 10, EndLine: 12, SHA: "1111111", Author: "monalisa", Date: "2024-01-02T03:04:05Z", Message: "Initial commit"},
+			}},
+		},
+		{
+			name:        "line range",
+			response:    blameResponse,
+			requestArgs: map[string]any{"start_line": float64(3), "end_line": float64(5)},
+			expectedResult: FileBlame{Path: "main.go", SHA: "abcdef1234567890", Hunks: []BlameHunk{
+				{StartLine: 3, EndLine: 3, SHA: "1111111", Author: "monalisa", Date: "2024-01-02T03:04:05Z", Message: "Initial commit"},
+				{StartLine: 4, EndLine: 5, SHA: "2222222", Author: "Hubot", Date: "2024-02-03T04:05:06Z", Message: "Fix the loop"},
+			}},
+		},
+		{
+			name:        "ref that isn't a commit",
+			response:    githubv4mock.DataResponse(map[string]any{"repository": map[string]any{"object": nil}}),
+			requestArgs: map[string]any{},
+			expectError: `could not resolve "HEAD" to a commit`,
+		},
+		{
+			name:        "invalid line range",
+			requestArgs: map[string]any{"start_line": float64(5), "end_line": float64(2)},
+			expectError: "end_line must not be before start_line",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			matcher := githubv4mock.NewQueryMatcher(qBlame, vars, tc.response)
+			client := githubv4.NewClient(githubv4mock.NewMockedHTTPClient(matcher))
+			_, handler := GetFileBlame(stubGetGQLClientFn(client), translations.NullTranslationHelper)
+			args := map[string]any{"owner": "owner", "repo": "repo", "path": "main.go"}
+			for k, v := range tc.requestArgs {
+				args[k] = v
+			}
+
+			result, err := handler(context.Background(), createMCPRequest(args))
+			require.NoError(t, err)
+
+			if tc.expectError != "" {
+				assert.Contains(t, getErrorResult(t, result).Text, tc.expectError)
+				return
+			}
+			var blame FileBlame
+			require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &blame))
+			assert.Equal(t, tc.expectedResult, blame)
+		})
+	}
+}
+
+func Test_ListFileHistory(t *testing.T) {
+	tool, _ := ListFileHistory(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+	assert.True(t, *tool.Annotations.ReadOnlyHint)
+	assert.ElementsMatch(t, tool.InputSchema.Required, []string{"owner", "repo", "path"})
+
+	commit := func(sha, message, login string) *github.RepositoryCommit {
+		return &github.RepositoryCommit{
+			SHA:    github.Ptr(sha),
+			Author: &github.User{Login: github.Ptr(login)},
+			Commit: &github.Commit{
+				Message: github.Ptr(message),
+				Author:  &github.CommitAuthor{Name: github.Ptr(login), Date: &github.Timestamp{Time: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)}},
+			},
+		}
+	}
+	// new.go has two commits, the oldest renaming old.go, which has two commits before
+	histories := map[string][]*github.RepositoryCommit{
+		"new.go": {commit("c4c4c4c4c4", "Tweak new.go", "mona"), commit("c3c3c3c3c3", "Rename old.go\n\nIt is new now", "mona")},
+		"old.go": {commit("c2c2c2c2c2", "Fix old.go", "hubot"), commit("c1c1c1c1c1", "Add old.go", "hubot")},
+	}
+	mockedClient := mock.NewMockedHTTPClient(
+		mock.WithRequestMatchHandler(
+			mock.GetReposCommitsByOwnerByRepo,
+			http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				q := r.URL.Query()
+				history := histories[q.Get("path")]
+				if q.Get("path") == "old.go" {
+					assert.Contains(t, []string{"parent-of-c3", "c1c1c1c1c1"}, q.Get("sha"))
+				}
+				// Listing from a commit starts the history at it
+				for i, c := range history {
+					if c.GetSHA() == q.Get("sha") {
+						history = history[i:]
+					}
+				}
+				perPage := 30
+				if q.Get("per_page") != "" {
+					perPage = int(q.Get("per_page")[0] - '0')
+				}
+				page := 1
+				if q.Get("page") != "" {
+					page = int(q.Get("page")[0] - '0')
+				}
+				start := min(len(history), (page-1)*perPage)
+				data, _ := json.Marshal(history[start:min(len(history), start+perPage)])
+				_, _ = w.Write(data)
+			}),
+		),
+		mock.WithRequestMatchHandler(
+			mock.GetReposCommitsByOwnerByRepoByRef,
+			http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				commit := &github.RepositoryCommit{SHA: github.Ptr("c1c1c1c1c1")}
+				if r.URL.Path == "/repos/owner/repo/commits/c3c3c3c3c3" {
+					commit = &github.RepositoryCommit{
+						SHA:     github.Ptr("c3c3c3c3c3"),
+						Parents: []*github.Commit{{SHA: github.Ptr("parent-of-c3")}},
+						Files:   []*github.CommitFile{{Filename: github.Ptr("new.go"), PreviousFilename: github.Ptr("old.go"), Status: github.Ptr("renamed")}},
+					}
+				}
+				data, _ := json.Marshal(commit)
+				_, _ = w.Write(data)
+			}),
+		),
+	)
+	_, handler := ListFileHistory(stubGetClientFn(github.NewClient(mockedClient)), translations.NullTranslationHelper)
+	list := func(args map[string]any) FileHistory {
+		args["owner"], args["repo"], args["path"] = "owner", "repo", "new.go"
+		result, err := handler(context.Background(), createMCPRequest(args))
+		require.NoError(t, err)
+		var history FileHistory
+		require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &history))
+		return history
+	}
+	shas := func(history FileHistory) []string {
+		var shas []string
+		for _, c := range history.Commits {
+			shas = append(shas, c.SHA)
+		}
+		return shas
+	}
+
+	t.Run("follows renames", func(t *testing.T) {
+		history := list(map[string]any{})
+		assert.Equal(t, []string{"c4c4c4c", "c3c3c3c", "c2c2c2c", "c1c1c1c"}, shas(history))
+		assert.Equal(t, FileHistoryCommit{SHA: "c3c3c3c", Author: "mona", Date: "2024-01-02T03:04:05Z", Message: "Rename old.go", Path: "new.go"}, history.Commits[1])
+		assert.Equal(t, "old.go", history.Commits[2].Path)
+		assert.Equal(t, []FileRename{{SHA: "c3c3c3c", From: "old.go", To: "new.go"}}, history.Renames)
+		assert.Empty(t, history.Cursor)
+	})
+
+	t.Run("without following renames", func(t *testing.T) {
+		history := list(map[string]any{"follow_renames": false})
+		assert.Equal(t, []string{"c4c4c4c", "c3c3c3c"}, shas(history))
+		assert.Empty(t, history.Renames)
+	})
+
+	t.Run("pages with a cursor", func(t *testing.T) {
+		var all []string
+		args := map[string]any{"perPage": float64(3)}
+		for pages := 1; ; pages++ {
+			require.LessOrEqual(t, pages, 3)
+			history := list(args)
+			all = append(all, shas(history)...)
+			if history.Cursor == "" {
+				break
+			}
+			args = map[string]any{"perPage": float64(3), "cursor": history.Cursor}
+		}
+		assert.Equal(t, []string{"c4c4c4c", "c3c3c3c", "c2c2c2c", "c1c1c1c"}, all)
+	})
+}
@@ -86,17 +86,66 @@ type MinimalCommit struct {
 	Files     []MinimalCommitFile `json:"files,omitempty"`
 }
 
+// MinimalGitVerification is the signature verification of a Git commit or tag.
+type MinimalGitVerification struct {
+	Verified bool   `json:"verified"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+// MinimalGitCommit is the trimmed output type for Git commit objects.
+type MinimalGitCommit struct {
+	SHA          string                  `json:"sha"`
+	HTMLURL      string                  `json:"html_url,omitempty"`
+	Message      string                  `json:"message"`
+	Author       *MinimalCommitAuthor    `json:"author,omitempty"`
+	Committer    *MinimalCommitAuthor    `json:"committer,omitempty"`
+	TreeSHA      string                  `json:"tree_sha"`
+	ParentSHAs   []string                `json:"parent_shas"`
+	Verification *MinimalGitVerification `json:"verification,omitempty"`
+}
+
+// MinimalGitRef is the trimmed output type for Git references.
+type MinimalGitRef struct {
+	Ref string `json:"ref"`
+	SHA string `json:"sha"`
+	// Type is the type of the object the reference points to, "commit" or "tag" for annotated tags
+	Type string `json:"type"`
+}
+
+// MinimalGitTag is the trimmed output type for annotated Git tag objects.
+type MinimalGitTag struct {
+	SHA          string                  `json:"sha"`
+	Tag          string                  `json:"tag"`
+	Message      string                  `json:"message"`
+	Tagger       *MinimalCommitAuthor    `json:"tagger,omitempty"`
+	ObjectSHA    string                  `json:"object_sha"`
+	ObjectType   string                  `json:"object_type"`
+	Verification *MinimalGitVerification `json:"verification,omitempty"`
+}
+
 // MinimalRelease is the trimmed output type for release objects.
 type MinimalRelease struct {
-	ID          int64        `json:"id"`
-	TagName     string       `json:"tag_name"`
-	Name        string       `json:"name,omitempty"`
-	Body        string       `json:"body,omitempty"`
-	HTMLURL     string       `json:"html_url"`
-	PublishedAt string       `json:"published_at,omitempty"`
-	Prerelease  bool         `json:"prerelease"`
-	Draft       bool         `json:"draft"`
-	Author      *MinimalUser `json:"author,omitempty"`
+	ID          int64                 `json:"id"`
+	TagName     string                `json:"tag_name"`
+	Name        string                `json:"name,omitempty"`
+	Body        string                `json:"body,omitempty"`
+	HTMLURL     string                `json:"html_url"`
+	PublishedAt string                `json:"published_at,omitempty"`
+	Prerelease  bool                  `json:"prerelease"`
+	Draft       bool                  `json:"draft"`
+	Author      *MinimalUser          `json:"author,omitempty"`
+	Assets      []MinimalReleaseAsset `json:"assets,omitempty"`
+}
+
+// MinimalReleaseAsset is the trimmed output type for release asset objects.
+type MinimalReleaseAsset struct {
+	ID                 int64  `json:"id"`
+	Name               string `json:"name"`
+	Label              string `json:"label,omitempty"`
+	ContentType        string `json:"content_type"`
+	Size               int    `json:"size"`
+	State              string `json:"state"`
+	BrowserDownloadURL string `json:"browser_download_url"`
 }
 
 // MinimalBranch is the trimmed output type for branch objects.
@@ -169,6 +218,57 @@ func convertToMinimalUser(user *github.User) *MinimalUser {
 	}
 }
 
+func convertToMinimalCommitAuthor(author *github.CommitAuthor) *MinimalCommitAuthor {
+	if author == nil {
+		return nil
+	}
+	minimalAuthor := &MinimalCommitAuthor{
+		Name:  author.GetName(),
+		Email: author.GetEmail(),
+	}
+	if author.Date != nil {
+		minimalAuthor.Date = author.Date.Format("2006-01-02T15:04:05Z")
+	}
+	return minimalAuthor
+}
+
+func convertToMinimalGitVerification(verification *github.SignatureVerification) *MinimalGitVerification {
+	if verification == nil {
+		return nil
+	}
+	return &MinimalGitVerification{
+		Verified: verification.GetVerified(),
+		Reason:   verification.GetReason(),
+	}
+}
+
+// convertToMinimalGitCommit converts a Git commit object to MinimalGitCommit
+func convertToMinimalGitCommit(commit *github.Commit) MinimalGitCommit {
+	minimalCommit := MinimalGitCommit{
+		SHA:          commit.GetSHA(),
+		HTMLURL:      commit.GetHTMLURL(),
+		Message:      commit.GetMessage(),
+		Author:       convertToMinimalCommitAuthor(commit.Author),
+		Committer:    convertToMinimalCommitAuthor(commit.Committer),
+		TreeSHA:      commit.GetTree().GetSHA(),
+		ParentSHAs:   []string{},
+		Verification: convertToMinimalGitVerification(commit.Verification),
+	}
+	for _, parent := range commit.Parents {
+		minimalCommit.ParentSHAs = append(minimalCommit.ParentSHAs, parent.GetSHA())
+	}
+	return minimalCommit
+}
+
+// convertToMinimalGitRef converts a Git reference to MinimalGitRef
+func convertToMinimalGitRef(ref *github.Reference) MinimalGitRef {
+	return MinimalGitRef{
+		Ref:  ref.GetRef(),
+		SHA:  ref.GetObject().GetSHA(),
+		Type: ref.GetObject().GetType(),
+	}
+}
+
 // convertToMinimalCommit converts a GitHub API RepositoryCommit to MinimalCommit
 func convertToMinimalCommit(commit *github.RepositoryCommit, includeDiffs bool) MinimalCommit {
 	minimalCommit := MinimalCommit{
@@ -256,3 +356,37 @@ func convertToMinimalBranch(branch *github.Branch) MinimalBranch {
 		Protected: branch.GetProtected(),
 	}
 }
+
+// convertToMinimalReleaseAsset converts a GitHub API ReleaseAsset to MinimalReleaseAsset
+func convertToMinimalReleaseAsset(asset *github.ReleaseAsset) MinimalReleaseAsset {
+	return MinimalReleaseAsset{
+		ID:                 asset.GetID(),
+		Name:               asset.GetName(),
+		Label:              asset.GetLabel(),
+		ContentType:        asset.GetContentType(),
+		Size:               asset.GetSize(),
+		State:              asset.GetState(),
+		BrowserDownloadURL: asset.GetBrowserDownloadURL(),
+	}
+}
+
+// convertToMinimalRelease converts a GitHub API RepositoryRelease to MinimalRelease
+func convertToMinimalRelease(release *github.RepositoryRelease) MinimalRelease {
+	minimalRelease := MinimalRelease{
+		ID:         release.GetID(),
+		TagName:    release.GetTagName(),
+		Name:       release.GetName(),
+		Body:       release.GetBody(),
+		HTMLURL:    release.GetHTMLURL(),
+		Prerelease: release.GetPrerelease(),
+		Draft:      release.GetDraft(),
+		Author:     convertToMinimalUser(release.Author),
+	}
+	if release.PublishedAt != nil {
+		minimalRelease.PublishedAt = release.PublishedAt.Format("2006-01-02T15:04:05Z")
+	}
+	for _, asset := range release.Assets {
+		minimalRelease.Assets = append(minimalRelease.Assets, convertToMinimalReleaseAsset(asset))
+	}
+	return minimalRelease
+}
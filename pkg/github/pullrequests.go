@@ -14,7 +14,6 @@ import (
 	"github.com/shurcooL/githubv4"
 
 	ghErrors "github.com/github/github-mcp-server/pkg/errors"
-	"github.com/github/github-mcp-server/pkg/sanitize"
 	"github.com/github/github-mcp-server/pkg/translations"
 )
 
@@ -124,16 +123,6 @@ func GetPullRequest(ctx context.Context, client *github.Client, owner, repo stri
 		return mcp.NewToolResultError(fmt.Sprintf("failed to get pull request: %s", string(body))), nil
 	}
 
-	// sanitize title/body on response
-	if pr != nil {
-		if pr.Title != nil {
-			pr.Title = github.Ptr(sanitize.Sanitize(*pr.Title))
-		}
-		if pr.Body != nil {
-			pr.Body = github.Ptr(sanitize.Sanitize(*pr.Body))
-		}
-	}
-
 	r, err := json.Marshal(pr)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal response: %w", err)
@@ -347,6 +336,9 @@ func CreatePullRequest(getClient GetClientFn, t translations.TranslationHelperFu
 			mcp.WithBoolean("maintainer_can_modify",
 				mcp.Description("Allow maintainer edits"),
 			),
+			mcp.WithBoolean("request_code_owner_reviews",
+				mcp.Description("Request reviews from the code owners of the changed files, per the CODEOWNERS file of the base branch"),
+			),
 		),
 		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			owner, err := RequiredParam[string](request, "owner")
@@ -385,6 +377,11 @@ func CreatePullRequest(getClient GetClientFn, t translations.TranslationHelperFu
 				return mcp.NewToolResultError(err.Error()), nil
 			}
 
+			requestCodeOwnerReviews, err := OptionalParam[bool](request, "request_code_owner_reviews")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
 			newPR := &github.NewPullRequest{
 				Title: github.Ptr(title),
 				Head:  github.Ptr(head),
@@ -420,6 +417,12 @@ func CreatePullRequest(getClient GetClientFn, t translations.TranslationHelperFu
 				return mcp.NewToolResultError(fmt.Sprintf("failed to create pull request: %s", string(body))), nil
 			}
 
+			if requestCodeOwnerReviews {
+				if result := requestCodeOwnerReviewers(ctx, client, owner, repo, pr); result != nil {
+					return result, nil
+				}
+			}
+
 			// Return minimal response with just essential information
 			minimalResponse := MinimalResponse{
 				ID:  fmt.Sprintf("%d", pr.GetID()),
@@ -815,19 +818,6 @@ func ListPullRequests(getClient GetClientFn, t translations.TranslationHelperFun
 				return mcp.NewToolResultError(fmt.Sprintf("failed to list pull requests: %s", string(body))), nil
 			}
 
-			// sanitize title/body on each PR
-			for _, pr := range prs {
-				if pr == nil {
-					continue
-				}
-				if pr.Title != nil {
-					pr.Title = github.Ptr(sanitize.Sanitize(*pr.Title))
-				}
-				if pr.Body != nil {
-					pr.Body = github.Ptr(sanitize.Sanitize(*pr.Body))
-				}
-			}
-
 			r, err := json.Marshal(prs)
 			if err != nil {
 				return nil, fmt.Errorf("failed to marshal response: %w", err)
@@ -905,11 +895,19 @@ func MergePullRequest(getClient GetClientFn, t translations.TranslationHelperFun
 			}
 			result, resp, err := client.PullRequests.Merge(ctx, owner, repo, pullNumber, commitMessage, options)
 			if err != nil {
-				return ghErrors.NewGitHubAPIErrorResponse(ctx,
+				errResult := ghErrors.NewGitHubAPIErrorResponse(ctx,
 					"failed to merge pull request",
 					resp,
 					err,
-				), nil
+				)
+				if resp != nil && isRuleViolationStatus(resp.StatusCode) {
+					// Point at the rules of the base branch, which are the usual reason a merge is rejected
+					if pr, prResp, prErr := client.PullRequests.Get(ctx, owner, repo, pullNumber); prErr == nil {
+						_ = prResp.Body.Close()
+						errResult = withBlockingRulesHint(ctx, client, owner, repo, pr.GetBase().GetRef(), errResult)
+					}
+				}
+				return errResult, nil
 			}
 			defer func() { _ = resp.Body.Close() }()
 
@@ -2,6 +2,7 @@ package github
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"net/http"
 	"testing"
@@ -11,6 +12,7 @@ import (
 	"github.com/github/github-mcp-server/internal/toolsnaps"
 	"github.com/github/github-mcp-server/pkg/translations"
 	"github.com/google/go-github/v77/github"
+	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/shurcooL/githubv4"
 
 	"github.com/migueleliasweb/go-github-mock/src/mock"
@@ -739,6 +741,7 @@ func Test_MergePullRequest(t *testing.T) {
 		expectError         bool
 		expectedMergeResult *github.PullRequestMergeResult
 		expectedErrMsg      string
+		expectedHint        string
 	}{
 		{
 			name: "successful merge",
@@ -784,6 +787,39 @@ func Test_MergePullRequest(t *testing.T) {
 			expectError:    true,
 			expectedErrMsg: "failed to merge pull request",
 		},
+		{
+			name: "merge blocked by rules of the base branch",
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(
+					mock.PutReposPullsMergeByOwnerByRepoByPullNumber,
+					mockResponse(t, http.StatusMethodNotAllowed, map[string]string{"message": "Repository rule violations found"}),
+				),
+				mock.WithRequestMatch(
+					mock.GetReposPullsByOwnerByRepoByPullNumber,
+					&github.PullRequest{Number: github.Ptr(42), Base: &github.PullRequestBranch{Ref: github.Ptr("main")}},
+				),
+				mock.WithRequestMatchHandler(
+					mock.GetReposRulesBranchesByOwnerByRepoByBranch,
+					expectPath(t, "/repos/owner/repo/rules/branches/main").andThen(
+						mockResponse(t, http.StatusOK, []map[string]any{
+							{"type": "required_signatures", "ruleset_source_type": "Repository", "ruleset_source": "owner/repo", "ruleset_id": 7},
+						}),
+					),
+				),
+				mock.WithRequestMatchHandler(
+					mock.GetReposBranchesProtectionByOwnerByRepoByBranch,
+					mockResponse(t, http.StatusNotFound, map[string]string{"message": "Branch not protected"}),
+				),
+			),
+			requestArgs: map[string]interface{}{
+				"owner":      "owner",
+				"repo":       "repo",
+				"pullNumber": float64(42),
+			},
+			expectError:    true,
+			expectedErrMsg: "failed to merge pull request",
+			expectedHint:   "The branch main is governed by the following rules, which may be blocking this change:\n- Commits must be signed (ruleset 7 from owner/repo)",
+		},
 	}
 
 	for _, tc := range tests {
@@ -802,6 +838,12 @@ func Test_MergePullRequest(t *testing.T) {
 			if tc.expectError {
 				require.NoError(t, err)
 				require.True(t, result.IsError)
+				if tc.expectedHint != "" {
+					require.Len(t, result.Content, 2)
+					assert.Contains(t, result.Content[0].(mcp.TextContent).Text, tc.expectedErrMsg)
+					assert.Equal(t, tc.expectedHint, result.Content[1].(mcp.TextContent).Text)
+					return
+				}
 				errorContent := getErrorResult(t, result)
 				assert.Contains(t, errorContent.Text, tc.expectedErrMsg)
 				return
@@ -1905,6 +1947,51 @@ func Test_CreatePullRequest(t *testing.T) {
 			expectError: false,
 			expectedPR:  mockPR,
 		},
+		{
+			name: "successful PR creation requesting code owner reviews",
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(
+					mock.PostReposPullsByOwnerByRepo,
+					mockResponse(t, http.StatusCreated, mockPR),
+				),
+				mock.WithRequestMatch(
+					mock.GetReposPullsFilesByOwnerByRepoByPullNumber,
+					[]*github.CommitFile{
+						{Filename: github.Ptr("docs/guide.md")},
+						{Filename: github.Ptr("pkg/server.go")},
+					},
+				),
+				mock.WithRequestMatchHandler(
+					mock.GetReposContentsByOwnerByRepoByPath,
+					expectPath(t, "/repos/owner/repo/contents/.github/CODEOWNERS").andThen(
+						mockResponse(t, http.StatusOK, &github.RepositoryContent{
+							Type:     github.Ptr("file"),
+							Encoding: github.Ptr("base64"),
+							Content:  github.Ptr(base64.StdEncoding.EncodeToString([]byte("* @testuser @other/team\n/docs/ @owner/docs @mona\n"))),
+						}),
+					),
+				),
+				mock.WithRequestMatchHandler(
+					mock.PostReposPullsRequestedReviewersByOwnerByRepoByPullNumber,
+					expectRequestBody(t, map[string]interface{}{
+						"reviewers":      []interface{}{"mona"},
+						"team_reviewers": []interface{}{"docs"},
+					}).andThen(
+						mockResponse(t, http.StatusCreated, mockPR),
+					),
+				),
+			),
+			requestArgs: map[string]interface{}{
+				"owner":                      "owner",
+				"repo":                       "repo",
+				"title":                      "Test PR",
+				"head":                       "feature-branch",
+				"base":                       "main",
+				"request_code_owner_reviews": true,
+			},
+			expectError: false,
+			expectedPR:  mockPR,
+		},
 		{
 			name:         "missing required parameter",
 			mockedClient: mock.NewMockedHTTPClient(),
@@ -0,0 +1,506 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"mime"
+	"net/url"
+	"path"
+
+	ghErrors "github.com/github/github-mcp-server/pkg/errors"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/google/go-github/v77/github"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// withReleaseParams adds the parameters shared by create_release and update_release.
+func withReleaseParams() mcp.ToolOption {
+	return func(tool *mcp.Tool) {
+		mcp.WithString("target_commitish",
+			mcp.Description("Branch or commit SHA the tag is created from, when it doesn't exist yet (defaults to the default branch)"),
+		)(tool)
+		mcp.WithString("name",
+			mcp.Description("Release title"),
+		)(tool)
+		mcp.WithString("body",
+			mcp.Description("Release notes in markdown"),
+		)(tool)
+		mcp.WithBoolean("prerelease",
+			mcp.Description("Mark the release as a pre-release"),
+		)(tool)
+		mcp.WithString("make_latest",
+			mcp.Description("Whether to mark the release as the latest one: true, false, or legacy to pick it by creation date and semantic version"),
+			mcp.Enum("true", "false", "legacy"),
+		)(tool)
+	}
+}
+
+// CreateRelease creates a tool to create a release, optionally with generated release notes.
+func CreateRelease(getClient GetClientFn, t translations.TranslationHelperFunc) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("create_release",
+			mcp.WithDescription(t("TOOL_CREATE_RELEASE_DESCRIPTION", "Create a release of a GitHub repository, published or as a draft. The tag is created if it doesn't exist. Set generate_notes to generate the release notes from the pull requests merged since the previous release, appended to body if given")),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:        t("TOOL_CREATE_RELEASE_USER_TITLE", "Create release"),
+				ReadOnlyHint: ToBoolPtr(false),
+			}),
+			mcp.WithString("owner",
+				mcp.Required(),
+				mcp.Description("Repository owner"),
+			),
+			mcp.WithString("repo",
+				mcp.Required(),
+				mcp.Description("Repository name"),
+			),
+			mcp.WithString("tag",
+				mcp.Required(),
+				mcp.Description("Tag name of the release (e.g., 'v1.0.0')"),
+			),
+			withReleaseParams(),
+			mcp.WithBoolean("draft",
+				mcp.Description("Create the release as an unpublished draft"),
+			),
+			mcp.WithBoolean("generate_notes",
+				mcp.Description("Generate the release notes (and the name, if not given) from the changes since the previous release"),
+			),
+			mcp.WithString("previous_tag",
+				mcp.Description("Tag to generate the release notes from (defaults to the previous release)"),
+			),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			owner, err := RequiredParam[string](request, "owner")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			repo, err := RequiredParam[string](request, "repo")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			tag, err := RequiredParam[string](request, "tag")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			targetCommitish, err := OptionalParam[string](request, "target_commitish")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			name, err := OptionalParam[string](request, "name")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			body, err := OptionalParam[string](request, "body")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			draft, err := OptionalParam[bool](request, "draft")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			prerelease, err := OptionalParam[bool](request, "prerelease")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			makeLatest, err := OptionalParam[string](request, "make_latest")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			generateNotes, err := OptionalParam[bool](request, "generate_notes")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			previousTag, err := OptionalParam[string](request, "previous_tag")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			if previousTag != "" && !generateNotes {
+				return mcp.NewToolResultError("previous_tag is only used with generate_notes"), nil
+			}
+
+			client, err := getClient(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get GitHub client: %w", err)
+			}
+
+			if generateNotes {
+				opts := &github.GenerateNotesOptions{TagName: tag}
+				if previousTag != "" {
+					opts.PreviousTagName = github.Ptr(previousTag)
+				}
+				if targetCommitish != "" {
+					opts.TargetCommitish = github.Ptr(targetCommitish)
+				}
+				notes, resp, err := client.Repositories.GenerateReleaseNotes(ctx, owner, repo, opts)
+				if err != nil {
+					return ghErrors.NewGitHubAPIErrorResponse(ctx,
+						"failed to generate release notes",
+						resp,
+						err,
+					), nil
+				}
+				_ = resp.Body.Close()
+
+				if name == "" {
+					name = notes.Name
+				}
+				if body == "" {
+					body = notes.Body
+				} else {
+					body += "\n\n" + notes.Body
+				}
+			}
+
+			newRelease := &github.RepositoryRelease{
+				TagName:    github.Ptr(tag),
+				Draft:      github.Ptr(draft),
+				Prerelease: github.Ptr(prerelease),
+			}
+			if targetCommitish != "" {
+				newRelease.TargetCommitish = github.Ptr(targetCommitish)
+			}
+			if name != "" {
+				newRelease.Name = github.Ptr(name)
+			}
+			if body != "" {
+				newRelease.Body = github.Ptr(body)
+			}
+			if makeLatest != "" {
+				newRelease.MakeLatest = github.Ptr(makeLatest)
+			}
+
+			release, resp, err := client.Repositories.CreateRelease(ctx, owner, repo, newRelease)
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx,
+					"failed to create release",
+					resp,
+					err,
+				), nil
+			}
+			defer func() { _ = resp.Body.Close() }()
+
+			return MarshalledTextResult(convertToMinimalRelease(release)), nil
+		}
+}
+
+// UpdateRelease creates a tool to edit or publish a release.
+func UpdateRelease(getClient GetClientFn, t translations.TranslationHelperFunc) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("update_release",
+			mcp.WithDescription(t("TOOL_UPDATE_RELEASE_DESCRIPTION", "Update a release of a GitHub repository. Only the given fields change. Set draft to false to publish a draft release")),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:        t("TOOL_UPDATE_RELEASE_USER_TITLE", "Update release"),
+				ReadOnlyHint: ToBoolPtr(false),
+			}),
+			mcp.WithString("owner",
+				mcp.Required(),
+				mcp.Description("Repository owner"),
+			),
+			mcp.WithString("repo",
+				mcp.Required(),
+				mcp.Description("Repository name"),
+			),
+			mcp.WithNumber("release_id",
+				mcp.Required(),
+				mcp.Description("ID of the release"),
+			),
+			mcp.WithString("tag",
+				mcp.Description("New tag name of the release"),
+			),
+			withReleaseParams(),
+			mcp.WithBoolean("draft",
+				mcp.Description("Whether the release is an unpublished draft, false publishes it"),
+			),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			owner, err := RequiredParam[string](request, "owner")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			repo, err := RequiredParam[string](request, "repo")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			releaseID, err := RequiredBigInt(request, "release_id")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			// Build the update only with provided fields
+			update := &github.RepositoryRelease{}
+			updateNeeded := false
+			for param, field := range map[string]**string{
+				"tag":              &update.TagName,
+				"target_commitish": &update.TargetCommitish,
+				"name":             &update.Name,
+				"body":             &update.Body,
+				"make_latest":      &update.MakeLatest,
+			} {
+				if value, ok, err := OptionalParamOK[string](request, param); err != nil {
+					return mcp.NewToolResultError(err.Error()), nil
+				} else if ok {
+					*field = github.Ptr(value)
+					updateNeeded = true
+				}
+			}
+			for param, field := range map[string]**bool{
+				"draft":      &update.Draft,
+				"prerelease": &update.Prerelease,
+			} {
+				if value, ok, err := OptionalParamOK[bool](request, param); err != nil {
+					return mcp.NewToolResultError(err.Error()), nil
+				} else if ok {
+					*field = github.Ptr(value)
+					updateNeeded = true
+				}
+			}
+			if !updateNeeded {
+				return mcp.NewToolResultError("No update parameters provided."), nil
+			}
+
+			client, err := getClient(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get GitHub client: %w", err)
+			}
+			release, resp, err := client.Repositories.EditRelease(ctx, owner, repo, releaseID, update)
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx,
+					"failed to update release",
+					resp,
+					err,
+				), nil
+			}
+			defer func() { _ = resp.Body.Close() }()
+
+			return MarshalledTextResult(convertToMinimalRelease(release)), nil
+		}
+}
+
+// DeleteRelease creates a tool to delete a release.
+func DeleteRelease(getClient GetClientFn, t translations.TranslationHelperFunc) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("delete_release",
+			mcp.WithDescription(t("TOOL_DELETE_RELEASE_DESCRIPTION", "Delete a release of a GitHub repository with its assets. The tag of the release is kept")),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:           t("TOOL_DELETE_RELEASE_USER_TITLE", "Delete release"),
+				ReadOnlyHint:    ToBoolPtr(false),
+				DestructiveHint: ToBoolPtr(true),
+			}),
+			mcp.WithString("owner",
+				mcp.Required(),
+				mcp.Description("Repository owner"),
+			),
+			mcp.WithString("repo",
+				mcp.Required(),
+				mcp.Description("Repository name"),
+			),
+			mcp.WithNumber("release_id",
+				mcp.Required(),
+				mcp.Description("ID of the release"),
+			),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			owner, err := RequiredParam[string](request, "owner")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			repo, err := RequiredParam[string](request, "repo")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			releaseID, err := RequiredBigInt(request, "release_id")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			client, err := getClient(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get GitHub client: %w", err)
+			}
+			resp, err := client.Repositories.DeleteRelease(ctx, owner, repo, releaseID)
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx,
+					"failed to delete release",
+					resp,
+					err,
+				), nil
+			}
+			defer func() { _ = resp.Body.Close() }()
+
+			return mcp.NewToolResultText(fmt.Sprintf("Deleted release %d", releaseID)), nil
+		}
+}
+
+// UploadReleaseAsset creates a tool to upload an asset to a release, through the upload URL of the client.
+func UploadReleaseAsset(getClient GetClientFn, t translations.TranslationHelperFunc) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("upload_release_asset",
+			mcp.WithDescription(t("TOOL_UPLOAD_RELEASE_ASSET_DESCRIPTION", "Upload a file from text or base64 content as an asset of a release")),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:        t("TOOL_UPLOAD_RELEASE_ASSET_USER_TITLE", "Upload release asset"),
+				ReadOnlyHint: ToBoolPtr(false),
+			}),
+			mcp.WithString("owner",
+				mcp.Required(),
+				mcp.Description("Repository owner"),
+			),
+			mcp.WithString("repo",
+				mcp.Required(),
+				mcp.Description("Repository name"),
+			),
+			mcp.WithNumber("release_id",
+				mcp.Required(),
+				mcp.Description("ID of the release"),
+			),
+			mcp.WithString("name",
+				mcp.Required(),
+				mcp.Description("File name of the asset"),
+			),
+			mcp.WithString("content",
+				mcp.Required(),
+				mcp.Description("Content of the asset"),
+			),
+			mcp.WithString("encoding",
+				mcp.Description("Encoding of content, base64 for binary content"),
+				mcp.Enum("utf-8", "base64"),
+			),
+			mcp.WithString("content_type",
+				mcp.Description("Media type of the asset (defaults to one guessed from the name, or application/octet-stream)"),
+			),
+			mcp.WithString("label",
+				mcp.Description("Label shown instead of the file name"),
+			),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			owner, err := RequiredParam[string](request, "owner")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			repo, err := RequiredParam[string](request, "repo")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			releaseID, err := RequiredBigInt(request, "release_id")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			name, err := RequiredParam[string](request, "name")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			content, err := RequiredParam[string](request, "content")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			encoding, err := OptionalParam[string](request, "encoding")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			contentType, err := OptionalParam[string](request, "content_type")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			label, err := OptionalParam[string](request, "label")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			var data []byte
+			switch encoding {
+			case "", "utf-8":
+				data = []byte(content)
+			case "base64":
+				data, err = base64.StdEncoding.DecodeString(content)
+				if err != nil {
+					return mcp.NewToolResultError(fmt.Sprintf("content is not valid base64: %s", err)), nil
+				}
+			default:
+				return mcp.NewToolResultError("encoding must be utf-8 or base64"), nil
+			}
+			if contentType == "" {
+				contentType = mime.TypeByExtension(path.Ext(name))
+			}
+			if contentType == "" {
+				contentType = "application/octet-stream"
+			}
+
+			client, err := getClient(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get GitHub client: %w", err)
+			}
+
+			// RepositoriesService.UploadReleaseAsset only uploads from an *os.File
+			query := url.Values{"name": {name}}
+			if label != "" {
+				query.Set("label", label)
+			}
+			u := fmt.Sprintf("repos/%s/%s/releases/%d/assets?%s", owner, repo, releaseID, query.Encode())
+			req, err := client.NewUploadRequest(u, bytes.NewReader(data), int64(len(data)), contentType)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create upload request: %w", err)
+			}
+			asset := new(github.ReleaseAsset)
+			resp, err := client.Do(ctx, req, asset)
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx,
+					"failed to upload release asset",
+					resp,
+					err,
+				), nil
+			}
+			defer func() { _ = resp.Body.Close() }()
+
+			return MarshalledTextResult(convertToMinimalReleaseAsset(asset)), nil
+		}
+}
+
+// DeleteReleaseAsset creates a tool to delete a release asset.
+func DeleteReleaseAsset(getClient GetClientFn, t translations.TranslationHelperFunc) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("delete_release_asset",
+			mcp.WithDescription(t("TOOL_DELETE_RELEASE_ASSET_DESCRIPTION", "Delete an asset of a release")),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:           t("TOOL_DELETE_RELEASE_ASSET_USER_TITLE", "Delete release asset"),
+				ReadOnlyHint:    ToBoolPtr(false),
+				DestructiveHint: ToBoolPtr(true),
+			}),
+			mcp.WithString("owner",
+				mcp.Required(),
+				mcp.Description("Repository owner"),
+			),
+			mcp.WithString("repo",
+				mcp.Required(),
+				mcp.Description("Repository name"),
+			),
+			mcp.WithNumber("asset_id",
+				mcp.Required(),
+				mcp.Description("ID of the asset"),
+			),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			owner, err := RequiredParam[string](request, "owner")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			repo, err := RequiredParam[string](request, "repo")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			assetID, err := RequiredBigInt(request, "asset_id")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			client, err := getClient(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get GitHub client: %w", err)
+			}
+			resp, err := client.Repositories.DeleteReleaseAsset(ctx, owner, repo, assetID)
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx,
+					"failed to delete release asset",
+					resp,
+					err,
+				), nil
+			}
+			defer func() { _ = resp.Body.Close() }()
+
+			return mcp.NewToolResultText(fmt.Sprintf("Deleted release asset %d", assetID)), nil
+		}
+}
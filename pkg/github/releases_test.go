@@ -0,0 +1,316 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/github/github-mcp-server/internal/toolsnaps"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/google/go-github/v77/github"
+	"github.com/migueleliasweb/go-github-mock/src/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_CreateRelease(t *testing.T) {
+	tool, _ := CreateRelease(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+	assert.False(t, *tool.Annotations.ReadOnlyHint)
+	assert.ElementsMatch(t, tool.InputSchema.Required, []string{"owner", "repo", "tag"})
+
+	release := &github.RepositoryRelease{
+		ID:      github.Ptr(int64(1)),
+		TagName: github.Ptr("v1.1.0"),
+		Name:    github.Ptr("v1.1.0"),
+		HTMLURL: github.Ptr("https://github.com/owner/repo/releases/tag/v1.1.0"),
+		Draft:   github.Ptr(true),
+	}
+	tests := []struct {
+		name         string
+		mockedClient *http.Client
+		requestArgs  map[string]any
+		expectError  string
+	}{
+		{
+			name: "creates a draft",
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(
+					mock.PostReposReleasesByOwnerByRepo,
+					expectRequestBody(t, map[string]any{
+						"tag_name":         "v1.1.0",
+						"target_commitish": "main",
+						"name":             "v1.1.0",
+						"body":             "Highlights",
+						"draft":            true,
+						"prerelease":       false,
+						"make_latest":      "false",
+					}).andThen(
+						mockResponse(t, http.StatusCreated, release),
+					),
+				),
+			),
+			requestArgs: map[string]any{
+				"target_commitish": "main",
+				"name":             "v1.1.0",
+				"body":             "Highlights",
+				"draft":            true,
+				"make_latest":      "false",
+			},
+		},
+		{
+			name: "appends generated notes",
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(
+					mock.PostReposReleasesGenerateNotesByOwnerByRepo,
+					expectRequestBody(t, map[string]any{
+						"tag_name":          "v1.1.0",
+						"previous_tag_name": "v1.0.0",
+					}).andThen(
+						mockResponse(t, http.StatusOK, &github.RepositoryReleaseNotes{
+							Name: "v1.1.0",
+							Body: "## What's Changed\n* Fix it by @mona in #2",
+						}),
+					),
+				),
+				mock.WithRequestMatchHandler(
+					mock.PostReposReleasesByOwnerByRepo,
+					expectRequestBody(t, map[string]any{
+						"tag_name":   "v1.1.0",
+						"name":       "v1.1.0",
+						"body":       "Highlights\n\n## What's Changed\n* Fix it by @mona in #2",
+						"draft":      false,
+						"prerelease": false,
+					}).andThen(
+						mockResponse(t, http.StatusCreated, release),
+					),
+				),
+			),
+			requestArgs: map[string]any{
+				"body":           "Highlights",
+				"generate_notes": true,
+				"previous_tag":   "v1.0.0",
+			},
+		},
+		{
+			name:         "previous tag without generated notes",
+			mockedClient: mock.NewMockedHTTPClient(),
+			requestArgs:  map[string]any{"previous_tag": "v1.0.0"},
+			expectError:  "previous_tag is only used with generate_notes",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, handler := CreateRelease(stubGetClientFn(github.NewClient(tc.mockedClient)), translations.NullTranslationHelper)
+			args := map[string]any{"owner": "owner", "repo": "repo", "tag": "v1.1.0"}
+			for k, v := range tc.requestArgs {
+				args[k] = v
+			}
+			result, err := handler(context.Background(), createMCPRequest(args))
+			require.NoError(t, err)
+
+			if tc.expectError != "" {
+				assert.Equal(t, tc.expectError, getErrorResult(t, result).Text)
+				return
+			}
+			var created MinimalRelease
+			require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &created))
+			assert.Equal(t, int64(1), created.ID)
+			assert.Equal(t, "v1.1.0", created.TagName)
+			assert.True(t, created.Draft)
+		})
+	}
+}
+
+func Test_UpdateRelease(t *testing.T) {
+	tool, _ := UpdateRelease(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+	assert.ElementsMatch(t, tool.InputSchema.Required, []string{"owner", "repo", "release_id"})
+
+	t.Run("publishes a draft", func(t *testing.T) {
+		mockedClient := mock.NewMockedHTTPClient(
+			mock.WithRequestMatchHandler(
+				mock.PatchReposReleasesByOwnerByRepoByReleaseId,
+				expect(t, expectations{
+					path:        "/repos/owner/repo/releases/1",
+					requestBody: map[string]any{"draft": false, "name": "Final"},
+				}).andThen(
+					mockResponse(t, http.StatusOK, &github.RepositoryRelease{
+						ID:      github.Ptr(int64(1)),
+						TagName: github.Ptr("v1.1.0"),
+						Name:    github.Ptr("Final"),
+						Draft:   github.Ptr(false),
+						Assets: []*github.ReleaseAsset{{
+							ID:   github.Ptr(int64(7)),
+							Name: github.Ptr("app.tar.gz"),
+						}},
+					}),
+				),
+			),
+		)
+		_, handler := UpdateRelease(stubGetClientFn(github.NewClient(mockedClient)), translations.NullTranslationHelper)
+		result, err := handler(context.Background(), createMCPRequest(map[string]any{
+			"owner":      "owner",
+			"repo":       "repo",
+			"release_id": float64(1),
+			"draft":      false,
+			"name":       "Final",
+		}))
+		require.NoError(t, err)
+
+		var updated MinimalRelease
+		require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &updated))
+		assert.False(t, updated.Draft)
+		assert.Equal(t, "Final", updated.Name)
+		require.Len(t, updated.Assets, 1)
+		assert.Equal(t, "app.tar.gz", updated.Assets[0].Name)
+	})
+
+	t.Run("nothing to update", func(t *testing.T) {
+		_, handler := UpdateRelease(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+		result, err := handler(context.Background(), createMCPRequest(map[string]any{
+			"owner":      "owner",
+			"repo":       "repo",
+			"release_id": float64(1),
+		}))
+		require.NoError(t, err)
+		assert.Equal(t, "No update parameters provided.", getErrorResult(t, result).Text)
+	})
+}
+
+func Test_DeleteRelease(t *testing.T) {
+	tool, _ := DeleteRelease(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+	assert.True(t, *tool.Annotations.DestructiveHint)
+
+	mockedClient := mock.NewMockedHTTPClient(
+		mock.WithRequestMatchHandler(
+			mock.DeleteReposReleasesByOwnerByRepoByReleaseId,
+			expectPath(t, "/repos/owner/repo/releases/1").andThen(
+				func(w http.ResponseWriter, _ *http.Request) {
+					w.WriteHeader(http.StatusNoContent)
+				},
+			),
+		),
+	)
+	_, handler := DeleteRelease(stubGetClientFn(github.NewClient(mockedClient)), translations.NullTranslationHelper)
+	result, err := handler(context.Background(), createMCPRequest(map[string]any{
+		"owner":      "owner",
+		"repo":       "repo",
+		"release_id": float64(1),
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, "Deleted release 1", getTextResult(t, result).Text)
+}
+
+func Test_UploadReleaseAsset(t *testing.T) {
+	tool, _ := UploadReleaseAsset(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+	assert.ElementsMatch(t, tool.InputSchema.Required, []string{"owner", "repo", "release_id", "name", "content"})
+
+	tests := []struct {
+		name                string
+		requestArgs         map[string]any
+		expectedContentType string
+		expectedContent     string
+		expectedLabel       string
+		expectError         string
+	}{
+		{
+			name:                "text content",
+			requestArgs:         map[string]any{"name": "sbom.json", "content": "{}\n", "label": "SBOM"},
+			expectedContentType: "application/json",
+			expectedContent:     "{}\n",
+			expectedLabel:       "SBOM",
+		},
+		{
+			name:                "base64 content",
+			requestArgs:         map[string]any{"name": "app.bin", "content": "AAEC", "encoding": "base64"},
+			expectedContentType: "application/octet-stream",
+			expectedContent:     "\x00\x01\x02",
+		},
+		{
+			name:        "invalid base64",
+			requestArgs: map[string]any{"name": "app.bin", "content": "not base64!", "encoding": "base64"},
+			expectError: "content is not valid base64",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockedClient := mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(
+					mock.PostReposReleasesAssetsByOwnerByRepoByReleaseId,
+					http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+						assert.Equal(t, "/repos/owner/repo/releases/1/assets", r.URL.Path)
+						assert.Equal(t, tc.requestArgs["name"], r.URL.Query().Get("name"))
+						assert.Equal(t, tc.expectedLabel, r.URL.Query().Get("label"))
+						assert.Equal(t, tc.expectedContentType, r.Header.Get("Content-Type"))
+						body, err := io.ReadAll(r.Body)
+						require.NoError(t, err)
+						assert.Equal(t, tc.expectedContent, string(body))
+
+						w.WriteHeader(http.StatusCreated)
+						data, _ := json.Marshal(&github.ReleaseAsset{
+							ID:          github.Ptr(int64(7)),
+							Name:        github.Ptr(r.URL.Query().Get("name")),
+							ContentType: github.Ptr(r.Header.Get("Content-Type")),
+							Size:        github.Ptr(len(body)),
+							State:       github.Ptr("uploaded"),
+						})
+						_, _ = w.Write(data)
+					}),
+				),
+			)
+			_, handler := UploadReleaseAsset(stubGetClientFn(github.NewClient(mockedClient)), translations.NullTranslationHelper)
+			args := map[string]any{"owner": "owner", "repo": "repo", "release_id": float64(1)}
+			for k, v := range tc.requestArgs {
+				args[k] = v
+			}
+			result, err := handler(context.Background(), createMCPRequest(args))
+			require.NoError(t, err)
+
+			if tc.expectError != "" {
+				assert.Contains(t, getErrorResult(t, result).Text, tc.expectError)
+				return
+			}
+			var asset MinimalReleaseAsset
+			require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &asset))
+			assert.Equal(t, MinimalReleaseAsset{
+				ID:          7,
+				Name:        tc.requestArgs["name"].(string),
+				ContentType: tc.expectedContentType,
+				Size:        len(tc.expectedContent),
+				State:       "uploaded",
+			}, asset)
+		})
+	}
+}
+
+func Test_DeleteReleaseAsset(t *testing.T) {
+	tool, _ := DeleteReleaseAsset(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+	assert.True(t, *tool.Annotations.DestructiveHint)
+
+	mockedClient := mock.NewMockedHTTPClient(
+		mock.WithRequestMatchHandler(
+			mock.DeleteReposReleasesAssetsByOwnerByRepoByAssetId,
+			expectPath(t, "/repos/owner/repo/releases/assets/7").andThen(
+				func(w http.ResponseWriter, _ *http.Request) {
+					w.WriteHeader(http.StatusNoContent)
+				},
+			),
+		),
+	)
+	_, handler := DeleteReleaseAsset(stubGetClientFn(github.NewClient(mockedClient)), translations.NullTranslationHelper)
+	result, err := handler(context.Background(), createMCPRequest(map[string]any{
+		"owner":    "owner",
+		"repo":     "repo",
+		"asset_id": float64(7),
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, "Deleted release asset 7", getTextResult(t, result).Text)
+}
@@ -1,17 +1,24 @@
 package github
 
 import (
+	"bufio"
+	"bytes"
 	"context"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"mime"
 	"net/http"
 	"net/url"
+	"path/filepath"
 	"strings"
+	"unicode/utf8"
 
 	ghErrors "github.com/github/github-mcp-server/pkg/errors"
 	"github.com/github/github-mcp-server/pkg/raw"
+	"github.com/github/github-mcp-server/pkg/sniff"
 	"github.com/github/github-mcp-server/pkg/translations"
 	"github.com/google/go-github/v77/github"
 	"github.com/mark3labs/mcp-go/mcp"
@@ -200,6 +207,231 @@ func ListCommits(getClient GetClientFn, t translations.TranslationHelperFunc) (t
 		}
 }
 
+const (
+	// defaultComparePatchBytes and maxComparePatchBytes bound the size of the patches compare_commits returns
+	defaultComparePatchBytes = 32 * 1024
+	maxComparePatchBytes     = 1024 * 1024
+)
+
+// ComparisonFile is a file changed between two commits.
+type ComparisonFile struct {
+	MinimalCommitFile
+	PreviousFilename string `json:"previous_filename,omitempty"`
+	Patch            string `json:"patch,omitempty"`
+	// PatchByteOffset is the offset of Patch in the whole patch of the file, when it continues a truncated patch
+	PatchByteOffset int `json:"patch_byte_offset,omitempty"`
+	// PatchTruncated is set when the patch alone is larger than the patch budget. The rest of it is returned
+	// by the next call, from next_patch_file and next_patch_byte_offset
+	PatchTruncated bool `json:"patch_truncated,omitempty"`
+}
+
+// CommitComparison is the comparison of two commits.
+type CommitComparison struct {
+	// Status is "ahead", "behind", "diverged" or "identical", from the point of view of head
+	Status       string           `json:"status"`
+	AheadBy      int              `json:"ahead_by"`
+	BehindBy     int              `json:"behind_by"`
+	TotalCommits int              `json:"total_commits"`
+	BaseSHA      string           `json:"base_sha"`
+	MergeBaseSHA string           `json:"merge_base_sha"`
+	HTMLURL      string           `json:"html_url"`
+	Commits      []MinimalCommit  `json:"commits"`
+	Files        []ComparisonFile `json:"files"`
+	Additions    int              `json:"additions"`
+	Deletions    int              `json:"deletions"`
+	// NextPatchFile is the patch_offset that returns the patches that didn't fit in the budget
+	NextPatchFile *int `json:"next_patch_file,omitempty"`
+	// NextPatchByteOffset is the patch_byte_offset that returns the rest of a truncated patch
+	NextPatchByteOffset int `json:"next_patch_byte_offset,omitempty"`
+}
+
+// addComparisonPatches adds the patches of files, starting at byteOffset in the patch of the file at offset,
+// until they reach maxBytes. It returns the file and byte offset to continue from, or nil if all patches fit.
+func addComparisonPatches(files []ComparisonFile, patches []string, offset, byteOffset, maxBytes int) (*int, int) {
+	used := 0
+	for i := offset; i < len(files); i++ {
+		patch := patches[i]
+		start := 0
+		if i == offset {
+			start = min(byteOffset, len(patch))
+			patch = patch[start:]
+		}
+		if patch == "" {
+			continue
+		}
+		if used+len(patch) > maxBytes {
+			if used > 0 {
+				return &i, 0
+			}
+			// A patch larger than the budget is cut at the last line that fits, so each chunk makes progress,
+			// and the next call continues after that line
+			cut := strings.LastIndexByte(patch[:maxBytes], '\n')
+			next := cut + 1
+			if cut <= 0 {
+				cut = maxBytes
+				for cut > 1 && !utf8.RuneStart(patch[cut]) {
+					cut--
+				}
+				next = cut
+			}
+			files[i].Patch = patch[:cut]
+			files[i].PatchByteOffset = start
+			files[i].PatchTruncated = true
+			return &i, start + next
+		}
+		files[i].Patch = patch
+		files[i].PatchByteOffset = start
+		used += len(patch)
+	}
+	return nil, 0
+}
+
+// CompareCommits creates a tool to compare two commits, branches or tags.
+func CompareCommits(getClient GetClientFn, t translations.TranslationHelperFunc) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("compare_commits",
+			mcp.WithDescription(t("TOOL_COMPARE_COMMITS_DESCRIPTION", "Compare two commits, branches or tags of a repository, or of a repository and one of its forks: how far head is ahead and behind base, their merge base, the commits in head that aren't in base, and the changed files with their stats. Patches are optional and returned in chunks of at most max_patch_bytes; pass next_patch_file as patch_offset, and next_patch_byte_offset as patch_byte_offset, to get the next chunk. Files and patches are only returned on the first page of commits.")),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:        t("TOOL_COMPARE_COMMITS_USER_TITLE", "Compare commits"),
+				ReadOnlyHint: ToBoolPtr(true),
+			}),
+			mcp.WithString("owner",
+				mcp.Required(),
+				mcp.Description("Repository owner"),
+			),
+			mcp.WithString("repo",
+				mcp.Required(),
+				mcp.Description("Repository name"),
+			),
+			mcp.WithString("base",
+				mcp.Required(),
+				mcp.Description("Base commit SHA, branch or tag, e.g. `v1.2.0`"),
+			),
+			mcp.WithString("head",
+				mcp.Required(),
+				mcp.Description("Head commit SHA, branch or tag, e.g. `v1.3.0`. Use `{fork_owner}:{branch}` to compare with a branch of a fork"),
+			),
+			mcp.WithBoolean("include_patches",
+				mcp.Description("Include the patches of the changed files. Default is false"),
+				mcp.DefaultBool(false),
+			),
+			mcp.WithNumber("max_patch_bytes",
+				mcp.Description(fmt.Sprintf("Maximum total size of the returned patches in bytes (default %d, max %d)", defaultComparePatchBytes, maxComparePatchBytes)),
+				mcp.Min(1),
+				mcp.Max(maxComparePatchBytes),
+			),
+			mcp.WithNumber("patch_offset",
+				mcp.Description("Index of the first file to return the patch of, from next_patch_file of the previous call"),
+				mcp.Min(0),
+			),
+			mcp.WithNumber("patch_byte_offset",
+				mcp.Description("Byte offset in the patch of the file at patch_offset to continue a truncated patch from, from next_patch_byte_offset of the previous call"),
+				mcp.Min(0),
+			),
+			WithPagination(),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			owner, err := RequiredParam[string](request, "owner")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			repo, err := RequiredParam[string](request, "repo")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			base, err := RequiredParam[string](request, "base")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			head, err := RequiredParam[string](request, "head")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			includePatches, err := OptionalBoolParamWithDefault(request, "include_patches", false)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			maxPatchBytes, err := OptionalIntParamWithDefault(request, "max_patch_bytes", defaultComparePatchBytes)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			patchOffset, err := OptionalIntParam(request, "patch_offset")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			if maxPatchBytes < 1 || maxPatchBytes > maxComparePatchBytes {
+				return mcp.NewToolResultError(fmt.Sprintf("max_patch_bytes must be between 1 and %d", maxComparePatchBytes)), nil
+			}
+			patchByteOffset, err := OptionalIntParam(request, "patch_byte_offset")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			if patchOffset < 0 || patchByteOffset < 0 {
+				return mcp.NewToolResultError("patch_offset and patch_byte_offset must not be negative"), nil
+			}
+			pagination, err := OptionalPaginationParams(request)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			if includePatches && pagination.Page > 1 {
+				// GitHub only lists the changed files on the first page of a comparison
+				return mcp.NewToolResultError("patches are only returned on the first page of commits, call again without page to get them"), nil
+			}
+
+			client, err := getClient(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get GitHub client: %w", err)
+			}
+			comparison, resp, err := client.Repositories.CompareCommits(ctx, owner, repo, base, head, &github.ListOptions{
+				Page:    pagination.Page,
+				PerPage: pagination.PerPage,
+			})
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx,
+					fmt.Sprintf("failed to compare %s...%s", base, head),
+					resp,
+					err,
+				), nil
+			}
+			defer func() { _ = resp.Body.Close() }()
+
+			result := CommitComparison{
+				Status:       comparison.GetStatus(),
+				AheadBy:      comparison.GetAheadBy(),
+				BehindBy:     comparison.GetBehindBy(),
+				TotalCommits: comparison.GetTotalCommits(),
+				BaseSHA:      comparison.GetBaseCommit().GetSHA(),
+				MergeBaseSHA: comparison.GetMergeBaseCommit().GetSHA(),
+				HTMLURL:      comparison.GetHTMLURL(),
+				Commits:      make([]MinimalCommit, 0, len(comparison.Commits)),
+				Files:        make([]ComparisonFile, 0, len(comparison.Files)),
+			}
+			for _, commit := range comparison.Commits {
+				result.Commits = append(result.Commits, convertToMinimalCommit(commit, false))
+			}
+			patches := make([]string, 0, len(comparison.Files))
+			for _, file := range comparison.Files {
+				result.Files = append(result.Files, ComparisonFile{
+					MinimalCommitFile: MinimalCommitFile{
+						Filename:  file.GetFilename(),
+						Status:    file.GetStatus(),
+						Additions: file.GetAdditions(),
+						Deletions: file.GetDeletions(),
+						Changes:   file.GetChanges(),
+					},
+					PreviousFilename: file.GetPreviousFilename(),
+				})
+				patches = append(patches, file.GetPatch())
+				result.Additions += file.GetAdditions()
+				result.Deletions += file.GetDeletions()
+			}
+			if includePatches {
+				result.NextPatchFile, result.NextPatchByteOffset = addComparisonPatches(result.Files, patches, patchOffset, patchByteOffset, maxPatchBytes)
+			}
+
+			return MarshalledTextResult(result), nil
+		}
+}
+
 // ListBranches creates a tool to list branches in a GitHub repository.
 func ListBranches(getClient GetClientFn, t translations.TranslationHelperFunc) (tool mcp.Tool, handler server.ToolHandlerFunc) {
 	return mcp.NewTool("list_branches",
@@ -482,9 +714,12 @@ func CreateRepository(getClient GetClientFn, t translations.TranslationHelperFun
 }
 
 // GetFileContents creates a tool to get the contents of a file or directory from a GitHub repository.
-func GetFileContents(getClient GetClientFn, getRawClient raw.GetRawClientFn, t translations.TranslationHelperFunc) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+func GetFileContents(getClient GetClientFn, getRawClient raw.GetRawClientFn, t translations.TranslationHelperFunc, maxInlineSize int) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	if maxInlineSize <= 0 {
+		maxInlineSize = DefaultMaxInlineSize
+	}
 	return mcp.NewTool("get_file_contents",
-			mcp.WithDescription(t("TOOL_GET_FILE_CONTENTS_DESCRIPTION", "Get the contents of a file or directory from a GitHub repository")),
+			mcp.WithDescription(t("TOOL_GET_FILE_CONTENTS_DESCRIPTION", "Get the contents of a file or directory from a GitHub repository. For large text files, use start_line, end_line and max_bytes to read them in parts. Images are returned as image content, and other known binary formats such as PDFs, archives and SQLite databases as a summary")),
 			mcp.WithToolAnnotation(mcp.ToolAnnotation{
 				Title:        t("TOOL_GET_FILE_CONTENTS_USER_TITLE", "Get file or directory contents"),
 				ReadOnlyHint: ToBoolPtr(true),
@@ -507,6 +742,22 @@ func GetFileContents(getClient GetClientFn, getRawClient raw.GetRawClientFn, t t
 			mcp.WithString("sha",
 				mcp.Description("Accepts optional commit SHA. If specified, it will be used instead of ref"),
 			),
+			mcp.WithNumber("start_line",
+				mcp.Description("First line of a text file to return (1-based). Use with end_line and max_bytes to page through large files"),
+				mcp.Min(1),
+			),
+			mcp.WithNumber("end_line",
+				mcp.Description("Last line of a text file to return (inclusive). Defaults to the end of the file"),
+				mcp.Min(1),
+			),
+			mcp.WithNumber("max_bytes",
+				mcp.Description("Maximum number of bytes of a text file to return. Lines past the limit are left out and the result reports the line to continue from"),
+				mcp.Min(1),
+			),
+			mcp.WithNumber("line_offset",
+				mcp.Description("Byte offset within start_line to continue from, as reported by next_line_offset when max_bytes cut a line short"),
+				mcp.Min(0),
+			),
 		),
 		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			owner, err := RequiredParam[string](request, "owner")
@@ -529,6 +780,29 @@ func GetFileContents(getClient GetClientFn, getRawClient raw.GetRawClientFn, t t
 			if err != nil {
 				return mcp.NewToolResultError(err.Error()), nil
 			}
+			startLine, err := OptionalIntParam(request, "start_line")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			endLine, err := OptionalIntParam(request, "end_line")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			maxBytes, err := OptionalIntParam(request, "max_bytes")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			lineOffset, err := OptionalIntParam(request, "line_offset")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			if startLine < 0 || endLine < 0 || maxBytes < 0 || lineOffset < 0 {
+				return mcp.NewToolResultError("start_line, end_line, max_bytes and line_offset must be positive"), nil
+			}
+			if endLine > 0 && endLine < max(startLine, 1) {
+				return mcp.NewToolResultError("end_line must not be before start_line"), nil
+			}
+			windowed := startLine > 0 || endLine > 0 || maxBytes > 0 || lineOffset > 0
 
 			client, err := getClient(ctx)
 			if err != nil {
@@ -568,7 +842,13 @@ func GetFileContents(getClient GetClientFn, getRawClient raw.GetRawClientFn, t t
 				if err != nil {
 					return mcp.NewToolResultError("failed to get GitHub raw content client"), nil
 				}
-				resp, err := rawClient.GetRawContent(ctx, owner, repo, path, rawOpts)
+				contentOpts := rawOpts
+				if maxBytes > 0 && startLine == 0 && endLine == 0 && lineOffset == 0 && fileContent.GetSize() > maxBytes {
+					// Reading from the start of the file, so only the first max_bytes need to be downloaded.
+					// Line ranges can't be mapped to byte offsets up front, so those read the whole file.
+					contentOpts = &raw.ContentOpts{Ref: rawOpts.Ref, SHA: rawOpts.SHA, Range: &raw.ByteRange{Start: 0, End: int64(maxBytes) - 1}}
+				}
+				resp, err := rawClient.GetRawContent(ctx, owner, repo, path, contentOpts)
 				if err != nil {
 					return mcp.NewToolResultError("failed to get raw repository content"), nil
 				}
@@ -576,63 +856,96 @@ func GetFileContents(getClient GetClientFn, getRawClient raw.GetRawClientFn, t t
 					_ = resp.Body.Close()
 				}()
 
-				if resp.StatusCode == http.StatusOK {
-					// If the raw content is found, return it directly
-					body, err := io.ReadAll(resp.Body)
+				if resp.StatusCode == http.StatusOK || resp.StatusCode == http.StatusPartialContent {
+					contentType := resp.Header.Get("Content-Type")
+					partial := resp.StatusCode == http.StatusPartialContent
+					size := fileContent.GetSize()
+
+					pointer, body, err := raw.DetectLFSPointer(resp.Body)
 					if err != nil {
 						return mcp.NewToolResultError("failed to read response body"), nil
 					}
-					contentType := resp.Header.Get("Content-Type")
-
-					var resourceURI string
-					switch {
-					case sha != "":
-						resourceURI, err = url.JoinPath("repo://", owner, repo, "sha", sha, "contents", path)
-						if err != nil {
-							return nil, fmt.Errorf("failed to create resource URI: %w", err)
-						}
-					case ref != "":
-						resourceURI, err = url.JoinPath("repo://", owner, repo, ref, "contents", path)
-						if err != nil {
-							return nil, fmt.Errorf("failed to create resource URI: %w", err)
-						}
-					default:
-						resourceURI, err = url.JoinPath("repo://", owner, repo, "contents", path)
-						if err != nil {
-							return nil, fmt.Errorf("failed to create resource URI: %w", err)
+					var lfs *LFSFileInfo
+					if pointer != nil {
+						lfs = &LFSFileInfo{OID: pointer.OID, Size: pointer.Size}
+						lfsResp, err := rawClient.GetLFSObject(ctx, owner, repo, pointer)
+						switch {
+						case err == nil:
+							defer func() { _ = lfsResp.Body.Close() }()
+							lfs.Resolved = true
+							body = lfsResp.Body
+							size = int(pointer.Size)
+							contentType = lfsContentType(path, lfsResp)
+						case errors.Is(err, raw.ErrLFSDisabled), errors.Is(err, raw.ErrLFSObjectTooLarge):
+							// Fall back to returning the pointer, so the agent at least learns the file is in LFS
+							lfs.Reason = err.Error()
+						default:
+							return mcp.NewToolResultError(fmt.Sprintf("failed to get git LFS object: %s", err)), nil
 						}
 					}
 
-					// Determine if content is text or binary
-					isTextContent := strings.HasPrefix(contentType, "text/") ||
-						contentType == "application/json" ||
-						contentType == "application/xml" ||
-						strings.HasSuffix(contentType, "+json") ||
-						strings.HasSuffix(contentType, "+xml")
+					resourceURI, err := fileContentsResourceURI(owner, repo, path, ref, sha)
+					if err != nil {
+						return nil, err
+					}
+
+					// Sniff the content rather than trusting the Content-Type header, which the raw
+					// content API sets to text/plain for most files, binary or not.
+					sniffed := bufio.NewReaderSize(body, sniff.SampleSize)
+					sample, err := sniffed.Peek(sniff.SampleSize)
+					if err != nil && !errors.Is(err, io.EOF) {
+						return mcp.NewToolResultError("failed to read response body"), nil
+					}
+					body = sniffed
+					format := sniff.Detect(sample)
+					if format.IsText() != isTextContentType(contentType) {
+						contentType = format.MIMEType
+					}
+
+					if windowed && format.IsText() {
+						return readFileWindow(body, partial, resourceURI, contentType, fileSHA, size, lfs, startLine, lineOffset, endLine, maxBytes)
+					}
+
+					if partial {
+						return mcp.NewToolResultError(fmt.Sprintf("%s is a binary file of %d bytes, which is larger than max_bytes", path, size)), nil
+					}
 
-					if isTextContent {
+					if format.IsText() {
+						// If the raw content is found, return it directly
+						content, err := io.ReadAll(body)
+						if err != nil {
+							return mcp.NewToolResultError("failed to read response body"), nil
+						}
 						result := mcp.TextResourceContents{
 							URI:      resourceURI,
-							Text:     string(body),
+							Text:     string(content),
 							MIMEType: contentType,
 						}
-						// Include SHA in the result metadata
-						if fileSHA != "" {
-							return mcp.NewToolResultResource(fmt.Sprintf("successfully downloaded text file (SHA: %s)", fileSHA), result), nil
-						}
-						return mcp.NewToolResultResource("successfully downloaded text file", result), nil
+						return mcp.NewToolResultResource(fileDownloadMessage("text", fileSHA, lfs), result), nil
 					}
 
-					result := mcp.BlobResourceContents{
-						URI:      resourceURI,
-						Blob:     base64.StdEncoding.EncodeToString(body),
-						MIMEType: contentType,
-					}
-					// Include SHA in the result metadata
-					if fileSHA != "" {
-						return mcp.NewToolResultResource(fmt.Sprintf("successfully downloaded binary file (SHA: %s)", fileSHA), result), nil
+					var readTail func(n int) ([]byte, error)
+					if lfs == nil || !lfs.Resolved {
+						// Fetch the end of the file with a range request rather than downloading all of it
+						readTail = func(n int) ([]byte, error) {
+							tailOpts := &raw.ContentOpts{Ref: rawOpts.Ref, SHA: rawOpts.SHA, Range: &raw.ByteRange{Start: int64(size - n), End: -1}}
+							tailResp, err := rawClient.GetRawContent(ctx, owner, repo, path, tailOpts)
+							if err != nil {
+								return nil, err
+							}
+							defer func() { _ = tailResp.Body.Close() }()
+							switch tailResp.StatusCode {
+							case http.StatusPartialContent:
+								return io.ReadAll(io.LimitReader(tailResp.Body, int64(n)))
+							case http.StatusOK:
+								// The range was ignored
+								return readLastBytes(tailResp.Body, n)
+							default:
+								return nil, fmt.Errorf("raw content API returned status %d", tailResp.StatusCode)
+							}
+						}
 					}
-					return mcp.NewToolResultResource("successfully downloaded binary file", result), nil
+					return binaryFileResult(body, format, contentType, size, resourceURI, fileSHA, lfs, maxInlineSize, readTail), nil
 				}
 				rawAPIResponseCode = resp.StatusCode
 			}
@@ -686,6 +999,246 @@ func GetFileContents(getClient GetClientFn, getRawClient raw.GetRawClientFn, t t
 		}
 }
 
+// isTextContentType reports whether a raw content response with the given Content-Type holds text.
+func isTextContentType(contentType string) bool {
+	return strings.HasPrefix(contentType, "text/") ||
+		contentType == "application/json" ||
+		contentType == "application/xml" ||
+		strings.HasSuffix(contentType, "+json") ||
+		strings.HasSuffix(contentType, "+xml")
+}
+
+// fileContentsResourceURI returns the repo:// URI of a file, matching the repository resource templates.
+func fileContentsResourceURI(owner, repo, path, ref, sha string) (string, error) {
+	var resourceURI string
+	var err error
+	switch {
+	case sha != "":
+		resourceURI, err = url.JoinPath("repo://", owner, repo, "sha", sha, "contents", path)
+	case ref != "":
+		resourceURI, err = url.JoinPath("repo://", owner, repo, ref, "contents", path)
+	default:
+		resourceURI, err = url.JoinPath("repo://", owner, repo, "contents", path)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to create resource URI: %w", err)
+	}
+	return resourceURI, nil
+}
+
+// DefaultMaxInlineSize is the default size limit for binary files returned inline by get_file_contents.
+const DefaultMaxInlineSize = 1024 * 1024
+
+// BinaryFileSummary describes a binary file that get_file_contents returns without its content.
+type BinaryFileSummary struct {
+	SHA string `json:"sha,omitempty"`
+	*sniff.Summary
+	// Note explains why the content was left out, if it wasn't only because of its format
+	Note string `json:"note,omitempty"`
+	// LFS is set when the file is stored in Git LFS
+	LFS *LFSFileInfo `json:"lfs,omitempty"`
+}
+
+// binaryFileResult returns a binary file as image content, a summary or a blob resource, depending on
+// its format and size. Files are only read up to maxInlineSize; larger files are summarized from their
+// beginning, which is where most formats keep their metadata, except for zip files, which are listed from
+// their end. readTail fetches the last n bytes of the file; when nil, the rest of body is read instead.
+func binaryFileResult(body io.Reader, format sniff.Format, contentType string, size int, resourceURI, fileSHA string, lfs *LFSFileInfo, maxInlineSize int, readTail func(n int) ([]byte, error)) *mcp.CallToolResult {
+	content, err := io.ReadAll(io.LimitReader(body, int64(maxInlineSize)+1))
+	if err != nil {
+		return mcp.NewToolResultError("failed to read response body")
+	}
+	tooLarge := len(content) > maxInlineSize
+
+	var summary *sniff.Summary
+	if tooLarge && format.Name == "zip" {
+		// The central directory that lists the entries of a zip file is at its end
+		n := min(maxInlineSize, size)
+		var tail []byte
+		if readTail != nil {
+			tail, err = readTail(n)
+		} else {
+			tail, err = readLastBytes(io.MultiReader(bytes.NewReader(content), body), n)
+		}
+		if err != nil {
+			summary = &sniff.Summary{Format: format, Size: int64(size), Error: fmt.Sprintf("no listing is available, failed to read the end of the zip file: %s", err)}
+		} else {
+			summary = sniff.SummarizeTail(format, tail, int64(size))
+		}
+	}
+	if tooLarge {
+		content = content[:maxInlineSize]
+	}
+	if summary == nil {
+		summary = sniff.Summarize(format, content, int64(size))
+	}
+
+	if format.IsImage() && !tooLarge {
+		return mcp.NewToolResultImage(imageDownloadMessage(fileSHA, lfs, summary), base64.StdEncoding.EncodeToString(content), format.MIMEType)
+	}
+	if tooLarge || format.Name != "binary" {
+		// Known formats are better described by their summary than by a blob the model can't read
+		result := BinaryFileSummary{SHA: fileSHA, Summary: summary, LFS: lfs}
+		if tooLarge {
+			result.Note = fmt.Sprintf("content omitted because the file is larger than the inline size limit of %d bytes", maxInlineSize)
+		}
+		return MarshalledTextResult(result)
+	}
+
+	return mcp.NewToolResultResource(fileDownloadMessage("binary", fileSHA, lfs), mcp.BlobResourceContents{
+		URI:      resourceURI,
+		Blob:     base64.StdEncoding.EncodeToString(content),
+		MIMEType: contentType,
+	})
+}
+
+// readLastBytes reads r to the end and returns its last n bytes.
+func readLastBytes(r io.Reader, n int) ([]byte, error) {
+	buf := make([]byte, 32*1024)
+	var tail []byte
+	for {
+		m, err := r.Read(buf)
+		tail = append(tail, buf[:m]...)
+		if len(tail) > n {
+			tail = tail[len(tail)-n:]
+		}
+		if errors.Is(err, io.EOF) {
+			return tail, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// imageDownloadMessage describes a downloaded image for the text content of get_file_contents results.
+func imageDownloadMessage(fileSHA string, lfs *LFSFileInfo, summary *sniff.Summary) string {
+	message := fileDownloadMessage("image", fileSHA, lfs)
+	if summary.Width > 0 && summary.Height > 0 {
+		message += fmt.Sprintf(", %s image of %dx%d pixels", summary.Name, summary.Width, summary.Height)
+	}
+	return message
+}
+
+// LFSFileInfo describes a file stored in Git LFS.
+type LFSFileInfo struct {
+	OID  string `json:"oid"`
+	Size int64  `json:"size"`
+	// Resolved is set when the result holds the LFS object rather than the pointer file
+	Resolved bool `json:"resolved"`
+	// Reason explains why the LFS object was not downloaded
+	Reason string `json:"reason,omitempty"`
+}
+
+// fileDownloadMessage describes a downloaded file for the text content of get_file_contents results.
+func fileDownloadMessage(kind, fileSHA string, lfs *LFSFileInfo) string {
+	switch {
+	case lfs == nil && fileSHA == "":
+		return fmt.Sprintf("successfully downloaded %s file", kind)
+	case lfs == nil:
+		return fmt.Sprintf("successfully downloaded %s file (SHA: %s)", kind, fileSHA)
+	case lfs.Resolved:
+		return fmt.Sprintf("successfully downloaded %s file from Git LFS (SHA: %s, LFS OID: %s, size: %d bytes)", kind, fileSHA, lfs.OID, lfs.Size)
+	default:
+		return fmt.Sprintf("file is stored in Git LFS (SHA: %s, LFS OID: %s, size: %d bytes), returning the LFS pointer instead of its content: %s", fileSHA, lfs.OID, lfs.Size, lfs.Reason)
+	}
+}
+
+// lfsContentType guesses the content type of an LFS object, since the storage
+// it is downloaded from usually doesn't know it.
+func lfsContentType(path string, resp *http.Response) string {
+	if contentType := mime.TypeByExtension(filepath.Ext(path)); contentType != "" {
+		return contentType
+	}
+	if contentType := resp.Header.Get("Content-Type"); contentType != "" {
+		return contentType
+	}
+	return "application/octet-stream"
+}
+
+// FileContentsWindow describes the part of a text file returned by get_file_contents
+// when start_line, end_line or max_bytes are set.
+type FileContentsWindow struct {
+	SHA string `json:"sha,omitempty"`
+	// Size of the whole file in bytes
+	Size int `json:"size"`
+	// TotalLines is omitted when only the beginning of the file was downloaded
+	TotalLines int `json:"total_lines,omitempty"`
+	StartLine  int `json:"start_line"`
+	EndLine    int `json:"end_line"`
+	// Truncated is set when max_bytes cut the window short
+	Truncated bool `json:"truncated"`
+	// NextStartLine is the start_line to request the rest of the file with, if any
+	NextStartLine int `json:"next_start_line,omitempty"`
+	// NextLineOffset is set when max_bytes cut the last line short: it is the line_offset to
+	// request the rest of the file with, along with NextStartLine
+	NextLineOffset int `json:"next_line_offset,omitempty"`
+	// LFS is set when the file is stored in Git LFS
+	LFS *LFSFileInfo `json:"lfs,omitempty"`
+}
+
+// readFileWindow returns the requested lines of a text file. partial is set when body
+// only holds the beginning of the file, because of a range request.
+func readFileWindow(body io.Reader, partial bool, resourceURI, contentType, fileSHA string, size int, lfs *LFSFileInfo, startLine, lineOffset, endLine, maxBytes int) (*mcp.CallToolResult, error) {
+	firstLineCut := false
+	if partial {
+		// Only the first max_bytes of the file were downloaded, so drop the final line
+		// if it was cut off, unless it's the only one.
+		data, err := io.ReadAll(body)
+		if err != nil {
+			return mcp.NewToolResultError("failed to read response body"), nil
+		}
+		if i := bytes.LastIndexByte(data, '\n'); i >= 0 {
+			data = data[:i+1]
+		} else {
+			firstLineCut = true
+		}
+		body = bytes.NewReader(data)
+	}
+
+	window, err := raw.ReadLineWindow(body, startLine, lineOffset, endLine, maxBytes)
+	if err != nil {
+		return mcp.NewToolResultError("failed to read response body"), nil
+	}
+	if firstLineCut && window.EndLineOffset == 0 {
+		// The download stopped in the middle of the first line
+		window.EndLineOffset = len(window.Text)
+	}
+	if !partial && window.TotalLines > 0 && window.StartLine > window.TotalLines {
+		return mcp.NewToolResultError(fmt.Sprintf("start_line %d is past the end of the file, which has %d lines", window.StartLine, window.TotalLines)), nil
+	}
+
+	meta := FileContentsWindow{
+		SHA:       fileSHA,
+		Size:      size,
+		StartLine: window.StartLine,
+		EndLine:   window.EndLine,
+		Truncated: window.Truncated || partial,
+		LFS:       lfs,
+	}
+	if !partial {
+		meta.TotalLines = window.TotalLines
+	}
+	switch {
+	case window.EndLineOffset > 0:
+		// Continue from the unread rest of the last line
+		meta.NextStartLine = window.EndLine
+		meta.NextLineOffset = window.EndLineOffset
+	case meta.Truncated || window.EndLine < window.TotalLines:
+		meta.NextStartLine = window.EndLine + 1
+	}
+
+	r, err := json.Marshal(meta)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal response: %w", err)
+	}
+	return mcp.NewToolResultResource(string(r), mcp.TextResourceContents{
+		URI:      resourceURI,
+		Text:     window.Text,
+		MIMEType: contentType,
+	}), nil
+}
+
 // ForkRepository creates a tool to fork a repository.
 func ForkRepository(getClient GetClientFn, t translations.TranslationHelperFunc) (tool mcp.Tool, handler server.ToolHandlerFunc) {
 	return mcp.NewTool("fork_repository",
@@ -1205,11 +1758,15 @@ func PushFiles(getClient GetClientFn, t translations.TranslationHelperFunc) (too
 				Force: github.Ptr(false),
 			})
 			if err != nil {
-				return ghErrors.NewGitHubAPIErrorResponse(ctx,
+				errResult := ghErrors.NewGitHubAPIErrorResponse(ctx,
 					"failed to update reference",
 					resp,
 					err,
-				), nil
+				)
+				if resp != nil && isRuleViolationStatus(resp.StatusCode) {
+					errResult = withBlockingRulesHint(ctx, client, owner, repo, branch, errResult)
+				}
+				return errResult, nil
 			}
 			defer func() { _ = resp.Body.Close() }()
 
@@ -1,9 +1,14 @@
 package github
 
 import (
+	"archive/zip"
+	"bytes"
 	"context"
 	"encoding/base64"
 	"encoding/json"
+	"fmt"
+	"image"
+	"image/png"
 	"net/http"
 	"net/url"
 	"strings"
@@ -12,6 +17,7 @@ import (
 
 	"github.com/github/github-mcp-server/internal/toolsnaps"
 	"github.com/github/github-mcp-server/pkg/raw"
+	"github.com/github/github-mcp-server/pkg/sniff"
 	"github.com/github/github-mcp-server/pkg/translations"
 	"github.com/google/go-github/v77/github"
 	"github.com/mark3labs/mcp-go/mcp"
@@ -24,7 +30,7 @@ func Test_GetFileContents(t *testing.T) {
 	// Verify tool definition once
 	mockClient := github.NewClient(nil)
 	mockRawClient := raw.NewClient(mockClient, &url.URL{Scheme: "https", Host: "raw.githubusercontent.com", Path: "/"})
-	tool, _ := GetFileContents(stubGetClientFn(mockClient), stubGetRawClientFn(mockRawClient), translations.NullTranslationHelper)
+	tool, _ := GetFileContents(stubGetClientFn(mockClient), stubGetRawClientFn(mockRawClient), translations.NullTranslationHelper, DefaultMaxInlineSize)
 	require.NoError(t, toolsnaps.Test(tool.Name, tool))
 
 	assert.Equal(t, "get_file_contents", tool.Name)
@@ -38,6 +44,11 @@ func Test_GetFileContents(t *testing.T) {
 
 	// Mock response for raw content
 	mockRawContent := []byte("# Test Repository\n\nThis is a test repository.")
+	mockBinaryContent := []byte("\x00\x01\x02\x03 unknown binary format")
+	var pngBuf bytes.Buffer
+	require.NoError(t, png.Encode(&pngBuf, image.NewGray(image.Rect(0, 0, 16, 8))))
+	mockPNGContent := pngBuf.Bytes()
+	mockPDFContent := []byte("%PDF-1.4\n1 0 obj << /Type /Pages /Kids [2 0 R 3 0 R] /Count 2 >> endobj\n%%EOF\n")
 
 	// Setup mock directory content for success case
 	mockDirContent := []*github.RepositoryContent{
@@ -127,8 +138,8 @@ func Test_GetFileContents(t *testing.T) {
 					http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
 						w.WriteHeader(http.StatusOK)
 						fileContent := &github.RepositoryContent{
-							Name: github.Ptr("test.png"),
-							Path: github.Ptr("test.png"),
+							Name: github.Ptr("data.bin"),
+							Path: github.Ptr("data.bin"),
 							SHA:  github.Ptr("def456"),
 							Type: github.Ptr("file"),
 						}
@@ -139,21 +150,62 @@ func Test_GetFileContents(t *testing.T) {
 				mock.WithRequestMatchHandler(
 					raw.GetRawReposContentsByOwnerByRepoByBranchByPath,
 					http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
-						w.Header().Set("Content-Type", "image/png")
-						_, _ = w.Write(mockRawContent)
+						w.Header().Set("Content-Type", "application/octet-stream")
+						_, _ = w.Write(mockBinaryContent)
 					}),
 				),
 			),
 			requestArgs: map[string]interface{}{
 				"owner": "owner",
 				"repo":  "repo",
-				"path":  "test.png",
+				"path":  "data.bin",
 				"ref":   "refs/heads/main",
 			},
 			expectError: false,
 			expectedResult: mcp.BlobResourceContents{
-				URI:      "repo://owner/repo/refs/heads/main/contents/test.png",
-				Blob:     base64.StdEncoding.EncodeToString(mockRawContent),
+				URI:      "repo://owner/repo/refs/heads/main/contents/data.bin",
+				Blob:     base64.StdEncoding.EncodeToString(mockBinaryContent),
+				MIMEType: "application/octet-stream",
+			},
+		},
+		{
+			name: "image returned as image content despite text content type",
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(
+					mock.GetReposGitRefByOwnerByRepoByRef,
+					http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+						w.WriteHeader(http.StatusOK)
+						_, _ = w.Write([]byte(`{"ref": "refs/heads/main", "object": {"sha": ""}}`))
+					}),
+				),
+				mock.WithRequestMatchHandler(
+					mock.GetReposContentsByOwnerByRepoByPath,
+					mockResponse(t, http.StatusOK, &github.RepositoryContent{
+						Name: github.Ptr("logo"),
+						Path: github.Ptr("logo"),
+						SHA:  github.Ptr("img123"),
+						Type: github.Ptr("file"),
+						Size: github.Ptr(len(mockPNGContent)),
+					}),
+				),
+				mock.WithRequestMatchHandler(
+					raw.GetRawReposContentsByOwnerByRepoByBranchByPath,
+					http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+						w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+						_, _ = w.Write(mockPNGContent)
+					}),
+				),
+			),
+			requestArgs: map[string]interface{}{
+				"owner": "owner",
+				"repo":  "repo",
+				"path":  "logo",
+				"ref":   "refs/heads/main",
+			},
+			expectError: false,
+			expectedResult: mcp.ImageContent{
+				Type:     "image",
+				Data:     base64.StdEncoding.EncodeToString(mockPNGContent),
 				MIMEType: "image/png",
 			},
 		},
@@ -185,7 +237,7 @@ func Test_GetFileContents(t *testing.T) {
 					raw.GetRawReposContentsByOwnerByRepoByBranchByPath,
 					http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
 						w.Header().Set("Content-Type", "application/pdf")
-						_, _ = w.Write(mockRawContent)
+						_, _ = w.Write(mockPDFContent)
 					}),
 				),
 			),
@@ -196,10 +248,12 @@ func Test_GetFileContents(t *testing.T) {
 				"ref":   "refs/heads/main",
 			},
 			expectError: false,
-			expectedResult: mcp.BlobResourceContents{
-				URI:      "repo://owner/repo/refs/heads/main/contents/document.pdf",
-				Blob:     base64.StdEncoding.EncodeToString(mockRawContent),
-				MIMEType: "application/pdf",
+			expectedResult: BinaryFileSummary{
+				SHA: "pdf123",
+				Summary: &sniff.Summary{
+					Format: sniff.Format{Name: "pdf", MIMEType: "application/pdf"},
+					Pages:  2,
+				},
 			},
 		},
 		{
@@ -283,7 +337,7 @@ func Test_GetFileContents(t *testing.T) {
 			// Setup client with mock
 			client := github.NewClient(tc.mockedClient)
 			mockRawClient := raw.NewClient(client, &url.URL{Scheme: "https", Host: "raw.example.com", Path: "/"})
-			_, handler := GetFileContents(stubGetClientFn(client), stubGetRawClientFn(mockRawClient), translations.NullTranslationHelper)
+			_, handler := GetFileContents(stubGetClientFn(client), stubGetRawClientFn(mockRawClient), translations.NullTranslationHelper, DefaultMaxInlineSize)
 
 			// Create call request
 			request := createMCPRequest(tc.requestArgs)
@@ -319,6 +373,15 @@ func Test_GetFileContents(t *testing.T) {
 					assert.Equal(t, *expected[i].Path, *content.Path)
 					assert.Equal(t, *expected[i].Type, *content.Type)
 				}
+			case mcp.ImageContent:
+				require.Len(t, result.Content, 2)
+				assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "png image of 16x8 pixels")
+				assert.Equal(t, expected, result.Content[1])
+			case BinaryFileSummary:
+				textContent := getTextResult(t, result)
+				var summary BinaryFileSummary
+				require.NoError(t, json.Unmarshal([]byte(textContent.Text), &summary))
+				assert.Equal(t, expected, summary)
 			case mcp.TextContent:
 				textContent := getErrorResult(t, result)
 				require.Equal(t, textContent, expected)
@@ -327,6 +390,430 @@ func Test_GetFileContents(t *testing.T) {
 	}
 }
 
+func Test_GetFileContentsLongLine(t *testing.T) {
+	content := strings.Repeat("x", 30) + strings.Repeat("y", 20)
+
+	newClient := func(t *testing.T, expectedRange string) *http.Client {
+		return mock.NewMockedHTTPClient(
+			mock.WithRequestMatchHandler(
+				mock.GetReposGitRefByOwnerByRepoByRef,
+				mockResponse(t, http.StatusOK, `{"ref": "refs/heads/main", "object": {"sha": ""}}`),
+			),
+			mock.WithRequestMatchHandler(
+				mock.GetReposContentsByOwnerByRepoByPath,
+				mockResponse(t, http.StatusOK, &github.RepositoryContent{
+					Name: github.Ptr("bundle.min.js"),
+					Path: github.Ptr("bundle.min.js"),
+					SHA:  github.Ptr("abc123"),
+					Type: github.Ptr("file"),
+					Size: github.Ptr(len(content)),
+				}),
+			),
+			mock.WithRequestMatchHandler(
+				raw.GetRawReposContentsByOwnerByRepoByBranchByPath,
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					assert.Equal(t, expectedRange, r.Header.Get("Range"))
+					w.Header().Set("Content-Type", "text/plain")
+					if expectedRange != "" {
+						w.WriteHeader(http.StatusPartialContent)
+						_, _ = w.Write([]byte(content[:20]))
+						return
+					}
+					_, _ = w.Write([]byte(content))
+				}),
+			),
+		)
+	}
+
+	tests := []struct {
+		name          string
+		requestArgs   map[string]any
+		expectedRange string
+		expectedText  string
+		expectedMeta  FileContentsWindow
+	}{
+		{
+			name:          "first bytes of the line",
+			requestArgs:   map[string]any{"max_bytes": float64(20)},
+			expectedRange: "bytes=0-19",
+			expectedText:  strings.Repeat("x", 20),
+			expectedMeta:  FileContentsWindow{SHA: "abc123", Size: len(content), StartLine: 1, EndLine: 1, Truncated: true, NextStartLine: 1, NextLineOffset: 20},
+		},
+		{
+			name:         "middle of the line",
+			requestArgs:  map[string]any{"start_line": float64(1), "line_offset": float64(20), "max_bytes": float64(20)},
+			expectedText: strings.Repeat("x", 10) + strings.Repeat("y", 10),
+			expectedMeta: FileContentsWindow{SHA: "abc123", Size: len(content), TotalLines: 1, StartLine: 1, EndLine: 1, Truncated: true, NextStartLine: 1, NextLineOffset: 40},
+		},
+		{
+			name:         "rest of the line",
+			requestArgs:  map[string]any{"start_line": float64(1), "line_offset": float64(40), "max_bytes": float64(20)},
+			expectedText: strings.Repeat("y", 10),
+			expectedMeta: FileContentsWindow{SHA: "abc123", Size: len(content), TotalLines: 1, StartLine: 1, EndLine: 1},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			client := github.NewClient(newClient(t, tc.expectedRange))
+			rawClient := raw.NewClient(client, &url.URL{Scheme: "https", Host: "raw.example.com", Path: "/"})
+			_, handler := GetFileContents(stubGetClientFn(client), stubGetRawClientFn(rawClient), translations.NullTranslationHelper, DefaultMaxInlineSize)
+
+			args := map[string]any{
+				"owner": "owner",
+				"repo":  "repo",
+				"path":  "bundle.min.js",
+				"ref":   "refs/heads/main",
+			}
+			for k, v := range tc.requestArgs {
+				args[k] = v
+			}
+			result, err := handler(context.Background(), createMCPRequest(args))
+			require.NoError(t, err)
+
+			require.False(t, result.IsError)
+			require.Len(t, result.Content, 2)
+			metaContent, ok := result.Content[0].(mcp.TextContent)
+			require.True(t, ok)
+			var meta FileContentsWindow
+			require.NoError(t, json.Unmarshal([]byte(metaContent.Text), &meta))
+			assert.Equal(t, tc.expectedMeta, meta)
+			assert.Equal(t, tc.expectedText, getTextResourceResult(t, result).Text)
+		})
+	}
+}
+
+func Test_GetFileContentsLineRanges(t *testing.T) {
+	var lines []string
+	for i := 1; i <= 100; i++ {
+		lines = append(lines, fmt.Sprintf("line %d", i))
+	}
+	content := strings.Join(lines, "\n") + "\n"
+
+	newClient := func(t *testing.T, expectedRange string) *http.Client {
+		return mock.NewMockedHTTPClient(
+			mock.WithRequestMatchHandler(
+				mock.GetReposGitRefByOwnerByRepoByRef,
+				mockResponse(t, http.StatusOK, `{"ref": "refs/heads/main", "object": {"sha": ""}}`),
+			),
+			mock.WithRequestMatchHandler(
+				mock.GetReposContentsByOwnerByRepoByPath,
+				mockResponse(t, http.StatusOK, &github.RepositoryContent{
+					Name: github.Ptr("gen.go"),
+					Path: github.Ptr("gen.go"),
+					SHA:  github.Ptr("abc123"),
+					Type: github.Ptr("file"),
+					Size: github.Ptr(len(content)),
+				}),
+			),
+			mock.WithRequestMatchHandler(
+				raw.GetRawReposContentsByOwnerByRepoByBranchByPath,
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					assert.Equal(t, expectedRange, r.Header.Get("Range"))
+					w.Header().Set("Content-Type", "text/plain")
+					if expectedRange != "" {
+						w.WriteHeader(http.StatusPartialContent)
+						_, _ = w.Write([]byte(content[:20]))
+						return
+					}
+					_, _ = w.Write([]byte(content))
+				}),
+			),
+		)
+	}
+
+	tests := []struct {
+		name          string
+		requestArgs   map[string]any
+		expectedRange string
+		expectedText  string
+		expectedMeta  FileContentsWindow
+		expectedErr   string
+	}{
+		{
+			name:         "line range",
+			requestArgs:  map[string]any{"start_line": float64(10), "end_line": float64(12)},
+			expectedText: "line 10\nline 11\nline 12\n",
+			expectedMeta: FileContentsWindow{SHA: "abc123", Size: len(content), TotalLines: 100, StartLine: 10, EndLine: 12, NextStartLine: 13},
+		},
+		{
+			name:         "last lines",
+			requestArgs:  map[string]any{"start_line": float64(99)},
+			expectedText: "line 99\nline 100\n",
+			expectedMeta: FileContentsWindow{SHA: "abc123", Size: len(content), TotalLines: 100, StartLine: 99, EndLine: 100},
+		},
+		{
+			name:         "line range capped by max_bytes",
+			requestArgs:  map[string]any{"start_line": float64(50), "max_bytes": float64(20)},
+			expectedText: "line 50\nline 51\n",
+			expectedMeta: FileContentsWindow{SHA: "abc123", Size: len(content), TotalLines: 100, StartLine: 50, EndLine: 51, Truncated: true, NextStartLine: 52},
+		},
+		{
+			name:          "max_bytes from the start uses a range request",
+			requestArgs:   map[string]any{"max_bytes": float64(20)},
+			expectedRange: "bytes=0-19",
+			expectedText:  "line 1\nline 2\n",
+			expectedMeta:  FileContentsWindow{SHA: "abc123", Size: len(content), StartLine: 1, EndLine: 2, Truncated: true, NextStartLine: 3},
+		},
+		{
+			name:        "start past the end",
+			requestArgs: map[string]any{"start_line": float64(101)},
+			expectedErr: "start_line 101 is past the end of the file, which has 100 lines",
+		},
+		{
+			name:        "end before start",
+			requestArgs: map[string]any{"start_line": float64(10), "end_line": float64(5)},
+			expectedErr: "end_line must not be before start_line",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			client := github.NewClient(newClient(t, tc.expectedRange))
+			rawClient := raw.NewClient(client, &url.URL{Scheme: "https", Host: "raw.example.com", Path: "/"})
+			_, handler := GetFileContents(stubGetClientFn(client), stubGetRawClientFn(rawClient), translations.NullTranslationHelper, DefaultMaxInlineSize)
+
+			args := map[string]any{
+				"owner": "owner",
+				"repo":  "repo",
+				"path":  "gen.go",
+				"ref":   "refs/heads/main",
+			}
+			for k, v := range tc.requestArgs {
+				args[k] = v
+			}
+			result, err := handler(context.Background(), createMCPRequest(args))
+			require.NoError(t, err)
+
+			if tc.expectedErr != "" {
+				assert.Equal(t, tc.expectedErr, getErrorResult(t, result).Text)
+				return
+			}
+
+			require.False(t, result.IsError)
+			require.Len(t, result.Content, 2)
+			metaContent, ok := result.Content[0].(mcp.TextContent)
+			require.True(t, ok)
+			var meta FileContentsWindow
+			require.NoError(t, json.Unmarshal([]byte(metaContent.Text), &meta))
+			assert.Equal(t, tc.expectedMeta, meta)
+			assert.Equal(t, tc.expectedText, getTextResourceResult(t, result).Text)
+		})
+	}
+}
+
+func Test_GetFileContentsLFS(t *testing.T) {
+	oid := "4d7a214614ab2935c943f9e0ff69d22eadbb8f32b1258daaa5e2ca24d17e2393"
+	pointer := "version https://git-lfs.github.com/spec/v1\noid sha256:" + oid + "\nsize 11\n"
+
+	mockedClient := mock.NewMockedHTTPClient(
+		mock.WithRequestMatchHandler(
+			mock.GetReposGitRefByOwnerByRepoByRef,
+			mockResponse(t, http.StatusOK, `{"ref": "refs/heads/main", "object": {"sha": ""}}`),
+		),
+		mock.WithRequestMatchHandler(
+			mock.GetReposContentsByOwnerByRepoByPath,
+			mockResponse(t, http.StatusOK, &github.RepositoryContent{
+				Name: github.Ptr("model.json"),
+				Path: github.Ptr("model.json"),
+				SHA:  github.Ptr("abc123"),
+				Type: github.Ptr("file"),
+				Size: github.Ptr(len(pointer)),
+			}),
+		),
+		mock.WithRequestMatchHandler(
+			raw.GetRawReposContentsByOwnerByRepoByBranchByPath,
+			http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+				_, _ = w.Write([]byte(pointer))
+			}),
+		),
+		mock.WithRequestMatchHandler(
+			raw.PostLFSObjectsBatchByOwnerByRepo,
+			mockResponse(t, http.StatusOK, `{"objects": [{"oid": "`+oid+`", "size": 11, "actions": {"download": {"href": "https://lfs.example.com/lfs-objects/`+oid+`"}}}]}`),
+		),
+		mock.WithRequestMatchHandler(
+			mock.EndpointPattern{Pattern: "/lfs-objects/{oid}", Method: "GET"},
+			http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("Content-Type", "application/octet-stream")
+				_, _ = w.Write([]byte(`{"a": true}`))
+			}),
+		),
+	)
+	requestArgs := map[string]any{
+		"owner": "owner",
+		"repo":  "repo",
+		"path":  "model.json",
+		"ref":   "refs/heads/main",
+	}
+
+	tests := []struct {
+		name            string
+		maxSize         int64
+		expectedMessage string
+		expectedText    string
+		expectedMIME    string
+	}{
+		{
+			name:            "resolves pointer",
+			maxSize:         raw.DefaultLFSMaxSize,
+			expectedMessage: "successfully downloaded text file from Git LFS (SHA: abc123, LFS OID: " + oid + ", size: 11 bytes)",
+			expectedText:    `{"a": true}`,
+			expectedMIME:    "application/json",
+		},
+		{
+			name:            "object larger than the limit",
+			maxSize:         10,
+			expectedMessage: "file is stored in Git LFS (SHA: abc123, LFS OID: " + oid + ", size: 11 bytes), returning the LFS pointer instead of its content: git LFS object is larger than the size limit: 11 bytes, limit is 10 bytes",
+			expectedText:    pointer,
+			expectedMIME:    "text/plain; charset=utf-8",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			client := github.NewClient(mockedClient)
+			serverURL, _ := url.Parse("https://github.example.com/")
+			rawClient := raw.NewClient(client, &url.URL{Scheme: "https", Host: "raw.example.com", Path: "/"}).WithLFS(raw.LFSOptions{
+				ServerURL:      serverURL,
+				MaxSize:        tc.maxSize,
+				DownloadClient: mockedClient,
+			})
+			_, handler := GetFileContents(stubGetClientFn(client), stubGetRawClientFn(rawClient), translations.NullTranslationHelper, DefaultMaxInlineSize)
+
+			result, err := handler(context.Background(), createMCPRequest(requestArgs))
+			require.NoError(t, err)
+			require.False(t, result.IsError)
+			require.Len(t, result.Content, 2)
+			message, ok := result.Content[0].(mcp.TextContent)
+			require.True(t, ok)
+			assert.Equal(t, tc.expectedMessage, message.Text)
+			resource := getTextResourceResult(t, result)
+			assert.Equal(t, tc.expectedText, resource.Text)
+			assert.Equal(t, tc.expectedMIME, resource.MIMEType)
+		})
+	}
+}
+
+func Test_GetFileContentsInlineSizeLimit(t *testing.T) {
+	var pngBuf bytes.Buffer
+	require.NoError(t, png.Encode(&pngBuf, image.NewGray(image.Rect(0, 0, 300, 200))))
+	content := pngBuf.Bytes()
+
+	mockedClient := mock.NewMockedHTTPClient(
+		mock.WithRequestMatchHandler(
+			mock.GetReposGitRefByOwnerByRepoByRef,
+			mockResponse(t, http.StatusOK, `{"ref": "refs/heads/main", "object": {"sha": ""}}`),
+		),
+		mock.WithRequestMatchHandler(
+			mock.GetReposContentsByOwnerByRepoByPath,
+			mockResponse(t, http.StatusOK, &github.RepositoryContent{
+				Name: github.Ptr("screenshot.png"),
+				Path: github.Ptr("screenshot.png"),
+				SHA:  github.Ptr("abc123"),
+				Type: github.Ptr("file"),
+				Size: github.Ptr(len(content)),
+			}),
+		),
+		mock.WithRequestMatchHandler(
+			raw.GetRawReposContentsByOwnerByRepoByBranchByPath,
+			http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("Content-Type", "image/png")
+				_, _ = w.Write(content)
+			}),
+		),
+	)
+	client := github.NewClient(mockedClient)
+	rawClient := raw.NewClient(client, &url.URL{Scheme: "https", Host: "raw.example.com", Path: "/"})
+	_, handler := GetFileContents(stubGetClientFn(client), stubGetRawClientFn(rawClient), translations.NullTranslationHelper, len(content)-1)
+
+	result, err := handler(context.Background(), createMCPRequest(map[string]any{
+		"owner": "owner",
+		"repo":  "repo",
+		"path":  "screenshot.png",
+		"ref":   "refs/heads/main",
+	}))
+	require.NoError(t, err)
+
+	// The image is larger than the limit, so only its summary is returned
+	textContent := getTextResult(t, result)
+	var summary BinaryFileSummary
+	require.NoError(t, json.Unmarshal([]byte(textContent.Text), &summary))
+	assert.Equal(t, BinaryFileSummary{
+		SHA: "abc123",
+		Summary: &sniff.Summary{
+			Format: sniff.Format{Name: "png", MIMEType: "image/png"},
+			Size:   int64(len(content)),
+			Width:  300,
+			Height: 200,
+		},
+		Note: fmt.Sprintf("content omitted because the file is larger than the inline size limit of %d bytes", len(content)-1),
+	}, summary)
+}
+
+func Test_GetFileContentsLargeZip(t *testing.T) {
+	var zipBuf bytes.Buffer
+	zw := zip.NewWriter(&zipBuf)
+	w, err := zw.CreateHeader(&zip.FileHeader{Name: "assets/data.bin", Method: zip.Store})
+	require.NoError(t, err)
+	_, err = w.Write(bytes.Repeat([]byte{0xff}, 4096))
+	require.NoError(t, err)
+	_, err = zw.Create("README.md")
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+	content := zipBuf.Bytes()
+	const maxInlineSize = 1024
+
+	mockedClient := mock.NewMockedHTTPClient(
+		mock.WithRequestMatchHandler(
+			mock.GetReposGitRefByOwnerByRepoByRef,
+			mockResponse(t, http.StatusOK, `{"ref": "refs/heads/main", "object": {"sha": ""}}`),
+		),
+		mock.WithRequestMatchHandler(
+			mock.GetReposContentsByOwnerByRepoByPath,
+			mockResponse(t, http.StatusOK, &github.RepositoryContent{
+				Name: github.Ptr("bundle.zip"),
+				Path: github.Ptr("bundle.zip"),
+				SHA:  github.Ptr("abc123"),
+				Type: github.Ptr("file"),
+				Size: github.Ptr(len(content)),
+			}),
+		),
+		mock.WithRequestMatchHandler(
+			raw.GetRawReposContentsByOwnerByRepoByBranchByPath,
+			http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/zip")
+				if rangeHeader := r.Header.Get("Range"); rangeHeader != "" {
+					// Only the end of the file is requested, to read the central directory
+					assert.Equal(t, fmt.Sprintf("bytes=%d-", len(content)-maxInlineSize), rangeHeader)
+					w.WriteHeader(http.StatusPartialContent)
+					_, _ = w.Write(content[len(content)-maxInlineSize:])
+					return
+				}
+				_, _ = w.Write(content)
+			}),
+		),
+	)
+	client := github.NewClient(mockedClient)
+	rawClient := raw.NewClient(client, &url.URL{Scheme: "https", Host: "raw.example.com", Path: "/"})
+	_, handler := GetFileContents(stubGetClientFn(client), stubGetRawClientFn(rawClient), translations.NullTranslationHelper, maxInlineSize)
+
+	result, err := handler(context.Background(), createMCPRequest(map[string]any{
+		"owner": "owner",
+		"repo":  "repo",
+		"path":  "bundle.zip",
+		"ref":   "refs/heads/main",
+	}))
+	require.NoError(t, err)
+
+	var summary BinaryFileSummary
+	require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &summary))
+	assert.Empty(t, summary.Error)
+	assert.Equal(t, "zip", summary.Name)
+	assert.Equal(t, 2, summary.TotalEntries)
+	assert.Equal(t, []sniff.ArchiveEntry{{Name: "assets/data.bin", Size: 4096}, {Name: "README.md"}}, summary.Entries)
+}
+
 func Test_ForkRepository(t *testing.T) {
 	// Verify tool definition once
 	mockClient := github.NewClient(nil)
@@ -1381,6 +1868,7 @@ func Test_PushFiles(t *testing.T) {
 		expectError    bool
 		expectedRef    *github.Reference
 		expectedErrMsg string
+		expectedHint   string
 	}{
 		{
 			name: "successful push of multiple files",
@@ -1617,6 +2105,59 @@ func Test_PushFiles(t *testing.T) {
 			expectError:    true,
 			expectedErrMsg: "failed to create tree",
 		},
+		{
+			name: "update of the reference rejected by branch protection",
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatch(
+					mock.GetReposGitRefByOwnerByRepoByRef,
+					mockRef,
+				),
+				mock.WithRequestMatch(
+					mock.GetReposGitCommitsByOwnerByRepoByCommitSha,
+					mockCommit,
+				),
+				mock.WithRequestMatch(
+					mock.PostReposGitTreesByOwnerByRepo,
+					mockTree,
+				),
+				mock.WithRequestMatch(
+					mock.PostReposGitCommitsByOwnerByRepo,
+					mockNewCommit,
+				),
+				mock.WithRequestMatchHandler(
+					mock.PatchReposGitRefsByOwnerByRepoByRef,
+					mockResponse(t, http.StatusUnprocessableEntity, map[string]string{"message": "Protected branch update failed for refs/heads/main."}),
+				),
+				mock.WithRequestMatch(
+					mock.GetReposRulesBranchesByOwnerByRepoByBranch,
+					[]any{},
+				),
+				mock.WithRequestMatch(
+					mock.GetReposBranchesProtectionByOwnerByRepoByBranch,
+					&github.Protection{
+						RequiredPullRequestReviews: &github.PullRequestReviewsEnforcement{RequiredApprovingReviewCount: 1},
+						EnforceAdmins:              &github.AdminEnforcement{Enabled: true},
+						AllowForcePushes:           &github.AllowForcePushes{Enabled: true},
+						AllowDeletions:             &github.AllowDeletions{Enabled: true},
+					},
+				),
+			),
+			requestArgs: map[string]interface{}{
+				"owner":  "owner",
+				"repo":   "repo",
+				"branch": "main",
+				"files": []interface{}{
+					map[string]interface{}{
+						"path":    "README.md",
+						"content": "# README",
+					},
+				},
+				"message": "Update file",
+			},
+			expectError:    true,
+			expectedErrMsg: "failed to update reference",
+			expectedHint:   "The branch main is governed by the following rules, which may be blocking this change:\n- Changes must go through a pull request with 1 approving review(s) (branch protection)",
+		},
 	}
 
 	for _, tc := range tests {
@@ -1635,6 +2176,12 @@ func Test_PushFiles(t *testing.T) {
 			if tc.expectError {
 				require.NoError(t, err)
 				require.True(t, result.IsError)
+				if tc.expectedHint != "" {
+					require.Len(t, result.Content, 2)
+					assert.Contains(t, result.Content[0].(mcp.TextContent).Text, tc.expectedErrMsg)
+					assert.Equal(t, tc.expectedHint, result.Content[1].(mcp.TextContent).Text)
+					return
+				}
 				errorContent := getErrorResult(t, result)
 				assert.Contains(t, errorContent.Text, tc.expectedErrMsg)
 				return
@@ -3412,3 +3959,133 @@ func Test_GetRepositoryTree(t *testing.T) {
 		})
 	}
 }
+
+func Test_CompareCommits(t *testing.T) {
+	tool, _ := CompareCommits(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+	assert.True(t, *tool.Annotations.ReadOnlyHint)
+	assert.ElementsMatch(t, tool.InputSchema.Required, []string{"owner", "repo", "base", "head"})
+
+	comparison := &github.CommitsComparison{
+		Status:          github.Ptr("ahead"),
+		AheadBy:         github.Ptr(2),
+		BehindBy:        github.Ptr(0),
+		TotalCommits:    github.Ptr(2),
+		BaseCommit:      &github.RepositoryCommit{SHA: github.Ptr("base-sha")},
+		MergeBaseCommit: &github.RepositoryCommit{SHA: github.Ptr("base-sha")},
+		HTMLURL:         github.Ptr("https://github.com/owner/repo/compare/v1.2...v1.3"),
+		Commits: []*github.RepositoryCommit{
+			{SHA: github.Ptr("c1"), HTMLURL: github.Ptr("https://github.com/owner/repo/commit/c1"), Commit: &github.Commit{Message: github.Ptr("Add feature")}},
+			{SHA: github.Ptr("c2"), HTMLURL: github.Ptr("https://github.com/owner/repo/commit/c2"), Commit: &github.Commit{Message: github.Ptr("Fix bug")}},
+		},
+		Files: []*github.CommitFile{
+			{Filename: github.Ptr("a.go"), Status: github.Ptr("modified"), Additions: github.Ptr(2), Deletions: github.Ptr(1), Changes: github.Ptr(3), Patch: github.Ptr("@@ -1 +1,2 @@\n-a\n+b\n+c")},
+			{Filename: github.Ptr("logo.png"), Status: github.Ptr("added")},
+			{Filename: github.Ptr("b.go"), PreviousFilename: github.Ptr("old.go"), Status: github.Ptr("renamed"), Additions: github.Ptr(1), Changes: github.Ptr(1), Patch: github.Ptr("@@ -1 +1,2 @@\n a\n+d")},
+		},
+	}
+	client := github.NewClient(mock.NewMockedHTTPClient(
+		mock.WithRequestMatchHandler(
+			mock.GetReposCompareByOwnerByRepoByBasehead,
+			expectPath(t, "/repos/owner/repo/compare/v1.2...fork:v1.3").andThen(mockResponse(t, http.StatusOK, comparison)),
+		),
+	))
+	_, handler := CompareCommits(stubGetClientFn(client), translations.NullTranslationHelper)
+	compare := func(args map[string]any) CommitComparison {
+		args["owner"], args["repo"], args["base"], args["head"] = "owner", "repo", "v1.2", "fork:v1.3"
+		result, err := handler(context.Background(), createMCPRequest(args))
+		require.NoError(t, err)
+		var comparison CommitComparison
+		require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &comparison))
+		return comparison
+	}
+
+	t.Run("without patches", func(t *testing.T) {
+		result := compare(map[string]any{})
+		assert.Equal(t, CommitComparison{
+			Status:       "ahead",
+			AheadBy:      2,
+			TotalCommits: 2,
+			BaseSHA:      "base-sha",
+			MergeBaseSHA: "base-sha",
+			HTMLURL:      "https://github.com/owner/repo/compare/v1.2...v1.3",
+			Commits: []MinimalCommit{
+				{SHA: "c1", HTMLURL: "https://github.com/owner/repo/commit/c1", Commit: &MinimalCommitInfo{Message: "Add feature"}},
+				{SHA: "c2", HTMLURL: "https://github.com/owner/repo/commit/c2", Commit: &MinimalCommitInfo{Message: "Fix bug"}},
+			},
+			Files: []ComparisonFile{
+				{MinimalCommitFile: MinimalCommitFile{Filename: "a.go", Status: "modified", Additions: 2, Deletions: 1, Changes: 3}},
+				{MinimalCommitFile: MinimalCommitFile{Filename: "logo.png", Status: "added"}},
+				{MinimalCommitFile: MinimalCommitFile{Filename: "b.go", Status: "renamed", Additions: 1, Changes: 1}, PreviousFilename: "old.go"},
+			},
+			Additions: 3,
+			Deletions: 1,
+		}, result)
+	})
+
+	t.Run("patches in chunks", func(t *testing.T) {
+		result := compare(map[string]any{"include_patches": true, "max_patch_bytes": float64(30)})
+		assert.Equal(t, "@@ -1 +1,2 @@\n-a\n+b\n+c", result.Files[0].Patch)
+		assert.Empty(t, result.Files[2].Patch)
+		require.NotNil(t, result.NextPatchFile)
+		assert.Equal(t, 2, *result.NextPatchFile)
+
+		result = compare(map[string]any{"include_patches": true, "max_patch_bytes": float64(30), "patch_offset": float64(*result.NextPatchFile)})
+		assert.Empty(t, result.Files[0].Patch)
+		assert.Equal(t, "@@ -1 +1,2 @@\n a\n+d", result.Files[2].Patch)
+		assert.Nil(t, result.NextPatchFile)
+	})
+
+	t.Run("patch larger than the budget is continued by the next call", func(t *testing.T) {
+		result := compare(map[string]any{"include_patches": true, "max_patch_bytes": float64(20)})
+		assert.Equal(t, "@@ -1 +1,2 @@\n-a\n+b", result.Files[0].Patch)
+		assert.True(t, result.Files[0].PatchTruncated)
+		assert.Empty(t, result.Files[2].Patch)
+		require.NotNil(t, result.NextPatchFile)
+		assert.Equal(t, 0, *result.NextPatchFile)
+		assert.Equal(t, 20, result.NextPatchByteOffset)
+
+		result = compare(map[string]any{"include_patches": true, "max_patch_bytes": float64(20), "patch_offset": float64(0), "patch_byte_offset": float64(20)})
+		assert.Equal(t, "+c", result.Files[0].Patch)
+		assert.Equal(t, 20, result.Files[0].PatchByteOffset)
+		assert.False(t, result.Files[0].PatchTruncated)
+		require.NotNil(t, result.NextPatchFile)
+		assert.Equal(t, 2, *result.NextPatchFile)
+		assert.Zero(t, result.NextPatchByteOffset)
+	})
+
+	t.Run("patches are not returned past the first page", func(t *testing.T) {
+		result, err := handler(context.Background(), createMCPRequest(map[string]any{"owner": "owner", "repo": "repo", "base": "v1.2", "head": "fork:v1.3", "include_patches": true, "page": float64(2)}))
+		require.NoError(t, err)
+		assert.Contains(t, getErrorResult(t, result).Text, "patches are only returned on the first page of commits")
+	})
+}
+
+func Test_AddComparisonPatches(t *testing.T) {
+	// A single patch much larger than the budget is returned whole over several calls
+	lines := make([]string, 50)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("+line %02d", i)
+	}
+	patch := "@@ -0,0 +1,50 @@\n" + strings.Join(lines, "\n")
+	patches := []string{patch, "@@ -1 +1 @@\n-a\n+b"}
+
+	var chunks []string
+	offset, byteOffset := 0, 0
+	for calls := 1; ; calls++ {
+		require.LessOrEqual(t, calls, 20)
+		files := make([]ComparisonFile, len(patches))
+		next, nextByteOffset := addComparisonPatches(files, patches, offset, byteOffset, 100)
+		if offset == 0 {
+			assert.LessOrEqual(t, len(files[0].Patch), 100)
+			assert.Equal(t, byteOffset, files[0].PatchByteOffset)
+			chunks = append(chunks, files[0].Patch)
+		}
+		if next == nil || *next == 1 {
+			assert.Equal(t, patch, strings.Join(chunks, "\n"))
+			return
+		}
+		assert.True(t, files[0].PatchTruncated)
+		offset, byteOffset = *next, nextByteOffset
+	}
+}
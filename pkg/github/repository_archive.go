@@ -0,0 +1,570 @@
+package github
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"regexp"
+
+	"github.com/github/github-mcp-server/pkg/archive"
+	ghErrors "github.com/github/github-mcp-server/pkg/errors"
+	"github.com/github/github-mcp-server/pkg/raw"
+	"github.com/github/github-mcp-server/pkg/sniff"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/google/go-github/v77/github"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	// defaultGrepResults and maxGrepResults bound the number of matches a grep returns
+	defaultGrepResults = 100
+	maxGrepResults     = 500
+	// maxGrepContextLines bounds the context lines returned around each match
+	maxGrepContextLines = 10
+	// maxGrepFileSize is the size of the largest file that is searched
+	maxGrepFileSize = 5 * 1024 * 1024
+)
+
+// withRepositoryArchiveParams adds the parameters that select a repository archive.
+func withRepositoryArchiveParams() mcp.ToolOption {
+	return func(tool *mcp.Tool) {
+		mcp.WithString("owner",
+			mcp.Required(),
+			mcp.Description("Repository owner (username or organization)"),
+		)(tool)
+		mcp.WithString("repo",
+			mcp.Required(),
+			mcp.Description("Repository name"),
+		)(tool)
+		mcp.WithString("ref",
+			mcp.Description("Git ref such as `main`, `refs/tags/{tag}` or `refs/pull/{pr_number}/head`. Defaults to the default branch"),
+		)(tool)
+		mcp.WithString("sha",
+			mcp.Description("Commit SHA. If specified, it will be used instead of ref"),
+		)(tool)
+		mcp.WithString("format",
+			mcp.Description("Archive format to download. An archive of the commit that is already cached is used whatever its format"),
+			mcp.Enum(string(archive.Tarball), string(archive.Zipball)),
+			mcp.DefaultString(string(archive.Tarball)),
+		)(tool)
+	}
+}
+
+// repositoryArchive is a cached archive of a repository at a commit.
+type repositoryArchive struct {
+	*archive.Archive
+	owner, repo, sha string
+	cached           bool
+}
+
+// getRepositoryArchive resolves the commit a request selects and returns its archive, downloading it
+// into the cache unless it is already there.
+func getRepositoryArchive(ctx context.Context, getClient GetClientFn, getRawClient raw.GetRawClientFn, cache *archive.Cache, request mcp.CallToolRequest) (*repositoryArchive, error) {
+	owner, err := RequiredParam[string](request, "owner")
+	if err != nil {
+		return nil, err
+	}
+	repo, err := RequiredParam[string](request, "repo")
+	if err != nil {
+		return nil, err
+	}
+	ref, err := OptionalParam[string](request, "ref")
+	if err != nil {
+		return nil, err
+	}
+	sha, err := OptionalParam[string](request, "sha")
+	if err != nil {
+		return nil, err
+	}
+	formatName, err := OptionalParam[string](request, "format")
+	if err != nil {
+		return nil, err
+	}
+	format, err := archive.ParseFormat(formatName)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := getClient(ctx)
+	if err != nil {
+		return nil, errors.New("failed to get GitHub client")
+	}
+	rawClient, err := getRawClient(ctx)
+	if err != nil {
+		return nil, errors.New("failed to get GitHub raw content client")
+	}
+
+	// Archives are cached by commit, so refs are resolved first
+	sha, err = resolveCommitSHA(ctx, client, owner, repo, ref, sha)
+	if err != nil {
+		return nil, err
+	}
+
+	download := func(ctx context.Context, format archive.Format, w io.Writer) error {
+		archiveFormat := github.Tarball
+		if format == archive.Zipball {
+			archiveFormat = github.Zipball
+		}
+		link, resp, err := client.Repositories.GetArchiveLink(ctx, owner, repo, archiveFormat, &github.RepositoryContentGetOptions{Ref: sha}, 1)
+		if err != nil {
+			_, _ = ghErrors.NewGitHubAPIErrorToCtx(ctx, "failed to get repository archive link", resp, err)
+			return fmt.Errorf("failed to get repository archive link: %w", err)
+		}
+		archiveResp, err := rawClient.GetArchive(ctx, link)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = archiveResp.Body.Close() }()
+		if _, err := io.Copy(w, archiveResp.Body); err != nil {
+			return fmt.Errorf("failed to download repository archive: %w", err)
+		}
+		return nil
+	}
+
+	a, cached, err := cache.Get(ctx, owner, repo, sha, format, download)
+	if err != nil {
+		return nil, err
+	}
+	return &repositoryArchive{Archive: a, owner: owner, repo: repo, sha: sha, cached: cached}, nil
+}
+
+// resolveCommitSHA resolves ref, or sha which may be abbreviated, to the full SHA of a commit. The commit
+// is always looked up through the API, even when sha is given, so that results cached by commit are only
+// served to callers that can read the repository.
+func resolveCommitSHA(ctx context.Context, client *github.Client, owner, repo, ref, sha string) (string, error) {
+	rawOpts, err := resolveGitReference(ctx, client, owner, repo, ref, sha)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve git reference: %w", err)
+	}
+	fullSHA, resp, err := client.Repositories.GetCommitSHA1(ctx, owner, repo, rawOpts.SHA, "")
+	if err != nil {
+		_, _ = ghErrors.NewGitHubAPIErrorToCtx(ctx, "failed to get commit", resp, err)
+		return "", fmt.Errorf("failed to get commit %s: %w", rawOpts.SHA, err)
+	}
+	return fullSHA, nil
+}
+
+// RepositoryArchiveInfo describes a cached repository archive.
+type RepositoryArchiveInfo struct {
+	Owner  string         `json:"owner"`
+	Repo   string         `json:"repo"`
+	SHA    string         `json:"sha"`
+	Format archive.Format `json:"format"`
+	// Cached is set when the archive was already cached rather than downloaded by this call
+	Cached bool `json:"cached"`
+	// ArchiveSize is the compressed size of the archive in bytes
+	ArchiveSize int64 `json:"archive_size"`
+	Files       int   `json:"files"`
+	Directories int   `json:"directories"`
+	Symlinks    int   `json:"symlinks"`
+	// TotalSize is the uncompressed size of all files in bytes
+	TotalSize int64 `json:"total_size"`
+}
+
+// GetRepositoryArchive creates a tool to download and cache the archive of a repository at a ref.
+func GetRepositoryArchive(getClient GetClientFn, getRawClient raw.GetRawClientFn, t translations.TranslationHelperFunc, cache *archive.Cache) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("get_repository_archive",
+			mcp.WithDescription(t("TOOL_GET_REPOSITORY_ARCHIVE_DESCRIPTION", "Download the archive of a repository at a ref and cache it on the server, returning its size and number of files. Use list_repository_archive_entries, get_repository_archive_entry and grep_repository_archive to survey the files of the archive without a call per file")),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:        t("TOOL_GET_REPOSITORY_ARCHIVE_USER_TITLE", "Get repository archive"),
+				ReadOnlyHint: ToBoolPtr(true),
+			}),
+			withRepositoryArchiveParams(),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			a, err := getRepositoryArchive(ctx, getClient, getRawClient, cache, request)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			defer func() { _ = a.Close() }()
+
+			info := RepositoryArchiveInfo{
+				Owner:       a.owner,
+				Repo:        a.repo,
+				SHA:         a.sha,
+				Format:      a.Format,
+				Cached:      a.cached,
+				ArchiveSize: a.Size,
+			}
+			err = a.Walk(func(e archive.Entry, _ io.Reader) error {
+				switch e.Type {
+				case "dir":
+					info.Directories++
+				case "symlink":
+					info.Symlinks++
+				default:
+					info.Files++
+					info.TotalSize += e.Size
+				}
+				return nil
+			})
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			return MarshalledTextResult(info), nil
+		}
+}
+
+// RepositoryArchiveEntries is a page of the entries of a repository archive.
+type RepositoryArchiveEntries struct {
+	SHA string `json:"sha"`
+	// TotalCount is the number of entries that match the filters
+	TotalCount int             `json:"total_count"`
+	Page       int             `json:"page"`
+	PerPage    int             `json:"per_page"`
+	Entries    []archive.Entry `json:"entries"`
+}
+
+// ListRepositoryArchiveEntries creates a tool to list the entries of a repository archive.
+func ListRepositoryArchiveEntries(getClient GetClientFn, getRawClient raw.GetRawClientFn, t translations.TranslationHelperFunc, cache *archive.Cache) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("list_repository_archive_entries",
+			mcp.WithDescription(t("TOOL_LIST_REPOSITORY_ARCHIVE_ENTRIES_DESCRIPTION", "List the files, directories and symbolic links in the archive of a repository at a ref, filtered by path globs. The archive is downloaded and cached if needed")),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:        t("TOOL_LIST_REPOSITORY_ARCHIVE_ENTRIES_USER_TITLE", "List repository archive entries"),
+				ReadOnlyHint: ToBoolPtr(true),
+			}),
+			withRepositoryArchiveParams(),
+			mcp.WithArray("include",
+				mcp.Description("Globs of the paths to list, e.g. `*.go` or `docs/**/*.md`. Globs without a slash match file names at any depth, and `**` matches any number of directories"),
+				mcp.WithStringItems(),
+			),
+			mcp.WithArray("exclude",
+				mcp.Description("Globs of the paths to leave out, e.g. `vendor/`"),
+				mcp.WithStringItems(),
+			),
+			mcp.WithString("type",
+				mcp.Description("Only list entries of this type"),
+				mcp.Enum("file", "dir", "symlink"),
+			),
+			WithPagination(),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			include, err := OptionalStringArrayParam(request, "include")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			exclude, err := OptionalStringArrayParam(request, "exclude")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			entryType, err := OptionalParam[string](request, "type")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			pagination, err := OptionalPaginationParams(request)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			filter, err := newPathFilter(include, exclude)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			a, err := getRepositoryArchive(ctx, getClient, getRawClient, cache, request)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			defer func() { _ = a.Close() }()
+
+			result := RepositoryArchiveEntries{SHA: a.sha, Page: pagination.Page, PerPage: pagination.PerPage, Entries: []archive.Entry{}}
+			first := (pagination.Page - 1) * pagination.PerPage
+			err = a.Walk(func(e archive.Entry, _ io.Reader) error {
+				if (entryType != "" && e.Type != entryType) || !filter.match(e.Path) {
+					return nil
+				}
+				if result.TotalCount >= first && len(result.Entries) < pagination.PerPage {
+					result.Entries = append(result.Entries, e)
+				}
+				result.TotalCount++
+				return nil
+			})
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			return MarshalledTextResult(result), nil
+		}
+}
+
+// GetRepositoryArchiveEntry creates a tool to read a file from a repository archive.
+func GetRepositoryArchiveEntry(getClient GetClientFn, getRawClient raw.GetRawClientFn, t translations.TranslationHelperFunc, cache *archive.Cache, maxInlineSize int) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	if maxInlineSize <= 0 {
+		maxInlineSize = DefaultMaxInlineSize
+	}
+	return mcp.NewTool("get_repository_archive_entry",
+			mcp.WithDescription(t("TOOL_GET_REPOSITORY_ARCHIVE_ENTRY_DESCRIPTION", "Read a file from the archive of a repository at a ref. Binary files are handled like get_file_contents does. The archive is downloaded and cached if needed")),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:        t("TOOL_GET_REPOSITORY_ARCHIVE_ENTRY_USER_TITLE", "Get repository archive entry"),
+				ReadOnlyHint: ToBoolPtr(true),
+			}),
+			withRepositoryArchiveParams(),
+			mcp.WithString("path",
+				mcp.Required(),
+				mcp.Description("Path of the file in the repository"),
+			),
+			mcp.WithNumber("start_line",
+				mcp.Description("First line of a text file to return (1-based)"),
+				mcp.Min(1),
+			),
+			mcp.WithNumber("end_line",
+				mcp.Description("Last line of a text file to return (inclusive). Defaults to the end of the file"),
+				mcp.Min(1),
+			),
+			mcp.WithNumber("max_bytes",
+				mcp.Description("Maximum number of bytes of a text file to return. Lines past the limit are left out and the result reports the line to continue from"),
+				mcp.Min(1),
+			),
+			mcp.WithNumber("line_offset",
+				mcp.Description("Byte offset within start_line to continue from, as reported by next_line_offset when max_bytes cut a line short"),
+				mcp.Min(0),
+			),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			path, err := RequiredParam[string](request, "path")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			startLine, err := OptionalIntParam(request, "start_line")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			endLine, err := OptionalIntParam(request, "end_line")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			maxBytes, err := OptionalIntParam(request, "max_bytes")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			lineOffset, err := OptionalIntParam(request, "line_offset")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			if startLine < 0 || endLine < 0 || maxBytes < 0 || lineOffset < 0 {
+				return mcp.NewToolResultError("start_line, end_line, max_bytes and line_offset must be positive"), nil
+			}
+			if endLine > 0 && endLine < max(startLine, 1) {
+				return mcp.NewToolResultError("end_line must not be before start_line"), nil
+			}
+			windowed := startLine > 0 || endLine > 0 || maxBytes > 0 || lineOffset > 0
+
+			a, err := getRepositoryArchive(ctx, getClient, getRawClient, cache, request)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			defer func() { _ = a.Close() }()
+			resourceURI, err := fileContentsResourceURI(a.owner, a.repo, path, "", a.sha)
+			if err != nil {
+				return nil, err
+			}
+
+			var result *mcp.CallToolResult
+			err = a.ReadEntry(path, func(e archive.Entry, r io.Reader) error {
+				switch e.Type {
+				case "dir":
+					result = mcp.NewToolResultError(fmt.Sprintf("%s is a directory, use list_repository_archive_entries to list its entries", e.Path))
+					return nil
+				case "symlink":
+					result = mcp.NewToolResultText(fmt.Sprintf("%s is a symbolic link to %s", e.Path, e.Target))
+					return nil
+				}
+
+				sniffed := bufio.NewReaderSize(r, sniff.SampleSize)
+				sample, err := sniffed.Peek(sniff.SampleSize)
+				if err != nil && !errors.Is(err, io.EOF) {
+					return err
+				}
+				format := sniff.Detect(sample)
+				if !format.IsText() {
+					result = binaryFileResult(sniffed, format, format.MIMEType, int(e.Size), resourceURI, "", nil, maxInlineSize, nil)
+					return nil
+				}
+
+				if windowed {
+					result, err = readFileWindow(sniffed, false, resourceURI, format.MIMEType, "", int(e.Size), nil, startLine, lineOffset, endLine, maxBytes)
+					return err
+				}
+				content, err := io.ReadAll(sniffed)
+				if err != nil {
+					return err
+				}
+				result = mcp.NewToolResultResource(fileDownloadMessage("text", "", nil), mcp.TextResourceContents{
+					URI:      resourceURI,
+					Text:     string(content),
+					MIMEType: format.MIMEType,
+				})
+				return nil
+			})
+			if errors.Is(err, fs.ErrNotExist) {
+				return mcp.NewToolResultError(fmt.Sprintf("%s does not exist in %s/%s at %s", path, a.owner, a.repo, a.sha)), nil
+			}
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to read %s from the repository archive: %s", path, err)), nil
+			}
+			return result, nil
+		}
+}
+
+// GrepResult holds the lines of a repository that match a grep pattern.
+type GrepResult struct {
+	SHA     string      `json:"sha"`
+	Matches []GrepMatch `json:"matches"`
+	// FilesSearched is the number of files that were searched, and FilesSkipped the number of
+	// files matching the path filters that were not, because they are binary or too large
+	FilesSearched int `json:"files_searched"`
+	FilesSkipped  int `json:"files_skipped,omitempty"`
+	// Truncated is set when the search stopped at max_results, so there may be more matches
+	Truncated bool `json:"truncated"`
+}
+
+// withGrepParams adds the parameters that configure a grep.
+func withGrepParams() mcp.ToolOption {
+	return func(tool *mcp.Tool) {
+		mcp.WithString("pattern",
+			mcp.Required(),
+			mcp.Description("Regular expression to search for, in Go RE2 syntax. It is matched against each line"),
+		)(tool)
+		mcp.WithBoolean("ignore_case",
+			mcp.Description("Match the pattern case-insensitively"),
+		)(tool)
+		mcp.WithArray("include",
+			mcp.Description("Globs of the paths to search, e.g. `*.go` or `src/**/*.ts`. Globs without a slash match file names at any depth"),
+			mcp.WithStringItems(),
+		)(tool)
+		mcp.WithArray("exclude",
+			mcp.Description("Globs of the paths to leave out, e.g. `vendor/` or `*_test.go`"),
+			mcp.WithStringItems(),
+		)(tool)
+		mcp.WithNumber("context_lines",
+			mcp.Description(fmt.Sprintf("Number of lines of context to return before and after each match (max %d)", maxGrepContextLines)),
+			mcp.Min(0),
+			mcp.Max(maxGrepContextLines),
+		)(tool)
+		mcp.WithNumber("max_results",
+			mcp.Description(fmt.Sprintf("Maximum number of matches to return (default %d, max %d)", defaultGrepResults, maxGrepResults)),
+			mcp.Min(1),
+			mcp.Max(maxGrepResults),
+		)(tool)
+	}
+}
+
+// grepOptions are the parsed parameters of a grep.
+type grepOptions struct {
+	re           *regexp.Regexp
+	filter       *pathFilter
+	contextLines int
+	maxResults   int
+}
+
+func parseGrepParams(request mcp.CallToolRequest) (*grepOptions, error) {
+	pattern, err := RequiredParam[string](request, "pattern")
+	if err != nil {
+		return nil, err
+	}
+	ignoreCase, err := OptionalParam[bool](request, "ignore_case")
+	if err != nil {
+		return nil, err
+	}
+	include, err := OptionalStringArrayParam(request, "include")
+	if err != nil {
+		return nil, err
+	}
+	exclude, err := OptionalStringArrayParam(request, "exclude")
+	if err != nil {
+		return nil, err
+	}
+	contextLines, err := OptionalIntParam(request, "context_lines")
+	if err != nil {
+		return nil, err
+	}
+	maxResults, err := OptionalIntParamWithDefault(request, "max_results", defaultGrepResults)
+	if err != nil {
+		return nil, err
+	}
+	if contextLines < 0 || contextLines > maxGrepContextLines {
+		return nil, fmt.Errorf("context_lines must be between 0 and %d", maxGrepContextLines)
+	}
+	if maxResults < 1 || maxResults > maxGrepResults {
+		return nil, fmt.Errorf("max_results must be between 1 and %d", maxGrepResults)
+	}
+
+	if ignoreCase {
+		pattern = "(?i)" + pattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern: %w", err)
+	}
+	filter, err := newPathFilter(include, exclude)
+	if err != nil {
+		return nil, err
+	}
+	return &grepOptions{re: re, filter: filter, contextLines: contextLines, maxResults: maxResults}, nil
+}
+
+// GrepRepositoryArchive creates a tool to search the files of a repository archive with a regular expression.
+func GrepRepositoryArchive(getClient GetClientFn, getRawClient raw.GetRawClientFn, t translations.TranslationHelperFunc, cache *archive.Cache) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("grep_repository_archive",
+			mcp.WithDescription(t("TOOL_GREP_REPOSITORY_ARCHIVE_DESCRIPTION", "Search the text files in the archive of a repository at a ref for lines matching a regular expression, returning the matches with line numbers and context. The archive is downloaded and cached if needed")),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:        t("TOOL_GREP_REPOSITORY_ARCHIVE_USER_TITLE", "Search repository archive"),
+				ReadOnlyHint: ToBoolPtr(true),
+			}),
+			withRepositoryArchiveParams(),
+			withGrepParams(),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			opts, err := parseGrepParams(request)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			a, err := getRepositoryArchive(ctx, getClient, getRawClient, cache, request)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			defer func() { _ = a.Close() }()
+
+			result := GrepResult{SHA: a.sha, Matches: []GrepMatch{}}
+			err = a.Walk(func(e archive.Entry, r io.Reader) error {
+				if e.Type != "file" || !opts.filter.match(e.Path) {
+					return nil
+				}
+				if e.Size > maxGrepFileSize {
+					result.FilesSkipped++
+					return nil
+				}
+				sniffed := bufio.NewReaderSize(r, sniff.SampleSize)
+				sample, err := sniffed.Peek(sniff.SampleSize)
+				if err != nil && !errors.Is(err, io.EOF) {
+					return err
+				}
+				if !sniff.Detect(sample).IsText() {
+					result.FilesSkipped++
+					return nil
+				}
+
+				result.FilesSearched++
+				matches, _, err := grepLines(sniffed, e.Path, opts.re, opts.contextLines, opts.maxResults-len(result.Matches))
+				if err != nil {
+					return err
+				}
+				result.Matches = append(result.Matches, matches...)
+				if len(result.Matches) == opts.maxResults {
+					result.Truncated = true
+					return fs.SkipAll
+				}
+				return nil
+			})
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			return MarshalledTextResult(result), nil
+		}
+}
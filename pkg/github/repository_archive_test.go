@@ -0,0 +1,336 @@
+package github
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"image"
+	"image/png"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/github/github-mcp-server/internal/toolsnaps"
+	"github.com/github/github-mcp-server/pkg/archive"
+	"github.com/github/github-mcp-server/pkg/raw"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/google/go-github/v77/github"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/migueleliasweb/go-github-mock/src/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testArchiveSHA = "abc123def4567890abc123def4567890abc123de"
+
+// repositoryTarball builds a tarball laid out like the ones GitHub serves. Paths ending in a slash are directories.
+func repositoryTarball(t *testing.T, files map[string]string, order []string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gw)
+	require.NoError(t, tw.WriteHeader(&tar.Header{Typeflag: tar.TypeDir, Name: "octocat-hello-abc123/", Mode: 0o755}))
+	for _, name := range order {
+		if strings.HasSuffix(name, "/") {
+			require.NoError(t, tw.WriteHeader(&tar.Header{Typeflag: tar.TypeDir, Name: "octocat-hello-abc123/" + name, Mode: 0o755}))
+			continue
+		}
+		require.NoError(t, tw.WriteHeader(&tar.Header{Typeflag: tar.TypeReg, Name: "octocat-hello-abc123/" + name, Mode: 0o644, Size: int64(len(files[name]))}))
+		_, err := tw.Write([]byte(files[name]))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gw.Close())
+	return buf.Bytes()
+}
+
+// archiveTestClients returns clients that serve the given tarball for refs/heads/main, and
+// a counter of the downloads.
+func archiveTestClients(t *testing.T, tarball []byte) (GetClientFn, raw.GetRawClientFn, *int) {
+	downloads := 0
+	mockedClient := mock.NewMockedHTTPClient(
+		mock.WithRequestMatchHandler(
+			mock.GetReposGitRefByOwnerByRepoByRef,
+			mockResponse(t, http.StatusOK, `{"ref": "refs/heads/main", "object": {"sha": "`+testArchiveSHA+`"}}`),
+		),
+		mock.WithRequestMatchHandler(
+			mock.GetReposCommitsByOwnerByRepoByRef,
+			testCommitSHAHandler(t),
+		),
+		mock.WithRequestMatchHandler(
+			mock.GetReposTarballByOwnerByRepoByRef,
+			http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/repos/octocat/hello/tarball/"+testArchiveSHA, r.URL.Path)
+				w.Header().Set("Location", "https://codeload.example.com/octocat/hello/legacy.tar.gz/"+testArchiveSHA+"?token=abc")
+				w.WriteHeader(http.StatusFound)
+			}),
+		),
+		mock.WithRequestMatchHandler(
+			raw.GetCodeloadTarballByOwnerByRepoByRef,
+			http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				downloads++
+				_, _ = w.Write(tarball)
+			}),
+		),
+	)
+	client := github.NewClient(mockedClient)
+	rawClient := raw.NewClient(client, &url.URL{Scheme: "https", Host: "raw.example.com", Path: "/"})
+	return stubGetClientFn(client), stubGetRawClientFn(rawClient), &downloads
+}
+
+// testCommitSHAHandler resolves abbreviations of testArchiveSHA to the full SHA, like the commits API does
+// when asked for a SHA.
+func testCommitSHAHandler(t *testing.T) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/vnd.github.v3.sha", r.Header.Get("Accept"))
+		ref := strings.TrimPrefix(r.URL.Path, "/repos/octocat/hello/commits/")
+		if len(ref) < 7 || !strings.HasPrefix(testArchiveSHA, ref) {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message": "No commit found for SHA: ` + ref + `"}`))
+			return
+		}
+		_, _ = w.Write([]byte(testArchiveSHA))
+	}
+}
+
+var testArchiveFiles = map[string]string{
+	"README.md":         "# Hello\n\nTODO: write docs\n",
+	"cmd/main.go":       "package main\n\nfunc main() {\n\t// TODO: implement\n}\n",
+	"cmd/main_test.go":  "package main\n\n// TODO: test\n",
+	"vendor/lib/lib.go": "package lib // TODO\n",
+}
+
+var testArchiveOrder = []string{"README.md", "cmd/", "cmd/main.go", "cmd/main_test.go", "vendor/", "vendor/lib/", "vendor/lib/lib.go"}
+
+func Test_GetRepositoryArchive(t *testing.T) {
+	cache := archive.NewCache(t.TempDir(), 0)
+	tool, _ := GetRepositoryArchive(stubGetClientFn(github.NewClient(nil)), stubGetRawClientFn(nil), translations.NullTranslationHelper, cache)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+	assert.True(t, *tool.Annotations.ReadOnlyHint)
+	assert.ElementsMatch(t, tool.InputSchema.Required, []string{"owner", "repo"})
+
+	tarball := repositoryTarball(t, testArchiveFiles, testArchiveOrder)
+	getClient, getRawClient, downloads := archiveTestClients(t, tarball)
+	_, handler := GetRepositoryArchive(getClient, getRawClient, translations.NullTranslationHelper, cache)
+	request := createMCPRequest(map[string]any{"owner": "octocat", "repo": "hello", "ref": "refs/heads/main"})
+
+	for i, cached := range []bool{false, true} {
+		result, err := handler(context.Background(), request)
+		require.NoError(t, err)
+		var info RepositoryArchiveInfo
+		require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &info))
+		assert.Equal(t, RepositoryArchiveInfo{
+			Owner:       "octocat",
+			Repo:        "hello",
+			SHA:         testArchiveSHA,
+			Format:      archive.Tarball,
+			Cached:      cached,
+			ArchiveSize: int64(len(tarball)),
+			Files:       4,
+			Directories: 3,
+			TotalSize:   int64(len(testArchiveFiles["README.md"]) + len(testArchiveFiles["cmd/main.go"]) + len(testArchiveFiles["cmd/main_test.go"]) + len(testArchiveFiles["vendor/lib/lib.go"])),
+		}, info, "call %d", i)
+	}
+	assert.Equal(t, 1, *downloads)
+
+	t.Run("abbreviated sha uses the cached archive", func(t *testing.T) {
+		result, err := handler(context.Background(), createMCPRequest(map[string]any{"owner": "octocat", "repo": "hello", "sha": testArchiveSHA[:7]}))
+		require.NoError(t, err)
+		var info RepositoryArchiveInfo
+		require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &info))
+		assert.Equal(t, testArchiveSHA, info.SHA)
+		assert.True(t, info.Cached)
+		assert.Equal(t, 1, *downloads)
+	})
+
+	t.Run("cached archive is not served without access to the commit", func(t *testing.T) {
+		// A caller who can't read the repository gets a 404 from the commits API
+		deniedClient := github.NewClient(mock.NewMockedHTTPClient(
+			mock.WithRequestMatchHandler(
+				mock.GetReposCommitsByOwnerByRepoByRef,
+				mockResponse(t, http.StatusNotFound, map[string]string{"message": "Not Found"}),
+			),
+		))
+		_, deniedHandler := GetRepositoryArchive(stubGetClientFn(deniedClient), getRawClient, translations.NullTranslationHelper, cache)
+		result, err := deniedHandler(context.Background(), createMCPRequest(map[string]any{"owner": "octocat", "repo": "hello", "sha": testArchiveSHA}))
+		require.NoError(t, err)
+		assert.Contains(t, getErrorResult(t, result).Text, "failed to get commit "+testArchiveSHA)
+	})
+}
+
+func Test_ListRepositoryArchiveEntries(t *testing.T) {
+	tool, _ := ListRepositoryArchiveEntries(stubGetClientFn(github.NewClient(nil)), stubGetRawClientFn(nil), translations.NullTranslationHelper, nil)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+
+	getClient, getRawClient, _ := archiveTestClients(t, repositoryTarball(t, testArchiveFiles, testArchiveOrder))
+	_, handler := ListRepositoryArchiveEntries(getClient, getRawClient, translations.NullTranslationHelper, archive.NewCache(t.TempDir(), 0))
+
+	tests := []struct {
+		name          string
+		args          map[string]any
+		expectedTotal int
+		expectedPaths []string
+	}{
+		{
+			name:          "all entries",
+			args:          map[string]any{},
+			expectedTotal: 7,
+			expectedPaths: []string{"README.md", "cmd", "cmd/main.go", "cmd/main_test.go", "vendor", "vendor/lib", "vendor/lib/lib.go"},
+		},
+		{
+			name:          "include and exclude globs",
+			args:          map[string]any{"include": []any{"*.go"}, "exclude": []any{"vendor/", "*_test.go"}},
+			expectedTotal: 1,
+			expectedPaths: []string{"cmd/main.go"},
+		},
+		{
+			name:          "directories",
+			args:          map[string]any{"type": "dir"},
+			expectedTotal: 3,
+			expectedPaths: []string{"cmd", "vendor", "vendor/lib"},
+		},
+		{
+			name:          "second page",
+			args:          map[string]any{"type": "file", "page": float64(2), "perPage": float64(3)},
+			expectedTotal: 4,
+			expectedPaths: []string{"vendor/lib/lib.go"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			args := map[string]any{"owner": "octocat", "repo": "hello", "ref": "refs/heads/main"}
+			for k, v := range tc.args {
+				args[k] = v
+			}
+			result, err := handler(context.Background(), createMCPRequest(args))
+			require.NoError(t, err)
+			var entries RepositoryArchiveEntries
+			require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &entries))
+			assert.Equal(t, testArchiveSHA, entries.SHA)
+			assert.Equal(t, tc.expectedTotal, entries.TotalCount)
+			paths := []string{}
+			for _, e := range entries.Entries {
+				paths = append(paths, e.Path)
+			}
+			assert.Equal(t, tc.expectedPaths, paths)
+		})
+	}
+}
+
+func Test_GetRepositoryArchiveEntry(t *testing.T) {
+	tool, _ := GetRepositoryArchiveEntry(stubGetClientFn(github.NewClient(nil)), stubGetRawClientFn(nil), translations.NullTranslationHelper, nil, DefaultMaxInlineSize)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+	assert.ElementsMatch(t, tool.InputSchema.Required, []string{"owner", "repo", "path"})
+
+	var pngBuf bytes.Buffer
+	require.NoError(t, png.Encode(&pngBuf, image.NewGray(image.Rect(0, 0, 4, 2))))
+	files := map[string]string{"logo.png": pngBuf.String()}
+	for k, v := range testArchiveFiles {
+		files[k] = v
+	}
+	getClient, getRawClient, _ := archiveTestClients(t, repositoryTarball(t, files, append(testArchiveOrder, "logo.png")))
+	_, handler := GetRepositoryArchiveEntry(getClient, getRawClient, translations.NullTranslationHelper, archive.NewCache(t.TempDir(), 0), DefaultMaxInlineSize)
+	call := func(args map[string]any) *mcp.CallToolResult {
+		args["owner"], args["repo"], args["ref"] = "octocat", "hello", "refs/heads/main"
+		result, err := handler(context.Background(), createMCPRequest(args))
+		require.NoError(t, err)
+		return result
+	}
+
+	t.Run("text file", func(t *testing.T) {
+		result := call(map[string]any{"path": "cmd/main.go"})
+		assert.Equal(t, mcp.TextResourceContents{
+			URI:      "repo://octocat/hello/sha/" + testArchiveSHA + "/contents/cmd/main.go",
+			Text:     testArchiveFiles["cmd/main.go"],
+			MIMEType: "text/plain; charset=utf-8",
+		}, getTextResourceResult(t, result))
+	})
+
+	t.Run("line range", func(t *testing.T) {
+		result := call(map[string]any{"path": "cmd/main.go", "start_line": float64(3), "end_line": float64(4)})
+		require.Len(t, result.Content, 2)
+		var window FileContentsWindow
+		require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &window))
+		assert.Equal(t, FileContentsWindow{Size: len(testArchiveFiles["cmd/main.go"]), TotalLines: 5, StartLine: 3, EndLine: 4, NextStartLine: 5}, window)
+		assert.Equal(t, "func main() {\n\t// TODO: implement\n", getTextResourceResult(t, result).Text)
+	})
+
+	t.Run("negative parameters", func(t *testing.T) {
+		for _, param := range []string{"max_bytes", "start_line", "line_offset"} {
+			result := call(map[string]any{"path": "cmd/main.go", param: float64(-1)})
+			assert.Equal(t, "start_line, end_line, max_bytes and line_offset must be positive", getErrorResult(t, result).Text, param)
+		}
+	})
+
+	t.Run("image", func(t *testing.T) {
+		result := call(map[string]any{"path": "logo.png"})
+		require.Len(t, result.Content, 2)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "png image of 4x2 pixels")
+		assert.Equal(t, "image/png", result.Content[1].(mcp.ImageContent).MIMEType)
+	})
+
+	t.Run("directory", func(t *testing.T) {
+		result := call(map[string]any{"path": "cmd/"})
+		assert.Contains(t, getErrorResult(t, result).Text, "cmd is a directory")
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		result := call(map[string]any{"path": "nope.go"})
+		assert.Equal(t, "nope.go does not exist in octocat/hello at "+testArchiveSHA, getErrorResult(t, result).Text)
+	})
+}
+
+func Test_GrepRepositoryArchive(t *testing.T) {
+	tool, _ := GrepRepositoryArchive(stubGetClientFn(github.NewClient(nil)), stubGetRawClientFn(nil), translations.NullTranslationHelper, nil)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+	assert.ElementsMatch(t, tool.InputSchema.Required, []string{"owner", "repo", "pattern"})
+
+	files := map[string]string{"bin/tool": "TODO\x00binary"}
+	for k, v := range testArchiveFiles {
+		files[k] = v
+	}
+	getClient, getRawClient, _ := archiveTestClients(t, repositoryTarball(t, files, append(testArchiveOrder, "bin/", "bin/tool")))
+	_, handler := GrepRepositoryArchive(getClient, getRawClient, translations.NullTranslationHelper, archive.NewCache(t.TempDir(), 0))
+	call := func(args map[string]any) *mcp.CallToolResult {
+		args["owner"], args["repo"], args["ref"] = "octocat", "hello", "refs/heads/main"
+		result, err := handler(context.Background(), createMCPRequest(args))
+		require.NoError(t, err)
+		return result
+	}
+
+	t.Run("matches with context", func(t *testing.T) {
+		result := call(map[string]any{"pattern": `todo: \w+`, "ignore_case": true, "include": []any{"*.go"}, "exclude": []any{"vendor/"}, "context_lines": float64(1)})
+		var grep GrepResult
+		require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &grep))
+		assert.Equal(t, GrepResult{
+			SHA: testArchiveSHA,
+			Matches: []GrepMatch{
+				{Path: "cmd/main.go", Line: 4, Text: "\t// TODO: implement", Before: []string{"func main() {"}, After: []string{"}"}},
+				{Path: "cmd/main_test.go", Line: 3, Text: "// TODO: test", Before: []string{""}},
+			},
+			FilesSearched: 2,
+		}, grep)
+	})
+
+	t.Run("binary files are skipped and results capped", func(t *testing.T) {
+		result := call(map[string]any{"pattern": "TODO", "max_results": float64(2)})
+		var grep GrepResult
+		require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &grep))
+		assert.Len(t, grep.Matches, 2)
+		assert.True(t, grep.Truncated)
+
+		result = call(map[string]any{"pattern": "TODO", "include": []any{"bin/"}})
+		require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &grep))
+		assert.Empty(t, grep.Matches)
+		assert.Equal(t, 1, grep.FilesSkipped)
+	})
+
+	t.Run("invalid pattern", func(t *testing.T) {
+		result := call(map[string]any{"pattern": "(unclosed"})
+		assert.Contains(t, getErrorResult(t, result).Text, "invalid pattern")
+	})
+}
@@ -0,0 +1,271 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+
+	ghErrors "github.com/github/github-mcp-server/pkg/errors"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/google/go-github/v77/github"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// isRepositoryDeletionAllowed reports whether owner/repo matches one of the patterns of the deletion allowlist.
+// Patterns are owner/repo names that may contain shell wildcards, such as my-org/* or my-org/sandbox-*, and
+// are matched case-insensitively.
+func isRepositoryDeletionAllowed(allowlist []string, owner, repo string) bool {
+	fullName := strings.ToLower(owner + "/" + repo)
+	for _, pattern := range allowlist {
+		pattern = strings.ToLower(strings.TrimSpace(pattern))
+		if strings.Count(pattern, "/") != 1 {
+			continue
+		}
+		if matched, err := path.Match(pattern, fullName); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateRepositoryFromTemplate creates a tool to create a repository from a template repository.
+func CreateRepositoryFromTemplate(getClient GetClientFn, t translations.TranslationHelperFunc) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("create_repository_from_template",
+			mcp.WithDescription(t("TOOL_CREATE_REPOSITORY_FROM_TEMPLATE_DESCRIPTION", "Create a new GitHub repository from a template repository, with the files and directories of the template")),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:        t("TOOL_CREATE_REPOSITORY_FROM_TEMPLATE_USER_TITLE", "Create repository from template"),
+				ReadOnlyHint: ToBoolPtr(false),
+			}),
+			mcp.WithString("template_owner",
+				mcp.Required(),
+				mcp.Description("Owner of the template repository"),
+			),
+			mcp.WithString("template_repo",
+				mcp.Required(),
+				mcp.Description("Name of the template repository"),
+			),
+			mcp.WithString("name",
+				mcp.Required(),
+				mcp.Description("Name of the new repository"),
+			),
+			mcp.WithString("owner",
+				mcp.Description("User or organization to create the repository in (omit to create in your personal account)"),
+			),
+			mcp.WithString("description",
+				mcp.Description("Description of the new repository"),
+			),
+			mcp.WithBoolean("private",
+				mcp.Description("Whether the new repository should be private"),
+			),
+			mcp.WithBoolean("include_all_branches",
+				mcp.Description("Copy all the branches of the template, instead of only its default branch"),
+			),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			templateOwner, err := RequiredParam[string](request, "template_owner")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			templateRepo, err := RequiredParam[string](request, "template_repo")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			name, err := RequiredParam[string](request, "name")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			owner, err := OptionalParam[string](request, "owner")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			description, err := OptionalParam[string](request, "description")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			private, err := OptionalParam[bool](request, "private")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			includeAllBranches, err := OptionalParam[bool](request, "include_all_branches")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			templateRequest := &github.TemplateRepoRequest{
+				Name:               github.Ptr(name),
+				Private:            github.Ptr(private),
+				IncludeAllBranches: github.Ptr(includeAllBranches),
+			}
+			if owner != "" {
+				templateRequest.Owner = github.Ptr(owner)
+			}
+			if description != "" {
+				templateRequest.Description = github.Ptr(description)
+			}
+
+			client, err := getClient(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get GitHub client: %w", err)
+			}
+
+			createdRepo, resp, err := client.Repositories.CreateFromTemplate(ctx, templateOwner, templateRepo, templateRequest)
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx,
+					fmt.Sprintf("failed to create repository from template %s/%s", templateOwner, templateRepo),
+					resp,
+					err,
+				), nil
+			}
+			defer func() { _ = resp.Body.Close() }()
+
+			// Return minimal response with just essential information
+			minimalResponse := MinimalResponse{
+				ID:  fmt.Sprintf("%d", createdRepo.GetID()),
+				URL: createdRepo.GetHTMLURL(),
+			}
+
+			r, err := json.Marshal(minimalResponse)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal response: %w", err)
+			}
+
+			return mcp.NewToolResultText(string(r)), nil
+		}
+}
+
+// TransferRepository creates a tool to transfer a repository to another user or organization.
+func TransferRepository(getClient GetClientFn, t translations.TranslationHelperFunc) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("transfer_repository",
+			mcp.WithDescription(t("TOOL_TRANSFER_REPOSITORY_DESCRIPTION", "Transfer a GitHub repository to another user or organization. When transferring to a user, they must accept the transfer before it happens")),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:           t("TOOL_TRANSFER_REPOSITORY_USER_TITLE", "Transfer repository"),
+				ReadOnlyHint:    ToBoolPtr(false),
+				DestructiveHint: ToBoolPtr(true),
+			}),
+			mcp.WithString("owner",
+				mcp.Required(),
+				mcp.Description(DescriptionRepositoryOwner),
+			),
+			mcp.WithString("repo",
+				mcp.Required(),
+				mcp.Description(DescriptionRepositoryName),
+			),
+			mcp.WithString("new_owner",
+				mcp.Required(),
+				mcp.Description("User or organization to transfer the repository to"),
+			),
+			mcp.WithString("new_name",
+				mcp.Description("New name of the repository (omit to keep the current name)"),
+			),
+			mcp.WithArray("team_ids",
+				mcp.Description("IDs of the teams of the new organization to grant access to the repository (e.g. [\"1234\"])"),
+				mcp.WithStringItems(),
+			),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			owner, err := RequiredParam[string](request, "owner")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			repo, err := RequiredParam[string](request, "repo")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			newOwner, err := RequiredParam[string](request, "new_owner")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			newName, err := OptionalParam[string](request, "new_name")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			teamIDs, err := OptionalBigIntArrayParam(request, "team_ids")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			transferRequest := github.TransferRequest{NewOwner: newOwner}
+			if newName != "" {
+				transferRequest.NewName = github.Ptr(newName)
+			}
+			if len(teamIDs) > 0 {
+				transferRequest.TeamID = teamIDs
+			}
+
+			client, err := getClient(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get GitHub client: %w", err)
+			}
+
+			_, resp, err := client.Repositories.Transfer(ctx, owner, repo, transferRequest)
+			// GitHub answers 202 Accepted and transfers the repository in the background
+			if err != nil && !isAcceptedError(err) {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx,
+					"failed to transfer repository",
+					resp,
+					err,
+				), nil
+			}
+			defer func() { _ = resp.Body.Close() }()
+
+			if newName == "" {
+				newName = repo
+			}
+			return mcp.NewToolResultText(fmt.Sprintf("Started the transfer of %s/%s to %s/%s. The transfer completes in the background, or once accepted when transferring to a user", owner, repo, newOwner, newName)), nil
+		}
+}
+
+// DeleteRepository creates a tool to delete a repository. Only the repositories matching one of the
+// patterns of allowlist can be deleted.
+func DeleteRepository(getClient GetClientFn, t translations.TranslationHelperFunc, allowlist []string) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("delete_repository",
+			mcp.WithDescription(t("TOOL_DELETE_REPOSITORY_DESCRIPTION", "Permanently delete a GitHub repository. Only the repositories allowlisted in the configuration of the server can be deleted")),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:           t("TOOL_DELETE_REPOSITORY_USER_TITLE", "Delete repository"),
+				ReadOnlyHint:    ToBoolPtr(false),
+				DestructiveHint: ToBoolPtr(true),
+			}),
+			mcp.WithString("owner",
+				mcp.Required(),
+				mcp.Description(DescriptionRepositoryOwner),
+			),
+			mcp.WithString("repo",
+				mcp.Required(),
+				mcp.Description(DescriptionRepositoryName),
+			),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			owner, err := RequiredParam[string](request, "owner")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			repo, err := RequiredParam[string](request, "repo")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			if !isRepositoryDeletionAllowed(allowlist, owner, repo) {
+				return mcp.NewToolResultError(fmt.Sprintf("deleting %s/%s is not allowed: it does not match the repository deletion allowlist of the server", owner, repo)), nil
+			}
+
+			client, err := getClient(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get GitHub client: %w", err)
+			}
+
+			resp, err := client.Repositories.Delete(ctx, owner, repo)
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx,
+					"failed to delete repository",
+					resp,
+					err,
+				), nil
+			}
+			defer func() { _ = resp.Body.Close() }()
+
+			return mcp.NewToolResultText(fmt.Sprintf("Deleted repository %s/%s", owner, repo)), nil
+		}
+}
@@ -0,0 +1,324 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/github/github-mcp-server/internal/toolsnaps"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/google/go-github/v77/github"
+	"github.com/migueleliasweb/go-github-mock/src/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_CreateRepositoryFromTemplate(t *testing.T) {
+	tool, _ := CreateRepositoryFromTemplate(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+	assert.False(t, *tool.Annotations.ReadOnlyHint)
+	assert.ElementsMatch(t, tool.InputSchema.Required, []string{"template_owner", "template_repo", "name"})
+
+	mockCreatedRepo := map[string]any{
+		"id":       123456,
+		"name":     "payments-service",
+		"html_url": "https://github.com/platform/payments-service",
+	}
+
+	tests := []struct {
+		name          string
+		mockedClient  *http.Client
+		requestArgs   map[string]any
+		expectError   string
+		expectedValue MinimalResponse
+	}{
+		{
+			name: "creates repository in an organization with all branches",
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(
+					mock.PostReposGenerateByTemplateOwnerByTemplateRepo,
+					expect(t, expectations{
+						path: "/repos/platform/service-template/generate",
+						requestBody: map[string]any{
+							"name":                 "payments-service",
+							"owner":                "platform",
+							"description":          "Payments service",
+							"private":              true,
+							"include_all_branches": true,
+						},
+					}).andThen(
+						mockResponse(t, http.StatusCreated, mockCreatedRepo),
+					),
+				),
+			),
+			requestArgs: map[string]any{
+				"template_owner":       "platform",
+				"template_repo":        "service-template",
+				"name":                 "payments-service",
+				"owner":                "platform",
+				"description":          "Payments service",
+				"private":              true,
+				"include_all_branches": true,
+			},
+			expectedValue: MinimalResponse{ID: "123456", URL: "https://github.com/platform/payments-service"},
+		},
+		{
+			name: "creates repository in the personal account with the default branch only",
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(
+					mock.PostReposGenerateByTemplateOwnerByTemplateRepo,
+					expectRequestBody(t, map[string]any{
+						"name":                 "payments-service",
+						"private":              false,
+						"include_all_branches": false,
+					}).andThen(
+						mockResponse(t, http.StatusCreated, mockCreatedRepo),
+					),
+				),
+			),
+			requestArgs: map[string]any{
+				"template_owner": "platform",
+				"template_repo":  "service-template",
+				"name":           "payments-service",
+			},
+			expectedValue: MinimalResponse{ID: "123456", URL: "https://github.com/platform/payments-service"},
+		},
+		{
+			name: "template repository not found",
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(
+					mock.PostReposGenerateByTemplateOwnerByTemplateRepo,
+					mockResponse(t, http.StatusNotFound, map[string]string{"message": "Not Found"}),
+				),
+			),
+			requestArgs: map[string]any{
+				"template_owner": "platform",
+				"template_repo":  "missing-template",
+				"name":           "payments-service",
+			},
+			expectError: "failed to create repository from template platform/missing-template",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, handler := CreateRepositoryFromTemplate(stubGetClientFn(github.NewClient(tc.mockedClient)), translations.NullTranslationHelper)
+			result, err := handler(context.Background(), createMCPRequest(tc.requestArgs))
+			require.NoError(t, err)
+
+			if tc.expectError != "" {
+				errorContent := getErrorResult(t, result)
+				assert.Contains(t, errorContent.Text, tc.expectError)
+				return
+			}
+
+			var returned MinimalResponse
+			require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &returned))
+			assert.Equal(t, tc.expectedValue, returned)
+		})
+	}
+}
+
+func Test_TransferRepository(t *testing.T) {
+	tool, _ := TransferRepository(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+	assert.False(t, *tool.Annotations.ReadOnlyHint)
+	assert.True(t, *tool.Annotations.DestructiveHint)
+	assert.ElementsMatch(t, tool.InputSchema.Required, []string{"owner", "repo", "new_owner"})
+
+	tests := []struct {
+		name         string
+		mockedClient *http.Client
+		requestArgs  map[string]any
+		expectError  string
+		expectedText string
+	}{
+		{
+			name: "transfer with new name and teams",
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(
+					mock.PostReposTransferByOwnerByRepo,
+					expect(t, expectations{
+						path: "/repos/mona/payments/transfer",
+						requestBody: map[string]any{
+							"new_owner": "platform",
+							"new_name":  "payments-service",
+							"team_ids":  []any{float64(1), float64(2)},
+						},
+					}).andThen(
+						mockResponse(t, http.StatusAccepted, map[string]any{"name": "payments"}),
+					),
+				),
+			),
+			requestArgs: map[string]any{
+				"owner":     "mona",
+				"repo":      "payments",
+				"new_owner": "platform",
+				"new_name":  "payments-service",
+				"team_ids":  []any{"1", "2"},
+			},
+			expectedText: "Started the transfer of mona/payments to platform/payments-service",
+		},
+		{
+			name: "transfer keeping the current name",
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(
+					mock.PostReposTransferByOwnerByRepo,
+					expectRequestBody(t, map[string]any{"new_owner": "platform"}).andThen(
+						mockResponse(t, http.StatusAccepted, map[string]any{"name": "payments"}),
+					),
+				),
+			),
+			requestArgs: map[string]any{
+				"owner":     "mona",
+				"repo":      "payments",
+				"new_owner": "platform",
+			},
+			expectedText: "Started the transfer of mona/payments to platform/payments",
+		},
+		{
+			name: "transfer forbidden",
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(
+					mock.PostReposTransferByOwnerByRepo,
+					mockResponse(t, http.StatusForbidden, map[string]string{"message": "You don't have the permission to create repositories on platform"}),
+				),
+			),
+			requestArgs: map[string]any{
+				"owner":     "mona",
+				"repo":      "payments",
+				"new_owner": "platform",
+			},
+			expectError: "failed to transfer repository",
+		},
+		{
+			name:         "invalid team id",
+			mockedClient: mock.NewMockedHTTPClient(),
+			requestArgs: map[string]any{
+				"owner":     "mona",
+				"repo":      "payments",
+				"new_owner": "platform",
+				"team_ids":  []any{"not-a-number"},
+			},
+			expectError: "team_ids",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, handler := TransferRepository(stubGetClientFn(github.NewClient(tc.mockedClient)), translations.NullTranslationHelper)
+			result, err := handler(context.Background(), createMCPRequest(tc.requestArgs))
+			require.NoError(t, err)
+
+			if tc.expectError != "" {
+				errorContent := getErrorResult(t, result)
+				assert.Contains(t, errorContent.Text, tc.expectError)
+				return
+			}
+
+			assert.Contains(t, getTextResult(t, result).Text, tc.expectedText)
+		})
+	}
+}
+
+func Test_DeleteRepository(t *testing.T) {
+	tool, _ := DeleteRepository(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper, []string{"owner/repo"})
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+	assert.False(t, *tool.Annotations.ReadOnlyHint)
+	assert.True(t, *tool.Annotations.DestructiveHint)
+	assert.ElementsMatch(t, tool.InputSchema.Required, []string{"owner", "repo"})
+
+	allowlist := []string{"platform/sandbox-*", "mona/scratch"}
+
+	tests := []struct {
+		name         string
+		mockedClient *http.Client
+		requestArgs  map[string]any
+		expectError  string
+		expectedText string
+	}{
+		{
+			name: "deletes repository matching a pattern",
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(
+					mock.DeleteReposByOwnerByRepo,
+					expectPath(t, "/repos/platform/sandbox-payments").andThen(
+						mockResponse(t, http.StatusNoContent, nil),
+					),
+				),
+			),
+			requestArgs:  map[string]any{"owner": "platform", "repo": "sandbox-payments"},
+			expectedText: "Deleted repository platform/sandbox-payments",
+		},
+		{
+			name: "deletes allowlisted repository regardless of case",
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(
+					mock.DeleteReposByOwnerByRepo,
+					mockResponse(t, http.StatusNoContent, nil),
+				),
+			),
+			requestArgs:  map[string]any{"owner": "Mona", "repo": "Scratch"},
+			expectedText: "Deleted repository Mona/Scratch",
+		},
+		{
+			name:         "refuses repository outside of the allowlist",
+			mockedClient: mock.NewMockedHTTPClient(),
+			requestArgs:  map[string]any{"owner": "platform", "repo": "payments-service"},
+			expectError:  "deleting platform/payments-service is not allowed",
+		},
+		{
+			name: "delete forbidden",
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(
+					mock.DeleteReposByOwnerByRepo,
+					mockResponse(t, http.StatusForbidden, map[string]string{"message": "Must have admin rights to Repository."}),
+				),
+			),
+			requestArgs: map[string]any{"owner": "mona", "repo": "scratch"},
+			expectError: "failed to delete repository",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, handler := DeleteRepository(stubGetClientFn(github.NewClient(tc.mockedClient)), translations.NullTranslationHelper, allowlist)
+			result, err := handler(context.Background(), createMCPRequest(tc.requestArgs))
+			require.NoError(t, err)
+
+			if tc.expectError != "" {
+				errorContent := getErrorResult(t, result)
+				assert.Contains(t, errorContent.Text, tc.expectError)
+				return
+			}
+
+			assert.Equal(t, tc.expectedText, getTextResult(t, result).Text)
+		})
+	}
+}
+
+func Test_IsRepositoryDeletionAllowed(t *testing.T) {
+	tests := []struct {
+		name      string
+		allowlist []string
+		owner     string
+		repo      string
+		expected  bool
+	}{
+		{name: "empty allowlist", allowlist: nil, owner: "mona", repo: "scratch", expected: false},
+		{name: "exact match", allowlist: []string{"mona/scratch"}, owner: "mona", repo: "scratch", expected: true},
+		{name: "case-insensitive match", allowlist: []string{"Mona/Scratch"}, owner: "mona", repo: "SCRATCH", expected: true},
+		{name: "whole owner", allowlist: []string{"platform/*"}, owner: "platform", repo: "payments", expected: true},
+		{name: "prefix pattern", allowlist: []string{"platform/sandbox-*"}, owner: "platform", repo: "payments", expected: false},
+		{name: "pattern does not match other owners", allowlist: []string{"platform/*"}, owner: "mona", repo: "payments", expected: false},
+		{name: "wildcard alone is ignored", allowlist: []string{"*"}, owner: "mona", repo: "scratch", expected: false},
+		{name: "surrounding spaces are ignored", allowlist: []string{" mona/scratch "}, owner: "mona", repo: "scratch", expected: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, isRepositoryDeletionAllowed(tc.allowlist, tc.owner, tc.repo))
+		})
+	}
+}
@@ -154,7 +154,27 @@ func RepositoryResourceContentsHandler(getClient GetClientFn, getRawClient raw.G
 				mimeType = mime.TypeByExtension(ext)
 			}
 
-			content, err := io.ReadAll(resp.Body)
+			pointer, body, err := raw.DetectLFSPointer(resp.Body)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read file content: %w", err)
+			}
+			if pointer != nil {
+				lfsResp, err := rawClient.GetLFSObject(ctx, owner, repo, pointer)
+				switch {
+				case err == nil:
+					defer func() { _ = lfsResp.Body.Close() }()
+					body = lfsResp.Body
+					if ext != ".md" {
+						mimeType = lfsContentType(path, lfsResp)
+					}
+				case errors.Is(err, raw.ErrLFSDisabled), errors.Is(err, raw.ErrLFSObjectTooLarge):
+					// The pointer is returned as the content, which at least tells the client the file is in LFS
+				default:
+					return nil, fmt.Errorf("failed to get git LFS object: %w", err)
+				}
+			}
+
+			content, err := io.ReadAll(body)
 			if err != nil {
 				return nil, fmt.Errorf("failed to read file content: %w", err)
 			}
@@ -0,0 +1,361 @@
+package github
+
+import (
+	"context"
+	"fmt"
+
+	ghErrors "github.com/github/github-mcp-server/pkg/errors"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/google/go-github/v77/github"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// RepositoryMergeSettings are the pull request merge settings of a repository. The API only returns them to
+// the users who can push to the repository, so the settings are left out rather than reported as false.
+type RepositoryMergeSettings struct {
+	AllowMergeCommit         *bool  `json:"allow_merge_commit,omitempty"`
+	AllowSquashMerge         *bool  `json:"allow_squash_merge,omitempty"`
+	AllowRebaseMerge         *bool  `json:"allow_rebase_merge,omitempty"`
+	AllowAutoMerge           *bool  `json:"allow_auto_merge,omitempty"`
+	AllowUpdateBranch        *bool  `json:"allow_update_branch,omitempty"`
+	DeleteBranchOnMerge      *bool  `json:"delete_branch_on_merge,omitempty"`
+	SquashMergeCommitTitle   string `json:"squash_merge_commit_title,omitempty"`
+	SquashMergeCommitMessage string `json:"squash_merge_commit_message,omitempty"`
+	MergeCommitTitle         string `json:"merge_commit_title,omitempty"`
+	MergeCommitMessage       string `json:"merge_commit_message,omitempty"`
+}
+
+// RepositoryDetails is the output type of get_repository and update_repository_settings.
+type RepositoryDetails struct {
+	ID             int64                    `json:"id"`
+	FullName       string                   `json:"full_name"`
+	Description    string                   `json:"description,omitempty"`
+	Homepage       string                   `json:"homepage,omitempty"`
+	HTMLURL        string                   `json:"html_url"`
+	Visibility     string                   `json:"visibility"`
+	DefaultBranch  string                   `json:"default_branch"`
+	Topics         []string                 `json:"topics,omitempty"`
+	Language       string                   `json:"language,omitempty"`
+	Languages      map[string]int           `json:"languages,omitempty"`
+	License        string                   `json:"license,omitempty"`
+	Fork           bool                     `json:"fork"`
+	Parent         string                   `json:"parent,omitempty"`
+	Archived       bool                     `json:"archived"`
+	IsTemplate     bool                     `json:"is_template"`
+	HasIssues      bool                     `json:"has_issues"`
+	HasProjects    bool                     `json:"has_projects"`
+	HasWiki        bool                     `json:"has_wiki"`
+	HasDiscussions bool                     `json:"has_discussions"`
+	Stars          int                      `json:"stargazers_count"`
+	Forks          int                      `json:"forks_count"`
+	Watchers       int                      `json:"subscribers_count"`
+	OpenIssues     int                      `json:"open_issues_count"`
+	CreatedAt      string                   `json:"created_at,omitempty"`
+	PushedAt       string                   `json:"pushed_at,omitempty"`
+	MergeSettings  *RepositoryMergeSettings `json:"merge_settings,omitempty"`
+	// Security is the status of the security features, only visible to the repository admins.
+	Security map[string]string `json:"security,omitempty"`
+}
+
+// convertToRepositoryDetails converts a GitHub API Repository and its languages to RepositoryDetails.
+func convertToRepositoryDetails(repo *github.Repository, languages map[string]int) RepositoryDetails {
+	details := RepositoryDetails{
+		ID:             repo.GetID(),
+		FullName:       repo.GetFullName(),
+		Description:    repo.GetDescription(),
+		Homepage:       repo.GetHomepage(),
+		HTMLURL:        repo.GetHTMLURL(),
+		Visibility:     repo.GetVisibility(),
+		DefaultBranch:  repo.GetDefaultBranch(),
+		Topics:         repo.Topics,
+		Language:       repo.GetLanguage(),
+		Languages:      languages,
+		License:        repo.GetLicense().GetSPDXID(),
+		Fork:           repo.GetFork(),
+		Parent:         repo.GetParent().GetFullName(),
+		Archived:       repo.GetArchived(),
+		IsTemplate:     repo.GetIsTemplate(),
+		HasIssues:      repo.GetHasIssues(),
+		HasProjects:    repo.GetHasProjects(),
+		HasWiki:        repo.GetHasWiki(),
+		HasDiscussions: repo.GetHasDiscussions(),
+		Stars:          repo.GetStargazersCount(),
+		Forks:          repo.GetForksCount(),
+		Watchers:       repo.GetSubscribersCount(),
+		OpenIssues:     repo.GetOpenIssuesCount(),
+	}
+	mergeSettings := RepositoryMergeSettings{
+		AllowMergeCommit:         repo.AllowMergeCommit,
+		AllowSquashMerge:         repo.AllowSquashMerge,
+		AllowRebaseMerge:         repo.AllowRebaseMerge,
+		AllowAutoMerge:           repo.AllowAutoMerge,
+		AllowUpdateBranch:        repo.AllowUpdateBranch,
+		DeleteBranchOnMerge:      repo.DeleteBranchOnMerge,
+		SquashMergeCommitTitle:   repo.GetSquashMergeCommitTitle(),
+		SquashMergeCommitMessage: repo.GetSquashMergeCommitMessage(),
+		MergeCommitTitle:         repo.GetMergeCommitTitle(),
+		MergeCommitMessage:       repo.GetMergeCommitMessage(),
+	}
+	if mergeSettings != (RepositoryMergeSettings{}) {
+		details.MergeSettings = &mergeSettings
+	}
+	if details.Visibility == "" {
+		details.Visibility = "public"
+		if repo.GetPrivate() {
+			details.Visibility = "private"
+		}
+	}
+	if repo.CreatedAt != nil {
+		details.CreatedAt = repo.CreatedAt.Format("2006-01-02T15:04:05Z")
+	}
+	if repo.PushedAt != nil {
+		details.PushedAt = repo.PushedAt.Format("2006-01-02T15:04:05Z")
+	}
+	if security := repo.GetSecurityAndAnalysis(); security != nil {
+		details.Security = make(map[string]string)
+		for feature, status := range map[string]string{
+			"advanced_security":               security.GetAdvancedSecurity().GetStatus(),
+			"secret_scanning":                 security.GetSecretScanning().GetStatus(),
+			"secret_scanning_push_protection": security.GetSecretScanningPushProtection().GetStatus(),
+			"secret_scanning_validity_checks": security.GetSecretScanningValidityChecks().GetStatus(),
+			"dependabot_security_updates":     security.GetDependabotSecurityUpdates().GetStatus(),
+		} {
+			if status != "" {
+				details.Security[feature] = status
+			}
+		}
+	}
+	return details
+}
+
+// GetRepository creates a tool to get the metadata and settings of a repository.
+func GetRepository(getClient GetClientFn, t translations.TranslationHelperFunc) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("get_repository",
+			mcp.WithDescription(t("TOOL_GET_REPOSITORY_DESCRIPTION", "Get the metadata and settings of a GitHub repository: visibility, default branch, topics, languages, license, merge settings and, for admins, the status of security features")),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:        t("TOOL_GET_REPOSITORY_USER_TITLE", "Get repository"),
+				ReadOnlyHint: ToBoolPtr(true),
+			}),
+			mcp.WithString("owner",
+				mcp.Required(),
+				mcp.Description("Repository owner"),
+			),
+			mcp.WithString("repo",
+				mcp.Required(),
+				mcp.Description("Repository name"),
+			),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			owner, err := RequiredParam[string](request, "owner")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			repo, err := RequiredParam[string](request, "repo")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			client, err := getClient(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get GitHub client: %w", err)
+			}
+
+			repository, resp, err := client.Repositories.Get(ctx, owner, repo)
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx,
+					"failed to get repository",
+					resp,
+					err,
+				), nil
+			}
+			_ = resp.Body.Close()
+
+			languages, resp, err := client.Repositories.ListLanguages(ctx, owner, repo)
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx,
+					"failed to list repository languages",
+					resp,
+					err,
+				), nil
+			}
+			defer func() { _ = resp.Body.Close() }()
+
+			return MarshalledTextResult(convertToRepositoryDetails(repository, languages)), nil
+		}
+}
+
+// UpdateRepositorySettings creates a tool to update the metadata and settings of a repository.
+func UpdateRepositorySettings(getClient GetClientFn, t translations.TranslationHelperFunc) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("update_repository_settings",
+			mcp.WithDescription(t("TOOL_UPDATE_REPOSITORY_SETTINGS_DESCRIPTION", "Update the metadata and settings of a GitHub repository. Only the given settings change. An archived repository is read-only until it is unarchived")),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:        t("TOOL_UPDATE_REPOSITORY_SETTINGS_USER_TITLE", "Update repository settings"),
+				ReadOnlyHint: ToBoolPtr(false),
+			}),
+			mcp.WithString("owner",
+				mcp.Required(),
+				mcp.Description("Repository owner"),
+			),
+			mcp.WithString("repo",
+				mcp.Required(),
+				mcp.Description("Repository name"),
+			),
+			mcp.WithString("description",
+				mcp.Description("Short description of the repository"),
+			),
+			mcp.WithString("homepage",
+				mcp.Description("URL of the website of the repository"),
+			),
+			mcp.WithArray("topics",
+				mcp.Description("Topics of the repository, replacing the current ones"),
+				mcp.Items(map[string]interface{}{"type": "string"}),
+			),
+			mcp.WithBoolean("allow_merge_commit",
+				mcp.Description("Allow merging pull requests with a merge commit"),
+			),
+			mcp.WithBoolean("allow_squash_merge",
+				mcp.Description("Allow squash-merging pull requests"),
+			),
+			mcp.WithBoolean("allow_rebase_merge",
+				mcp.Description("Allow rebase-merging pull requests"),
+			),
+			mcp.WithBoolean("allow_auto_merge",
+				mcp.Description("Allow auto-merge on pull requests"),
+			),
+			mcp.WithBoolean("allow_update_branch",
+				mcp.Description("Suggest updating pull request branches that are behind their base"),
+			),
+			mcp.WithBoolean("delete_branch_on_merge",
+				mcp.Description("Delete head branches automatically when their pull request is merged"),
+			),
+			mcp.WithBoolean("archived",
+				mcp.Description("true archives the repository, false unarchives it"),
+			),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			owner, err := RequiredParam[string](request, "owner")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			repo, err := RequiredParam[string](request, "repo")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			// Build the update only with provided fields
+			update := &github.Repository{}
+			updateNeeded := false
+			for param, field := range map[string]**string{
+				"description": &update.Description,
+				"homepage":    &update.Homepage,
+			} {
+				if value, ok, err := OptionalParamOK[string](request, param); err != nil {
+					return mcp.NewToolResultError(err.Error()), nil
+				} else if ok {
+					*field = github.Ptr(value)
+					updateNeeded = true
+				}
+			}
+			for param, field := range map[string]**bool{
+				"allow_merge_commit":     &update.AllowMergeCommit,
+				"allow_squash_merge":     &update.AllowSquashMerge,
+				"allow_rebase_merge":     &update.AllowRebaseMerge,
+				"allow_auto_merge":       &update.AllowAutoMerge,
+				"allow_update_branch":    &update.AllowUpdateBranch,
+				"delete_branch_on_merge": &update.DeleteBranchOnMerge,
+			} {
+				if value, ok, err := OptionalParamOK[bool](request, param); err != nil {
+					return mcp.NewToolResultError(err.Error()), nil
+				} else if ok {
+					*field = github.Ptr(value)
+					updateNeeded = true
+				}
+			}
+			_, topicsProvided := request.GetArguments()["topics"]
+			topics, err := OptionalStringArrayParam(request, "topics")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			archived, archivedProvided, err := OptionalParamOK[bool](request, "archived")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			if !updateNeeded && !topicsProvided && !archivedProvided {
+				return mcp.NewToolResultError("No update parameters provided."), nil
+			}
+
+			client, err := getClient(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get GitHub client: %w", err)
+			}
+
+			// Archived repositories can't be changed, so unarchive first and archive last. When a later step
+			// fails, the error tells the repository is left unarchived.
+			unarchived := false
+			failure := func(message string) string {
+				if unarchived {
+					return message + ", the repository was unarchived and is left unarchived"
+				}
+				return message
+			}
+			edit := func(update *github.Repository) (*github.Repository, *mcp.CallToolResult) {
+				repository, resp, err := client.Repositories.Edit(ctx, owner, repo, update)
+				if err != nil {
+					return nil, ghErrors.NewGitHubAPIErrorResponse(ctx,
+						failure("failed to update repository"),
+						resp,
+						err,
+					)
+				}
+				_ = resp.Body.Close()
+				return repository, nil
+			}
+			var repository *github.Repository
+			var errResult *mcp.CallToolResult
+			if archivedProvided && !archived {
+				if repository, errResult = edit(&github.Repository{Archived: github.Ptr(false)}); errResult != nil {
+					return errResult, nil
+				}
+				unarchived = true
+			}
+			if updateNeeded {
+				if repository, errResult = edit(update); errResult != nil {
+					return errResult, nil
+				}
+			}
+			if topicsProvided {
+				replaced, resp, err := client.Repositories.ReplaceAllTopics(ctx, owner, repo, topics)
+				if err != nil {
+					return ghErrors.NewGitHubAPIErrorResponse(ctx,
+						failure("failed to update repository topics"),
+						resp,
+						err,
+					), nil
+				}
+				_ = resp.Body.Close()
+				if repository != nil {
+					repository.Topics = replaced
+				}
+			}
+			if archivedProvided && archived {
+				if repository, errResult = edit(&github.Repository{Archived: github.Ptr(true)}); errResult != nil {
+					return errResult, nil
+				}
+			}
+			if repository == nil {
+				var resp *github.Response
+				repository, resp, err = client.Repositories.Get(ctx, owner, repo)
+				if err != nil {
+					return ghErrors.NewGitHubAPIErrorResponse(ctx,
+						"failed to get repository",
+						resp,
+						err,
+					), nil
+				}
+				_ = resp.Body.Close()
+			}
+
+			return MarshalledTextResult(convertToRepositoryDetails(repository, nil)), nil
+		}
+}
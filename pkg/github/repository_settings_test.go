@@ -0,0 +1,205 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/github/github-mcp-server/internal/toolsnaps"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/google/go-github/v77/github"
+	"github.com/migueleliasweb/go-github-mock/src/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_GetRepository(t *testing.T) {
+	tool, _ := GetRepository(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+	assert.True(t, *tool.Annotations.ReadOnlyHint)
+	assert.ElementsMatch(t, tool.InputSchema.Required, []string{"owner", "repo"})
+
+	mockedClient := mock.NewMockedHTTPClient(
+		mock.WithRequestMatch(
+			mock.GetReposByOwnerByRepo,
+			&github.Repository{
+				ID:                     github.Ptr(int64(1)),
+				FullName:               github.Ptr("owner/repo"),
+				Visibility:             github.Ptr("internal"),
+				DefaultBranch:          github.Ptr("main"),
+				Topics:                 []string{"mcp", "go"},
+				License:                &github.License{SPDXID: github.Ptr("MIT")},
+				AllowSquashMerge:       github.Ptr(true),
+				SquashMergeCommitTitle: github.Ptr("PR_TITLE"),
+				DeleteBranchOnMerge:    github.Ptr(true),
+				SecurityAndAnalysis: &github.SecurityAndAnalysis{
+					SecretScanning:               &github.SecretScanning{Status: github.Ptr("enabled")},
+					SecretScanningPushProtection: &github.SecretScanningPushProtection{Status: github.Ptr("disabled")},
+				},
+			},
+		),
+		mock.WithRequestMatch(
+			mock.GetReposLanguagesByOwnerByRepo,
+			map[string]int{"Go": 1000, "Shell": 20},
+		),
+	)
+	_, handler := GetRepository(stubGetClientFn(github.NewClient(mockedClient)), translations.NullTranslationHelper)
+	result, err := handler(context.Background(), createMCPRequest(map[string]any{
+		"owner": "owner",
+		"repo":  "repo",
+	}))
+	require.NoError(t, err)
+
+	var details RepositoryDetails
+	require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &details))
+	assert.Equal(t, RepositoryDetails{
+		ID:            1,
+		FullName:      "owner/repo",
+		Visibility:    "internal",
+		DefaultBranch: "main",
+		Topics:        []string{"mcp", "go"},
+		Languages:     map[string]int{"Go": 1000, "Shell": 20},
+		License:       "MIT",
+		MergeSettings: &RepositoryMergeSettings{
+			AllowSquashMerge:       github.Ptr(true),
+			SquashMergeCommitTitle: "PR_TITLE",
+			DeleteBranchOnMerge:    github.Ptr(true),
+		},
+		Security: map[string]string{
+			"secret_scanning":                 "enabled",
+			"secret_scanning_push_protection": "disabled",
+		},
+	}, details)
+}
+
+func Test_UpdateRepositorySettings(t *testing.T) {
+	tool, _ := UpdateRepositorySettings(stubGetClientFn(github.NewClient(nil)), translations.NullTranslationHelper)
+	require.NoError(t, toolsnaps.Test(tool.Name, tool))
+	assert.False(t, *tool.Annotations.ReadOnlyHint)
+	assert.ElementsMatch(t, tool.InputSchema.Required, []string{"owner", "repo"})
+
+	tests := []struct {
+		name             string
+		mockedClient     *http.Client
+		requestArgs      map[string]any
+		expectError      string
+		expectErrorStart string
+		expectedArchived bool
+		expectedTopics   []string
+	}{
+		{
+			name: "unarchives before updating settings and topics",
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(
+					mock.PatchReposByOwnerByRepo,
+					// The repository is unarchived first, then its settings are updated
+					func() http.HandlerFunc {
+						edits := []map[string]any{
+							{"archived": false},
+							{"description": "An MCP server", "allow_rebase_merge": false, "delete_branch_on_merge": true},
+						}
+						return func(w http.ResponseWriter, r *http.Request) {
+							var body map[string]any
+							require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+							require.NotEmpty(t, edits, "unexpected edit %v", body)
+							assert.Equal(t, edits[0], body)
+							edits = edits[1:]
+							mockResponse(t, http.StatusOK, &github.Repository{
+								FullName:    github.Ptr("owner/repo"),
+								Description: github.Ptr("An MCP server"),
+								Topics:      []string{"old"},
+							})(w, r)
+						}
+					}(),
+				),
+				mock.WithRequestMatchHandler(
+					mock.PutReposTopicsByOwnerByRepo,
+					expectRequestBody(t, map[string]any{"names": []any{"mcp", "go"}}).andThen(
+						mockResponse(t, http.StatusOK, map[string]any{"names": []string{"mcp", "go"}}),
+					),
+				),
+			),
+			requestArgs: map[string]any{
+				"archived":               false,
+				"description":            "An MCP server",
+				"allow_rebase_merge":     false,
+				"delete_branch_on_merge": true,
+				"topics":                 []any{"mcp", "go"},
+			},
+			expectedTopics: []string{"mcp", "go"},
+		},
+		{
+			name: "archives",
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(
+					mock.PatchReposByOwnerByRepo,
+					expectRequestBody(t, map[string]any{"archived": true}).andThen(
+						mockResponse(t, http.StatusOK, &github.Repository{FullName: github.Ptr("owner/repo"), Archived: github.Ptr(true)}),
+					),
+				),
+			),
+			requestArgs:      map[string]any{"archived": true},
+			expectedArchived: true,
+		},
+		{
+			name: "failure after unarchiving",
+			mockedClient: mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(
+					mock.PatchReposByOwnerByRepo,
+					func() http.HandlerFunc {
+						edits := 0
+						return func(w http.ResponseWriter, r *http.Request) {
+							edits++
+							if edits == 1 {
+								mockResponse(t, http.StatusOK, &github.Repository{FullName: github.Ptr("owner/repo")})(w, r)
+								return
+							}
+							mockResponse(t, http.StatusUnprocessableEntity, map[string]string{"message": "Validation Failed"})(w, r)
+						}
+					}(),
+				),
+			),
+			requestArgs: map[string]any{
+				"archived":    false,
+				"description": "An MCP server",
+			},
+			expectErrorStart: "failed to update repository, the repository was unarchived and is left unarchived: ",
+		},
+		{
+			name:         "nothing to update",
+			mockedClient: mock.NewMockedHTTPClient(),
+			requestArgs:  map[string]any{},
+			expectError:  "No update parameters provided.",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, handler := UpdateRepositorySettings(stubGetClientFn(github.NewClient(tc.mockedClient)), translations.NullTranslationHelper)
+			args := map[string]any{"owner": "owner", "repo": "repo"}
+			for k, v := range tc.requestArgs {
+				args[k] = v
+			}
+			result, err := handler(context.Background(), createMCPRequest(args))
+			require.NoError(t, err)
+
+			if tc.expectError != "" {
+				assert.Equal(t, tc.expectError, getErrorResult(t, result).Text)
+				return
+			}
+			if tc.expectErrorStart != "" {
+				assert.True(t, strings.HasPrefix(getErrorResult(t, result).Text, tc.expectErrorStart), getErrorResult(t, result).Text)
+				return
+			}
+			var details RepositoryDetails
+			require.NoError(t, json.Unmarshal([]byte(getTextResult(t, result).Text), &details))
+			assert.Equal(t, "owner/repo", details.FullName)
+			assert.Equal(t, tc.expectedArchived, details.Archived)
+			assert.Equal(t, tc.expectedTopics, details.Topics)
+			// The mocked repositories have no merge settings, as for the users without push access
+			assert.Nil(t, details.MergeSettings)
+		})
+	}
+}
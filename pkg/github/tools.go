@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/github/github-mcp-server/pkg/archive"
 	"github.com/github/github-mcp-server/pkg/raw"
 	"github.com/github/github-mcp-server/pkg/toolsets"
 	"github.com/github/github-mcp-server/pkg/translations"
@@ -103,6 +104,18 @@ var (
 		ID:          "stargazers",
 		Description: "GitHub Stargazers related tools",
 	}
+	ToolsetMetadataWebhooks = ToolsetMetadata{
+		ID:          "webhooks",
+		Description: "GitHub Webhooks related tools",
+	}
+	ToolsetMetadataDeployments = ToolsetMetadata{
+		ID:          "deployments",
+		Description: "GitHub Deployments and Environments related tools",
+	}
+	ToolsetMetadataChecks = ToolsetMetadata{
+		ID:          "checks",
+		Description: "GitHub Checks and commit statuses related tools",
+	}
 	ToolsetMetadataDynamic = ToolsetMetadata{
 		ID:          "dynamic",
 		Description: "Discover GitHub MCP tools that can help achieve tasks by enabling additional sets of tools, you can control the enablement of any toolset to access its tools when this toolset is enabled.",
@@ -132,6 +145,9 @@ func AvailableTools() []ToolsetMetadata {
 		ToolsetMetadataSecurityAdvisories,
 		ToolsetMetadataProjects,
 		ToolsetMetadataStargazers,
+		ToolsetMetadataWebhooks,
+		ToolsetMetadataDeployments,
+		ToolsetMetadataChecks,
 		ToolsetMetadataDynamic,
 		ToolsetLabels,
 	}
@@ -159,32 +175,86 @@ func GetDefaultToolsetIDs() []string {
 	}
 }
 
-func DefaultToolsetGroup(readOnly bool, getClient GetClientFn, getGQLClient GetGQLClientFn, getRawClient raw.GetRawClientFn, t translations.TranslationHelperFunc, contentWindowSize int, flags FeatureFlags) *toolsets.ToolsetGroup {
+// ToolsetConfig holds the server settings that tools of the default toolsets are built with.
+type ToolsetConfig struct {
+	// MaxInlineSize is the size limit in bytes for binary files returned inline, DefaultMaxInlineSize when 0
+	MaxInlineSize int
+	// ArchiveCache stores the repository archives of the archive tools, a temporary cache of the
+	// default size when nil
+	ArchiveCache *archive.Cache
+	// RepositoryDeletionAllowlist lists the owner/repo patterns delete_repository may delete.
+	// delete_repository is not registered when it is empty.
+	RepositoryDeletionAllowlist []string
+}
+
+func DefaultToolsetGroup(readOnly bool, getClient GetClientFn, getGQLClient GetGQLClientFn, getRawClient raw.GetRawClientFn, t translations.TranslationHelperFunc, contentWindowSize int, cfg ToolsetConfig, flags FeatureFlags) *toolsets.ToolsetGroup {
 	tsg := toolsets.NewToolsetGroup(readOnly)
 
+	maxInlineSize := cfg.MaxInlineSize
+	if maxInlineSize <= 0 {
+		maxInlineSize = DefaultMaxInlineSize
+	}
+	archiveCache := cfg.ArchiveCache
+	if archiveCache == nil {
+		archiveCache = archive.NewCache("", 0)
+	}
+
 	// Define all available features with their default state (disabled)
 	// Create toolsets
 	repos := toolsets.NewToolset(ToolsetMetadataRepos.ID, ToolsetMetadataRepos.Description).
 		AddReadTools(
 			toolsets.NewServerTool(SearchRepositories(getClient, t)),
-			toolsets.NewServerTool(GetFileContents(getClient, getRawClient, t)),
+			toolsets.NewServerTool(GetRepository(getClient, t)),
+			toolsets.NewServerTool(GetFileContents(getClient, getRawClient, t, maxInlineSize)),
 			toolsets.NewServerTool(ListCommits(getClient, t)),
+			toolsets.NewServerTool(CompareCommits(getClient, t)),
+			toolsets.NewServerTool(GetFileBlame(getGQLClient, t)),
+			toolsets.NewServerTool(ListFileHistory(getClient, t)),
 			toolsets.NewServerTool(SearchCode(getClient, t)),
 			toolsets.NewServerTool(GetCommit(getClient, t)),
 			toolsets.NewServerTool(ListBranches(getClient, t)),
+			toolsets.NewServerTool(GetBranchProtection(getClient, t)),
+			toolsets.NewServerTool(ListRulesets(getClient, t)),
+			toolsets.NewServerTool(GetEffectiveRulesForBranch(getClient, t)),
+			toolsets.NewServerTool(GetCodeOwners(getClient, t)),
+			toolsets.NewServerTool(ListRepositoryCollaborators(getClient, t)),
+			toolsets.NewServerTool(GetCollaboratorPermission(getClient, t)),
+			toolsets.NewServerTool(ListRepositoryTeams(getClient, t)),
 			toolsets.NewServerTool(ListTags(getClient, t)),
 			toolsets.NewServerTool(GetTag(getClient, t)),
 			toolsets.NewServerTool(ListReleases(getClient, t)),
 			toolsets.NewServerTool(GetLatestRelease(getClient, t)),
 			toolsets.NewServerTool(GetReleaseByTag(getClient, t)),
+			toolsets.NewServerTool(GenerateChangelog(getClient, getGQLClient, t)),
+			toolsets.NewServerTool(GetRepositoryArchive(getClient, getRawClient, t, archiveCache)),
+			toolsets.NewServerTool(ListRepositoryArchiveEntries(getClient, getRawClient, t, archiveCache)),
+			toolsets.NewServerTool(GetRepositoryArchiveEntry(getClient, getRawClient, t, archiveCache, maxInlineSize)),
+			toolsets.NewServerTool(GrepRepositoryArchive(getClient, getRawClient, t, archiveCache)),
+			toolsets.NewServerTool(GrepRepository(getClient, getRawClient, t)),
 		).
 		AddWriteTools(
 			toolsets.NewServerTool(CreateOrUpdateFile(getClient, t)),
 			toolsets.NewServerTool(CreateRepository(getClient, t)),
+			toolsets.NewServerTool(CreateRepositoryFromTemplate(getClient, t)),
+			toolsets.NewServerTool(TransferRepository(getClient, t)),
+			toolsets.NewServerTool(UpdateRepositorySettings(getClient, t)),
+			toolsets.NewServerTool(AddCollaborator(getClient, t)),
+			toolsets.NewServerTool(RemoveCollaborator(getClient, t)),
+			toolsets.NewServerTool(SetTeamRepositoryPermission(getClient, t)),
 			toolsets.NewServerTool(ForkRepository(getClient, t)),
 			toolsets.NewServerTool(CreateBranch(getClient, t)),
+			toolsets.NewServerTool(DeleteBranch(getClient, t)),
+			toolsets.NewServerTool(RenameBranch(getClient, t)),
+			toolsets.NewServerTool(MergeBranch(getClient, t)),
+			toolsets.NewServerTool(SyncForkBranch(getClient, t)),
 			toolsets.NewServerTool(PushFiles(getClient, t)),
 			toolsets.NewServerTool(DeleteFile(getClient, t)),
+			toolsets.NewServerTool(CommitChanges(getClient, t)),
+			toolsets.NewServerTool(CreateRelease(getClient, t)),
+			toolsets.NewServerTool(UpdateRelease(getClient, t)),
+			toolsets.NewServerTool(DeleteRelease(getClient, t)),
+			toolsets.NewServerTool(UploadReleaseAsset(getClient, t)),
+			toolsets.NewServerTool(DeleteReleaseAsset(getClient, t)),
 		).
 		AddResourceTemplates(
 			toolsets.NewServerResourceTemplate(GetRepositoryResourceContent(getClient, getRawClient, t)),
@@ -193,9 +263,25 @@ func DefaultToolsetGroup(readOnly bool, getClient GetClientFn, getGQLClient GetG
 			toolsets.NewServerResourceTemplate(GetRepositoryResourceTagContent(getClient, getRawClient, t)),
 			toolsets.NewServerResourceTemplate(GetRepositoryResourcePrContent(getClient, getRawClient, t)),
 		)
+	// Deleting repositories is only possible for the repositories allowlisted in the server configuration
+	if len(cfg.RepositoryDeletionAllowlist) > 0 {
+		repos.AddWriteTools(toolsets.NewServerTool(DeleteRepository(getClient, t, cfg.RepositoryDeletionAllowlist)))
+	}
 	git := toolsets.NewToolset(ToolsetMetadataGit.ID, ToolsetMetadataGit.Description).
 		AddReadTools(
 			toolsets.NewServerTool(GetRepositoryTree(getClient, t)),
+			toolsets.NewServerTool(GetGitBlob(getClient, t, maxInlineSize)),
+			toolsets.NewServerTool(GetGitCommit(getClient, t)),
+			toolsets.NewServerTool(GetGitRef(getClient, t)),
+			toolsets.NewServerTool(ListGitRefs(getClient, t)),
+			toolsets.NewServerTool(GetGitTag(getClient, t)),
+		).
+		AddWriteTools(
+			toolsets.NewServerTool(CreateBlob(getClient, t)),
+			toolsets.NewServerTool(CreateTree(getClient, t)),
+			toolsets.NewServerTool(CreateCommit(getClient, t)),
+			toolsets.NewServerTool(UpdateRef(getClient, t)),
+			toolsets.NewServerTool(DeleteRef(getClient, t)),
 		)
 	issues := toolsets.NewToolset(ToolsetMetadataIssues.ID, ToolsetMetadataIssues.Description).
 		AddReadTools(
@@ -311,6 +397,7 @@ func DefaultToolsetGroup(readOnly bool, getClient GetClientFn, getGQLClient GetG
 			toolsets.NewServerTool(GetMe(getClient, t)),
 			toolsets.NewServerTool(GetTeams(getClient, getGQLClient, t)),
 			toolsets.NewServerTool(GetTeamMembers(getGQLClient, t)),
+			toolsets.NewServerTool(DiagnoseRepositoryAccess(getClient, t)),
 		)
 
 	gists := toolsets.NewToolset(ToolsetMetadataGists.ID, ToolsetMetadataGists.Description).
@@ -347,6 +434,45 @@ func DefaultToolsetGroup(readOnly bool, getClient GetClientFn, getGQLClient GetG
 			toolsets.NewServerTool(StarRepository(getClient, t)),
 			toolsets.NewServerTool(UnstarRepository(getClient, t)),
 		)
+	webhooks := toolsets.NewToolset(ToolsetMetadataWebhooks.ID, ToolsetMetadataWebhooks.Description).
+		AddReadTools(
+			toolsets.NewServerTool(ListWebhooks(getClient, t)),
+			toolsets.NewServerTool(GetWebhook(getClient, t)),
+			toolsets.NewServerTool(ListHookDeliveries(getClient, t)),
+			toolsets.NewServerTool(GetHookDelivery(getClient, t)),
+		).
+		AddWriteTools(
+			toolsets.NewServerTool(CreateWebhook(getClient, t)),
+			toolsets.NewServerTool(UpdateWebhook(getClient, t)),
+			toolsets.NewServerTool(DeleteWebhook(getClient, t)),
+			toolsets.NewServerTool(RedeliverHookDelivery(getClient, t)),
+		)
+	deployments := toolsets.NewToolset(ToolsetMetadataDeployments.ID, ToolsetMetadataDeployments.Description).
+		AddReadTools(
+			toolsets.NewServerTool(ListEnvironments(getClient, t)),
+			toolsets.NewServerTool(GetEnvironment(getClient, t)),
+			toolsets.NewServerTool(ListDeployments(getClient, t)),
+			toolsets.NewServerTool(GetDeployment(getClient, t)),
+			toolsets.NewServerTool(ListDeploymentStatuses(getClient, t)),
+			toolsets.NewServerTool(GetPendingDeployments(getClient, t)),
+		).
+		AddWriteTools(
+			toolsets.NewServerTool(CreateDeployment(getClient, t)),
+			toolsets.NewServerTool(CreateDeploymentStatus(getClient, t)),
+			toolsets.NewServerTool(ReviewPendingDeployments(getClient, t)),
+		)
+	checks := toolsets.NewToolset(ToolsetMetadataChecks.ID, ToolsetMetadataChecks.Description).
+		AddReadTools(
+			toolsets.NewServerTool(ListCheckRuns(getClient, t)),
+			toolsets.NewServerTool(ListCheckSuites(getClient, t)),
+			toolsets.NewServerTool(GetCheckRun(getClient, t)),
+		).
+		AddWriteTools(
+			toolsets.NewServerTool(CreateCheckRun(getClient, t)),
+			toolsets.NewServerTool(UpdateCheckRun(getClient, t)),
+			toolsets.NewServerTool(CreateCommitStatus(getClient, t)),
+			toolsets.NewServerTool(RerequestCheckSuite(getClient, t)),
+		)
 	labels := toolsets.NewToolset(ToolsetLabels.ID, ToolsetLabels.Description).
 		AddReadTools(
 			// get
@@ -377,6 +503,9 @@ func DefaultToolsetGroup(readOnly bool, getClient GetClientFn, getGQLClient GetG
 	tsg.AddToolset(securityAdvisories)
 	tsg.AddToolset(projects)
 	tsg.AddToolset(stargazers)
+	tsg.AddToolset(webhooks)
+	tsg.AddToolset(deployments)
+	tsg.AddToolset(checks)
 	tsg.AddToolset(labels)
 
 	return tsg
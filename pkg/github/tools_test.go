@@ -3,6 +3,7 @@ package github
 import (
 	"testing"
 
+	"github.com/github/github-mcp-server/pkg/translations"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -280,3 +281,18 @@ func TestContainsToolset(t *testing.T) {
 		})
 	}
 }
+
+func TestDefaultToolsetGroupRepositoryDeletion(t *testing.T) {
+	hasDeleteRepository := func(cfg ToolsetConfig) bool {
+		tsg := DefaultToolsetGroup(false, nil, nil, nil, translations.NullTranslationHelper, 5000, cfg, FeatureFlags{})
+		for _, tool := range tsg.Toolsets[ToolsetMetadataRepos.ID].GetAvailableTools() {
+			if tool.Tool.Name == "delete_repository" {
+				return true
+			}
+		}
+		return false
+	}
+
+	assert.False(t, hasDeleteRepository(ToolsetConfig{}), "delete_repository should not be registered without an allowlist")
+	assert.True(t, hasDeleteRepository(ToolsetConfig{RepositoryDeletionAllowlist: []string{"owner/*"}}))
+}
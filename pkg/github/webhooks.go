@@ -0,0 +1,840 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	ghErrors "github.com/github/github-mcp-server/pkg/errors"
+	"github.com/github/github-mcp-server/pkg/translations"
+	"github.com/google/go-github/v77/github"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// maxHookPayloadLength is the maximum length of the textual payloads of a hook delivery.
+const maxHookPayloadLength = 10000
+
+// sensitiveHookFieldPattern matches the headers and payload fields of hook deliveries that are redacted.
+var sensitiveHookFieldPattern = regexp.MustCompile(`(?i)(secret|token|password|passwd|signature|authorization|cookie|credential|private[_-]?key|api[_-]?key)`)
+
+// sensitiveHookTextPatterns match the credentials found in free text, such as a response body that isn't JSON.
+var sensitiveHookTextPatterns = []struct {
+	pattern     *regexp.Regexp
+	replacement string
+}{
+	// Bearer and basic credentials, as in an echoed Authorization header
+	{regexp.MustCompile(`(?i)\b(bearer|basic)\s+[\w.~+/=-]+`), "${1} [REDACTED]"},
+	// Values of sensitive keys, as in query strings, form bodies, headers or YAML
+	{regexp.MustCompile(`(?i)([\w-]*(?:secret|token|password|passwd|signature|authorization|cookie|credential|private[_-]?key|api[_-]?key)[\w-]*"?\s*[:=]\s*"?)[^\s"&,;]+`), "${1}[REDACTED]"},
+	// User information of URLs
+	{regexp.MustCompile(`([a-zA-Z][a-zA-Z0-9+.-]*://)[^/\s@]+@`), "${1}[REDACTED]@"},
+}
+
+// MinimalWebhookLastResponse is the last response of a webhook's endpoint.
+type MinimalWebhookLastResponse struct {
+	Code    int    `json:"code,omitempty"`
+	Status  string `json:"status,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// MinimalWebhook is the output type of the webhook tools. The secret is never returned, and the user
+// information, the path below the first segment and the query parameter values of the URL are redacted.
+type MinimalWebhook struct {
+	ID           int64                       `json:"id"`
+	Name         string                      `json:"name"`
+	Active       bool                        `json:"active"`
+	Events       []string                    `json:"events"`
+	URL          string                      `json:"url,omitempty"`
+	ContentType  string                      `json:"content_type,omitempty"`
+	InsecureSSL  bool                        `json:"insecure_ssl"`
+	HasSecret    bool                        `json:"has_secret"`
+	CreatedAt    string                      `json:"created_at,omitempty"`
+	UpdatedAt    string                      `json:"updated_at,omitempty"`
+	LastResponse *MinimalWebhookLastResponse `json:"last_response,omitempty"`
+}
+
+// MinimalHookDelivery is the output type of list_hook_deliveries.
+type MinimalHookDelivery struct {
+	ID          int64   `json:"id"`
+	GUID        string  `json:"guid"`
+	DeliveredAt string  `json:"delivered_at,omitempty"`
+	Event       string  `json:"event"`
+	Action      string  `json:"action,omitempty"`
+	Status      string  `json:"status"`
+	StatusCode  int     `json:"status_code"`
+	Redelivery  bool    `json:"redelivery"`
+	Duration    float64 `json:"duration"`
+}
+
+// HookDeliveryMessage is the request or the response of a hook delivery.
+type HookDeliveryMessage struct {
+	Headers map[string]string `json:"headers,omitempty"`
+	Payload any               `json:"payload,omitempty"`
+}
+
+// HookDeliveryDetails is the output type of get_hook_delivery.
+type HookDeliveryDetails struct {
+	MinimalHookDelivery
+	Request  HookDeliveryMessage `json:"request"`
+	Response HookDeliveryMessage `json:"response"`
+}
+
+func convertToMinimalWebhook(hook *github.Hook) MinimalWebhook {
+	m := MinimalWebhook{
+		ID:     hook.GetID(),
+		Name:   hook.GetName(),
+		Active: hook.GetActive(),
+		Events: hook.Events,
+	}
+	if m.Events == nil {
+		m.Events = []string{}
+	}
+	if config := hook.GetConfig(); config != nil {
+		m.URL = redactHookURL(config.GetURL())
+		m.ContentType = config.GetContentType()
+		m.InsecureSSL = config.GetInsecureSSL() == "1"
+		m.HasSecret = config.GetSecret() != ""
+	}
+	if hook.CreatedAt != nil {
+		m.CreatedAt = hook.CreatedAt.Format("2006-01-02T15:04:05Z")
+	}
+	if hook.UpdatedAt != nil {
+		m.UpdatedAt = hook.UpdatedAt.Format("2006-01-02T15:04:05Z")
+	}
+	if hook.LastResponse != nil {
+		lastResponse := &MinimalWebhookLastResponse{}
+		if code, ok := hook.LastResponse["code"].(float64); ok {
+			lastResponse.Code = int(code)
+		}
+		lastResponse.Status, _ = hook.LastResponse["status"].(string)
+		lastResponse.Message, _ = hook.LastResponse["message"].(string)
+		if *lastResponse != (MinimalWebhookLastResponse{}) {
+			m.LastResponse = lastResponse
+		}
+	}
+	return m
+}
+
+func convertToMinimalHookDelivery(delivery *github.HookDelivery) MinimalHookDelivery {
+	m := MinimalHookDelivery{
+		ID:         delivery.GetID(),
+		GUID:       delivery.GetGUID(),
+		Event:      delivery.GetEvent(),
+		Action:     delivery.GetAction(),
+		Status:     delivery.GetStatus(),
+		StatusCode: delivery.GetStatusCode(),
+		Redelivery: delivery.GetRedelivery(),
+	}
+	if duration := delivery.GetDuration(); duration != nil {
+		m.Duration = *duration
+	}
+	if delivery.DeliveredAt != nil {
+		m.DeliveredAt = delivery.DeliveredAt.Format("2006-01-02T15:04:05Z")
+	}
+	return m
+}
+
+// redactHookHeaders redacts the sensitive headers of a hook delivery, such as its signature.
+func redactHookHeaders(headers map[string]string) map[string]string {
+	redacted := make(map[string]string, len(headers))
+	for name, value := range headers {
+		if sensitiveHookFieldPattern.MatchString(name) {
+			value = "[REDACTED]"
+		}
+		redacted[name] = value
+	}
+	return redacted
+}
+
+// redactHookURL redacts the user information, the path below its first segment and the query parameter
+// values of a webhook URL, which may carry credentials. Services such as Slack and Discord put the token
+// in the path, e.g. /services/T000/B000/<token>.
+func redactHookURL(rawURL string) string {
+	if rawURL == "" {
+		return ""
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "[REDACTED]"
+	}
+	if u.User != nil {
+		u.User = url.User("REDACTED")
+	}
+	if first, rest, _ := strings.Cut(strings.TrimPrefix(u.Path, "/"), "/"); rest != "" {
+		u.Path, u.RawPath = "/"+first+"/REDACTED", ""
+	}
+	if u.RawQuery != "" {
+		query := u.Query()
+		for key := range query {
+			query[key] = []string{"REDACTED"}
+		}
+		u.RawQuery = query.Encode()
+	}
+	return u.String()
+}
+
+// redactHookText redacts the credentials found in free text and truncates it without splitting a
+// multi-byte character.
+func redactHookText(text string) string {
+	for _, p := range sensitiveHookTextPatterns {
+		text = p.pattern.ReplaceAllString(text, p.replacement)
+	}
+	if len(text) > maxHookPayloadLength {
+		n := maxHookPayloadLength
+		for n > 0 && !utf8.RuneStart(text[n]) {
+			n--
+		}
+		text = text[:n] + "... (truncated)"
+	}
+	return text
+}
+
+// redactHookPayload decodes the payload of a hook delivery and redacts its sensitive fields. Textual
+// payloads, such as the body of the response, are decoded as JSON when possible and redacted as free
+// text otherwise.
+func redactHookPayload(raw *json.RawMessage) any {
+	if raw == nil {
+		return nil
+	}
+	var payload any
+	if err := json.Unmarshal(*raw, &payload); err != nil {
+		return nil
+	}
+	if text, ok := payload.(string); ok {
+		if err := json.Unmarshal([]byte(text), &payload); err != nil {
+			return redactHookText(text)
+		}
+	}
+	return redactHookValue(payload)
+}
+
+func redactHookValue(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, field := range v {
+			if _, isString := field.(string); isString && sensitiveHookFieldPattern.MatchString(key) {
+				v[key] = "[REDACTED]"
+				continue
+			}
+			v[key] = redactHookValue(field)
+		}
+	case []any:
+		for i, item := range v {
+			v[i] = redactHookValue(item)
+		}
+	}
+	return value
+}
+
+// hookTarget is the owner of webhooks: a repository, or an organization when repo is empty.
+type hookTarget struct {
+	client *github.Client
+	owner  string
+	repo   string
+}
+
+func (h hookTarget) list(ctx context.Context, opts *github.ListOptions) ([]*github.Hook, *github.Response, error) {
+	if h.repo == "" {
+		return h.client.Organizations.ListHooks(ctx, h.owner, opts)
+	}
+	return h.client.Repositories.ListHooks(ctx, h.owner, h.repo, opts)
+}
+
+func (h hookTarget) get(ctx context.Context, id int64) (*github.Hook, *github.Response, error) {
+	if h.repo == "" {
+		return h.client.Organizations.GetHook(ctx, h.owner, id)
+	}
+	return h.client.Repositories.GetHook(ctx, h.owner, h.repo, id)
+}
+
+func (h hookTarget) create(ctx context.Context, hook *github.Hook) (*github.Hook, *github.Response, error) {
+	if h.repo == "" {
+		return h.client.Organizations.CreateHook(ctx, h.owner, hook)
+	}
+	return h.client.Repositories.CreateHook(ctx, h.owner, h.repo, hook)
+}
+
+func (h hookTarget) edit(ctx context.Context, id int64, hook *github.Hook) (*github.Hook, *github.Response, error) {
+	if h.repo == "" {
+		return h.client.Organizations.EditHook(ctx, h.owner, id, hook)
+	}
+	return h.client.Repositories.EditHook(ctx, h.owner, h.repo, id, hook)
+}
+
+func (h hookTarget) editConfiguration(ctx context.Context, id int64, config *github.HookConfig) (*github.HookConfig, *github.Response, error) {
+	if h.repo == "" {
+		return h.client.Organizations.EditHookConfiguration(ctx, h.owner, id, config)
+	}
+	return h.client.Repositories.EditHookConfiguration(ctx, h.owner, h.repo, id, config)
+}
+
+func (h hookTarget) delete(ctx context.Context, id int64) (*github.Response, error) {
+	if h.repo == "" {
+		return h.client.Organizations.DeleteHook(ctx, h.owner, id)
+	}
+	return h.client.Repositories.DeleteHook(ctx, h.owner, h.repo, id)
+}
+
+func (h hookTarget) listDeliveries(ctx context.Context, id int64, opts *github.ListCursorOptions) ([]*github.HookDelivery, *github.Response, error) {
+	if h.repo == "" {
+		return h.client.Organizations.ListHookDeliveries(ctx, h.owner, id, opts)
+	}
+	return h.client.Repositories.ListHookDeliveries(ctx, h.owner, h.repo, id, opts)
+}
+
+func (h hookTarget) getDelivery(ctx context.Context, id, deliveryID int64) (*github.HookDelivery, *github.Response, error) {
+	if h.repo == "" {
+		return h.client.Organizations.GetHookDelivery(ctx, h.owner, id, deliveryID)
+	}
+	return h.client.Repositories.GetHookDelivery(ctx, h.owner, h.repo, id, deliveryID)
+}
+
+func (h hookTarget) redeliver(ctx context.Context, id, deliveryID int64) (*github.HookDelivery, *github.Response, error) {
+	if h.repo == "" {
+		return h.client.Organizations.RedeliverHookDelivery(ctx, h.owner, id, deliveryID)
+	}
+	return h.client.Repositories.RedeliverHookDelivery(ctx, h.owner, h.repo, id, deliveryID)
+}
+
+// withHookTargetParams adds the parameters identifying the owner of webhooks.
+func withHookTargetParams() mcp.ToolOption {
+	return func(tool *mcp.Tool) {
+		mcp.WithString("owner",
+			mcp.Required(),
+			mcp.Description("Repository owner, or organization name for organization webhooks"),
+		)(tool)
+		mcp.WithString("repo",
+			mcp.Description("Repository name. Omit it to use the webhooks of the organization"),
+		)(tool)
+	}
+}
+
+// requiredHookTarget gets the owner of the webhooks of a request.
+func requiredHookTarget(ctx context.Context, getClient GetClientFn, request mcp.CallToolRequest) (hookTarget, *mcp.CallToolResult, error) {
+	owner, err := RequiredParam[string](request, "owner")
+	if err != nil {
+		return hookTarget{}, mcp.NewToolResultError(err.Error()), nil
+	}
+	repo, err := OptionalParam[string](request, "repo")
+	if err != nil {
+		return hookTarget{}, mcp.NewToolResultError(err.Error()), nil
+	}
+	client, err := getClient(ctx)
+	if err != nil {
+		return hookTarget{}, nil, fmt.Errorf("failed to get GitHub client: %w", err)
+	}
+	return hookTarget{client: client, owner: owner, repo: repo}, nil, nil
+}
+
+// ListWebhooks creates a tool to list the webhooks of a repository or an organization.
+func ListWebhooks(getClient GetClientFn, t translations.TranslationHelperFunc) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("list_webhooks",
+			mcp.WithDescription(t("TOOL_LIST_WEBHOOKS_DESCRIPTION", "List the webhooks of a GitHub repository or organization, with the status of their last delivery")),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:        t("TOOL_LIST_WEBHOOKS_USER_TITLE", "List webhooks"),
+				ReadOnlyHint: ToBoolPtr(true),
+			}),
+			withHookTargetParams(),
+			WithPagination(),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			pagination, err := OptionalPaginationParams(request)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			target, result, err := requiredHookTarget(ctx, getClient, request)
+			if result != nil || err != nil {
+				return result, err
+			}
+
+			hooks, resp, err := target.list(ctx, &github.ListOptions{
+				Page:    pagination.Page,
+				PerPage: pagination.PerPage,
+			})
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx,
+					"failed to list webhooks",
+					resp,
+					err,
+				), nil
+			}
+			defer func() { _ = resp.Body.Close() }()
+
+			minimalHooks := make([]MinimalWebhook, 0, len(hooks))
+			for _, hook := range hooks {
+				minimalHooks = append(minimalHooks, convertToMinimalWebhook(hook))
+			}
+
+			return MarshalledTextResult(minimalHooks), nil
+		}
+}
+
+// GetWebhook creates a tool to get a webhook of a repository or an organization.
+func GetWebhook(getClient GetClientFn, t translations.TranslationHelperFunc) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("get_webhook",
+			mcp.WithDescription(t("TOOL_GET_WEBHOOK_DESCRIPTION", "Get a webhook of a GitHub repository or organization")),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:        t("TOOL_GET_WEBHOOK_USER_TITLE", "Get webhook"),
+				ReadOnlyHint: ToBoolPtr(true),
+			}),
+			withHookTargetParams(),
+			mcp.WithNumber("hook_id",
+				mcp.Required(),
+				mcp.Description("ID of the webhook"),
+			),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			hookID, err := RequiredBigInt(request, "hook_id")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			target, result, err := requiredHookTarget(ctx, getClient, request)
+			if result != nil || err != nil {
+				return result, err
+			}
+
+			hook, resp, err := target.get(ctx, hookID)
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx,
+					"failed to get webhook",
+					resp,
+					err,
+				), nil
+			}
+			defer func() { _ = resp.Body.Close() }()
+
+			return MarshalledTextResult(convertToMinimalWebhook(hook)), nil
+		}
+}
+
+// CreateWebhook creates a tool to create a webhook for a repository or an organization.
+func CreateWebhook(getClient GetClientFn, t translations.TranslationHelperFunc) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("create_webhook",
+			mcp.WithDescription(t("TOOL_CREATE_WEBHOOK_DESCRIPTION", "Create a webhook for a GitHub repository or organization")),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:        t("TOOL_CREATE_WEBHOOK_USER_TITLE", "Create webhook"),
+				ReadOnlyHint: ToBoolPtr(false),
+			}),
+			withHookTargetParams(),
+			mcp.WithString("url",
+				mcp.Required(),
+				mcp.Description("URL the payloads are delivered to"),
+			),
+			mcp.WithString("content_type",
+				mcp.Description("Media type of the payloads (default json)"),
+				mcp.Enum("json", "form"),
+			),
+			mcp.WithString("secret",
+				mcp.Description("Secret used to sign the payloads"),
+			),
+			mcp.WithBoolean("insecure_ssl",
+				mcp.Description("Skip the verification of the SSL certificate of the URL (default false)"),
+			),
+			mcp.WithArray("events",
+				mcp.Description("Events that trigger the webhook (default [\"push\"]). Use [\"*\"] for all events"),
+				mcp.Items(map[string]interface{}{
+					"type": "string",
+				}),
+			),
+			mcp.WithBoolean("active",
+				mcp.Description("Whether the webhook delivers payloads (default true)"),
+			),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			url, err := RequiredParam[string](request, "url")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			contentType, err := OptionalParam[string](request, "content_type")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			secret, err := OptionalParam[string](request, "secret")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			insecureSSL, err := OptionalParam[bool](request, "insecure_ssl")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			events, err := OptionalStringArrayParam(request, "events")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			active, err := OptionalBoolParamWithDefault(request, "active", true)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			target, result, err := requiredHookTarget(ctx, getClient, request)
+			if result != nil || err != nil {
+				return result, err
+			}
+
+			if contentType == "" {
+				contentType = "json"
+			}
+			if len(events) == 0 {
+				events = []string{"push"}
+			}
+			config := &github.HookConfig{
+				URL:         github.Ptr(url),
+				ContentType: github.Ptr(contentType),
+				InsecureSSL: github.Ptr(map[bool]string{false: "0", true: "1"}[insecureSSL]),
+			}
+			if secret != "" {
+				config.Secret = github.Ptr(secret)
+			}
+
+			hook, resp, err := target.create(ctx, &github.Hook{
+				Name:   github.Ptr("web"),
+				Config: config,
+				Events: events,
+				Active: github.Ptr(active),
+			})
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx,
+					"failed to create webhook",
+					resp,
+					err,
+				), nil
+			}
+			defer func() { _ = resp.Body.Close() }()
+
+			return MarshalledTextResult(convertToMinimalWebhook(hook)), nil
+		}
+}
+
+// UpdateWebhook creates a tool to update a webhook of a repository or an organization.
+func UpdateWebhook(getClient GetClientFn, t translations.TranslationHelperFunc) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("update_webhook",
+			mcp.WithDescription(t("TOOL_UPDATE_WEBHOOK_DESCRIPTION", "Update a webhook of a GitHub repository or organization. Only the provided fields are changed")),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:        t("TOOL_UPDATE_WEBHOOK_USER_TITLE", "Update webhook"),
+				ReadOnlyHint: ToBoolPtr(false),
+			}),
+			withHookTargetParams(),
+			mcp.WithNumber("hook_id",
+				mcp.Required(),
+				mcp.Description("ID of the webhook"),
+			),
+			mcp.WithString("url",
+				mcp.Description("New URL the payloads are delivered to"),
+			),
+			mcp.WithString("content_type",
+				mcp.Description("New media type of the payloads"),
+				mcp.Enum("json", "form"),
+			),
+			mcp.WithString("secret",
+				mcp.Description("New secret used to sign the payloads"),
+			),
+			mcp.WithBoolean("insecure_ssl",
+				mcp.Description("Skip the verification of the SSL certificate of the URL"),
+			),
+			mcp.WithArray("events",
+				mcp.Description("Events that trigger the webhook, replacing the current ones"),
+				mcp.Items(map[string]interface{}{
+					"type": "string",
+				}),
+			),
+			mcp.WithBoolean("active",
+				mcp.Description("Whether the webhook delivers payloads"),
+			),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			hookID, err := RequiredBigInt(request, "hook_id")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			config := &github.HookConfig{}
+			configChanged := false
+			for param, field := range map[string]**string{
+				"url":          &config.URL,
+				"content_type": &config.ContentType,
+				"secret":       &config.Secret,
+			} {
+				value, ok, err := OptionalParamOK[string](request, param)
+				if err != nil {
+					return mcp.NewToolResultError(err.Error()), nil
+				}
+				if ok {
+					*field = github.Ptr(value)
+					configChanged = true
+				}
+			}
+			insecureSSL, ok, err := OptionalParamOK[bool](request, "insecure_ssl")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			if ok {
+				config.InsecureSSL = github.Ptr(map[bool]string{false: "0", true: "1"}[insecureSSL])
+				configChanged = true
+			}
+
+			hookUpdate := &github.Hook{}
+			hookChanged := false
+			if _, ok := request.GetArguments()["events"]; ok {
+				events, err := OptionalStringArrayParam(request, "events")
+				if err != nil {
+					return mcp.NewToolResultError(err.Error()), nil
+				}
+				hookUpdate.Events = events
+				hookChanged = true
+			}
+			active, ok, err := OptionalParamOK[bool](request, "active")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			if ok {
+				hookUpdate.Active = github.Ptr(active)
+				hookChanged = true
+			}
+
+			if !configChanged && !hookChanged {
+				return mcp.NewToolResultError("No update parameters provided."), nil
+			}
+			target, result, err := requiredHookTarget(ctx, getClient, request)
+			if result != nil || err != nil {
+				return result, err
+			}
+
+			// The configuration endpoint changes only the given fields, unlike the config of the hook endpoint
+			if configChanged {
+				_, resp, err := target.editConfiguration(ctx, hookID, config)
+				if err != nil {
+					return ghErrors.NewGitHubAPIErrorResponse(ctx,
+						"failed to update webhook configuration",
+						resp,
+						err,
+					), nil
+				}
+				_ = resp.Body.Close()
+			}
+
+			var hook *github.Hook
+			var resp *github.Response
+			if hookChanged {
+				hook, resp, err = target.edit(ctx, hookID, hookUpdate)
+			} else {
+				hook, resp, err = target.get(ctx, hookID)
+			}
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx,
+					"failed to update webhook",
+					resp,
+					err,
+				), nil
+			}
+			defer func() { _ = resp.Body.Close() }()
+
+			return MarshalledTextResult(convertToMinimalWebhook(hook)), nil
+		}
+}
+
+// DeleteWebhook creates a tool to delete a webhook of a repository or an organization.
+func DeleteWebhook(getClient GetClientFn, t translations.TranslationHelperFunc) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("delete_webhook",
+			mcp.WithDescription(t("TOOL_DELETE_WEBHOOK_DESCRIPTION", "Delete a webhook of a GitHub repository or organization")),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:           t("TOOL_DELETE_WEBHOOK_USER_TITLE", "Delete webhook"),
+				ReadOnlyHint:    ToBoolPtr(false),
+				DestructiveHint: ToBoolPtr(true),
+			}),
+			withHookTargetParams(),
+			mcp.WithNumber("hook_id",
+				mcp.Required(),
+				mcp.Description("ID of the webhook"),
+			),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			hookID, err := RequiredBigInt(request, "hook_id")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			target, result, err := requiredHookTarget(ctx, getClient, request)
+			if result != nil || err != nil {
+				return result, err
+			}
+
+			resp, err := target.delete(ctx, hookID)
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx,
+					"failed to delete webhook",
+					resp,
+					err,
+				), nil
+			}
+			defer func() { _ = resp.Body.Close() }()
+
+			return mcp.NewToolResultText(fmt.Sprintf("Deleted webhook %d", hookID)), nil
+		}
+}
+
+// ListHookDeliveries creates a tool to list the deliveries of a webhook.
+func ListHookDeliveries(getClient GetClientFn, t translations.TranslationHelperFunc) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("list_hook_deliveries",
+			mcp.WithDescription(t("TOOL_LIST_HOOK_DELIVERIES_DESCRIPTION", "List the recent deliveries of a webhook of a GitHub repository or organization, most recent first, to find out whether an event was delivered and how the endpoint responded")),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:        t("TOOL_LIST_HOOK_DELIVERIES_USER_TITLE", "List webhook deliveries"),
+				ReadOnlyHint: ToBoolPtr(true),
+			}),
+			withHookTargetParams(),
+			mcp.WithNumber("hook_id",
+				mcp.Required(),
+				mcp.Description("ID of the webhook"),
+			),
+			WithCursorPagination(),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			hookID, err := RequiredBigInt(request, "hook_id")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			pagination, err := OptionalCursorPaginationParams(request)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			target, result, err := requiredHookTarget(ctx, getClient, request)
+			if result != nil || err != nil {
+				return result, err
+			}
+
+			deliveries, resp, err := target.listDeliveries(ctx, hookID, &github.ListCursorOptions{
+				PerPage: pagination.PerPage,
+				Cursor:  pagination.After,
+			})
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx,
+					"failed to list webhook deliveries",
+					resp,
+					err,
+				), nil
+			}
+			defer func() { _ = resp.Body.Close() }()
+
+			minimalDeliveries := make([]MinimalHookDelivery, 0, len(deliveries))
+			for _, delivery := range deliveries {
+				minimalDeliveries = append(minimalDeliveries, convertToMinimalHookDelivery(delivery))
+			}
+
+			return MarshalledTextResult(map[string]any{
+				"deliveries": minimalDeliveries,
+				"pageInfo": map[string]any{
+					"hasNextPage": resp.Cursor != "",
+					"endCursor":   resp.Cursor,
+				},
+			}), nil
+		}
+}
+
+// GetHookDelivery creates a tool to get a delivery of a webhook, with its request and response.
+func GetHookDelivery(getClient GetClientFn, t translations.TranslationHelperFunc) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("get_hook_delivery",
+			mcp.WithDescription(t("TOOL_GET_HOOK_DELIVERY_DESCRIPTION", "Get a delivery of a webhook of a GitHub repository or organization, with the headers and payloads of its request and response. Signatures, secrets and tokens are redacted")),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:        t("TOOL_GET_HOOK_DELIVERY_USER_TITLE", "Get webhook delivery"),
+				ReadOnlyHint: ToBoolPtr(true),
+			}),
+			withHookTargetParams(),
+			mcp.WithNumber("hook_id",
+				mcp.Required(),
+				mcp.Description("ID of the webhook"),
+			),
+			mcp.WithNumber("delivery_id",
+				mcp.Required(),
+				mcp.Description("ID of the delivery"),
+			),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			hookID, err := RequiredBigInt(request, "hook_id")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			deliveryID, err := RequiredBigInt(request, "delivery_id")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			target, result, err := requiredHookTarget(ctx, getClient, request)
+			if result != nil || err != nil {
+				return result, err
+			}
+
+			delivery, resp, err := target.getDelivery(ctx, hookID, deliveryID)
+			if err != nil {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx,
+					"failed to get webhook delivery",
+					resp,
+					err,
+				), nil
+			}
+			defer func() { _ = resp.Body.Close() }()
+
+			details := HookDeliveryDetails{MinimalHookDelivery: convertToMinimalHookDelivery(delivery)}
+			if delivery.Request != nil {
+				details.Request = HookDeliveryMessage{
+					Headers: redactHookHeaders(delivery.Request.Headers),
+					Payload: redactHookPayload(delivery.Request.RawPayload),
+				}
+			}
+			if delivery.Response != nil {
+				details.Response = HookDeliveryMessage{
+					Headers: redactHookHeaders(delivery.Response.Headers),
+					Payload: redactHookPayload(delivery.Response.RawPayload),
+				}
+			}
+
+			return MarshalledTextResult(details), nil
+		}
+}
+
+// RedeliverHookDelivery creates a tool to redeliver a delivery of a webhook.
+func RedeliverHookDelivery(getClient GetClientFn, t translations.TranslationHelperFunc) (tool mcp.Tool, handler server.ToolHandlerFunc) {
+	return mcp.NewTool("redeliver_hook_delivery",
+			mcp.WithDescription(t("TOOL_REDELIVER_HOOK_DELIVERY_DESCRIPTION", "Redeliver a delivery of a webhook of a GitHub repository or organization. The redelivery is queued and shows up in list_hook_deliveries once attempted")),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:        t("TOOL_REDELIVER_HOOK_DELIVERY_USER_TITLE", "Redeliver webhook delivery"),
+				ReadOnlyHint: ToBoolPtr(false),
+			}),
+			withHookTargetParams(),
+			mcp.WithNumber("hook_id",
+				mcp.Required(),
+				mcp.Description("ID of the webhook"),
+			),
+			mcp.WithNumber("delivery_id",
+				mcp.Required(),
+				mcp.Description("ID of the delivery to redeliver"),
+			),
+		),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			hookID, err := RequiredBigInt(request, "hook_id")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			deliveryID, err := RequiredBigInt(request, "delivery_id")
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			target, result, err := requiredHookTarget(ctx, getClient, request)
+			if result != nil || err != nil {
+				return result, err
+			}
+
+			_, resp, err := target.redeliver(ctx, hookID, deliveryID)
+			// The API answers 202 Accepted, which go-github reports as an AcceptedError
+			if _, accepted := err.(*github.AcceptedError); err != nil && !accepted {
+				return ghErrors.NewGitHubAPIErrorResponse(ctx,
+					"failed to redeliver webhook delivery",
+					resp,
+					err,
+				), nil
+			}
+			defer func() { _ = resp.Body.Close() }()
+
+			return mcp.NewToolResultText(fmt.Sprintf("Queued the redelivery of delivery %d of webhook %d", deliveryID, hookID)), nil
+		}
+}
@@ -0,0 +1,62 @@
+package raw
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// maxArchiveRedirects is the number of redirects GetArchive follows, like http.Client does by default.
+const maxArchiveRedirects = 10
+
+// GetArchive downloads a repository archive from a link returned by the repository archive API,
+// such as the one from Repositories.GetArchiveLink. The caller must close the body of the returned response.
+//
+// The link is requested with the GitHub token. A redirect to another host, such as a storage
+// service, is followed with http.DefaultClient so that the token is not sent along.
+func (c *Client) GetArchive(ctx context.Context, archiveURL *url.URL) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, archiveURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	// The token is added by the transport of the GitHub client, so it would survive redirects
+	authClient := *c.client.Client()
+	authClient.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if len(via) >= maxArchiveRedirects {
+			return fmt.Errorf("stopped after %d redirects", maxArchiveRedirects)
+		}
+		if req.URL.Host != via[0].URL.Host {
+			return http.ErrUseLastResponse
+		}
+		return nil
+	}
+	resp, err := authClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download repository archive: %w", err)
+	}
+	if location, err := resp.Location(); err == nil && isRedirect(resp.StatusCode) {
+		_ = resp.Body.Close()
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, location.String(), nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err = http.DefaultClient.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to download repository archive: %w", err)
+		}
+	}
+	if resp.StatusCode != http.StatusOK {
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("repository archive download returned status %d", resp.StatusCode)
+	}
+	return resp, nil
+}
+
+func isRedirect(status int) bool {
+	switch status {
+	case http.StatusMovedPermanently, http.StatusFound, http.StatusSeeOther, http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+		return true
+	}
+	return false
+}
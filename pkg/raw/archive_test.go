@@ -0,0 +1,79 @@
+package raw
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-github/v77/github"
+	"github.com/migueleliasweb/go-github-mock/src/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetArchive(t *testing.T) {
+	base, _ := url.Parse("https://raw.example.com/")
+	mockedClient := mock.NewMockedHTTPClient(
+		mock.WithRequestMatchHandler(
+			GetCodeloadTarballByOwnerByRepoByRef,
+			http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Query().Get("token") != "abc" {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				_, _ = w.Write([]byte("archive"))
+			}),
+		),
+	)
+	client := NewClient(github.NewClient(mockedClient), base)
+
+	link, _ := url.Parse("https://codeload.example.com/octocat/hello/legacy.tar.gz/refs/heads/main?token=abc")
+	resp, err := client.GetArchive(context.Background(), link)
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	content, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "archive", string(content))
+
+	link, _ = url.Parse("https://codeload.example.com/octocat/hello/legacy.tar.gz/refs/heads/main?token=expired")
+	_, err = client.GetArchive(context.Background(), link)
+	require.ErrorContains(t, err, "returned status 404")
+}
+
+func TestGetArchiveRedirects(t *testing.T) {
+	storage := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		_, _ = w.Write([]byte("archive"))
+	}))
+	defer storage.Close()
+	codeload := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path == "/legacy.tar.gz" {
+			// Redirects within the host keep the token
+			http.Redirect(w, r, "/archive.tar.gz", http.StatusFound)
+			return
+		}
+		http.Redirect(w, r, storage.URL+"/archive.tar.gz?sig=abc", http.StatusFound)
+	}))
+	defer codeload.Close()
+
+	base, _ := url.Parse("https://raw.example.com/")
+	client := NewClient(github.NewClient(nil).WithAuthToken("secret"), base)
+
+	link, _ := url.Parse(codeload.URL + "/legacy.tar.gz")
+	resp, err := client.GetArchive(context.Background(), link)
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	content, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "archive", string(content))
+}
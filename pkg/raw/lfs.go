@@ -0,0 +1,214 @@
+package raw
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+)
+
+// lfsPointerMaxSize is the size limit git-lfs itself applies when checking whether a file is a pointer.
+const lfsPointerMaxSize = 1024
+
+// DefaultLFSMaxSize is the default size limit for LFS objects that are downloaded.
+const DefaultLFSMaxSize = 10 * 1024 * 1024
+
+var (
+	// ErrLFSDisabled is returned when resolving a pointer with a client that has LFS resolution disabled.
+	ErrLFSDisabled = errors.New("git LFS resolution is disabled")
+	// ErrLFSObjectTooLarge is returned when an LFS object is larger than the configured limit.
+	ErrLFSObjectTooLarge = errors.New("git LFS object is larger than the size limit")
+)
+
+var (
+	lfsPointerVersion = []byte("version https://git-lfs.github.com/spec/v1\n")
+	lfsOIDPattern     = regexp.MustCompile(`(?m)^oid sha256:([0-9a-f]{64})$`)
+	lfsSizePattern    = regexp.MustCompile(`(?m)^size (\d+)$`)
+)
+
+// LFSPointer identifies a file stored in Git LFS.
+type LFSPointer struct {
+	OID  string `json:"oid"`
+	Size int64  `json:"size"`
+}
+
+// ParseLFSPointer parses data as a Git LFS pointer file. It returns nil if data isn't one.
+func ParseLFSPointer(data []byte) *LFSPointer {
+	if len(data) > lfsPointerMaxSize || !bytes.HasPrefix(data, lfsPointerVersion) {
+		return nil
+	}
+	oid := lfsOIDPattern.FindSubmatch(data)
+	size := lfsSizePattern.FindSubmatch(data)
+	if oid == nil || size == nil {
+		return nil
+	}
+	n, err := strconv.ParseInt(string(size[1]), 10, 64)
+	if err != nil {
+		return nil
+	}
+	return &LFSPointer{OID: string(oid[1]), Size: n}
+}
+
+// DetectLFSPointer peeks at the start of r to check whether it is a Git LFS pointer file.
+// The returned reader yields the complete content of r either way.
+func DetectLFSPointer(r io.Reader) (*LFSPointer, io.Reader, error) {
+	br := bufio.NewReaderSize(r, lfsPointerMaxSize+1)
+	head, err := br.Peek(lfsPointerMaxSize + 1)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, nil, err
+	}
+	return ParseLFSPointer(head), br, nil
+}
+
+// LFSOptions configures how the client resolves Git LFS pointers.
+type LFSOptions struct {
+	// ServerURL is the URL of the GitHub instance, e.g. https://github.com/, which serves the LFS batch API
+	ServerURL *url.URL
+	// MaxSize is the size of the largest object that is downloaded, in bytes
+	MaxSize int64
+	// DownloadClient is used for the object download URLs returned by the batch API. These carry their
+	// own authorization, so they must not be sent the GitHub token. Defaults to http.DefaultClient.
+	DownloadClient *http.Client
+}
+
+// WithLFS returns a copy of the client that resolves Git LFS pointers with the given options.
+func (c *Client) WithLFS(opts LFSOptions) *Client {
+	if opts.DownloadClient == nil {
+		opts.DownloadClient = http.DefaultClient
+	}
+	c2 := *c
+	c2.lfs = &opts
+	return &c2
+}
+
+// LFSMaxSize returns the size limit for LFS objects, or 0 if LFS resolution is disabled.
+func (c *Client) LFSMaxSize() int64 {
+	if c.lfs == nil {
+		return 0
+	}
+	return c.lfs.MaxSize
+}
+
+type lfsBatchRequest struct {
+	Operation string        `json:"operation"`
+	Transfers []string      `json:"transfers"`
+	Objects   []*LFSPointer `json:"objects"`
+}
+
+type lfsBatchResponse struct {
+	Objects []struct {
+		OID     string `json:"oid"`
+		Actions struct {
+			Download *struct {
+				Href   string            `json:"href"`
+				Header map[string]string `json:"header"`
+			} `json:"download"`
+		} `json:"actions"`
+		Error *struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	} `json:"objects"`
+}
+
+// GetLFSObject downloads the object a pointer refers to through the Git LFS batch API.
+// The caller must close the body of the returned response.
+func (c *Client) GetLFSObject(ctx context.Context, owner, repo string, pointer *LFSPointer) (*http.Response, error) {
+	if c.lfs == nil || c.lfs.ServerURL == nil || c.lfs.MaxSize <= 0 {
+		return nil, ErrLFSDisabled
+	}
+	if pointer.Size > c.lfs.MaxSize {
+		return nil, fmt.Errorf("%w: %d bytes, limit is %d bytes", ErrLFSObjectTooLarge, pointer.Size, c.lfs.MaxSize)
+	}
+
+	body, err := json.Marshal(lfsBatchRequest{
+		Operation: "download",
+		Transfers: []string{"basic"},
+		Objects:   []*LFSPointer{pointer},
+	})
+	if err != nil {
+		return nil, err
+	}
+	batchURL := c.lfs.ServerURL.JoinPath(owner, repo+".git", "info", "lfs", "objects", "batch").String()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, batchURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/vnd.git-lfs+json")
+	req.Header.Set("Content-Type", "application/vnd.git-lfs+json")
+
+	// The batch API is authorized with the GitHub token, like the raw content API
+	resp, err := c.client.Client().Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call git LFS batch API: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("git LFS batch API returned status %d", resp.StatusCode)
+	}
+
+	var batch lfsBatchResponse
+	if err := json.NewDecoder(resp.Body).Decode(&batch); err != nil {
+		return nil, fmt.Errorf("failed to decode git LFS batch response: %w", err)
+	}
+	if len(batch.Objects) == 0 {
+		return nil, errors.New("git LFS batch response has no objects")
+	}
+	object := batch.Objects[0]
+	if object.Error != nil {
+		return nil, fmt.Errorf("git LFS object %s: %d %s", pointer.OID, object.Error.Code, object.Error.Message)
+	}
+	if object.Actions.Download == nil {
+		return nil, fmt.Errorf("git LFS object %s has no download action", pointer.OID)
+	}
+
+	req, err = http.NewRequestWithContext(ctx, http.MethodGet, object.Actions.Download.Href, nil)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range object.Actions.Download.Header {
+		req.Header.Set(k, v)
+	}
+	objectResp, err := c.lfs.DownloadClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download git LFS object: %w", err)
+	}
+	if objectResp.StatusCode != http.StatusOK {
+		_ = objectResp.Body.Close()
+		return nil, fmt.Errorf("git LFS object download returned status %d", objectResp.StatusCode)
+	}
+	if objectResp.ContentLength > c.lfs.MaxSize {
+		_ = objectResp.Body.Close()
+		return nil, fmt.Errorf("%w: %d bytes, limit is %d bytes", ErrLFSObjectTooLarge, objectResp.ContentLength, c.lfs.MaxSize)
+	}
+	// The size in the pointer is only a claim, so the download itself is capped too
+	objectResp.Body = &lfsLimitedBody{
+		ReadCloser: objectResp.Body,
+		r:          io.LimitReader(objectResp.Body, c.lfs.MaxSize+1),
+		remaining:  c.lfs.MaxSize,
+	}
+	return objectResp, nil
+}
+
+// lfsLimitedBody fails reads with ErrLFSObjectTooLarge once more than remaining bytes were read.
+type lfsLimitedBody struct {
+	io.ReadCloser
+	r         io.Reader
+	remaining int64
+}
+
+func (b *lfsLimitedBody) Read(p []byte) (int, error) {
+	n, err := b.r.Read(p)
+	b.remaining -= int64(n)
+	if b.remaining < 0 {
+		return n + int(b.remaining), ErrLFSObjectTooLarge
+	}
+	return n, err
+}
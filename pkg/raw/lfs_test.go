@@ -0,0 +1,142 @@
+package raw
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v77/github"
+	"github.com/migueleliasweb/go-github-mock/src/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testLFSOID = "4d7a214614ab2935c943f9e0ff69d22eadbb8f32b1258daaa5e2ca24d17e2393"
+
+var testLFSPointer = "version https://git-lfs.github.com/spec/v1\noid sha256:" + testLFSOID + "\nsize 12345\n"
+
+func TestParseLFSPointer(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected *LFSPointer
+	}{
+		{
+			name:     "pointer",
+			input:    testLFSPointer,
+			expected: &LFSPointer{OID: testLFSOID, Size: 12345},
+		},
+		{
+			name:  "regular file",
+			input: "package main\n",
+		},
+		{
+			name:  "missing oid",
+			input: "version https://git-lfs.github.com/spec/v1\nsize 12345\n",
+		},
+		{
+			name:  "too large to be a pointer",
+			input: testLFSPointer + strings.Repeat("x", 1024),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, ParseLFSPointer([]byte(tc.input)))
+		})
+	}
+}
+
+func TestDetectLFSPointer(t *testing.T) {
+	large := strings.Repeat("a", 4096)
+	for _, input := range []string{testLFSPointer, large, ""} {
+		pointer, r, err := DetectLFSPointer(strings.NewReader(input))
+		require.NoError(t, err)
+		assert.Equal(t, input == testLFSPointer, pointer != nil)
+
+		// The content can still be read in full after detection
+		content, err := io.ReadAll(r)
+		require.NoError(t, err)
+		assert.Equal(t, input, string(content))
+	}
+}
+
+func TestGetLFSObject(t *testing.T) {
+	serverURL, _ := url.Parse("https://github.example.com/")
+	pointer := &LFSPointer{OID: testLFSOID, Size: 12345}
+
+	newClient := func(t *testing.T, batchResponse, objectContent string) *Client {
+		mockedClient := mock.NewMockedHTTPClient(
+			mock.WithRequestMatchHandler(
+				PostLFSObjectsBatchByOwnerByRepo,
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					assert.Equal(t, "/octocat/hello.git/info/lfs/objects/batch", r.URL.Path)
+					assert.Equal(t, "application/vnd.git-lfs+json", r.Header.Get("Accept"))
+					var req lfsBatchRequest
+					assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+					assert.Equal(t, "download", req.Operation)
+					assert.Equal(t, []*LFSPointer{pointer}, req.Objects)
+					w.Header().Set("Content-Type", "application/vnd.git-lfs+json")
+					_, _ = w.Write([]byte(batchResponse))
+				}),
+			),
+			mock.WithRequestMatchHandler(
+				mock.EndpointPattern{Pattern: "/lfs-objects/{oid}", Method: "GET"},
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					assert.Equal(t, "RemoteAuth abc", r.Header.Get("Authorization"))
+					_, _ = w.Write([]byte(objectContent))
+				}),
+			),
+		)
+		base, _ := url.Parse("https://raw.example.com/")
+		return NewClient(github.NewClient(mockedClient), base).WithLFS(LFSOptions{
+			ServerURL:      serverURL,
+			MaxSize:        DefaultLFSMaxSize,
+			DownloadClient: mockedClient,
+		})
+	}
+
+	t.Run("downloads object", func(t *testing.T) {
+		client := newClient(t, `{"objects": [{"oid": "`+testLFSOID+`", "size": 12345, "actions": {"download": {"href": "https://lfs.example.com/lfs-objects/`+testLFSOID+`", "header": {"Authorization": "RemoteAuth abc"}}}}]}`, "real content")
+		resp, err := client.GetLFSObject(context.Background(), "octocat", "hello", pointer)
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		content, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		assert.Equal(t, "real content", string(content))
+	})
+
+	t.Run("object error", func(t *testing.T) {
+		client := newClient(t, `{"objects": [{"oid": "`+testLFSOID+`", "size": 12345, "error": {"code": 404, "message": "Object does not exist"}}]}`, "")
+		_, err := client.GetLFSObject(context.Background(), "octocat", "hello", pointer)
+		require.ErrorContains(t, err, "404 Object does not exist")
+	})
+
+	t.Run("object too large", func(t *testing.T) {
+		client := newClient(t, "", "").WithLFS(LFSOptions{ServerURL: serverURL, MaxSize: 100})
+		_, err := client.GetLFSObject(context.Background(), "octocat", "hello", pointer)
+		require.ErrorIs(t, err, ErrLFSObjectTooLarge)
+	})
+
+	t.Run("download larger than the pointer claims", func(t *testing.T) {
+		client := newClient(t, `{"objects": [{"oid": "`+testLFSOID+`", "size": 12345, "actions": {"download": {"href": "https://lfs.example.com/lfs-objects/`+testLFSOID+`", "header": {"Authorization": "RemoteAuth abc"}}}}]}`, strings.Repeat("x", 20000))
+		client = client.WithLFS(LFSOptions{ServerURL: serverURL, MaxSize: pointer.Size, DownloadClient: client.lfs.DownloadClient})
+		resp, err := client.GetLFSObject(context.Background(), "octocat", "hello", pointer)
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		content, err := io.ReadAll(resp.Body)
+		require.ErrorIs(t, err, ErrLFSObjectTooLarge)
+		assert.Len(t, content, int(pointer.Size))
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		base, _ := url.Parse("https://raw.example.com/")
+		client := NewClient(github.NewClient(nil), base)
+		_, err := client.GetLFSObject(context.Background(), "octocat", "hello", pointer)
+		require.ErrorIs(t, err, ErrLFSDisabled)
+	})
+}
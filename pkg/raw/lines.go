@@ -0,0 +1,100 @@
+package raw
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"unicode/utf8"
+)
+
+// LineWindow is a contiguous range of lines read from a file.
+type LineWindow struct {
+	// Text holds the lines of the window, including their line endings
+	Text string
+	// StartLine and EndLine are the 1-based, inclusive line numbers of the window.
+	// EndLine is StartLine-1 when the window holds no lines.
+	StartLine int
+	EndLine   int
+	// TotalLines is the number of lines in the whole input
+	TotalLines int
+	// TotalBytes is the size of the whole input
+	TotalBytes int64
+	// Truncated is set when the window stopped short of the requested end line because of the byte limit
+	Truncated bool
+	// EndLineOffset is set when the byte limit cut EndLine short: it is the byte offset within
+	// EndLine to continue reading from
+	EndLineOffset int
+}
+
+// ReadLineWindow reads r to the end and keeps the lines from startLine to endLine (1-based and
+// inclusive, 0 meaning the last line), stopping early once the window would exceed maxBytes
+// (0 or less meaning no limit). The whole input is scanned so that TotalLines and TotalBytes are exact.
+// A single line longer than maxBytes is cut at a UTF-8 character boundary, and startOffset skips
+// the first bytes of startLine to resume such a line.
+func ReadLineWindow(r io.Reader, startLine, startOffset, endLine, maxBytes int) (*LineWindow, error) {
+	if startLine < 1 {
+		startLine = 1
+	}
+	w := &LineWindow{StartLine: startLine, EndLine: startLine - 1}
+
+	text := make([]byte, 0, 4096)
+	br := bufio.NewReader(r)
+	for {
+		line, err := br.ReadBytes('\n')
+		if len(line) > 0 {
+			w.TotalLines++
+			w.TotalBytes += int64(len(line))
+
+			inWindow := w.TotalLines >= startLine && (endLine == 0 || w.TotalLines <= endLine)
+			if inWindow && !w.Truncated {
+				offset := 0
+				if w.TotalLines == startLine {
+					offset = min(max(startOffset, 0), len(line))
+				}
+				rest := line[offset:]
+				switch {
+				case maxBytes <= 0 || len(text)+len(rest) <= maxBytes:
+					text = append(text, rest...)
+					w.EndLine = w.TotalLines
+				case len(text) == 0:
+					cut := truncateUTF8(rest, maxBytes)
+					if len(cut) == 0 {
+						// Always make progress, even if the first character is larger than maxBytes
+						_, size := utf8.DecodeRune(rest)
+						cut = rest[:size]
+					}
+					text = append(text, cut...)
+					w.EndLine = w.TotalLines
+					w.EndLineOffset = offset + len(cut)
+					w.Truncated = true
+				default:
+					w.Truncated = true
+				}
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	w.Text = string(text)
+	return w, nil
+}
+
+// truncateUTF8 cuts b to at most n bytes without splitting a multi-byte character.
+func truncateUTF8(b []byte, n int) []byte {
+	if len(b) <= n {
+		return b
+	}
+	b = b[:n]
+	for i := 0; i < utf8.UTFMax-1 && len(b) > 0; i++ {
+		if r, size := utf8.DecodeLastRune(b); r != utf8.RuneError || size > 1 {
+			break
+		}
+		b = b[:len(b)-1]
+	}
+	return b
+}
@@ -0,0 +1,101 @@
+package raw
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadLineWindow(t *testing.T) {
+	input := "one\ntwo\nthree\nfour\nfive"
+
+	tests := []struct {
+		name        string
+		input       string
+		startLine   int
+		startOffset int
+		endLine     int
+		maxBytes    int
+		expected    LineWindow
+	}{
+		{
+			name:     "whole file",
+			input:    input,
+			expected: LineWindow{Text: input, StartLine: 1, EndLine: 5, TotalLines: 5, TotalBytes: 23},
+		},
+		{
+			name:      "line range",
+			input:     input,
+			startLine: 2,
+			endLine:   3,
+			expected:  LineWindow{Text: "two\nthree\n", StartLine: 2, EndLine: 3, TotalLines: 5, TotalBytes: 23},
+		},
+		{
+			name:      "open ended range",
+			input:     input,
+			startLine: 4,
+			expected:  LineWindow{Text: "four\nfive", StartLine: 4, EndLine: 5, TotalLines: 5, TotalBytes: 23},
+		},
+		{
+			name:     "byte limit stops at line boundary",
+			input:    input,
+			maxBytes: 10,
+			expected: LineWindow{Text: "one\ntwo\n", StartLine: 1, EndLine: 2, TotalLines: 5, TotalBytes: 23, Truncated: true},
+		},
+		{
+			name:      "long line is cut at character boundary",
+			input:     "short\nhéllo wörld\n",
+			startLine: 2,
+			maxBytes:  2,
+			expected:  LineWindow{Text: "h", StartLine: 2, EndLine: 2, TotalLines: 2, TotalBytes: 20, Truncated: true, EndLineOffset: 1},
+		},
+		{
+			name:        "long line is resumed from an offset",
+			input:       "short\nhéllo wörld\nend",
+			startLine:   2,
+			startOffset: 1,
+			maxBytes:    8,
+			expected:    LineWindow{Text: "éllo w", StartLine: 2, EndLine: 2, TotalLines: 3, TotalBytes: 23, Truncated: true, EndLineOffset: 8},
+		},
+		{
+			name:        "rest of a resumed line",
+			input:       "short\nhéllo wörld\nend",
+			startLine:   2,
+			startOffset: 8,
+			maxBytes:    20,
+			expected:    LineWindow{Text: "örld\nend", StartLine: 2, EndLine: 3, TotalLines: 3, TotalBytes: 23},
+		},
+		{
+			name:      "start past the end",
+			input:     input,
+			startLine: 10,
+			expected:  LineWindow{StartLine: 10, EndLine: 9, TotalLines: 5, TotalBytes: 23},
+		},
+		{
+			name:     "trailing newline does not count as a line",
+			input:    "a\nb\n",
+			expected: LineWindow{Text: "a\nb\n", StartLine: 1, EndLine: 2, TotalLines: 2, TotalBytes: 4},
+		},
+		{
+			name:     "negative byte limit means no limit",
+			input:    input,
+			maxBytes: -1,
+			expected: LineWindow{Text: input, StartLine: 1, EndLine: 5, TotalLines: 5, TotalBytes: 23},
+		},
+		{
+			name:     "empty file",
+			input:    "",
+			expected: LineWindow{StartLine: 1, EndLine: 0},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			window, err := ReadLineWindow(strings.NewReader(tc.input), tc.startLine, tc.startOffset, tc.endLine, tc.maxBytes)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, *window)
+		})
+	}
+}
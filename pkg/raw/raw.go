@@ -3,6 +3,7 @@ package raw
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"net/url"
 
@@ -16,6 +17,7 @@ type GetRawClientFn func(context.Context) (*Client, error)
 type Client struct {
 	url    *url.URL
 	client *gogithub.Client
+	lfs    *LFSOptions
 }
 
 // NewClient creates a new instance of the raw API Client with the provided GitHub client and provided URL.
@@ -59,6 +61,23 @@ func (c *Client) commitURL(owner, repo, sha, path string) string {
 type ContentOpts struct {
 	Ref string
 	SHA string
+	// Range requests only part of the file. The server may ignore it and respond
+	// with the whole file and 200 OK instead of 206 Partial Content.
+	Range *ByteRange
+}
+
+// ByteRange is an inclusive range of byte offsets. A negative End reads until the end of the file.
+type ByteRange struct {
+	Start int64
+	End   int64
+}
+
+// header returns the value of the HTTP Range header for the range.
+func (r ByteRange) header() string {
+	if r.End < 0 {
+		return fmt.Sprintf("bytes=%d-", r.Start)
+	}
+	return fmt.Sprintf("bytes=%d-%d", r.Start, r.End)
 }
 
 // GetRawContent fetches the raw content of a file from a GitHub repository.
@@ -68,6 +87,9 @@ func (c *Client) GetRawContent(ctx context.Context, owner, repo, path string, op
 	if err != nil {
 		return nil, err
 	}
+	if opts != nil && opts.Range != nil {
+		req.Header.Set("Range", opts.Range.header())
+	}
 
 	return c.client.Client().Do(req)
 }
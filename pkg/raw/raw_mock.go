@@ -18,3 +18,15 @@ var GetRawReposContentsByOwnerByRepoBySHAByPath mock.EndpointPattern = mock.Endp
 	Pattern: "/{owner}/{repo}/{sha}/{path:.*}",
 	Method:  "GET",
 }
+var PostLFSObjectsBatchByOwnerByRepo mock.EndpointPattern = mock.EndpointPattern{
+	Pattern: "/{owner}/{repo}/info/lfs/objects/batch",
+	Method:  "POST",
+}
+var GetCodeloadTarballByOwnerByRepoByRef mock.EndpointPattern = mock.EndpointPattern{
+	Pattern: "/{owner}/{repo}/legacy.tar.gz/{ref:.*}",
+	Method:  "GET",
+}
+var GetCodeloadZipballByOwnerByRepoByRef mock.EndpointPattern = mock.EndpointPattern{
+	Pattern: "/{owner}/{repo}/legacy.zip/{ref:.*}",
+	Method:  "GET",
+}
@@ -100,6 +100,51 @@ func TestGetRawContent(t *testing.T) {
 	}
 }
 
+func TestGetRawContentRange(t *testing.T) {
+	base, _ := url.Parse("https://raw.example.com/")
+
+	tests := []struct {
+		name          string
+		opts          *ContentOpts
+		expectedRange string
+	}{
+		{
+			name:          "no range",
+			opts:          &ContentOpts{SHA: "abc123"},
+			expectedRange: "",
+		},
+		{
+			name:          "bounded range",
+			opts:          &ContentOpts{SHA: "abc123", Range: &ByteRange{Start: 0, End: 1023}},
+			expectedRange: "bytes=0-1023",
+		},
+		{
+			name:          "open ended range",
+			opts:          &ContentOpts{SHA: "abc123", Range: &ByteRange{Start: 512, End: -1}},
+			expectedRange: "bytes=512-",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockedClient := mock.NewMockedHTTPClient(
+				mock.WithRequestMatchHandler(
+					GetRawReposContentsByOwnerByRepoBySHAByPath,
+					http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+						require.Equal(t, tc.expectedRange, r.Header.Get("Range"))
+						w.WriteHeader(http.StatusPartialContent)
+					}),
+				),
+			)
+			client := NewClient(github.NewClient(mockedClient), base)
+			resp, err := client.GetRawContent(context.Background(), "octocat", "hello", "README.md", tc.opts)
+			require.NoError(t, err)
+			defer func() { _ = resp.Body.Close() }()
+			require.Equal(t, http.StatusPartialContent, resp.StatusCode)
+		})
+	}
+}
+
 func TestUrlFromOpts(t *testing.T) {
 	base, _ := url.Parse("https://raw.example.com/")
 	ghClient := github.NewClient(nil)
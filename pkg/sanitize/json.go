@@ -0,0 +1,135 @@
+package sanitize
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// MarkdownFields lists the JSON keys that carry user-authored markdown in GitHub API payloads:
+// issue, pull request and discussion bodies, comment and review bodies and release notes.
+// These are rendered by clients, so unsafe HTML, hidden code fence metadata and links are filtered.
+var MarkdownFields = []string{
+	"body",
+}
+
+// TextFields lists the JSON keys that carry user-authored plain text in GitHub API payloads:
+// titles, commit messages, descriptions, summaries, notification subjects, annotation details and
+// profile fields. These are not rendered as markdown, so only invisible characters are removed and
+// the text is otherwise left as is.
+var TextFields = []string{
+	"title",
+	"display_title",
+	"message",
+	"description",
+	"short_description",
+	"summary",
+	"raw_details",
+	"bio",
+	"company",
+	"location",
+	"blog",
+}
+
+type fieldKind int
+
+const (
+	otherField fieldKind = iota
+	markdownField
+	textField
+)
+
+var fieldKinds = func() map[string]fieldKind {
+	kinds := make(map[string]fieldKind, len(MarkdownFields)+len(TextFields))
+	for _, f := range MarkdownFields {
+		kinds[f] = markdownField
+	}
+	for _, f := range TextFields {
+		kinds[f] = textField
+	}
+	return kinds
+}()
+
+// JSONFields walks a JSON document and sanitizes every string value stored under one of
+// the MarkdownFields or TextFields keys, at any depth. It returns the re-encoded document and whether
+// anything changed. Input that is not a JSON object or array is returned untouched.
+func JSONFields(data []byte) ([]byte, bool) {
+	return defaultSanitizer.JSONFields(data)
+}
+
+// JSONFields sanitizes a JSON document like the JSONFields function, using the allowed link hosts of
+// the sanitizer.
+func (s *Sanitizer) JSONFields(data []byte) ([]byte, bool) {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 || (trimmed[0] != '{' && trimmed[0] != '[') {
+		return data, false
+	}
+
+	// UseNumber keeps large IDs intact when the document is re-encoded
+	dec := json.NewDecoder(bytes.NewReader(trimmed))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil || dec.More() {
+		return data, false
+	}
+
+	v, changed := s.Value(v)
+	if !changed {
+		return data, false
+	}
+
+	out, err := json.Marshal(v)
+	if err != nil {
+		return data, false
+	}
+	return out, true
+}
+
+// Value sanitizes the MarkdownFields and TextFields of a decoded JSON value in place, at any depth,
+// and returns the value along with whether anything changed.
+func Value(v any) (any, bool) {
+	return defaultSanitizer.Value(v)
+}
+
+// Value sanitizes a decoded JSON value like the Value function, using the allowed link hosts of the sanitizer.
+func (s *Sanitizer) Value(v any) (any, bool) {
+	return s.sanitizeValue(v, otherField)
+}
+
+func (s *Sanitizer) sanitizeValue(v any, kind fieldKind) (any, bool) {
+	switch val := v.(type) {
+	case string:
+		var sanitized string
+		switch kind {
+		case markdownField:
+			sanitized = s.Markdown(val)
+		case textField:
+			sanitized = FilterInvisibleCharacters(val)
+		default:
+			return val, false
+		}
+		return sanitized, sanitized != val
+	case map[string]any:
+		changed := false
+		for k, child := range val {
+			newChild, childChanged := s.sanitizeValue(child, fieldKinds[k])
+			if childChanged {
+				val[k] = newChild
+				changed = true
+			}
+		}
+		return val, changed
+	case []any:
+		changed := false
+		for i, child := range val {
+			// Arrays inherit the field they belong to, e.g. a list of commit messages
+			newChild, childChanged := s.sanitizeValue(child, kind)
+			if childChanged {
+				val[i] = newChild
+				changed = true
+			}
+		}
+		return val, changed
+	default:
+		return v, false
+	}
+}
@@ -0,0 +1,85 @@
+package sanitize
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJSONFields(t *testing.T) {
+	tests := []struct {
+		name            string
+		input           string
+		expected        string
+		expectedChanged bool
+	}{
+		{
+			name:            "not json",
+			input:           "<script>alert(1)</script>",
+			expected:        "<script>alert(1)</script>",
+			expectedChanged: false,
+		},
+		{
+			name:            "json scalar",
+			input:           `"<script>alert(1)</script>"`,
+			expected:        `"<script>alert(1)</script>"`,
+			expectedChanged: false,
+		},
+		{
+			name:            "nothing to sanitize",
+			input:           `{"title":"Hello","body":"World"}`,
+			expected:        `{"title":"Hello","body":"World"}`,
+			expectedChanged: false,
+		},
+		{
+			name:            "sanitizes markdown fields only",
+			input:           `{"body":"<script>x</script>hi","path":"<script>x</script>"}`,
+			expected:        `{"body":"hi","path":"\u003cscript\u003ex\u003c/script\u003e"}`,
+			expectedChanged: true,
+		},
+		{
+			name:            "sanitizes nested objects and arrays",
+			input:           `[{"commit":{"message":"a\u200Bb"}},{"messages":["x"],"body":"<iframe></iframe>d"}]`,
+			expected:        `[{"commit":{"message":"ab"}},{"body":"d","messages":["x"]}]`,
+			expectedChanged: true,
+		},
+		{
+			name:            "preserves large numbers",
+			input:           `{"id":9007199254740993,"body":"<b>x</b><img onerror=y src=z>"}`,
+			expected:        `{"body":"\u003cb\u003ex\u003c/b\u003e\u003cimg src=\"z\"\u003e","id":9007199254740993}`,
+			expectedChanged: true,
+		},
+		{
+			name:            "plain text fields only lose invisible characters",
+			input:           `{"title":"Fix\u200B <T> & \"quotes\"","message":"a <br> b"}`,
+			expected:        `{"message":"a \u003cbr\u003e b","title":"Fix \u003cT\u003e \u0026 \"quotes\""}`,
+			expectedChanged: true,
+		},
+		{
+			name:            "summaries and profile fields are plain text",
+			input:           `{"summary":"CVE\u200B <x>","display_title":"Run\u200B","bio":"Hi\u200B"}`,
+			expected:        `{"bio":"Hi","display_title":"Run","summary":"CVE \u003cx\u003e"}`,
+			expectedChanged: true,
+		},
+		{
+			name:            "plain markdown is not entity-encoded",
+			input:           `{"body":"Tom & Jerry's \"show\""}`,
+			expected:        `{"body":"Tom & Jerry's \"show\""}`,
+			expectedChanged: false,
+		},
+		{
+			name:            "invalid json is left untouched",
+			input:           `{"body":"<script>`,
+			expected:        `{"body":"<script>`,
+			expectedChanged: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, changed := JSONFields([]byte(tt.input))
+			assert.Equal(t, tt.expectedChanged, changed)
+			assert.Equal(t, tt.expected, string(result))
+		})
+	}
+}
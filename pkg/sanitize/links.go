@@ -0,0 +1,365 @@
+package sanitize
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// DefaultAllowedLinkHosts are the hosts (and their subdomains) that markdown links and images
+// may point to. Everything else is neutralized by FilterMarkdownLinks.
+var DefaultAllowedLinkHosts = []string{
+	"github.com",
+	"githubusercontent.com",
+	"githubassets.com",
+}
+
+// IsAllowedLinkHost reports whether host is one of DefaultAllowedLinkHosts or a subdomain of one.
+func IsAllowedLinkHost(host string) bool {
+	return defaultSanitizer.IsAllowedLinkHost(host)
+}
+
+// IsAllowedLinkHost reports whether host is one of the allowed link hosts of the sanitizer or a subdomain of one.
+func (s *Sanitizer) IsAllowedLinkHost(host string) bool {
+	host = strings.ToLower(host)
+	for _, allowed := range s.allowedLinkHosts {
+		if host == allowed || strings.HasSuffix(host, "."+allowed) {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	linkReferenceDefinition = regexp.MustCompile(`^ {0,3}\[([^\]]+)\]:[ \t]*<?([^\s>]+)>?`)
+	imageReference          = regexp.MustCompile(`!\[([^\]]*)\](?:\[([^\]]*)\])?`)
+)
+
+// imageReferenceLabels returns the normalized labels of the reference definitions that images may use,
+// such as shot in ![a screenshot][shot], ![shot][] or ![shot].
+func imageReferenceLabels(input string) map[string]bool {
+	labels := make(map[string]bool)
+	for _, m := range imageReference.FindAllStringSubmatch(input, -1) {
+		label := m[2]
+		if label == "" {
+			label = m[1]
+		}
+		labels[normalizeReferenceLabel(label)] = true
+	}
+	return labels
+}
+
+func normalizeReferenceLabel(label string) string {
+	return strings.ToLower(strings.Join(strings.Fields(label), " "))
+}
+
+// FilterMarkdownLinks rewrites markdown links and images that could be used to exfiltrate data
+// when a client renders them:
+// - Links to hosts that are not allowed are replaced by their text
+// - Images on hosts that are not allowed are replaced by their alt text
+// - Query strings and fragments are stripped from all image URLs, including the reference definitions images use
+// - Reference definitions and autolinks pointing to hosts that are not allowed are removed
+// Relative links are kept, and fenced code blocks and code spans are left untouched.
+// Only DefaultAllowedLinkHosts are allowed.
+func FilterMarkdownLinks(input string) string {
+	return defaultSanitizer.FilterMarkdownLinks(input)
+}
+
+// FilterMarkdownLinks rewrites markdown links and images like the FilterMarkdownLinks function, allowing
+// the allowed link hosts of the sanitizer.
+func (s *Sanitizer) FilterMarkdownLinks(input string) string {
+	if input == "" || !strings.ContainsAny(input, "[<") {
+		return input
+	}
+
+	imageLabels := imageReferenceLabels(input)
+	lines := strings.Split(input, "\n")
+	out := make([]string, 0, len(lines))
+	var block []string
+	flush := func() {
+		if len(block) > 0 {
+			out = append(out, s.filterInlineLinks(strings.Join(block, "\n")))
+			block = block[:0]
+		}
+	}
+
+	fence := ""
+	for _, line := range lines {
+		if marker := codeFenceMarker(line); marker != "" {
+			switch {
+			case fence == "":
+				flush()
+				fence = marker
+			case strings.HasPrefix(marker, fence):
+				fence = ""
+				out = append(out, line)
+				continue
+			}
+		}
+		if fence != "" {
+			out = append(out, line)
+			continue
+		}
+		if m := linkReferenceDefinition.FindStringSubmatch(line); m != nil {
+			if !s.isAllowedLinkDestination(m[2]) {
+				continue
+			}
+			if imageLabels[normalizeReferenceLabel(m[1])] {
+				line = strings.Replace(line, m[2], stripQuery(m[2]), 1)
+			}
+			block = append(block, line)
+			continue
+		}
+		block = append(block, line)
+	}
+	flush()
+
+	return strings.Join(out, "\n")
+}
+
+// codeFenceMarker returns the backtick or tilde run that opens or closes a fenced code block on line, if any.
+func codeFenceMarker(line string) string {
+	trimmed := strings.TrimLeft(line, " ")
+	if len(line)-len(trimmed) > 3 || len(trimmed) < 3 {
+		return ""
+	}
+	c := trimmed[0]
+	if c != '`' && c != '~' {
+		return ""
+	}
+	n := 0
+	for n < len(trimmed) && trimmed[n] == c {
+		n++
+	}
+	if n < 3 {
+		return ""
+	}
+	return trimmed[:n]
+}
+
+func (s *Sanitizer) filterInlineLinks(input string) string {
+	var b strings.Builder
+	b.Grow(len(input))
+
+	for i := 0; i < len(input); {
+		switch c := input[i]; {
+		case c == '\\' && i+1 < len(input):
+			b.WriteString(input[i : i+2])
+			i += 2
+		case c == '`':
+			end := codeSpanEnd(input, i)
+			b.WriteString(input[i:end])
+			i = end
+		case c == '<':
+			if end, dest, ok := parseAutolink(input, i); ok {
+				if s.isAllowedLinkDestination(dest) {
+					b.WriteString(input[i:end])
+				} else if u, err := url.Parse(dest); err == nil && u.Host != "" {
+					b.WriteString(u.Hostname())
+				}
+				i = end
+				continue
+			}
+			b.WriteByte(c)
+			i++
+		case c == '!' && i+1 < len(input) && input[i+1] == '[':
+			if l, ok := parseInlineLink(input, i+1); ok {
+				b.WriteString(s.rewriteImage(l))
+				i = l.end
+				continue
+			}
+			b.WriteByte(c)
+			i++
+		case c == '[':
+			if l, ok := parseInlineLink(input, i); ok {
+				b.WriteString(s.rewriteLink(l))
+				i = l.end
+				continue
+			}
+			b.WriteByte(c)
+			i++
+		default:
+			b.WriteByte(c)
+			i++
+		}
+	}
+	return b.String()
+}
+
+type inlineLink struct {
+	text  string
+	dest  string
+	title string
+	end   int
+}
+
+func (s *Sanitizer) rewriteLink(l inlineLink) string {
+	text := s.filterInlineLinks(l.text)
+	if !s.isAllowedLinkDestination(l.dest) {
+		return text
+	}
+	return "[" + text + "](" + l.dest + l.title + ")"
+}
+
+func (s *Sanitizer) rewriteImage(l inlineLink) string {
+	if !s.isAllowedLinkDestination(l.dest) {
+		return l.text
+	}
+	return "![" + l.text + "](" + stripQuery(l.dest) + l.title + ")"
+}
+
+// isAllowedLinkDestination reports whether dest is relative or points to an allowed host over http(s).
+func (s *Sanitizer) isAllowedLinkDestination(dest string) bool {
+	u, err := url.Parse(strings.Trim(dest, "<>"))
+	if err != nil {
+		return false
+	}
+	if u.Scheme == "" && u.Host == "" {
+		return true
+	}
+	if u.Scheme != "http" && u.Scheme != "https" && u.Scheme != "" {
+		return false
+	}
+	return s.IsAllowedLinkHost(u.Hostname())
+}
+
+func stripQuery(dest string) string {
+	u, err := url.Parse(strings.Trim(dest, "<>"))
+	if err != nil {
+		return dest
+	}
+	u.RawQuery = ""
+	u.ForceQuery = false
+	u.Fragment = ""
+	u.RawFragment = ""
+	return u.String()
+}
+
+// codeSpanEnd returns the index just past the code span starting at i, or just past the
+// backtick run if the span is never closed.
+func codeSpanEnd(s string, i int) int {
+	n := 0
+	for i+n < len(s) && s[i+n] == '`' {
+		n++
+	}
+	run := s[i : i+n]
+	for j := i + n; j < len(s); {
+		k := strings.Index(s[j:], run)
+		if k == -1 {
+			break
+		}
+		k += j
+		m := 0
+		for k+m < len(s) && s[k+m] == '`' {
+			m++
+		}
+		if m == n {
+			return k + n
+		}
+		j = k + m
+	}
+	return i + n
+}
+
+// parseAutolink parses an autolink such as <https://github.com> starting at i.
+func parseAutolink(s string, i int) (int, string, bool) {
+	end := strings.IndexAny(s[i+1:], "<> \t\n")
+	if end == -1 || s[i+1+end] != '>' {
+		return 0, "", false
+	}
+	dest := s[i+1 : i+1+end]
+	if !strings.Contains(dest, "://") {
+		return 0, "", false
+	}
+	return i + end + 2, dest, true
+}
+
+// parseInlineLink parses [text](destination "title") starting at the opening bracket at i.
+func parseInlineLink(s string, i int) (inlineLink, bool) {
+	textEnd := -1
+	depth := 0
+	for j := i; j < len(s); j++ {
+		switch s[j] {
+		case '\\':
+			j++
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth == 0 {
+				textEnd = j
+			}
+		}
+		if textEnd != -1 {
+			break
+		}
+	}
+	if textEnd == -1 || textEnd+1 >= len(s) || s[textEnd+1] != '(' {
+		return inlineLink{}, false
+	}
+
+	j := textEnd + 2
+	for j < len(s) && (s[j] == ' ' || s[j] == '\t' || s[j] == '\n') {
+		j++
+	}
+
+	destStart := j
+	if j < len(s) && s[j] == '<' {
+		k := strings.IndexAny(s[j+1:], ">\n")
+		if k == -1 || s[j+1+k] != '>' {
+			return inlineLink{}, false
+		}
+		j += k + 2
+	} else {
+		parens := 0
+	dest:
+		for ; j < len(s); j++ {
+			switch s[j] {
+			case '\\':
+				j++
+			case '(':
+				parens++
+			case ')':
+				if parens == 0 {
+					break dest
+				}
+				parens--
+			case ' ', '\t', '\n':
+				break dest
+			}
+		}
+	}
+	if j > len(s) {
+		return inlineLink{}, false
+	}
+	dest := s[destStart:j]
+
+	titleStart := j
+	for j < len(s) && (s[j] == ' ' || s[j] == '\t' || s[j] == '\n') {
+		j++
+	}
+	if j < len(s) && (s[j] == '"' || s[j] == '\'' || s[j] == '(') {
+		closer := s[j]
+		if closer == '(' {
+			closer = ')'
+		}
+		k := strings.IndexByte(s[j+1:], closer)
+		if k == -1 {
+			return inlineLink{}, false
+		}
+		j += k + 2
+		for j < len(s) && (s[j] == ' ' || s[j] == '\t' || s[j] == '\n') {
+			j++
+		}
+	}
+	if j >= len(s) || s[j] != ')' {
+		return inlineLink{}, false
+	}
+
+	return inlineLink{
+		text:  s[i+1 : textEnd],
+		dest:  dest,
+		title: s[titleStart:j],
+		end:   j + 1,
+	}, true
+}
@@ -0,0 +1,172 @@
+package sanitize
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilterMarkdownLinks(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "plain text",
+			input:    "no links here",
+			expected: "no links here",
+		},
+		{
+			name:     "keep github link",
+			input:    "see [the docs](https://docs.github.com/en/rest?tab=1) for details",
+			expected: "see [the docs](https://docs.github.com/en/rest?tab=1) for details",
+		},
+		{
+			name:     "keep relative link",
+			input:    "see [readme](../README.md#usage)",
+			expected: "see [readme](../README.md#usage)",
+		},
+		{
+			name:     "neutralize link to other host",
+			input:    "click [here](https://attacker.example/?data=secret \"title\") now",
+			expected: "click here now",
+		},
+		{
+			name:     "neutralize javascript link",
+			input:    "[x](javascript:alert(1))",
+			expected: "x",
+		},
+		{
+			name:     "drop image on other host",
+			input:    "![x](https://attacker.example/?data=secret)",
+			expected: "x",
+		},
+		{
+			name:     "strip query from github hosted image",
+			input:    "![screenshot](https://user-images.githubusercontent.com/1/a.png?token=abc#frag \"shot\")",
+			expected: "![screenshot](https://user-images.githubusercontent.com/1/a.png \"shot\")",
+		},
+		{
+			name:     "badge linking to other host",
+			input:    "[![build](https://attacker.example/badge.svg?d=1)](https://attacker.example/ci)",
+			expected: "build",
+		},
+		{
+			name:     "badge hosted on github",
+			input:    "[![build](https://github.com/o/r/actions/workflows/ci.yml/badge.svg?branch=main)](https://github.com/o/r/actions)",
+			expected: "[![build](https://github.com/o/r/actions/workflows/ci.yml/badge.svg)](https://github.com/o/r/actions)",
+		},
+		{
+			name:     "url with parentheses",
+			input:    "[wiki](https://github.com/o/r/wiki/Foo_(bar)) and [bad](https://evil.example/a_(b))",
+			expected: "[wiki](https://github.com/o/r/wiki/Foo_(bar)) and bad",
+		},
+		{
+			name:     "drop reference definition to other host",
+			input:    "see [docs][1]\n\n[1]: https://attacker.example/?data=x\n[2]: https://github.com/o/r",
+			expected: "see [docs][1]\n\n[2]: https://github.com/o/r",
+		},
+		{
+			name:     "strip query from reference definitions used by images",
+			input:    "![shot][s] and ![logo]\n\n[s]: https://github.com/a.png?token=x\n[Logo]: <https://github.com/l.png?d=1>\n[docs]: https://github.com/o/r?tab=1",
+			expected: "![shot][s] and ![logo]\n\n[s]: https://github.com/a.png\n[Logo]: <https://github.com/l.png>\n[docs]: https://github.com/o/r?tab=1",
+		},
+		{
+			name:     "autolinks",
+			input:    "<https://attacker.example/?q=1> and <https://github.com/o/r>",
+			expected: "attacker.example and <https://github.com/o/r>",
+		},
+		{
+			name:     "code spans are left untouched",
+			input:    "use `![x](https://attacker.example/?q=1)` in docs",
+			expected: "use `![x](https://attacker.example/?q=1)` in docs",
+		},
+		{
+			name:     "fenced code blocks are left untouched",
+			input:    "```md\n![x](https://attacker.example/?q=1)\n```\n![y](https://attacker.example/?q=2)",
+			expected: "```md\n![x](https://attacker.example/?q=1)\n```\ny",
+		},
+		{
+			name:     "escaped brackets are not links",
+			input:    `\[x](https://attacker.example)`,
+			expected: `\[x](https://attacker.example)`,
+		},
+		{
+			name:     "unterminated link",
+			input:    "[x](https://attacker.example",
+			expected: "[x](https://attacker.example",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := FilterMarkdownLinks(tt.input)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestNewAllowedLinkHosts(t *testing.T) {
+	input := "[a](https://docs.example.com/x) [b](https://github.com/o/r)"
+	html := `<a href="https://docs.example.com/x">a</a>`
+
+	s := New([]string{" Example.com "})
+	assert.True(t, s.IsAllowedLinkHost("docs.example.com"))
+	assert.True(t, s.IsAllowedLinkHost("github.com"))
+	assert.False(t, s.IsAllowedLinkHost("notexample.com"))
+	assert.Equal(t, input, s.FilterMarkdownLinks(input))
+	assert.Contains(t, s.Sanitize(html), `href="https://docs.example.com/x"`)
+
+	// Other sanitizers, including the default one, keep their own allowlist
+	assert.False(t, IsAllowedLinkHost("docs.example.com"))
+	assert.Equal(t, "a [b](https://github.com/o/r)", FilterMarkdownLinks(input))
+	assert.Equal(t, "a", Sanitize(html))
+}
+
+func TestSanitizeFiltersEntityEncodedLinks(t *testing.T) {
+	input := "&#91;x&#93;(https://attacker.example/?data=1) <img src=\"https://github.com/a.png?d=1\">"
+	expected := "x <img src=\"https://github.com/a.png\">"
+
+	assert.Equal(t, expected, Sanitize(input))
+}
+
+func TestSanitizeFiltersHTMLLinksAndImages(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "anchor to other host is reduced to its text",
+			input:    `see <a href="https://attacker.example/?data=secret">the docs</a>`,
+			expected: "see the docs",
+		},
+		{
+			name:     "anchor to github is kept",
+			input:    `see <a href="https://github.com/o/r?tab=1">the repo</a>`,
+			expected: `see <a href="https://github.com/o/r?tab=1" rel="nofollow noreferrer noopener" target="_blank">the repo</a>`,
+		},
+		{
+			name:     "image on other host is reduced to its alt text",
+			input:    `<img src="https://attacker.example/leak/secret-data" alt="logo"> done`,
+			expected: "logo done",
+		},
+		{
+			name:     "image on other host without alt text is dropped",
+			input:    `a<img src="https://attacker.example/leak/secret-data">b`,
+			expected: "ab",
+		},
+		{
+			name:     "image on github keeps its path only",
+			input:    `<img src="https://github.com/o/r/raw/main/logo.png?token=abc" alt="logo">`,
+			expected: `<img src="https://github.com/o/r/raw/main/logo.png" alt="logo">`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Sanitize(tt.input))
+		})
+	}
+}
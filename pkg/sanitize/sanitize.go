@@ -1,18 +1,90 @@
 package sanitize
 
 import (
+	"net/url"
+	"regexp"
 	"strings"
-	"sync"
 	"unicode"
 
 	"github.com/microcosm-cc/bluemonday"
 )
 
-var policy *bluemonday.Policy
-var policyOnce sync.Once
+// Sanitizer filters user-authored content, holding links and images to its allowed link hosts.
+// The package-level functions use a Sanitizer that only allows DefaultAllowedLinkHosts.
+type Sanitizer struct {
+	allowedLinkHosts []string
+	policy           *bluemonday.Policy
+}
+
+var defaultSanitizer = New(nil)
+
+// New returns a Sanitizer that allows links and images to point to DefaultAllowedLinkHosts and to
+// allowedLinkHosts. A host also allows all of its subdomains.
+func New(allowedLinkHosts []string) *Sanitizer {
+	s := &Sanitizer{allowedLinkHosts: append([]string{}, DefaultAllowedLinkHosts...)}
+	for _, h := range allowedLinkHosts {
+		h = strings.ToLower(strings.TrimSpace(h))
+		if h != "" {
+			s.allowedLinkHosts = append(s.allowedLinkHosts, h)
+		}
+	}
+	s.policy = newPolicy(s.IsAllowedLinkHost)
+	return s
+}
 
 func Sanitize(input string) string {
-	return FilterHTMLTags(FilterCodeFenceMetadata(FilterInvisibleCharacters(input)))
+	return defaultSanitizer.Sanitize(input)
+}
+
+// Sanitize filters input like the Sanitize function, using the allowed link hosts of the sanitizer.
+func (s *Sanitizer) Sanitize(input string) string {
+	// Links are filtered last so that entity-encoded markdown emitted by the HTML filter is covered too
+	return s.FilterMarkdownLinks(s.FilterHTMLTags(FilterCodeFenceMetadata(FilterInvisibleCharacters(input))))
+}
+
+// Markdown sanitizes user-authored markdown the way Sanitize does, but only runs the HTML filter
+// when the text contains markup, and restores the ampersands and quotes the HTML filter entity-encodes
+// in text. Plain markdown such as "a & b" is therefore returned unchanged.
+func Markdown(input string) string {
+	return defaultSanitizer.Markdown(input)
+}
+
+// Markdown sanitizes input like the Markdown function, using the allowed link hosts of the sanitizer.
+func (s *Sanitizer) Markdown(input string) string {
+	out := FilterCodeFenceMetadata(FilterInvisibleCharacters(input))
+	if htmlMarkupPattern.MatchString(out) {
+		out = unescapeHTMLText(s.FilterHTMLTags(out))
+	}
+	return s.FilterMarkdownLinks(out)
+}
+
+// htmlMarkupPattern matches the start of a tag, comment or processing instruction. A "<" followed by
+// anything else is text to an HTML parser.
+var htmlMarkupPattern = regexp.MustCompile(`<[a-zA-Z/!?]`)
+
+var htmlTextUnescaper = strings.NewReplacer("&amp;", "&", "&#34;", `"`, "&#39;", "'")
+
+// unescapeHTMLText decodes ampersands and quotes in the text between the tags of sanitized HTML.
+// Tags and attribute values are left encoded, and "&lt;" is never decoded, so no markup can be introduced.
+func unescapeHTMLText(input string) string {
+	var b strings.Builder
+	b.Grow(len(input))
+	for input != "" {
+		start := strings.IndexByte(input, '<')
+		if start == -1 {
+			b.WriteString(htmlTextUnescaper.Replace(input))
+			break
+		}
+		b.WriteString(htmlTextUnescaper.Replace(input[:start]))
+		end := strings.IndexByte(input[start:], '>')
+		if end == -1 {
+			b.WriteString(input[start:])
+			break
+		}
+		b.WriteString(input[start : start+end+1])
+		input = input[start+end+1:]
+	}
+	return b.String()
 }
 
 // FilterInvisibleCharacters removes invisible or control characters that should not appear
@@ -36,10 +108,37 @@ func FilterInvisibleCharacters(input string) string {
 }
 
 func FilterHTMLTags(input string) string {
+	return defaultSanitizer.FilterHTMLTags(input)
+}
+
+// FilterHTMLTags filters input like the FilterHTMLTags function, using the allowed link hosts of the sanitizer.
+func (s *Sanitizer) FilterHTMLTags(input string) string {
 	if input == "" {
 		return input
 	}
-	return getPolicy().Sanitize(input)
+	return dropImagesWithoutSource(s.policy.Sanitize(input))
+}
+
+var (
+	sanitizedImgTag = regexp.MustCompile(`<img\b[^>]*>`)
+	sanitizedImgAlt = regexp.MustCompile(` alt="([^"]*)"`)
+)
+
+// dropImagesWithoutSource replaces the images whose source was removed by the policy, because it
+// points to a host that is not allowed, by their alt text, the way FilterMarkdownLinks does.
+func dropImagesWithoutSource(input string) string {
+	if !strings.Contains(input, "<img") {
+		return input
+	}
+	return sanitizedImgTag.ReplaceAllStringFunc(input, func(tag string) string {
+		if strings.Contains(tag, ` src="`) {
+			return tag
+		}
+		if m := sanitizedImgAlt.FindStringSubmatch(tag); m != nil {
+			return m[1]
+		}
+		return ""
+	})
 }
 
 // FilterCodeFenceMetadata removes hidden or suspicious info strings from fenced code blocks.
@@ -145,32 +244,41 @@ func isSafeCodeFenceToken(token string) bool {
 	return true
 }
 
-func getPolicy() *bluemonday.Policy {
-	policyOnce.Do(func() {
-		p := bluemonday.StrictPolicy()
-
-		p.AllowElements(
-			"b", "blockquote", "br", "code", "em",
-			"h1", "h2", "h3", "h4", "h5", "h6",
-			"hr", "i", "li", "ol", "p", "pre",
-			"strong", "sub", "sup", "table", "tbody",
-			"td", "th", "thead", "tr", "ul",
-			"a", "img",
-		)
-
-		p.AllowAttrs("href").OnElements("a")
-		p.AllowURLSchemes("http", "https")
-		p.RequireParseableURLs(true)
-		p.RequireNoFollowOnLinks(true)
-		p.RequireNoReferrerOnLinks(true)
-		p.AddTargetBlankToFullyQualifiedLinks(true)
-
-		p.AllowImages()
-		p.AllowAttrs("src", "alt", "title").OnElements("img")
-
-		policy = p
+func newPolicy(isAllowedHost func(host string) bool) *bluemonday.Policy {
+	p := bluemonday.StrictPolicy()
+
+	p.AllowElements(
+		"b", "blockquote", "br", "code", "em",
+		"h1", "h2", "h3", "h4", "h5", "h6",
+		"hr", "i", "li", "ol", "p", "pre",
+		"strong", "sub", "sup", "table", "tbody",
+		"td", "th", "thead", "tr", "ul",
+		"a", "img",
+	)
+
+	p.AllowAttrs("href").OnElements("a")
+	// Links and images in raw HTML are held to the same host allowlist as markdown ones. An
+	// anchor without href is reduced to its text, and an image without src to its alt text.
+	allowedHost := func(u *url.URL) bool { return isAllowedHost(u.Hostname()) }
+	p.AllowURLSchemeWithCustomPolicy("http", allowedHost)
+	p.AllowURLSchemeWithCustomPolicy("https", allowedHost)
+	p.RequireParseableURLs(true)
+	p.RequireNoFollowOnLinks(true)
+	p.RequireNoReferrerOnLinks(true)
+	p.AddTargetBlankToFullyQualifiedLinks(true)
+
+	// AllowImages is not used as it allows every http(s) URL, bypassing the host allowlist above
+	p.AllowRelativeURLs(true)
+	p.AllowAttrs("align").Matching(bluemonday.ImageAlign).OnElements("img")
+	p.AllowAttrs("height", "width").Matching(bluemonday.NumberOrPercent).OnElements("img")
+	p.AllowAttrs("src", "alt", "title").OnElements("img")
+	// Query strings on image URLs can carry exfiltrated data to whoever serves the image
+	p.RewriteSrc(func(u *url.URL) {
+		u.RawQuery = ""
+		u.ForceQuery = false
 	})
-	return policy
+
+	return p
 }
 
 func shouldRemoveRune(r rune) bool {
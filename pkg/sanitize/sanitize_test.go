@@ -220,13 +220,13 @@ func TestFilterHtmlTags(t *testing.T) {
 		},
 		{
 			name:     "allow anchor with https href",
-			input:    "Click <a href=\"https://example.com\">here</a> now",
-			expected: "Click <a href=\"https://example.com\" rel=\"nofollow noreferrer noopener\" target=\"_blank\">here</a> now",
+			input:    "Click <a href=\"https://github.com\">here</a> now",
+			expected: "Click <a href=\"https://github.com\" rel=\"nofollow noreferrer noopener\" target=\"_blank\">here</a> now",
 		},
 		{
 			name:     "anchor removed but inner text kept",
-			input:    "before <a href='https://example.com' onclick='alert(1)' title='foo' alt='bar'>link</a> after",
-			expected: "before <a href=\"https://example.com\" rel=\"nofollow noreferrer noopener\" target=\"_blank\">link</a> after",
+			input:    "before <a href='https://github.com' onclick='alert(1)' title='foo' alt='bar'>link</a> after",
+			expected: "before <a href=\"https://github.com\" rel=\"nofollow noreferrer noopener\" target=\"_blank\">link</a> after",
 		},
 		{
 			name:     "image removed (no textual fallback)",
@@ -300,3 +300,48 @@ func TestSanitizeRemovesInvisibleCodeFenceMetadata(t *testing.T) {
 	result := Sanitize(input)
 	assert.Equal(t, expected, result)
 }
+
+func TestMarkdown(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "plain text is not entity-encoded",
+			input:    `Tom & Jerry's "show" -> a < b`,
+			expected: `Tom & Jerry's "show" -> a < b`,
+		},
+		{
+			name:     "entities are kept as is without markup",
+			input:    "&lt;script&gt;alert(1)&lt;/script&gt;",
+			expected: "&lt;script&gt;alert(1)&lt;/script&gt;",
+		},
+		{
+			name:     "unsafe markup is removed and text decoded",
+			input:    `<b>Tom & Jerry's</b><script>alert(1)</script> "show"`,
+			expected: `<b>Tom & Jerry's</b> "show"`,
+		},
+		{
+			name:     "encoded markup stays encoded",
+			input:    "<b>x</b> &amp;lt;script&amp;gt; &lt;img&gt;",
+			expected: "<b>x</b> &lt;script&gt; &lt;img&gt;",
+		},
+		{
+			name:     "attribute values stay encoded",
+			input:    `<a href="https://github.com/?a=1&b=&#34;x&#34;">link & text</a>`,
+			expected: `<a href="https://github.com/?a=1&amp;b=&#34;x&#34;" rel="nofollow noreferrer noopener" target="_blank">link & text</a>`,
+		},
+		{
+			name:     "invisible characters and code fence metadata are removed",
+			input:    "a​b\n```go steal secrets\ncode\n```",
+			expected: "ab\n```\ncode\n```",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Markdown(tt.input))
+		})
+	}
+}
@@ -0,0 +1,96 @@
+// Package sniff detects the type of file contents from their first bytes and summarizes
+// binary formats that can't usefully be returned to a model as is.
+package sniff
+
+import (
+	"bytes"
+	"net/http"
+	"unicode/utf8"
+)
+
+// SampleSize is the number of leading bytes Detect needs to identify a file.
+const SampleSize = 8192
+
+// Format identifies the type of a file.
+type Format struct {
+	// Name is a short identifier such as "png", "pdf" or "zip", "text" for text
+	// files and "binary" for binary files of an unknown format
+	Name     string `json:"format"`
+	MIMEType string `json:"mime_type"`
+}
+
+// IsText reports whether the file holds text.
+func (f Format) IsText() bool {
+	return f.Name == "text"
+}
+
+// IsImage reports whether the file is an image that MCP clients can display.
+func (f Format) IsImage() bool {
+	switch f.Name {
+	case "png", "jpeg", "gif", "webp":
+		return true
+	default:
+		return false
+	}
+}
+
+var (
+	formatText   = Format{Name: "text", MIMEType: "text/plain; charset=utf-8"}
+	formatBinary = Format{Name: "binary", MIMEType: "application/octet-stream"}
+)
+
+type signature struct {
+	offset int
+	magic  []byte
+	format Format
+}
+
+var signatures = []signature{
+	{0, []byte("\x89PNG\r\n\x1a\n"), Format{Name: "png", MIMEType: "image/png"}},
+	{0, []byte("\xff\xd8\xff"), Format{Name: "jpeg", MIMEType: "image/jpeg"}},
+	{0, []byte("GIF87a"), Format{Name: "gif", MIMEType: "image/gif"}},
+	{0, []byte("GIF89a"), Format{Name: "gif", MIMEType: "image/gif"}},
+	{0, []byte("%PDF-"), Format{Name: "pdf", MIMEType: "application/pdf"}},
+	{0, []byte("PK\x03\x04"), Format{Name: "zip", MIMEType: "application/zip"}},
+	{0, []byte("PK\x05\x06"), Format{Name: "zip", MIMEType: "application/zip"}},
+	{0, []byte("\x1f\x8b"), Format{Name: "gzip", MIMEType: "application/gzip"}},
+	{0, []byte("SQLite format 3\x00"), Format{Name: "sqlite", MIMEType: "application/vnd.sqlite3"}},
+	{257, []byte("ustar"), Format{Name: "tar", MIMEType: "application/x-tar"}},
+}
+
+// Detect identifies the format of a file from its first bytes, which should hold
+// at least SampleSize bytes unless the file is smaller.
+func Detect(sample []byte) Format {
+	for _, s := range signatures {
+		if len(sample) >= s.offset+len(s.magic) && bytes.Equal(sample[s.offset:s.offset+len(s.magic)], s.magic) {
+			return s.format
+		}
+	}
+	if len(sample) >= 12 && bytes.Equal(sample[:4], []byte("RIFF")) && bytes.Equal(sample[8:12], []byte("WEBP")) {
+		return Format{Name: "webp", MIMEType: "image/webp"}
+	}
+	if LooksLikeText(sample) {
+		return formatText
+	}
+	if mimeType := http.DetectContentType(sample); mimeType != "application/octet-stream" && mimeType != "text/plain; charset=utf-8" {
+		return Format{Name: "binary", MIMEType: mimeType}
+	}
+	return formatBinary
+}
+
+// LooksLikeText reports whether sample is valid UTF-8 without NUL bytes. A multi-byte
+// character cut off at the end of the sample is ignored.
+func LooksLikeText(sample []byte) bool {
+	if bytes.IndexByte(sample, 0) != -1 {
+		return false
+	}
+	for i := len(sample) - 1; i >= 0 && i >= len(sample)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(sample[i]) {
+			if !utf8.FullRune(sample[i:]) {
+				sample = sample[:i]
+			}
+			break
+		}
+	}
+	return utf8.Valid(sample)
+}
@@ -0,0 +1,49 @@
+package sniff
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetect(t *testing.T) {
+	tarHeader := make([]byte, 512)
+	copy(tarHeader, "file.txt")
+	copy(tarHeader[257:], "ustar\x0000")
+
+	tests := []struct {
+		name     string
+		sample   []byte
+		expected string
+	}{
+		{name: "png", sample: []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"), expected: "png"},
+		{name: "jpeg", sample: []byte("\xff\xd8\xff\xe0\x00\x10JFIF"), expected: "jpeg"},
+		{name: "gif", sample: []byte("GIF89a\x01\x00\x01\x00"), expected: "gif"},
+		{name: "webp", sample: []byte("RIFF\x24\x00\x00\x00WEBPVP8 "), expected: "webp"},
+		{name: "pdf", sample: []byte("%PDF-1.7\n%\xe2\xe3\xcf\xd3\n"), expected: "pdf"},
+		{name: "zip", sample: []byte("PK\x03\x04\x14\x00"), expected: "zip"},
+		{name: "gzip", sample: []byte("\x1f\x8b\x08\x00"), expected: "gzip"},
+		{name: "sqlite", sample: []byte("SQLite format 3\x00\x10\x00"), expected: "sqlite"},
+		{name: "tar", sample: tarHeader, expected: "tar"},
+		{name: "utf-8 text", sample: []byte("# Héllo wörld\n"), expected: "text"},
+		{name: "empty file is text", sample: []byte{}, expected: "text"},
+		{name: "text cut inside a character", sample: []byte("héllo")[:2], expected: "text"},
+		{name: "invalid utf-8", sample: []byte("caf\xe9 au lait"), expected: "binary"},
+		{name: "nul bytes", sample: []byte("abc\x00def"), expected: "binary"},
+		{name: "text content type header does not matter", sample: append([]byte("\x89PNG\r\n\x1a\n"), bytes.Repeat([]byte("a"), 100)...), expected: "png"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, Detect(tc.sample).Name)
+		})
+	}
+}
+
+func TestFormatKinds(t *testing.T) {
+	assert.True(t, Detect([]byte("plain")).IsText())
+	assert.False(t, Detect([]byte("plain")).IsImage())
+	assert.True(t, Detect([]byte("GIF87a")).IsImage())
+	assert.False(t, Detect([]byte("%PDF-1.4")).IsImage())
+}
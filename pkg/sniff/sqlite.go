@@ -0,0 +1,188 @@
+package sniff
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// sqliteHeaderSize is the size of the database header at the start of page 1.
+const sqliteHeaderSize = 100
+
+// sqliteMaxDepth bounds how deep the schema b-tree is walked.
+const sqliteMaxDepth = 8
+
+// SQLiteInfo describes a SQLite database.
+type SQLiteInfo struct {
+	PageSize  int    `json:"page_size"`
+	PageCount int    `json:"page_count"`
+	Encoding  string `json:"encoding"`
+	// Tables lists the tables in the schema. It is incomplete when the schema
+	// spans pages that were not read.
+	Tables []string `json:"tables,omitempty"`
+}
+
+// parseSQLite reads the header and the table names of a SQLite database. data must
+// hold at least the first page to list the tables.
+func parseSQLite(data []byte) (*SQLiteInfo, error) {
+	if len(data) < sqliteHeaderSize {
+		return nil, errors.New("sqlite header is truncated")
+	}
+	info := &SQLiteInfo{
+		PageSize:  int(binary.BigEndian.Uint16(data[16:18])),
+		PageCount: int(binary.BigEndian.Uint32(data[28:32])),
+	}
+	if info.PageSize == 1 {
+		info.PageSize = 65536
+	}
+	switch binary.BigEndian.Uint32(data[56:60]) {
+	case 2:
+		info.Encoding = "utf-16le"
+	case 3:
+		info.Encoding = "utf-16be"
+	default:
+		info.Encoding = "utf-8"
+	}
+	if info.PageSize < 512 {
+		return info, fmt.Errorf("invalid sqlite page size %d", info.PageSize)
+	}
+
+	// The schema table sqlite_master is the b-tree rooted at page 1
+	p := &sqlitePages{data: data, pageSize: info.PageSize}
+	if err := p.walk(1, 0, func(record [][]byte) {
+		if len(record) >= 2 && string(record[0]) == "table" {
+			info.Tables = append(info.Tables, string(record[1]))
+		}
+	}); err != nil {
+		return info, err
+	}
+	return info, nil
+}
+
+type sqlitePages struct {
+	data     []byte
+	pageSize int
+}
+
+// walk visits the records of the table b-tree rooted at page n. Pages that were not read are skipped.
+func (p *sqlitePages) walk(n uint32, depth int, visit func([][]byte)) error {
+	if depth > sqliteMaxDepth {
+		return errors.New("sqlite schema b-tree is too deep")
+	}
+	start := (int(n) - 1) * p.pageSize
+	if n == 0 || start+p.pageSize > len(p.data) {
+		return nil
+	}
+	page := p.data[start : start+p.pageSize]
+	// Page 1 holds the database header before its b-tree header
+	hdr := 0
+	if n == 1 {
+		hdr = sqliteHeaderSize
+	}
+	if len(page) < hdr+12 {
+		return errors.New("sqlite page is truncated")
+	}
+
+	kind := page[hdr]
+	cells := int(binary.BigEndian.Uint16(page[hdr+3 : hdr+5]))
+	pointers := hdr + 8
+	if kind == 0x05 {
+		pointers = hdr + 12
+	}
+	if pointers+2*cells > len(page) {
+		return errors.New("sqlite cell pointer array is truncated")
+	}
+
+	for i := 0; i < cells; i++ {
+		off := int(binary.BigEndian.Uint16(page[pointers+2*i:]))
+		if off >= len(page) {
+			return errors.New("sqlite cell pointer is out of bounds")
+		}
+		cell := page[off:]
+		switch kind {
+		case 0x05:
+			// Interior table cell: left child page number, then the key
+			if len(cell) < 4 {
+				return errors.New("sqlite cell is truncated")
+			}
+			if err := p.walk(binary.BigEndian.Uint32(cell), depth+1, visit); err != nil {
+				return err
+			}
+		case 0x0d:
+			// Leaf table cell: payload size, row id, then the record
+			size, k := sqliteVarint(cell)
+			_, m := sqliteVarint(cell[k:])
+			payload := cell[k+m:]
+			// Payloads that spill to overflow pages are read as far as they fit on this page
+			if size < uint64(len(payload)) {
+				payload = payload[:size]
+			}
+			visit(sqliteRecord(payload))
+		default:
+			return fmt.Errorf("unexpected sqlite page type 0x%02x", kind)
+		}
+	}
+	if kind == 0x05 {
+		return p.walk(binary.BigEndian.Uint32(page[hdr+8:hdr+12]), depth+1, visit)
+	}
+	return nil
+}
+
+// sqliteRecord decodes the columns of a record. Columns that are not text or blobs are returned
+// as nil, and decoding stops at the first column that doesn't fit in payload.
+func sqliteRecord(payload []byte) [][]byte {
+	headerSize, n := sqliteVarint(payload)
+	if n == 0 || headerSize > uint64(len(payload)) {
+		return nil
+	}
+	var columns [][]byte
+	body := payload[headerSize:]
+	for h := payload[n:headerSize]; len(h) > 0; {
+		serialType, k := sqliteVarint(h)
+		if k == 0 {
+			break
+		}
+		h = h[k:]
+
+		var size int
+		switch {
+		case serialType >= 12:
+			size = int((serialType - 12) / 2)
+		case serialType >= 1 && serialType <= 4:
+			size = int(serialType)
+		case serialType == 5:
+			size = 6
+		case serialType == 6 || serialType == 7:
+			size = 8
+		}
+		if size > len(body) {
+			break
+		}
+		if serialType >= 12 {
+			columns = append(columns, body[:size])
+		} else {
+			columns = append(columns, nil)
+		}
+		body = body[size:]
+	}
+	return columns
+}
+
+// sqliteVarint decodes a SQLite variable-length integer, returning the value and the number of
+// bytes read, which is 0 if b is truncated.
+func sqliteVarint(b []byte) (uint64, int) {
+	var v uint64
+	for i := 0; i < 9; i++ {
+		if i >= len(b) {
+			return 0, 0
+		}
+		if i == 8 {
+			return v<<8 | uint64(b[i]), 9
+		}
+		v = v<<7 | uint64(b[i]&0x7f)
+		if b[i]&0x80 == 0 {
+			return v, i + 1
+		}
+	}
+	return v, 9
+}
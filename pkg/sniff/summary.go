@@ -0,0 +1,229 @@
+package sniff
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"  // register GIF for image.DecodeConfig
+	_ "image/jpeg" // register JPEG for image.DecodeConfig
+	_ "image/png"  // register PNG for image.DecodeConfig
+	"io"
+	"regexp"
+	"strconv"
+)
+
+// MaxArchiveEntries is the number of entries listed in the summary of an archive.
+const MaxArchiveEntries = 100
+
+// maxDecompressedSize bounds how much of a gzip stream is decompressed to list a tarball.
+const maxDecompressedSize = 64 * 1024 * 1024
+
+// Summary describes a binary file without including its content.
+type Summary struct {
+	Format
+	Size int64 `json:"size"`
+	// Width and Height are set for images
+	Width  int `json:"width,omitempty"`
+	Height int `json:"height,omitempty"`
+	// Pages is set for PDFs, when the page count could be determined
+	Pages int `json:"pages,omitempty"`
+	// Entries lists up to MaxArchiveEntries entries of zip and tar archives
+	Entries      []ArchiveEntry `json:"entries,omitempty"`
+	TotalEntries int            `json:"total_entries,omitempty"`
+	// SQLite is set for SQLite databases
+	SQLite *SQLiteInfo `json:"sqlite,omitempty"`
+	// Error explains why the file could not be summarized further, e.g. when it is corrupt or truncated
+	Error string `json:"error,omitempty"`
+}
+
+// ArchiveEntry is a file or directory in an archive.
+type ArchiveEntry struct {
+	Name string `json:"name"`
+	Size int64  `json:"size"`
+	Dir  bool   `json:"dir,omitempty"`
+}
+
+// Summarize describes data, the content of a file in the given format. size is the size of the whole file,
+// which may be larger than data if only its beginning was read.
+func Summarize(format Format, data []byte, size int64) *Summary {
+	s := &Summary{Format: format, Size: size}
+
+	var err error
+	switch format.Name {
+	case "png", "jpeg", "gif":
+		var cfg image.Config
+		if cfg, _, err = image.DecodeConfig(bytes.NewReader(data)); err == nil {
+			s.Width, s.Height = cfg.Width, cfg.Height
+		}
+	case "webp":
+		s.Width, s.Height, err = webpDimensions(data)
+	case "pdf":
+		s.Pages = pdfPageCount(data)
+	case "zip":
+		err = s.listZip(data)
+	case "tar":
+		err = s.listTar(bytes.NewReader(data))
+	case "gzip":
+		err = s.listGzip(data)
+	case "sqlite":
+		s.SQLite, err = parseSQLite(data)
+	}
+	if err != nil {
+		s.Error = err.Error()
+	}
+	return s
+}
+
+// tailReaderAt reads the last bytes of a file of the given size. The part before them reads as zeros,
+// which the zip reader rejects if the central directory starts there.
+type tailReaderAt struct {
+	tail []byte
+	size int64
+}
+
+func (r *tailReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	if off >= r.size {
+		return 0, io.EOF
+	}
+	start := r.size - int64(len(r.tail))
+	n := 0
+	if off < start {
+		n = int(min(int64(len(p)), start-off))
+		clear(p[:n])
+		off += int64(n)
+	}
+	if n < len(p) {
+		n += copy(p[n:], r.tail[off-start:])
+	}
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// SummarizeTail describes a zip file from tail, its last bytes, which hold the central directory listing
+// its entries. size is the size of the whole file. Other formats are summarized from their beginning, so
+// the summary only holds their format.
+func SummarizeTail(format Format, tail []byte, size int64) *Summary {
+	s := &Summary{Format: format, Size: size}
+	if format.Name != "zip" {
+		return s
+	}
+	r, err := zip.NewReader(&tailReaderAt{tail: tail, size: size}, size)
+	if err != nil {
+		s.Error = fmt.Sprintf("no listing is available, the central directory of the zip file could not be read from its last %d bytes: %s", len(tail), err)
+		return s
+	}
+	for _, f := range r.File {
+		s.addEntry(ArchiveEntry{Name: f.Name, Size: int64(f.UncompressedSize64), Dir: f.FileInfo().IsDir()})
+	}
+	return s
+}
+
+func (s *Summary) addEntry(e ArchiveEntry) {
+	s.TotalEntries++
+	if len(s.Entries) < MaxArchiveEntries {
+		s.Entries = append(s.Entries, e)
+	}
+}
+
+func (s *Summary) listZip(data []byte) error {
+	r, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return err
+	}
+	for _, f := range r.File {
+		s.addEntry(ArchiveEntry{Name: f.Name, Size: int64(f.UncompressedSize64), Dir: f.FileInfo().IsDir()})
+	}
+	return nil
+}
+
+func (s *Summary) listTar(r io.Reader) error {
+	tr := tar.NewReader(r)
+	for {
+		h, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		s.addEntry(ArchiveEntry{Name: h.Name, Size: h.Size, Dir: h.Typeflag == tar.TypeDir})
+	}
+}
+
+// listGzip lists the entries of a gzipped tarball. Other gzip streams are only reported as gzip.
+func (s *Summary) listGzip(data []byte) error {
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	defer func() { _ = zr.Close() }()
+
+	br := io.LimitReader(zr, maxDecompressedSize)
+	head := make([]byte, 262)
+	n, err := io.ReadFull(br, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return err
+	}
+	if Detect(head[:n]).Name != "tar" {
+		return nil
+	}
+	s.Format = Format{Name: "tar.gz", MIMEType: "application/gzip"}
+	return s.listTar(io.MultiReader(bytes.NewReader(head[:n]), br))
+}
+
+var (
+	pdfPagesCount = regexp.MustCompile(`/Type\s*/Pages\b[^>]*?/Count\s+(\d+)|/Count\s+(\d+)[^>]*?/Type\s*/Pages\b`)
+	pdfPage       = regexp.MustCompile(`/Type\s*/Page\b`)
+)
+
+// pdfPageCount returns the number of pages of a PDF, or 0 if it can't be determined.
+// It reads the /Count of the page tree, which is the largest one as nested page trees count fewer pages,
+// and otherwise counts the page objects. Both miss pages stored in compressed object streams.
+func pdfPageCount(data []byte) int {
+	count := 0
+	for _, m := range pdfPagesCount.FindAllSubmatch(data, -1) {
+		v := m[1]
+		if v == nil {
+			v = m[2]
+		}
+		if n, err := strconv.Atoi(string(v)); err == nil && n > count {
+			count = n
+		}
+	}
+	if count > 0 {
+		return count
+	}
+	return len(pdfPage.FindAllIndex(data, -1))
+}
+
+// webpDimensions reads the canvas size of a WebP image from its first chunk.
+func webpDimensions(data []byte) (int, int, error) {
+	if len(data) < 30 {
+		return 0, 0, errors.New("webp header is truncated")
+	}
+	switch string(data[12:16]) {
+	case "VP8 ":
+		// Lossy: 14-bit dimensions after the frame start code
+		w := int(binary.LittleEndian.Uint16(data[26:28]) & 0x3fff)
+		h := int(binary.LittleEndian.Uint16(data[28:30]) & 0x3fff)
+		return w, h, nil
+	case "VP8L":
+		// Lossless: 14-bit width-1 and height-1 packed after the signature byte
+		bits := binary.LittleEndian.Uint32(data[21:25])
+		return int(bits&0x3fff) + 1, int((bits>>14)&0x3fff) + 1, nil
+	case "VP8X":
+		// Extended: 24-bit canvas width-1 and height-1
+		w := int(data[24]) | int(data[25])<<8 | int(data[26])<<16
+		h := int(data[27]) | int(data[28])<<8 | int(data[29])<<16
+		return w + 1, h + 1, nil
+	default:
+		return 0, 0, errors.New("unknown webp chunk")
+	}
+}
@@ -0,0 +1,195 @@
+package sniff
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func summarize(data []byte) *Summary {
+	return Summarize(Detect(data), data, int64(len(data)))
+}
+
+func TestSummarizeImages(t *testing.T) {
+	var pngData bytes.Buffer
+	require.NoError(t, png.Encode(&pngData, image.NewRGBA(image.Rect(0, 0, 40, 30))))
+	var gifData bytes.Buffer
+	require.NoError(t, gif.Encode(&gifData, image.NewPaletted(image.Rect(0, 0, 7, 5), color.Palette{color.Black, color.White}), nil))
+
+	// Canvas size of a lossless WebP image: 14 bits each of width-1 and height-1
+	vp8l := []byte("RIFF\x00\x00\x00\x00WEBPVP8L\x00\x00\x00\x00\x2f")
+	vp8l = binary.LittleEndian.AppendUint32(vp8l, uint32(99)|uint32(49)<<14)
+	vp8l = append(vp8l, make([]byte, 8)...)
+
+	tests := []struct {
+		name           string
+		data           []byte
+		format         string
+		width, height  int
+		expectingError bool
+	}{
+		{name: "png", data: pngData.Bytes(), format: "png", width: 40, height: 30},
+		{name: "gif", data: gifData.Bytes(), format: "gif", width: 7, height: 5},
+		{name: "webp", data: vp8l, format: "webp", width: 100, height: 50},
+		{name: "corrupt png", data: []byte("\x89PNG\r\n\x1a\nnot really"), format: "png", expectingError: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			s := summarize(tc.data)
+			assert.Equal(t, tc.format, s.Name)
+			assert.Equal(t, tc.width, s.Width)
+			assert.Equal(t, tc.height, s.Height)
+			assert.Equal(t, tc.expectingError, s.Error != "")
+		})
+	}
+}
+
+func TestSummarizePDF(t *testing.T) {
+	t.Run("page tree count", func(t *testing.T) {
+		data := []byte("%PDF-1.4\n1 0 obj << /Type /Catalog /Pages 2 0 R >> endobj\n" +
+			"2 0 obj << /Type /Pages /Kids [3 0 R 4 0 R 5 0 R] /Count 3 >> endobj\n")
+		assert.Equal(t, 3, summarize(data).Pages)
+	})
+	t.Run("count before type", func(t *testing.T) {
+		data := []byte("%PDF-1.4\n2 0 obj << /Count 12 /Kids [] /Type /Pages >> endobj\n")
+		assert.Equal(t, 12, summarize(data).Pages)
+	})
+	t.Run("page objects without a page tree count", func(t *testing.T) {
+		data := []byte("%PDF-1.4\n3 0 obj << /Type /Page >> endobj\n4 0 obj << /Type/Page >> endobj\n")
+		assert.Equal(t, 2, summarize(data).Pages)
+	})
+}
+
+func TestSummarizeArchives(t *testing.T) {
+	var zipData bytes.Buffer
+	zw := zip.NewWriter(&zipData)
+	_, err := zw.Create("docs/")
+	require.NoError(t, err)
+	w, err := zw.Create("docs/README.md")
+	require.NoError(t, err)
+	_, err = w.Write([]byte("# Hello"))
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+
+	var tarData bytes.Buffer
+	tw := tar.NewWriter(&tarData)
+	for i := 0; i < MaxArchiveEntries+5; i++ {
+		content := fmt.Sprintf("file %d", i)
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: fmt.Sprintf("f%d.txt", i), Mode: 0o644, Size: int64(len(content))}))
+		_, err := tw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+
+	var tgzData bytes.Buffer
+	gw := gzip.NewWriter(&tgzData)
+	_, err = gw.Write(tarData.Bytes())
+	require.NoError(t, err)
+	require.NoError(t, gw.Close())
+
+	t.Run("zip", func(t *testing.T) {
+		s := summarize(zipData.Bytes())
+		assert.Equal(t, "zip", s.Name)
+		assert.Equal(t, 2, s.TotalEntries)
+		assert.Equal(t, []ArchiveEntry{{Name: "docs/", Dir: true}, {Name: "docs/README.md", Size: 7}}, s.Entries)
+	})
+	t.Run("tar listing is capped", func(t *testing.T) {
+		s := summarize(tarData.Bytes())
+		assert.Equal(t, "tar", s.Name)
+		assert.Equal(t, MaxArchiveEntries+5, s.TotalEntries)
+		assert.Len(t, s.Entries, MaxArchiveEntries)
+		assert.Equal(t, ArchiveEntry{Name: "f0.txt", Size: 6}, s.Entries[0])
+	})
+	t.Run("gzipped tar", func(t *testing.T) {
+		s := summarize(tgzData.Bytes())
+		assert.Equal(t, "tar.gz", s.Name)
+		assert.Equal(t, MaxArchiveEntries+5, s.TotalEntries)
+	})
+	t.Run("truncated zip", func(t *testing.T) {
+		s := summarize(zipData.Bytes()[:20])
+		assert.Equal(t, "zip", s.Name)
+		assert.NotEmpty(t, s.Error)
+	})
+	t.Run("zip from its tail", func(t *testing.T) {
+		data := zipData.Bytes()
+		s := SummarizeTail(Format{Name: "zip", MIMEType: "application/zip"}, data[len(data)-150:], int64(len(data)))
+		assert.Empty(t, s.Error)
+		assert.Equal(t, int64(len(data)), s.Size)
+		assert.Equal(t, 2, s.TotalEntries)
+		assert.Equal(t, []ArchiveEntry{{Name: "docs/", Dir: true}, {Name: "docs/README.md", Size: 7}}, s.Entries)
+	})
+	t.Run("zip tail too short for the central directory", func(t *testing.T) {
+		data := zipData.Bytes()
+		s := SummarizeTail(Format{Name: "zip", MIMEType: "application/zip"}, data[len(data)-30:], int64(len(data)))
+		assert.NotEmpty(t, s.Error)
+		assert.Empty(t, s.Entries)
+	})
+}
+
+// sqliteFixture builds a single page SQLite database whose schema holds the given tables.
+func sqliteFixture(tables ...string) []byte {
+	const pageSize = 512
+	page := make([]byte, pageSize)
+	copy(page, "SQLite format 3\x00")
+	binary.BigEndian.PutUint16(page[16:], pageSize)
+	binary.BigEndian.PutUint32(page[28:], 1)
+	binary.BigEndian.PutUint32(page[56:], 1)
+
+	// Leaf table b-tree page, with cells packed at the end of the page
+	page[100] = 0x0d
+	binary.BigEndian.PutUint16(page[103:], uint16(len(tables)))
+	end := pageSize
+	for i, name := range tables {
+		sql := "CREATE TABLE " + name + "(id)"
+		// Columns: type, name, tbl_name, rootpage, sql
+		columns := []string{"table", name, name}
+		header := []byte{0}
+		var body []byte
+		for _, c := range columns {
+			header = append(header, byte(len(c)*2+13))
+			body = append(body, c...)
+		}
+		header = append(header, 1, byte(len(sql)*2+13))
+		body = append(body, byte(i+2))
+		body = append(body, sql...)
+		header[0] = byte(len(header))
+		record := append(header, body...)
+
+		cell := append([]byte{byte(len(record)), byte(i + 1)}, record...)
+		end -= len(cell)
+		copy(page[end:], cell)
+		binary.BigEndian.PutUint16(page[108+2*i:], uint16(end))
+	}
+	binary.BigEndian.PutUint16(page[105:], uint16(end))
+	return page
+}
+
+func TestSummarizeSQLite(t *testing.T) {
+	s := summarize(sqliteFixture("users", "repos"))
+	assert.Equal(t, "sqlite", s.Name)
+	assert.Empty(t, s.Error)
+	assert.Equal(t, &SQLiteInfo{PageSize: 512, PageCount: 1, Encoding: "utf-8", Tables: []string{"users", "repos"}}, s.SQLite)
+
+	t.Run("truncated header", func(t *testing.T) {
+		s := summarize(sqliteFixture("users")[:50])
+		assert.Nil(t, s.SQLite)
+		assert.NotEmpty(t, s.Error)
+	})
+	t.Run("schema page not read", func(t *testing.T) {
+		s := summarize(sqliteFixture("users")[:200])
+		assert.Empty(t, s.Error)
+		assert.Empty(t, s.SQLite.Tables)
+	})
+}